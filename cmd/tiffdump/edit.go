@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/fedragon/tiff-parser/writer"
+)
+
+// entryFlag collects the entries given by repeated flags, by name (e.g. "Artist") or ID (e.g. "0x013b").
+type entryFlag []tiff.EntryID
+
+func (f *entryFlag) String() string {
+	names := make([]string, len(*f))
+	for i, id := range *f {
+		names[i] = id.Name()
+	}
+
+	return strings.Join(names, ",")
+}
+
+func (f *entryFlag) Set(value string) error {
+	id, err := parseEntryID(value)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, id)
+
+	return nil
+}
+
+// setFlag collects the values set by repeated flags, e.g. `-set Artist="Jane Doe"`.
+type setFlag map[tiff.EntryID]string
+
+func (f setFlag) String() string {
+	return fmt.Sprint(map[tiff.EntryID]string(f))
+}
+
+func (f setFlag) Set(value string) error {
+	name, text, ok := strings.Cut(value, "=")
+	if !ok {
+		return errors.New("expected <entry>=<value>")
+	}
+	id, err := parseEntryID(name)
+	if err != nil {
+		return err
+	}
+	f[id] = text
+
+	return nil
+}
+
+// parseEntryID returns the ID of an entry given its name (e.g. "Artist") or its ID (e.g. "0x013b").
+func parseEntryID(name string) (tiff.EntryID, error) {
+	if strings.HasPrefix(name, "0x") {
+		id, err := strconv.ParseUint(name[2:], 16, 16)
+		if err != nil {
+			return 0, fmt.Errorf("invalid entry ID %q", name)
+		}
+		return tiff.EntryID(id), nil
+	}
+	for id := range tiff.Defaults {
+		if id.Name() == name {
+			return id, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown entry %q", name)
+}
+
+func edit(args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("edit", "<files>", stderr)
+	edits := writer.Edits{Set: make(map[tiff.EntryID]string)}
+	flags.Var(setFlag(edits.Set), "set", "set a text entry of IFD#0, of the Exif or of the GPSInfo IFD, as <entry>=<value> (repeatable)")
+	flags.Var((*entryFlag)(&edits.Delete), "delete", "delete an entry, by name or ID, e.g. GPSInfo for all GPS data (repeatable)")
+	flags.DurationVar(&edits.ShiftTime, "shift", 0, "shift DateTime and DateTimeOriginal, e.g. 2h or -30m")
+	files, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(edits.Set) == 0 && len(edits.Delete) == 0 && edits.ShiftTime == 0 {
+		fmt.Fprintln(stderr, "expected at least one of -set, -delete, -shift")
+		flags.Usage()
+		return errUsage
+	}
+	if err := edits.Validate(); err != nil {
+		fmt.Fprintln(stderr, err)
+		flags.Usage()
+		return errUsage
+	}
+
+	failed := 0
+	for _, path := range files {
+		if err := writer.ApplyFile(path, edits, writer.FileOptions{}); err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", path, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(stdout, "%s: edited\n", path)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files not edited", failed, len(files))
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEdit(t *testing.T) {
+	inTempDir(t, "image.cr2")
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"edit", "-set", "Artist=Jane Doe", "-set", "0x8298=(c) 2021 Jane Doe", "-delete", "Make", "-shift", "2h", "image.cr2", "missing.cr2"}
+	assert.Equal(t, 1, run(args, &stdout, &stderr))
+	assert.Equal(t, "image.cr2: edited\n", stdout.String())
+	assert.Contains(t, stderr.String(), "missing.cr2: ")
+	assert.Contains(t, stderr.String(), "1 of 2 files not edited")
+
+	f, err := os.Open("image.cr2")
+	assert.NoError(t, err)
+	defer f.Close()
+	p, err := tiff.NewParser(f)
+	assert.NoError(t, err)
+	entries, err := p.Parse(tiff.Artist, tiff.Copyright, tiff.Make, tiff.DateTimeOriginal)
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane Doe", *entries[tiff.Artist].Value.String)
+	assert.Equal(t, "(c) 2021 Jane Doe", *entries[tiff.Copyright].Value.String)
+	assert.Equal(t, "2021:11:19 14:21:10", *entries[tiff.DateTimeOriginal].Value.String)
+	assert.NotContains(t, entries, tiff.Make)
+}
+
+func TestEdit_Usage(t *testing.T) {
+	inTempDir(t, "image.cr2")
+
+	for _, args := range [][]string{
+		{"edit", "image.cr2"},
+		{"edit", "-set", "Artist"},
+		{"edit", "-set", "Unknown=value", "image.cr2"},
+		{"edit", "-delete", "0xzz", "image.cr2"},
+		{"edit", "-delete", "StripOffsets", "image.cr2"},
+		{"edit", "-set", "Artist=Jane Doe", "-delete", "Artist", "image.cr2"},
+	} {
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, 2, run(args, &stdout, &stderr), args)
+		assert.Empty(t, stdout.String(), args)
+	}
+}
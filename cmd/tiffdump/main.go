@@ -0,0 +1,115 @@
+// Command tiffdump inspects and manages TIFF-like files (e.g. CR2, ORF) from the command line.
+//
+// Usage:
+//
+//	tiffdump <command> [flags] <files>
+//
+// Run `tiffdump <command> -h` for the flags of each command.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// command is a subcommand of tiffdump.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) error
+}
+
+var commands = []command{
+	{name: "edit", summary: "set, delete or shift entries of TIFF files, in place", run: edit},
+	{name: "rename", summary: "rename files by capture date", run: rename},
+	{name: "strip", summary: "remove metadata from a TIFF or JPEG file", run: strip},
+	{name: "validate", summary: "check the structure of files", run: validate},
+}
+
+// errUsage is returned by commands whose flags or arguments are invalid, once they have reported why.
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command named by the first argument, returning the exit code: 0 on success, 1 on failure and 2 on
+// invalid usage.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+
+		err := cmd.run(args[1:], stdout, stderr)
+		switch {
+		case err == nil, errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errUsage):
+			return 2
+		default:
+			fmt.Fprintf(stderr, "tiffdump %s: %v\n", cmd.name, err)
+			return 1
+		}
+	}
+
+	fmt.Fprintf(stderr, "tiffdump: unknown command %q\n", args[0])
+	usage(stderr)
+
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: tiffdump <command> [flags] <files>")
+	fmt.Fprintln(w, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+}
+
+// newFlagSet returns the flag set of a command, reporting errors to stderr.
+func newFlagSet(name, args string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: tiffdump %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// parseFlags parses the arguments of a command, in which flags and files can be interleaved (e.g. `in.tif -o out.tif`),
+// returning the files. It returns errUsage if the arguments are invalid or no file is given.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var files []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errUsage
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		files = append(files, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(files) == 0 {
+		fmt.Fprintln(fs.Output(), "no file given")
+		fs.Usage()
+		return nil, errUsage
+	}
+
+	return files, nil
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+const defaultRenamePattern = "2006/01/02/150405_{model}.{ext}"
+
+// placeholder matches the placeholders of a rename pattern, e.g. {model}.
+var placeholder = regexp.MustCompile(`\{(\w+)\}`)
+
+// cleanName replaces characters that are not allowed in file names.
+var cleanName = strings.NewReplacer("/", "_", "\\", "_", ":", "_", "*", "_", "?", "_", `"`, "_", "<", "_", ">", "_", "|", "_")
+
+func rename(args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("rename", "<files>", stderr)
+	pattern := flags.String("pattern", defaultRenamePattern, "destination of files, relative to the current directory: a Go time "+
+		"layout applied to the capture date, which can contain the {make}, {model}, {name} (file name without extension) "+
+		"and {ext} (extension without dot) placeholders")
+	dryRun := flags.Bool("dry-run", false, "only print how files would be renamed")
+	files, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	taken := make(map[string]bool) // destinations of the files renamed so far
+	for _, path := range files {
+		dst, err := renameDestination(path, *pattern)
+		if err == nil && taken[dst] {
+			err = fmt.Errorf("%s is the destination of another file", dst)
+		}
+		if err == nil && *dryRun {
+			err = checkDestination(path, dst)
+		}
+		if err == nil && !*dryRun {
+			err = move(path, dst)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", path, err)
+			failed++
+			continue
+		}
+
+		taken[dst] = true
+		fmt.Fprintf(stdout, "%s -> %s\n", path, dst)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files not renamed", failed, len(files))
+	}
+
+	return nil
+}
+
+// renameDestination reads the capture date, make and model of a file and returns its destination according to pattern.
+func renameDestination(path, pattern string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	p, err := tiff.NewParser(f)
+	if err != nil {
+		return "", err
+	}
+	entries, err := p.Parse(tiff.Make, tiff.Model, tiff.DateTimeOriginal, tiff.OffsetTimeOriginal, tiff.OffsetTime)
+	if err != nil {
+		return "", err
+	}
+	t, err := tiff.CaptureTime(entries)
+	if err != nil {
+		return "", err
+	}
+
+	return expandPattern(pattern, t, entries, path)
+}
+
+// expandPattern formats the capture time t with the pattern, replacing its placeholders with the values of the file.
+// Placeholders are replaced after formatting, so that their values are not mistaken for parts of the layout.
+func expandPattern(pattern string, t time.Time, entries map[tiff.EntryID]tiff.Entry, path string) (string, error) {
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+
+	var sb strings.Builder
+	last := 0
+	for _, match := range placeholder.FindAllStringSubmatchIndex(pattern, -1) {
+		sb.WriteString(t.Format(pattern[last:match[0]]))
+		last = match[1]
+
+		var value string
+		switch name := pattern[match[2]:match[3]]; name {
+		case "make":
+			value = entries[tiff.Make].Text()
+		case "model":
+			value = entries[tiff.Model].Text()
+		case "name":
+			value = strings.TrimSuffix(base, ext)
+		case "ext":
+			value = strings.TrimPrefix(ext, ".")
+		default:
+			return "", fmt.Errorf("unknown placeholder {%s}", name)
+		}
+		if value == "" {
+			value = "unknown"
+		}
+		sb.WriteString(cleanName.Replace(value))
+	}
+	sb.WriteString(t.Format(pattern[last:]))
+
+	return filepath.Clean(sb.String()), nil
+}
+
+// checkDestination returns an error if a file other than the one at path exists at dst, which move would refuse to
+// overwrite.
+func checkDestination(path, dst string) error {
+	if filepath.Clean(path) == dst {
+		return nil
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		return fmt.Errorf("%s already exists", dst)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// move renames a file to dst, creating the directories it needs. It never overwrites an existing file, even one created
+// concurrently: the file is linked to dst, which fails if dst exists, then unlinked from path. Where hard links are not
+// supported (e.g. on FAT file systems, or across devices), it is copied to a file created exclusively instead.
+func move(path, dst string) error {
+	if filepath.Clean(path) == dst {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	err := os.Link(path, dst)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists", dst)
+	}
+	if err != nil {
+		if err := copyExclusive(path, dst); err != nil {
+			return err
+		}
+	}
+
+	return os.Remove(path)
+}
+
+// copyExclusive copies the file at path to dst, which must not exist, keeping its permissions and modification time as
+// renaming it would.
+func copyExclusive(path, dst string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists", dst)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	// the permissions given when creating the file are restricted by the umask
+	if err := os.Chmod(dst, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	return os.Chtimes(dst, time.Time{}, info.ModTime())
+}
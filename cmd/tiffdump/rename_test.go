@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/stretchr/testify/assert"
+)
+
+// inTempDir copies the given test images to a temporary directory and makes it the current directory.
+func inTempDir(t *testing.T, images ...string) {
+	dir := t.TempDir()
+	for _, image := range images {
+		data, err := os.ReadFile(filepath.Join("..", "..", "tiff", "testdata", image))
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, image), data, 0o644))
+	}
+
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+}
+
+func TestExpandPattern(t *testing.T) {
+	model := "EOS 5D/Mark IV"
+	entries := map[tiff.EntryID]tiff.Entry{
+		tiff.Model: {ID: tiff.Model, DataType: tiff.DataType_String, Value: tiff.EntryValue{String: &model}},
+	}
+	captured := time.Date(2021, 11, 19, 12, 21, 10, 0, time.UTC)
+
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{defaultRenamePattern, "2021/11/19/122110_EOS 5D_Mark IV.CR2"},
+		{"{name}-20060102.{ext}", "IMG_0001-20211119.CR2"},
+		{"{make}/Jan 2/{model}", "unknown/Nov 19/EOS 5D_Mark IV"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := expandPattern(tt.pattern, captured, entries, "photos/IMG_0001.CR2")
+			assert.NoError(t, err)
+			assert.Equal(t, filepath.FromSlash(tt.want), got)
+		})
+	}
+
+	_, err := expandPattern("{lens}", captured, entries, "IMG_0001.CR2")
+	assert.Error(t, err)
+}
+
+func TestRename(t *testing.T) {
+	inTempDir(t, "image.cr2", "image.orf")
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"rename", "-dry-run", "image.cr2", "image.orf"}, &stdout, &stderr))
+	assert.Equal(t, "image.cr2 -> 2021/11/19/122110_Canon EOS 7D.cr2\nimage.orf -> 2016/08/12/133254_E-M10MarkII.orf\n", stdout.String())
+	assert.FileExists(t, "image.cr2")
+	assert.NoFileExists(t, "2021/11/19/122110_Canon EOS 7D.cr2")
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"rename", "-pattern", "{model}.{ext}", "image.cr2", "image.orf"}, &stdout, &stderr))
+	assert.NoFileExists(t, "image.cr2")
+	assert.FileExists(t, "Canon EOS 7D.cr2")
+	assert.FileExists(t, "E-M10MarkII.orf")
+	assert.Empty(t, stderr.String())
+}
+
+func TestRename_Conflicts(t *testing.T) {
+	inTempDir(t, "image.cr2", "image.orf")
+	assert.NoError(t, os.WriteFile("existing.cr2", nil, 0o644))
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, run([]string{"rename", "-pattern", "existing.{ext}", "image.cr2"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "already exists")
+	assert.FileExists(t, "image.cr2")
+
+	stderr.Reset()
+	assert.Equal(t, 1, run([]string{"rename", "-dry-run", "-pattern", "existing.{ext}", "image.cr2"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "already exists")
+
+	stderr.Reset()
+	assert.Equal(t, 1, run([]string{"rename", "-dry-run", "-pattern", "2006", "image.cr2", "image.cr2"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "destination of another file")
+
+	stderr.Reset()
+	assert.Equal(t, 1, run([]string{"rename", "existing.cr2"}, &stdout, &stderr))
+	assert.FileExists(t, "existing.cr2")
+}
+
+func TestMove(t *testing.T) {
+	dir := t.TempDir()
+	src, dst := filepath.Join(dir, "a.cr2"), filepath.Join(dir, "b.cr2")
+	assert.NoError(t, os.WriteFile(src, []byte("raw"), 0o600))
+	assert.NoError(t, os.Chmod(src, 0o666)) // unlike os.WriteFile, not restricted by the umask
+	mtime := time.Date(2021, 11, 19, 12, 21, 10, 0, time.UTC)
+	assert.NoError(t, os.Chtimes(src, mtime, mtime))
+
+	assert.NoError(t, copyExclusive(src, dst))
+	info, err := os.Stat(dst)
+	assert.NoError(t, err)
+	assert.True(t, mtime.Equal(info.ModTime()))
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, os.FileMode(0o666), info.Mode().Perm())
+	}
+	assert.ErrorContains(t, copyExclusive(src, dst), "already exists")
+	assert.ErrorContains(t, move(src, dst), "already exists")
+	assert.FileExists(t, src)
+
+	assert.NoError(t, os.Remove(dst))
+	assert.NoError(t, move(src, dst))
+	assert.NoFileExists(t, src)
+	data, err := os.ReadFile(dst)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("raw"), data)
+}
+
+func TestRun_Usage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, run(nil, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"unknown"}, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"rename"}, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"rename", "-unknown", "image.cr2"}, &stdout, &stderr))
+	assert.Equal(t, 0, run([]string{"rename", "-h"}, &stdout, &stderr))
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fedragon/tiff-parser/writer"
+)
+
+func strip(args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("strip", "-o <output> <file>", stderr)
+	var opts writer.StripOptions
+	flags.BoolVar(&opts.GPS, "gps", false, "remove GPS data")
+	flags.BoolVar(&opts.MakerNotes, "makernotes", false, "remove maker notes")
+	flags.BoolVar(&opts.All, "all", false, "remove all metadata that is not needed to display the image")
+	anonymize := flags.Bool("anonymize", false, "remove GPS data, serial numbers, names, maker notes and unique IDs, and normalize Software")
+	output := flags.String("o", "", "output file (can be the input file)")
+	files, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(files) != 1 || *output == "" || !(opts.GPS || opts.MakerNotes || opts.All || *anonymize) {
+		fmt.Fprintln(stderr, "expected one file, an output file and at least one of -gps, -makernotes, -all, -anonymize")
+		flags.Usage()
+		return errUsage
+	}
+
+	// read the whole file first, as the output file may be the input file
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if *anonymize {
+		if err := writer.Anonymize(bytes.NewReader(data), &buf, writer.DefaultProfile()); err != nil {
+			return fmt.Errorf("%s: %w", files[0], err)
+		}
+		data = bytes.Clone(buf.Bytes())
+		buf.Reset()
+	}
+	if err := writer.Strip(bytes.NewReader(data), &buf, opts); err != nil {
+		return fmt.Errorf("%s: %w", files[0], err)
+	}
+	// the input file is only replaced once the output is complete, keeping its permissions and modification time
+	if err := writer.WriteFile(*output, buf.Bytes(), writer.FileOptions{}); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "%s -> %s\n", files[0], *output)
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrip(t *testing.T) {
+	inTempDir(t, "image.cr2")
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"strip", "-makernotes", "image.cr2", "-o", "out.cr2"}, &stdout, &stderr))
+	assert.Equal(t, "image.cr2 -> out.cr2\n", stdout.String())
+
+	parse := func(path string, ids ...tiff.EntryID) map[tiff.EntryID]tiff.Entry {
+		f, err := os.Open(path)
+		assert.NoError(t, err)
+		defer f.Close()
+		p, err := tiff.NewParser(f)
+		assert.NoError(t, err)
+		entries, err := p.Parse(ids...)
+		assert.NoError(t, err)
+		return entries
+	}
+	assert.Contains(t, parse("image.cr2", tiff.MakerNotes), tiff.MakerNotes)
+	assert.NotContains(t, parse("out.cr2", tiff.MakerNotes), tiff.MakerNotes)
+
+	assert.Equal(t, 0, run([]string{"strip", "-all", "-o", "image.cr2", "image.cr2"}, &stdout, &stderr))
+	assert.Empty(t, parse("image.cr2", tiff.Make))
+	assert.Empty(t, stderr.String())
+}
+
+func TestStrip_Usage(t *testing.T) {
+	inTempDir(t, "image.cr2")
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, run([]string{"strip", "-gps", "image.cr2"}, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"strip", "-o", "out.cr2", "image.cr2"}, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"strip", "-gps", "-o", "out.cr2", "image.cr2", "image.orf"}, &stdout, &stderr))
+	assert.Equal(t, 1, run([]string{"strip", "-gps", "-o", "out.cr2", "missing.cr2"}, &stdout, &stderr))
+	assert.NoFileExists(t, "out.cr2")
+}
+
+func TestStrip_Anonymize(t *testing.T) {
+	inTempDir(t, "image.cr2")
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"strip", "-anonymize", "image.cr2", "-o", "out.cr2"}, &stdout, &stderr))
+	assert.Empty(t, stderr.String())
+
+	data, err := os.ReadFile("out.cr2")
+	assert.NoError(t, err)
+	assert.False(t, bytes.Contains(data, []byte("Riccardo Zen")))
+	p, err := tiff.NewParser(bytes.NewReader(data))
+	assert.NoError(t, err)
+	entries, err := p.Parse(tiff.Make, tiff.MakerNotes)
+	assert.NoError(t, err)
+	assert.Contains(t, entries, tiff.Make)
+	assert.NotContains(t, entries, tiff.MakerNotes)
+}
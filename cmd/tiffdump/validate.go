@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// fileReport is the validation report of a file.
+type fileReport struct {
+	File  string `json:"file"`
+	Valid bool   `json:"valid"`
+	tiff.Report
+}
+
+func validate(args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("validate", "<files>", stderr)
+	format := flags.String("format", "text", "format of the report: text or json")
+	cog := flags.Bool("cog", false, "also check the layout of cloud-optimized GeoTIFFs")
+	files, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "unknown format %q\n", *format)
+		flags.Usage()
+		return errUsage
+	}
+
+	reports := make([]fileReport, len(files))
+	invalid := 0
+	for i, path := range files {
+		reports[i] = fileReport{File: path, Report: validateFile(path, *cog)}
+		reports[i].Valid = reports[i].Report.Valid()
+		if !reports[i].Valid {
+			invalid++
+		}
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reports); err != nil {
+			return err
+		}
+	} else {
+		for _, report := range reports {
+			status := "valid"
+			if !report.Valid {
+				status = "invalid"
+			}
+			fmt.Fprintf(stdout, "%s: %s\n", report.File, status)
+			for _, issue := range report.Issues {
+				fmt.Fprintf(stdout, "  %s\n", issue)
+			}
+		}
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d files are not valid", invalid, len(files))
+	}
+
+	return nil
+}
+
+// validateFile validates a file, as a cloud-optimized GeoTIFF if cog is true, reporting files that cannot be read or
+// are not TIFF files as an error.
+func validateFile(path string, cog bool) tiff.Report {
+	f, err := os.Open(path)
+	if err != nil {
+		return tiff.Report{Issues: []tiff.Issue{{Severity: tiff.Severity_Error, Message: err.Error()}}}
+	}
+	defer f.Close()
+
+	p, err := tiff.NewParser(f)
+	if err != nil {
+		return tiff.Report{Issues: []tiff.Issue{{Severity: tiff.Severity_Error, Message: err.Error()}}}
+	}
+
+	if cog {
+		return p.ValidateCOG()
+	}
+
+	return p.Validate()
+}
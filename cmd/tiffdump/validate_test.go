@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	inTempDir(t, "image.cr2", "image.orf")
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"validate", "image.cr2", "image.orf"}, &stdout, &stderr))
+	assert.Equal(t, strings.Join([]string{
+		"image.cr2: valid",
+		"  warning at offset 16 (entry 0x0102): IFD#0: BitsPerSample holds 3 values, but SamplesPerPixel is 1",
+		"  warning at offset 48966 (entry 0x0100): IFD#3 has image data but no ImageWidth",
+		"  warning at offset 48966 (entry 0x0101): IFD#3 has image data but no ImageHeight",
+		"image.orf: valid",
+		"",
+	}, "\n"), stdout.String())
+
+	data, err := os.ReadFile("image.cr2")
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile("truncated.cr2", data[:200], 0o644))
+
+	stdout.Reset()
+	assert.Equal(t, 1, run([]string{"validate", "-format", "json", "image.cr2", "truncated.cr2", "missing.cr2"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "2 of 3 files are not valid")
+
+	var reports []fileReport
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &reports))
+	assert.Len(t, reports, 3)
+	assert.True(t, reports[0].Valid)
+	assert.False(t, reports[1].Valid)
+	assert.Equal(t, "truncated.cr2", reports[1].File)
+	assert.Contains(t, stdout.String(), `"severity": "error"`)
+	assert.Contains(t, stdout.String(), `"message": "IFD#0 is truncated: its 18 entries end after the end of the file"`)
+	assert.False(t, reports[2].Valid)
+
+	assert.Equal(t, 2, run([]string{"validate", "-format", "xml", "image.cr2"}, &stdout, &stderr))
+}
+
+func TestValidate_COG(t *testing.T) {
+	inTempDir(t, "image.cr2")
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, run([]string{"validate", "-cog", "image.cr2"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "image.cr2: invalid")
+	assert.Contains(t, stdout.String(), "IFD#0 is not tiled")
+}
@@ -0,0 +1,50 @@
+//go:build js && wasm
+
+// Command tiffwasm runs the parser in the browser, e.g. to show the metadata of an image before uploading it. Build it
+// with:
+//
+//	GOOS=js GOARCH=wasm go build -o tiff.wasm ./cmd/tiffwasm
+//
+// and load it with the wasm_exec.js shipped with Go (see $(go env GOROOT)/lib/wasm). It registers a global function
+// tiffMetadata(bytes), which takes the content of a file (or only its start, e.g. its first 256 KiB) as a Uint8Array
+// and returns its metadata as a JSON string like `exiftool -j -G` would, or an Error:
+//
+//	const data = new Uint8Array(await file.slice(0, 256 * 1024).arrayBuffer());
+//	const res = tiffMetadata(data);
+//	if (res instanceof Error) throw res;
+//	const metadata = JSON.parse(res);
+package main
+
+import (
+	"errors"
+	"syscall/js"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+func main() {
+	js.Global().Set("tiffMetadata", js.FuncOf(metadata))
+
+	// keeps the function available
+	select {}
+}
+
+func metadata(_ js.Value, args []js.Value) any {
+	if len(args) != 1 || !args[0].InstanceOf(js.Global().Get("Uint8Array")) {
+		return jsError("expected a Uint8Array")
+	}
+	data := make([]byte, args[0].Length())
+	js.CopyBytesToGo(data, args[0])
+
+	// the start of a file is often all a browser reads
+	res, err := tiff.ExifToolJSON(data)
+	if err != nil && !errors.Is(err, tiff.ErrTruncated) {
+		return jsError(err.Error())
+	}
+
+	return string(res)
+}
+
+func jsError(message string) js.Value {
+	return js.Global().Get("Error").New(message)
+}
@@ -0,0 +1,242 @@
+// Command server is an HTTP service returning the metadata of TIFF-based files (e.g. raw images) as JSON, keyed and
+// formatted as `exiftool -j -G` would:
+//
+//	POST /metadata            reads the file from the body of the request
+//	GET  /metadata?url=<url>  reads the file from an http(s) URL, fetching only the parts it needs with range requests
+//
+// Usage:
+//
+//	go run ./examples/server -addr :8080
+//	curl --data-binary @image.cr2 localhost:8080/metadata
+//	curl 'localhost:8080/metadata?url=https://example.com/image.cr2'
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+const (
+	maxUploadSize    = 64 << 20 // largest file accepted in the body of a request
+	maxValueSize     = 1 << 20  // largest value, thumbnail or strip the parser may read
+	maxEntriesPerIFD = 1000     // largest IFD the parser may read
+	blockSize        = 64 << 10 // size of the blocks fetched from URLs
+	maxBlocks        = 32       // number of blocks fetched from a URL at most
+	requestTimeout   = 30 * time.Second
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metadata", newServer())
+	srv := &http.Server{Addr: *addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Print(err)
+		}
+	}()
+
+	log.Printf("listening on %s", *addr)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
+
+type server struct {
+	client  *http.Client
+	ids     []tiff.EntryID
+	parsers sync.Pool
+}
+
+func newServer() *server {
+	// maker notes are vendor-specific blobs, and large ones at that (e.g. Olympus embeds its preview in them)
+	ids := slices.DeleteFunc(slices.Sorted(maps.Keys(tiff.Defaults)), func(id tiff.EntryID) bool {
+		return id == tiff.MakerNotes
+	})
+
+	return &server{
+		client: &http.Client{Timeout: requestTimeout},
+		ids:    ids,
+		parsers: sync.Pool{New: func() any {
+			// options are kept across Reset
+			return new(tiff.Parser).WithMaxValueSize(maxValueSize).WithMaxEntriesPerIFD(maxEntriesPerIFD)
+		}},
+	}
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// stops reading the file when the client goes away or the request takes too long
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	var file io.ReadSeeker
+	switch r.Method {
+	case http.MethodPost:
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxUploadSize))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, fmt.Sprintf("file exceeds %d bytes", tooLarge.Limit), http.StatusRequestEntityTooLarge)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		file = bytes.NewReader(data)
+	case http.MethodGet:
+		u, err := url.Parse(r.URL.Query().Get("url"))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			http.Error(w, "expected an http(s) url parameter", http.StatusBadRequest)
+			return
+		}
+		if file, err = newRangeReader(ctx, s.client, u.String()); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	p := s.parsers.Get().(*tiff.Parser)
+	defer s.parsers.Put(p)
+	if err := p.Reset(file); err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	entries, err := p.Parse(s.ids...)
+	if ctx.Err() != nil {
+		http.Error(w, ctx.Err().Error(), http.StatusGatewayTimeout)
+		return
+	}
+	// truncated files still have metadata worth returning
+	if err != nil && !errors.Is(err, tiff.ErrTruncated) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(p.ExifTool(entries)); err != nil {
+		log.Print(err)
+	}
+}
+
+// rangeReader reads a remote file with HTTP range requests, one block at a time, so that parsing it only downloads the
+// parts of the file the parser reads (typically a few blocks, whatever the size of the file).
+type rangeReader struct {
+	ctx    context.Context
+	client *http.Client
+	url    string
+	size   int64
+	offset int64
+
+	block      []byte // last block fetched
+	blockStart int64  // offset of the last block fetched
+	fetched    int    // number of blocks fetched
+}
+
+// newRangeReader returns a reader of the file at the given URL, fetching its first block to learn its size. It returns
+// an error if the server does not support range requests.
+func newRangeReader(ctx context.Context, client *http.Client, url string) (*rangeReader, error) {
+	r := &rangeReader{ctx: ctx, client: client, url: url, size: blockSize}
+	if err := r.fetch(0); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *rangeReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	if r.block == nil || r.offset < r.blockStart || r.offset >= r.blockStart+int64(len(r.block)) {
+		if err := r.fetch(r.offset - r.offset%blockSize); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.block[r.offset-r.blockStart:])
+	r.offset += int64(n)
+
+	return n, nil
+}
+
+func (r *rangeReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	r.offset = offset
+
+	return offset, nil
+}
+
+// fetch fetches the block starting at the given offset.
+func (r *rangeReader) fetch(start int64) error {
+	if r.fetched == maxBlocks {
+		return fmt.Errorf("read more than %d blocks of %d bytes", maxBlocks, blockSize)
+	}
+	r.fetched++
+
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, min(start+blockSize, r.size)-1))
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("expected a partial content, got %s", resp.Status)
+	}
+
+	var first, last int64
+	if _, err := fmt.Sscanf(resp.Header.Get("Content-Range"), "bytes %d-%d/%d", &first, &last, &r.size); err != nil {
+		return fmt.Errorf("invalid Content-Range %q: %w", resp.Header.Get("Content-Range"), err)
+	}
+	if first != start {
+		return fmt.Errorf("expected a range starting at %d, got %d", start, first)
+	}
+	block, err := io.ReadAll(io.LimitReader(resp.Body, blockSize))
+	if err != nil {
+		return err
+	}
+	if len(block) == 0 {
+		return io.ErrUnexpectedEOF
+	}
+	r.block, r.blockStart = block, start
+
+	return nil
+}
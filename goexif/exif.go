@@ -0,0 +1,252 @@
+// Package goexif mirrors the API of github.com/rwcarlsen/goexif/exif on top of the tiff package, so that projects
+// using goexif can migrate by changing their imports, and read raw files (e.g. CR2, NEF, ORF, DNG) besides JPEG and
+// TIFF ones:
+//
+//	x, err := goexif.Decode(f)
+//	if err != nil {
+//		return err
+//	}
+//	tag, err := x.Get(goexif.Model)
+//	if err != nil {
+//		return err
+//	}
+//	model, err := tag.StringVal()
+//
+// Fields are read from IFD#0, the Exif and GPSInfo IFDs, and IFD#1 (thumbnail), as goexif does. Unlike goexif, Decode
+// does not read maker notes, and tags give access to the entries they were read from rather than to their raw bytes.
+package goexif
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// TagNotPresentError is returned when the file has no such field.
+type TagNotPresentError FieldName
+
+func (tag TagNotPresentError) Error() string {
+	return fmt.Sprintf("exif: tag %q is not present", string(tag))
+}
+
+// IsTagNotPresentError tells whether err is a TagNotPresentError.
+func IsTagNotPresentError(err error) bool {
+	var notPresent TagNotPresentError
+
+	return errors.As(err, &notPresent)
+}
+
+// Walker is called for each field of the file (see Exif.Walk).
+type Walker interface {
+	Walk(name FieldName, tag *Tag) error
+}
+
+// Exif holds the fields of a file.
+type Exif struct {
+	entries   map[field]tiff.Entry
+	thumbnail []byte
+}
+
+// Decode reads the fields of a TIFF-based file (e.g. a raw image) or of a JPEG file, from the Exif metadata of its
+// APP1 segment. Files are read from their start, and should be io.ReadSeekers (e.g. *os.File): other readers are read
+// to memory first. The thumbnail is read as well, so that r is no longer needed once Decode returns.
+func Decode(r io.Reader) (*Exif, error) {
+	rs, ok := r.(io.ReadSeeker)
+	if !ok {
+		br := bufio.NewReader(r)
+		if magic, err := br.Peek(2); err == nil && isJPEG(magic) {
+			// only reads the file up to its metadata
+			_, _ = br.Discard(2)
+			return decodeJPEG(br)
+		}
+		data, err := io.ReadAll(br)
+		if err != nil {
+			return nil, err
+		}
+		rs = bytes.NewReader(data)
+	}
+
+	magic := make([]byte, 2)
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(rs, magic); err != nil {
+		return nil, fmt.Errorf("cannot read header: %w", err)
+	}
+	if isJPEG(magic) {
+		return decodeJPEG(rs)
+	}
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return decode(rs)
+}
+
+func isJPEG(magic []byte) bool {
+	return magic[0] == 0xff && magic[1] == 0xd8
+}
+
+// decodeJPEG decodes the Exif metadata of a JPEG file, whose start of image marker has been read already.
+func decodeJPEG(r io.Reader) (*Exif, error) {
+	exif, err := readJPEGExif(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(bytes.NewReader(exif))
+}
+
+// decode decodes the fields of a TIFF file.
+func decode(rs io.ReadSeeker) (*Exif, error) {
+	p, err := tiff.NewParser(rs)
+	if err != nil {
+		return nil, err
+	}
+	found, err := p.Find(func(tiff.Entry) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+
+	x := &Exif{entries: make(map[field]tiff.Entry)}
+	for _, e := range found {
+		key := field{id: e.ID, group: e.Group}
+		if _, ok := x.entries[key]; !ok {
+			x.entries[key] = e
+		}
+	}
+	if thumbnail, err := p.ReadThumbnail(); err == nil {
+		x.thumbnail = thumbnail
+	}
+
+	return x, nil
+}
+
+// Get returns the given field, or a TagNotPresentError if the file has no such field.
+func (x *Exif) Get(name FieldName) (*Tag, error) {
+	for _, f := range fields {
+		if f.name != name {
+			continue
+		}
+		if e, ok := x.entries[field{id: f.id, group: f.group}]; ok {
+			return newTag(e), nil
+		}
+	}
+
+	return nil, TagNotPresentError(name)
+}
+
+// Walk calls w for each field of the file, in the order goexif lists them, stopping at the first error.
+func (x *Exif) Walk(w Walker) error {
+	for _, f := range fields {
+		if e, ok := x.entries[field{id: f.id, group: f.group}]; ok {
+			if err := w.Walk(f.name, newTag(e)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// DateTime returns the time the image was taken, read from DateTimeOriginal or, if missing, from DateTime. Unlike
+// goexif, which returns local times, times are returned in the time zone told by OffsetTimeOriginal or OffsetTime, if
+// any, or in UTC (see tiff.CaptureTime).
+func (x *Exif) DateTime() (time.Time, error) {
+	entries := map[tiff.EntryID]tiff.Entry{}
+	for _, id := range []tiff.EntryID{tiff.DateTimeOriginal, tiff.OffsetTime, tiff.OffsetTimeOriginal} {
+		if e, ok := x.entries[field{id: id, group: tiff.Group_Exif}]; ok {
+			entries[id] = e
+		}
+	}
+	if _, ok := entries[tiff.DateTimeOriginal]; !ok {
+		e, ok := x.entries[field{id: tiff.DateTime, group: tiff.Group_IFD0}]
+		if !ok {
+			return time.Time{}, TagNotPresentError(DateTimeOriginal)
+		}
+		entries[tiff.DateTimeOriginal] = e
+	}
+
+	return tiff.CaptureTime(entries)
+}
+
+// LatLong returns the latitude and longitude of the place the image was taken, in degrees, negative south of the
+// equator and west of the prime meridian.
+func (x *Exif) LatLong() (lat, long float64, err error) {
+	if lat, err = x.coordinate(GPSLatitude, GPSLatitudeRef, "S"); err != nil {
+		return 0, 0, err
+	}
+	if long, err = x.coordinate(GPSLongitude, GPSLongitudeRef, "W"); err != nil {
+		return 0, 0, err
+	}
+
+	return lat, long, nil
+}
+
+// coordinate reads a coordinate given as degrees, minutes and seconds, negating it if its reference is negativeRef.
+func (x *Exif) coordinate(name, refName FieldName, negativeRef string) (float64, error) {
+	tag, err := x.Get(name)
+	if err != nil {
+		return 0, err
+	}
+	values, err := tag.Entry.Floats()
+	if err != nil {
+		return 0, err
+	}
+	if len(values) != 3 {
+		return 0, fmt.Errorf("%s has %d values, expected 3", name, len(values))
+	}
+	res := values[0] + values[1]/60 + values[2]/3600
+
+	ref, err := x.Get(refName)
+	if err != nil {
+		return 0, err
+	}
+	if s, _ := ref.StringVal(); strings.EqualFold(strings.TrimSpace(s), negativeRef) {
+		res = -res
+	}
+
+	return res, nil
+}
+
+// JpegThumbnail returns the thumbnail of the file (see tiff.Parser.ReadThumbnail).
+func (x *Exif) JpegThumbnail() ([]byte, error) {
+	if x.thumbnail == nil {
+		return nil, TagNotPresentError(ThumbJPEGInterchangeFormat)
+	}
+
+	return x.thumbnail, nil
+}
+
+// readJPEGExif returns the TIFF structure held by the APP1 segment of a JPEG file, reading its segments up to that one
+// (its start of image marker has been read already).
+func readJPEGExif(r io.Reader) ([]byte, error) {
+	header := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return nil, fmt.Errorf("cannot read segment: %w", err)
+		}
+		if header[0] != 0xff {
+			return nil, errors.New("invalid JPEG marker")
+		}
+		marker, length := header[1], int(binary.BigEndian.Uint16(header[2:]))
+		if marker == 0xda || marker == 0xd9 || length < 2 {
+			// start of scan or end of image: no Exif metadata
+			return nil, errors.New("no Exif metadata")
+		}
+		payload := make([]byte, length-2)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return nil, fmt.Errorf("cannot read segment: %w", err)
+		}
+		if marker == 0xe1 && bytes.HasPrefix(payload, []byte("Exif\x00\x00")) {
+			return payload[6:], nil
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package goexif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// jpegFile wraps a TIFF structure in the APP1 segment of a JPEG file, after an APP0 segment.
+func jpegFile(tiff []byte) []byte {
+	data := []byte{0xff, 0xd8, 0xff, 0xe0, 0x00, 0x07, 'J', 'F', 'I', 'F', 0x00}
+	data = append(data, 0xff, 0xe1)
+	data = binary.BigEndian.AppendUint16(data, uint16(2+6+len(tiff)))
+	data = append(data, "Exif\x00\x00"...)
+	data = append(data, tiff...)
+
+	return append(data, 0xff, 0xda, 0x00, 0x02, 0xff, 0xd9)
+}
+
+func testFile() []byte {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	ifd0 := b.AddIFD().
+		WithString(0x010f, "Canon").
+		WithString(0x0110, "Canon EOS 7D").
+		WithString(0x0132, "2020:01:02 03:04:05")
+	ifd0.WithSubIFD(0x8769).
+		WithString(0x9003, "2019:06:07 08:09:10").
+		WithUints16(0x8827, 200).
+		WithURationals(0x829a, 1, 40).
+		WithRationals(0x9204, -1, 3)
+	ifd0.WithSubIFD(0x8825).
+		WithString(0x0001, "S").
+		WithURationals(0x0002, 33, 1, 51, 1, 36, 1).
+		WithString(0x0003, "E").
+		WithURationals(0x0004, 151, 1, 12, 1, 36, 1)
+
+	return b.Bytes()
+}
+
+func TestDecode(t *testing.T) {
+	tiffFile := testFile()
+	tests := []struct {
+		name string
+		r    io.Reader
+	}{
+		{"TIFF", bytes.NewReader(tiffFile)},
+		{"TIFF, not seekable", io.MultiReader(bytes.NewReader(tiffFile))},
+		{"JPEG", bytes.NewReader(jpegFile(tiffFile))},
+		{"JPEG, not seekable", io.MultiReader(bytes.NewReader(jpegFile(tiffFile)))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, err := Decode(tt.r)
+			assert.NoError(t, err)
+
+			tag, err := x.Get(Model)
+			assert.NoError(t, err)
+			model, err := tag.StringVal()
+			assert.NoError(t, err)
+			assert.Equal(t, "Canon EOS 7D", model)
+
+			tag, err = x.Get(ISOSpeedRatings)
+			assert.NoError(t, err)
+			iso, err := tag.Int(0)
+			assert.NoError(t, err)
+			assert.Equal(t, 200, iso)
+
+			tm, err := x.DateTime()
+			assert.NoError(t, err)
+			assert.Equal(t, time.Date(2019, 6, 7, 8, 9, 10, 0, time.UTC), tm)
+
+			lat, long, err := x.LatLong()
+			assert.NoError(t, err)
+			assert.InDelta(t, -33.86, lat, 0.001)
+			assert.InDelta(t, 151.21, long, 0.001)
+
+			_, err = x.Get(LensModel)
+			assert.True(t, IsTagNotPresentError(err))
+			assert.EqualError(t, err, `exif: tag "LensModel" is not present`)
+		})
+	}
+}
+
+func TestDecode_NoExif(t *testing.T) {
+	_, err := Decode(bytes.NewReader([]byte{0xff, 0xd8, 0xff, 0xda, 0x00, 0x02, 0xff, 0xd9}))
+	assert.Error(t, err)
+
+	_, err = Decode(bytes.NewReader([]byte("not an image")))
+	assert.Error(t, err)
+}
+
+func TestTag(t *testing.T) {
+	x, err := Decode(bytes.NewReader(testFile()))
+	assert.NoError(t, err)
+
+	exposure, err := x.Get(ExposureTime)
+	assert.NoError(t, err)
+	assert.Equal(t, RatVal, exposure.Format())
+	rat, err := exposure.Rat(0)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(1, 40), rat)
+	assert.Equal(t, `"1/40"`, exposure.String())
+	_, err = exposure.Int(0)
+	assert.EqualError(t, err, "tag 0x829a holds rational values, not int")
+	_, err = exposure.Rat(1)
+	assert.Error(t, err)
+
+	bias, err := x.Get(ExposureBiasValue)
+	assert.NoError(t, err)
+	num, den, err := bias.Rat2(0)
+	assert.NoError(t, err)
+	assert.Equal(t, [2]int64{-1, 3}, [2]int64{num, den})
+
+	latitude, err := x.Get(GPSLatitude)
+	assert.NoError(t, err)
+	assert.Equal(t, `["33/1","51/1","36/1"]`, latitude.String())
+
+	iso, err := x.Get(ISOSpeedRatings)
+	assert.NoError(t, err)
+	assert.Equal(t, IntVal, iso.Format())
+	assert.Equal(t, "200", iso.String())
+	_, err = iso.StringVal()
+	assert.Error(t, err)
+
+	model, err := x.Get(Model)
+	assert.NoError(t, err)
+	assert.Equal(t, `"Canon EOS 7D"`, model.String())
+}
+
+type walker map[FieldName]string
+
+func (w walker) Walk(name FieldName, tag *Tag) error {
+	w[name] = tag.String()
+
+	return nil
+}
+
+func TestExif_Walk(t *testing.T) {
+	x, err := Decode(bytes.NewReader(testFile()))
+	assert.NoError(t, err)
+
+	w := walker{}
+	assert.NoError(t, x.Walk(w))
+	assert.Len(t, w, 13)
+	assert.Equal(t, `"Canon"`, w[Make])
+	assert.Equal(t, `"S"`, w[GPSLatitudeRef])
+}
+
+func TestDecode_CR2(t *testing.T) {
+	f, err := os.Open("../tiff/testdata/image.cr2")
+	assert.NoError(t, err)
+	defer f.Close()
+
+	x, err := Decode(f)
+	assert.NoError(t, err)
+
+	tag, err := x.Get(ImageWidth)
+	assert.NoError(t, err)
+	width, err := tag.Int(0)
+	assert.NoError(t, err)
+	assert.Equal(t, 5184, width)
+
+	tag, err = x.Get(ExposureTime)
+	assert.NoError(t, err)
+	assert.Equal(t, `"1/40"`, tag.String())
+
+	thumbnail, err := x.JpegThumbnail()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 0xd8}, thumbnail[:2])
+}
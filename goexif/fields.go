@@ -0,0 +1,265 @@
+package goexif
+
+import "github.com/fedragon/tiff-parser/tiff"
+
+// FieldName is the name of an Exif field, as used by github.com/rwcarlsen/goexif (e.g. "ISOSpeedRatings" for the entry
+// exiftool names ISO).
+type FieldName string
+
+// Fields of IFD#0
+const (
+	ImageWidth                FieldName = "ImageWidth"
+	ImageLength               FieldName = "ImageLength"
+	BitsPerSample             FieldName = "BitsPerSample"
+	Compression               FieldName = "Compression"
+	PhotometricInterpretation FieldName = "PhotometricInterpretation"
+	Orientation               FieldName = "Orientation"
+	SamplesPerPixel           FieldName = "SamplesPerPixel"
+	PlanarConfiguration       FieldName = "PlanarConfiguration"
+	YCbCrSubSampling          FieldName = "YCbCrSubSampling"
+	YCbCrPositioning          FieldName = "YCbCrPositioning"
+	XResolution               FieldName = "XResolution"
+	YResolution               FieldName = "YResolution"
+	ResolutionUnit            FieldName = "ResolutionUnit"
+	DateTime                  FieldName = "DateTime"
+	ImageDescription          FieldName = "ImageDescription"
+	Make                      FieldName = "Make"
+	Model                     FieldName = "Model"
+	Software                  FieldName = "Software"
+	Artist                    FieldName = "Artist"
+	Copyright                 FieldName = "Copyright"
+	ExifIFDPointer            FieldName = "ExifIFDPointer"
+	GPSInfoIFDPointer         FieldName = "GPSInfoIFDPointer"
+)
+
+// Fields of the Exif IFD
+const (
+	InteroperabilityIFDPointer FieldName = "InteroperabilityIFDPointer"
+	ExifVersion                FieldName = "ExifVersion"
+	FlashpixVersion            FieldName = "FlashpixVersion"
+	ColorSpace                 FieldName = "ColorSpace"
+	ComponentsConfiguration    FieldName = "ComponentsConfiguration"
+	CompressedBitsPerPixel     FieldName = "CompressedBitsPerPixel"
+	PixelXDimension            FieldName = "PixelXDimension"
+	PixelYDimension            FieldName = "PixelYDimension"
+	MakerNote                  FieldName = "MakerNote"
+	UserComment                FieldName = "UserComment"
+	RelatedSoundFile           FieldName = "RelatedSoundFile"
+	DateTimeOriginal           FieldName = "DateTimeOriginal"
+	DateTimeDigitized          FieldName = "DateTimeDigitized"
+	SubSecTime                 FieldName = "SubSecTime"
+	SubSecTimeOriginal         FieldName = "SubSecTimeOriginal"
+	SubSecTimeDigitized        FieldName = "SubSecTimeDigitized"
+	ImageUniqueID              FieldName = "ImageUniqueID"
+	ExposureTime               FieldName = "ExposureTime"
+	FNumber                    FieldName = "FNumber"
+	ExposureProgram            FieldName = "ExposureProgram"
+	SpectralSensitivity        FieldName = "SpectralSensitivity"
+	ISOSpeedRatings            FieldName = "ISOSpeedRatings"
+	OECF                       FieldName = "OECF"
+	ShutterSpeedValue          FieldName = "ShutterSpeedValue"
+	ApertureValue              FieldName = "ApertureValue"
+	BrightnessValue            FieldName = "BrightnessValue"
+	ExposureBiasValue          FieldName = "ExposureBiasValue"
+	MaxApertureValue           FieldName = "MaxApertureValue"
+	SubjectDistance            FieldName = "SubjectDistance"
+	MeteringMode               FieldName = "MeteringMode"
+	LightSource                FieldName = "LightSource"
+	Flash                      FieldName = "Flash"
+	FocalLength                FieldName = "FocalLength"
+	SubjectArea                FieldName = "SubjectArea"
+	FlashEnergy                FieldName = "FlashEnergy"
+	SpatialFrequencyResponse   FieldName = "SpatialFrequencyResponse"
+	FocalPlaneXResolution      FieldName = "FocalPlaneXResolution"
+	FocalPlaneYResolution      FieldName = "FocalPlaneYResolution"
+	FocalPlaneResolutionUnit   FieldName = "FocalPlaneResolutionUnit"
+	SubjectLocation            FieldName = "SubjectLocation"
+	ExposureIndex              FieldName = "ExposureIndex"
+	SensingMethod              FieldName = "SensingMethod"
+	FileSource                 FieldName = "FileSource"
+	SceneType                  FieldName = "SceneType"
+	CFAPattern                 FieldName = "CFAPattern"
+	CustomRendered             FieldName = "CustomRendered"
+	ExposureMode               FieldName = "ExposureMode"
+	WhiteBalance               FieldName = "WhiteBalance"
+	DigitalZoomRatio           FieldName = "DigitalZoomRatio"
+	FocalLengthIn35mmFilm      FieldName = "FocalLengthIn35mmFilm"
+	SceneCaptureType           FieldName = "SceneCaptureType"
+	GainControl                FieldName = "GainControl"
+	Contrast                   FieldName = "Contrast"
+	Saturation                 FieldName = "Saturation"
+	Sharpness                  FieldName = "Sharpness"
+	DeviceSettingDescription   FieldName = "DeviceSettingDescription"
+	SubjectDistanceRange       FieldName = "SubjectDistanceRange"
+	LensMake                   FieldName = "LensMake"
+	LensModel                  FieldName = "LensModel"
+)
+
+// Fields of the GPSInfo IFD
+const (
+	GPSVersionID        FieldName = "GPSVersionID"
+	GPSLatitudeRef      FieldName = "GPSLatitudeRef"
+	GPSLatitude         FieldName = "GPSLatitude"
+	GPSLongitudeRef     FieldName = "GPSLongitudeRef"
+	GPSLongitude        FieldName = "GPSLongitude"
+	GPSAltitudeRef      FieldName = "GPSAltitudeRef"
+	GPSAltitude         FieldName = "GPSAltitude"
+	GPSTimeStamp        FieldName = "GPSTimeStamp"
+	GPSSatelites        FieldName = "GPSSatelites" // sic, as goexif spells it
+	GPSStatus           FieldName = "GPSStatus"
+	GPSMeasureMode      FieldName = "GPSMeasureMode"
+	GPSDOP              FieldName = "GPSDOP"
+	GPSSpeedRef         FieldName = "GPSSpeedRef"
+	GPSSpeed            FieldName = "GPSSpeed"
+	GPSTrackRef         FieldName = "GPSTrackRef"
+	GPSTrack            FieldName = "GPSTrack"
+	GPSImgDirectionRef  FieldName = "GPSImgDirectionRef"
+	GPSImgDirection     FieldName = "GPSImgDirection"
+	GPSMapDatum         FieldName = "GPSMapDatum"
+	GPSDestLatitudeRef  FieldName = "GPSDestLatitudeRef"
+	GPSDestLatitude     FieldName = "GPSDestLatitude"
+	GPSDestLongitudeRef FieldName = "GPSDestLongitudeRef"
+	GPSDestLongitude    FieldName = "GPSDestLongitude"
+	GPSDestBearingRef   FieldName = "GPSDestBearingRef"
+	GPSDestBearing      FieldName = "GPSDestBearing"
+	GPSDestDistanceRef  FieldName = "GPSDestDistanceRef"
+	GPSDestDistance     FieldName = "GPSDestDistance"
+	GPSProcessingMethod FieldName = "GPSProcessingMethod"
+	GPSAreaInformation  FieldName = "GPSAreaInformation"
+	GPSDateStamp        FieldName = "GPSDateStamp"
+	GPSDifferential     FieldName = "GPSDifferential"
+)
+
+// Fields of IFD#1
+const (
+	ThumbJPEGInterchangeFormat       FieldName = "ThumbJPEGInterchangeFormat"
+	ThumbJPEGInterchangeFormatLength FieldName = "ThumbJPEGInterchangeFormatLength"
+)
+
+// field tells where the entry of a field is found.
+type field struct {
+	name  FieldName
+	id    tiff.EntryID
+	group tiff.Group
+}
+
+// fields lists the fields known to goexif, in the order Walk visits them.
+var fields = []field{
+	{ImageWidth, 0x0100, tiff.Group_IFD0},
+	{ImageLength, 0x0101, tiff.Group_IFD0},
+	{BitsPerSample, 0x0102, tiff.Group_IFD0},
+	{Compression, 0x0103, tiff.Group_IFD0},
+	{PhotometricInterpretation, 0x0106, tiff.Group_IFD0},
+	{Orientation, 0x0112, tiff.Group_IFD0},
+	{SamplesPerPixel, 0x0115, tiff.Group_IFD0},
+	{PlanarConfiguration, 0x011c, tiff.Group_IFD0},
+	{YCbCrSubSampling, 0x0212, tiff.Group_IFD0},
+	{YCbCrPositioning, 0x0213, tiff.Group_IFD0},
+	{XResolution, 0x011a, tiff.Group_IFD0},
+	{YResolution, 0x011b, tiff.Group_IFD0},
+	{ResolutionUnit, 0x0128, tiff.Group_IFD0},
+	{DateTime, 0x0132, tiff.Group_IFD0},
+	{ImageDescription, 0x010e, tiff.Group_IFD0},
+	{Make, 0x010f, tiff.Group_IFD0},
+	{Model, 0x0110, tiff.Group_IFD0},
+	{Software, 0x0131, tiff.Group_IFD0},
+	{Artist, 0x013b, tiff.Group_IFD0},
+	{Copyright, 0x8298, tiff.Group_IFD0},
+	{ExifIFDPointer, 0x8769, tiff.Group_IFD0},
+	{GPSInfoIFDPointer, 0x8825, tiff.Group_IFD0},
+
+	{InteroperabilityIFDPointer, 0xa005, tiff.Group_Exif},
+	{ExifVersion, 0x9000, tiff.Group_Exif},
+	{FlashpixVersion, 0xa000, tiff.Group_Exif},
+	{ColorSpace, 0xa001, tiff.Group_Exif},
+	{ComponentsConfiguration, 0x9101, tiff.Group_Exif},
+	{CompressedBitsPerPixel, 0x9102, tiff.Group_Exif},
+	{PixelXDimension, 0xa002, tiff.Group_Exif},
+	{PixelYDimension, 0xa003, tiff.Group_Exif},
+	{MakerNote, 0x927c, tiff.Group_Exif},
+	{UserComment, 0x9286, tiff.Group_Exif},
+	{RelatedSoundFile, 0xa004, tiff.Group_Exif},
+	{DateTimeOriginal, 0x9003, tiff.Group_Exif},
+	{DateTimeDigitized, 0x9004, tiff.Group_Exif},
+	{SubSecTime, 0x9290, tiff.Group_Exif},
+	{SubSecTimeOriginal, 0x9291, tiff.Group_Exif},
+	{SubSecTimeDigitized, 0x9292, tiff.Group_Exif},
+	{ImageUniqueID, 0xa420, tiff.Group_Exif},
+	{ExposureTime, 0x829a, tiff.Group_Exif},
+	{FNumber, 0x829d, tiff.Group_Exif},
+	{ExposureProgram, 0x8822, tiff.Group_Exif},
+	{SpectralSensitivity, 0x8824, tiff.Group_Exif},
+	{ISOSpeedRatings, 0x8827, tiff.Group_Exif},
+	{OECF, 0x8828, tiff.Group_Exif},
+	{ShutterSpeedValue, 0x9201, tiff.Group_Exif},
+	{ApertureValue, 0x9202, tiff.Group_Exif},
+	{BrightnessValue, 0x9203, tiff.Group_Exif},
+	{ExposureBiasValue, 0x9204, tiff.Group_Exif},
+	{MaxApertureValue, 0x9205, tiff.Group_Exif},
+	{SubjectDistance, 0x9206, tiff.Group_Exif},
+	{MeteringMode, 0x9207, tiff.Group_Exif},
+	{LightSource, 0x9208, tiff.Group_Exif},
+	{Flash, 0x9209, tiff.Group_Exif},
+	{FocalLength, 0x920a, tiff.Group_Exif},
+	{SubjectArea, 0x9214, tiff.Group_Exif},
+	{FlashEnergy, 0xa20b, tiff.Group_Exif},
+	{SpatialFrequencyResponse, 0xa20c, tiff.Group_Exif},
+	{FocalPlaneXResolution, 0xa20e, tiff.Group_Exif},
+	{FocalPlaneYResolution, 0xa20f, tiff.Group_Exif},
+	{FocalPlaneResolutionUnit, 0xa210, tiff.Group_Exif},
+	{SubjectLocation, 0xa214, tiff.Group_Exif},
+	{ExposureIndex, 0xa215, tiff.Group_Exif},
+	{SensingMethod, 0xa217, tiff.Group_Exif},
+	{FileSource, 0xa300, tiff.Group_Exif},
+	{SceneType, 0xa301, tiff.Group_Exif},
+	{CFAPattern, 0xa302, tiff.Group_Exif},
+	{CustomRendered, 0xa401, tiff.Group_Exif},
+	{ExposureMode, 0xa402, tiff.Group_Exif},
+	{WhiteBalance, 0xa403, tiff.Group_Exif},
+	{DigitalZoomRatio, 0xa404, tiff.Group_Exif},
+	{FocalLengthIn35mmFilm, 0xa405, tiff.Group_Exif},
+	{SceneCaptureType, 0xa406, tiff.Group_Exif},
+	{GainControl, 0xa407, tiff.Group_Exif},
+	{Contrast, 0xa408, tiff.Group_Exif},
+	{Saturation, 0xa409, tiff.Group_Exif},
+	{Sharpness, 0xa40a, tiff.Group_Exif},
+	{DeviceSettingDescription, 0xa40b, tiff.Group_Exif},
+	{SubjectDistanceRange, 0xa40c, tiff.Group_Exif},
+	{LensMake, 0xa433, tiff.Group_Exif},
+	{LensModel, 0xa434, tiff.Group_Exif},
+
+	{GPSVersionID, 0x0000, tiff.Group_GPSInfo},
+	{GPSLatitudeRef, 0x0001, tiff.Group_GPSInfo},
+	{GPSLatitude, 0x0002, tiff.Group_GPSInfo},
+	{GPSLongitudeRef, 0x0003, tiff.Group_GPSInfo},
+	{GPSLongitude, 0x0004, tiff.Group_GPSInfo},
+	{GPSAltitudeRef, 0x0005, tiff.Group_GPSInfo},
+	{GPSAltitude, 0x0006, tiff.Group_GPSInfo},
+	{GPSTimeStamp, 0x0007, tiff.Group_GPSInfo},
+	{GPSSatelites, 0x0008, tiff.Group_GPSInfo},
+	{GPSStatus, 0x0009, tiff.Group_GPSInfo},
+	{GPSMeasureMode, 0x000a, tiff.Group_GPSInfo},
+	{GPSDOP, 0x000b, tiff.Group_GPSInfo},
+	{GPSSpeedRef, 0x000c, tiff.Group_GPSInfo},
+	{GPSSpeed, 0x000d, tiff.Group_GPSInfo},
+	{GPSTrackRef, 0x000e, tiff.Group_GPSInfo},
+	{GPSTrack, 0x000f, tiff.Group_GPSInfo},
+	{GPSImgDirectionRef, 0x0010, tiff.Group_GPSInfo},
+	{GPSImgDirection, 0x0011, tiff.Group_GPSInfo},
+	{GPSMapDatum, 0x0012, tiff.Group_GPSInfo},
+	{GPSDestLatitudeRef, 0x0013, tiff.Group_GPSInfo},
+	{GPSDestLatitude, 0x0014, tiff.Group_GPSInfo},
+	{GPSDestLongitudeRef, 0x0015, tiff.Group_GPSInfo},
+	{GPSDestLongitude, 0x0016, tiff.Group_GPSInfo},
+	{GPSDestBearingRef, 0x0017, tiff.Group_GPSInfo},
+	{GPSDestBearing, 0x0018, tiff.Group_GPSInfo},
+	{GPSDestDistanceRef, 0x0019, tiff.Group_GPSInfo},
+	{GPSDestDistance, 0x001a, tiff.Group_GPSInfo},
+	{GPSProcessingMethod, 0x001b, tiff.Group_GPSInfo},
+	{GPSAreaInformation, 0x001c, tiff.Group_GPSInfo},
+	{GPSDateStamp, 0x001d, tiff.Group_GPSInfo},
+	{GPSDifferential, 0x001e, tiff.Group_GPSInfo},
+
+	{ThumbJPEGInterchangeFormat, 0x0201, tiff.Group_IFD1},
+	{ThumbJPEGInterchangeFormatLength, 0x0202, tiff.Group_IFD1},
+}
@@ -0,0 +1,205 @@
+package goexif
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// Format tells how the values of a tag are read, as in goexif.
+type Format int
+
+const (
+	IntVal Format = iota
+	FloatVal
+	RatVal
+	StringVal
+	UndefVal
+	OtherVal
+)
+
+func (f Format) String() string {
+	switch f {
+	case IntVal:
+		return "int"
+	case FloatVal:
+		return "float"
+	case RatVal:
+		return "rational"
+	case StringVal:
+		return "string"
+	case UndefVal:
+		return "undefined"
+	default:
+		return "other"
+	}
+}
+
+// Tag is a field of the file, with the accessors of goexif's tiff.Tag. Entry holds the entry it was read from, to ease
+// migrating to the tiff package.
+type Tag struct {
+	Id    uint16 // named as in goexif
+	Type  tiff.DataType
+	Count uint32
+	Entry tiff.Entry
+}
+
+func newTag(e tiff.Entry) *Tag {
+	return &Tag{Id: uint16(e.ID), Type: e.DataType, Count: e.Length, Entry: e}
+}
+
+// Format returns how the values of the tag are read, given its data type.
+func (t *Tag) Format() Format {
+	switch t.Type {
+	case tiff.DataType_UByte, tiff.DataType_UShort, tiff.DataType_ULong, tiff.DataType_Byte, tiff.DataType_Short,
+		tiff.DataType_Long:
+		return IntVal
+	case tiff.DataType_URational, tiff.DataType_Rational:
+		return RatVal
+	case tiff.DataType_Float, tiff.DataType_Double:
+		return FloatVal
+	case tiff.DataType_String, tiff.DataType_UTF8:
+		return StringVal
+	case tiff.DataType_UByte_Sequence:
+		return UndefVal
+	default:
+		return OtherVal
+	}
+}
+
+// Int64 returns the i-th value of an integer tag.
+func (t *Tag) Int64(i int) (int64, error) {
+	if err := t.check(IntVal); err != nil {
+		return 0, err
+	}
+	values, err := t.Entry.Int64s()
+	if err != nil {
+		return 0, err
+	}
+	if i < 0 || i >= len(values) {
+		return 0, t.indexError(i, len(values))
+	}
+
+	return values[i], nil
+}
+
+// Int returns the i-th value of an integer tag.
+func (t *Tag) Int(i int) (int, error) {
+	v, err := t.Int64(i)
+
+	return int(v), err
+}
+
+// Rat2 returns the numerator and denominator of the i-th value of a rational tag.
+func (t *Tag) Rat2(i int) (num, den int64, err error) {
+	if err := t.check(RatVal); err != nil {
+		return 0, 0, err
+	}
+
+	v := t.Entry.Value
+	switch {
+	case v.URational != nil && i == 0:
+		return int64(v.URational.Numerator), int64(v.URational.Denominator), nil
+	case v.Rational != nil && i == 0:
+		return int64(v.Rational.Numerator), int64(v.Rational.Denominator), nil
+	case i >= 0 && i < len(v.URationals):
+		return int64(v.URationals[i].Numerator), int64(v.URationals[i].Denominator), nil
+	case i >= 0 && i < len(v.Rationals):
+		return int64(v.Rationals[i].Numerator), int64(v.Rationals[i].Denominator), nil
+	default:
+		return 0, 0, t.indexError(i, int(t.Count))
+	}
+}
+
+// Rat returns the i-th value of a rational tag. It returns an error if its denominator is zero.
+func (t *Tag) Rat(i int) (*big.Rat, error) {
+	num, den, err := t.Rat2(i)
+	if err != nil {
+		return nil, err
+	}
+	if den == 0 {
+		return nil, fmt.Errorf("tag 0x%04x has a zero denominator", t.Id)
+	}
+
+	return big.NewRat(num, den), nil
+}
+
+// Float returns the i-th value of a floating point tag.
+func (t *Tag) Float(i int) (float64, error) {
+	if err := t.check(FloatVal); err != nil {
+		return 0, err
+	}
+	values, err := t.Entry.Floats()
+	if err != nil {
+		return 0, err
+	}
+	if i < 0 || i >= len(values) {
+		return 0, t.indexError(i, len(values))
+	}
+
+	return values[i], nil
+}
+
+// StringVal returns the value of a string tag, without its trailing NULs.
+func (t *Tag) StringVal() (string, error) {
+	if err := t.check(StringVal); err != nil {
+		return "", err
+	}
+	if t.Entry.Value.String == nil {
+		return "", nil
+	}
+
+	return strings.TrimRight(*t.Entry.Value.String, "\x00"), nil
+}
+
+// String returns the value(s) of the tag, formatted as goexif does: strings are quoted, rationals are quoted fractions
+// (e.g. "1/40") and several values are listed between brackets.
+func (t *Tag) String() string {
+	var values []string
+	switch t.Format() {
+	case StringVal:
+		s, _ := t.StringVal()
+		return fmt.Sprintf("%q", s)
+	case RatVal:
+		for i := 0; i < int(t.Count); i++ {
+			num, den, err := t.Rat2(i)
+			if err != nil {
+				break
+			}
+			values = append(values, fmt.Sprintf(`"%d/%d"`, num, den))
+		}
+	case IntVal:
+		ints, _ := t.Entry.Int64s()
+		for _, v := range ints {
+			values = append(values, fmt.Sprint(v))
+		}
+	case FloatVal:
+		floats, _ := t.Entry.Floats()
+		for _, v := range floats {
+			values = append(values, fmt.Sprint(v))
+		}
+	default:
+		return fmt.Sprintf("%q", t.Entry.Value.Bytes)
+	}
+
+	if len(values) == 1 {
+		return values[0]
+	}
+
+	return "[" + strings.Join(values, ",") + "]"
+}
+
+// check returns an error if the tag is not of the given format.
+func (t *Tag) check(want Format) error {
+	if got := t.Format(); got != want {
+		return fmt.Errorf("tag 0x%04x holds %s values, not %s", t.Id, got, want)
+	}
+
+	return nil
+}
+
+func (t *Tag) indexError(i, n int) error {
+	return fmt.Errorf("tag 0x%04x has %d values, cannot read value %d", t.Id, n, i)
+}
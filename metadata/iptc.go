@@ -0,0 +1,97 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// IPTCDataSet identifies an IPTC-IIM dataset by its record and its number, as record<<8 | number (e.g. 0x0219 for
+// 2:25, Keywords).
+type IPTCDataSet uint16
+
+const (
+	IPTCDataSet_CodedCharacterSet IPTCDataSet = 0x015a // 1:90
+	IPTCDataSet_ObjectName        IPTCDataSet = 0x0205 // 2:05
+	IPTCDataSet_Keywords          IPTCDataSet = 0x0219 // 2:25
+	IPTCDataSet_Byline            IPTCDataSet = 0x0250 // 2:80
+	IPTCDataSet_CopyrightNotice   IPTCDataSet = 0x0274 // 2:116
+	IPTCDataSet_Caption           IPTCDataSet = 0x0278 // 2:120
+)
+
+const (
+	iptcTagMarker = 0x1c
+	iptcUTF8      = "\x1b%G" // value of CodedCharacterSet for UTF-8
+)
+
+// IPTC holds the datasets of the application record of IPTC-IIM data (e.g. held by the IPTC-NAA entry of TIFF
+// files), decoded as text, in the order they are found: repeatable datasets (e.g. Keywords) may have several values.
+type IPTC map[IPTCDataSet][]string
+
+// ParseIPTC parses IPTC-IIM data, keeping the datasets of the application record (2), except its binary RecordVersion.
+// Values are decoded as UTF-8 if CodedCharacterSet says so or if they are valid UTF-8, and as Latin-1 otherwise.
+// Trailing NUL bytes, which pad values of TIFF entries to their data type, are ignored.
+func ParseIPTC(data []byte) (IPTC, error) {
+	type dataset struct {
+		id    IPTCDataSet
+		value []byte
+	}
+	var datasets []dataset
+	isUTF8 := false
+	for len(bytes.TrimRight(data, "\x00")) > 0 {
+		if data[0] != iptcTagMarker {
+			return nil, fmt.Errorf("invalid IPTC tag marker 0x%02x", data[0])
+		}
+		if len(data) < 5 {
+			return nil, errors.New("truncated IPTC dataset")
+		}
+		id := IPTCDataSet(data[1])<<8 | IPTCDataSet(data[2])
+		size, header := int(binary.BigEndian.Uint16(data[3:])), 5
+		if size&0x8000 != 0 { // extended dataset: its size is held by the next size&0x7fff bytes
+			n := size & 0x7fff
+			if n > 4 || len(data) < header+n {
+				return nil, fmt.Errorf("invalid size of IPTC dataset %d:%d", id>>8, id&0xff)
+			}
+			size = 0
+			for _, b := range data[header : header+n] {
+				size = size<<8 | int(b)
+			}
+			header += n
+		}
+		if len(data) < header+size {
+			return nil, fmt.Errorf("truncated IPTC dataset %d:%d", id>>8, id&0xff)
+		}
+
+		value := data[header : header+size]
+		switch {
+		case id == IPTCDataSet_CodedCharacterSet:
+			isUTF8 = string(value) == iptcUTF8
+		case id>>8 == 2 && id&0xff != 0:
+			datasets = append(datasets, dataset{id, value})
+		}
+		data = data[header+size:]
+	}
+
+	iptc := make(IPTC)
+	for _, d := range datasets {
+		iptc[d.id] = append(iptc[d.id], decodeIPTC(d.value, isUTF8))
+	}
+
+	return iptc, nil
+}
+
+// decodeIPTC decodes the value of a dataset as UTF-8 if it is declared or valid as such, and as Latin-1 otherwise.
+func decodeIPTC(value []byte, isUTF8 bool) string {
+	if isUTF8 || utf8.Valid(value) {
+		return string(value)
+	}
+
+	runes := make([]rune, len(value))
+	for i, b := range value {
+		runes[i] = rune(b)
+	}
+
+	return string(runes)
+}
@@ -0,0 +1,74 @@
+package metadata
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// iptcDataSet encodes an IPTC-IIM dataset.
+func iptcDataSet(id IPTCDataSet, value string) []byte {
+	return append([]byte{iptcTagMarker, byte(id >> 8), byte(id), byte(len(value) >> 8), byte(len(value))}, value...)
+}
+
+func TestParseIPTC(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    IPTC
+		wantErr bool
+	}{
+		{
+			name: "datasets",
+			data: bytes.Join([][]byte{
+				iptcDataSet(0x0200, "\x00\x04"), // RecordVersion
+				iptcDataSet(IPTCDataSet_Keywords, "cat"),
+				iptcDataSet(IPTCDataSet_Caption, "A cat"),
+				iptcDataSet(IPTCDataSet_Keywords, "dog"),
+				{0, 0, 0}, // padding
+			}, nil),
+			want: IPTC{IPTCDataSet_Keywords: {"cat", "dog"}, IPTCDataSet_Caption: {"A cat"}},
+		},
+		{
+			name: "Latin-1",
+			data: iptcDataSet(IPTCDataSet_Keywords, "Z\xfcrich"),
+			want: IPTC{IPTCDataSet_Keywords: {"Zürich"}},
+		},
+		{
+			name: "UTF-8",
+			data: bytes.Join([][]byte{
+				iptcDataSet(IPTCDataSet_CodedCharacterSet, iptcUTF8),
+				iptcDataSet(IPTCDataSet_Keywords, "Zürich"),
+			}, nil),
+			want: IPTC{IPTCDataSet_Keywords: {"Zürich"}},
+		},
+		{
+			name: "extended dataset",
+			data: []byte{iptcTagMarker, 2, 120, 0x80, 2, 0, 3, 'c', 'a', 't'},
+			want: IPTC{IPTCDataSet_Caption: {"cat"}},
+		},
+		{
+			name:    "invalid marker",
+			data:    []byte{0x1d, 2, 25, 0, 0},
+			wantErr: true,
+		},
+		{
+			name:    "truncated",
+			data:    iptcDataSet(IPTCDataSet_Keywords, "cat")[:6],
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseIPTC(tt.data)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
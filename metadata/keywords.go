@@ -0,0 +1,41 @@
+package metadata
+
+import (
+	"strings"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// Keywords returns the keywords of an image gathered from all the places ecosystems store them, in this order: the XMP
+// properties dc:subject and lr:hierarchicalSubject (the leaf of each hierarchy, e.g. "Cats" for "Animals|Cats"), the
+// IPTC Keywords and the XPKeywords entry set by Windows. Keywords are trimmed and deduplicated ignoring case, keeping
+// the first spelling found. Any of the sources may be nil; XPKeywords is ignored if it cannot be decoded.
+func Keywords(entries map[tiff.EntryID]tiff.Entry, xmp XMP, iptc IPTC) []string {
+	var candidates []string
+	candidates = append(candidates, xmp["dc:subject"]...)
+	for _, hierarchy := range xmp["lr:hierarchicalSubject"] {
+		candidates = append(candidates, hierarchy[strings.LastIndexByte(hierarchy, '|')+1:])
+	}
+	candidates = append(candidates, iptc[IPTCDataSet_Keywords]...)
+	if xp, err := tiff.ReadXPKeywords(entries); err == nil {
+		candidates = append(candidates, xp...)
+	}
+
+	var keywords []string
+	seen := make(map[string]bool)
+	for _, keyword := range candidates {
+		keyword = strings.TrimSpace(keyword)
+		if key := strings.ToLower(keyword); keyword != "" && !seen[key] {
+			seen[key] = true
+			keywords = append(keywords, keyword)
+		}
+	}
+
+	return keywords
+}
+
+// Keywords returns the keywords of the file and of its sidecar (see Keywords), the XMP properties being merged
+// according to the policy.
+func (m *Metadata) Keywords() []string {
+	return Keywords(m.Entries, m.XMP(), m.IPTC)
+}
@@ -0,0 +1,54 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/stretchr/testify/assert"
+)
+
+// xpKeywords encodes keywords as Windows does in XPKeywords.
+func xpKeywords(s string) []byte {
+	var data []byte
+	for _, unit := range utf16.Encode([]rune(s + "\x00")) {
+		data = binary.LittleEndian.AppendUint16(data, unit)
+	}
+
+	return data
+}
+
+func TestKeywords(t *testing.T) {
+	xmp := XMP{"dc:subject": {"Cat", "dog"}, "lr:hierarchicalSubject": {"Animals|Cats|Cat", "Places|Zürich"}}
+	iptc := IPTC{IPTCDataSet_Keywords: {"cat", " bird "}}
+	entries := map[tiff.EntryID]tiff.Entry{
+		tiff.XPKeywords: {ID: tiff.XPKeywords, Value: tiff.EntryValue{Bytes: xpKeywords("Dog;fish")}},
+	}
+
+	assert.Equal(t, []string{"Cat", "dog", "Zürich", "bird", "fish"}, Keywords(entries, xmp, iptc))
+	assert.Equal(t, []string{"cat", "bird"}, Keywords(nil, nil, iptc))
+	assert.Empty(t, Keywords(nil, nil, nil))
+}
+
+func TestMetadata_Keywords(t *testing.T) {
+	iptc := append(iptcDataSet(IPTCDataSet_Keywords, "kitten"), 0) // written as is, padded to LONGs
+
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().
+		WithField(uint16(tiff.IPTC), test.TypeLong, uint32(len(iptc)/4), iptc).
+		WithBytes(uint16(tiff.XPKeywords), test.TypeByte, xpKeywords("dog")...)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "image.tif")
+	assert.NoError(t, os.WriteFile(path, b.Bytes(), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "image.xmp"), packet("", `
+    <dc:subject><rdf:Bag><rdf:li>bird</rdf:li></rdf:Bag></dc:subject>`), 0o644))
+
+	m, err := Read(path)
+	assert.NoError(t, err)
+	assert.Equal(t, IPTC{IPTCDataSet_Keywords: {"kitten"}}, m.IPTC)
+	assert.Equal(t, []string{"bird", "kitten", "dog"}, m.Keywords())
+}
@@ -0,0 +1,221 @@
+// Package metadata reads the metadata of raw files together with their XMP sidecars, building on the low-level parser
+// of package tiff: most raw-editing workflows (e.g. Lightroom, darktable) store ratings, keywords and edits in a sidecar
+// next to the raw file rather than rewriting it.
+package metadata
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// MergePolicy tells which of the sidecar and of the file wins when both hold a property.
+type MergePolicy int
+
+const (
+	MergePolicy_PreferSidecar  MergePolicy = iota // the sidecar overrides the file, as edits are written to the former
+	MergePolicy_PreferEmbedded                    // the file overrides the sidecar
+)
+
+// xmpEntries maps XMP properties to the entries of the file holding the same information, which Metadata.Get falls
+// back to when neither the sidecar nor the embedded packet has them.
+var xmpEntries = map[string]tiff.EntryID{
+	"xmp:Rating":     tiff.Rating,
+	"dc:creator":     tiff.Artist,
+	"dc:rights":      tiff.Copyright,
+	"dc:description": tiff.ImageDescription,
+}
+
+// Metadata is the metadata of a file: its entries, its embedded XMP packet (XMLPacket) and IPTC data (IPTC), and its
+// XMP sidecar, if any.
+type Metadata struct {
+	Path        string
+	Entries     map[tiff.EntryID]tiff.Entry // entries of IFD#0, of the Exif and of the GPSInfo IFDs (see tiff.Defaults)
+	Embedded    XMP                         // XMP packet of the file, if any
+	IPTC        IPTC                        // IPTC data of the file, if any
+	Sidecar     XMP                         // XMP sidecar of the file, if any
+	SidecarPath string                      // path of the sidecar, if any (see SidecarPaths)
+	Policy      MergePolicy
+}
+
+// Read reads the metadata of the file at path and of its XMP sidecar, if any, the latter overriding the former (see
+// ReadWithPolicy).
+func Read(path string) (*Metadata, error) {
+	return ReadWithPolicy(path, MergePolicy_PreferSidecar)
+}
+
+// ReadWithPolicy reads the metadata of the file at path and of its XMP sidecar, if any (see SidecarPaths), merging
+// them according to policy. Like tiff.Parser.Parse, if the end of the file is reached before all entries could be
+// read, it returns the metadata read so far together with an error matching tiff.ErrTruncated.
+func ReadWithPolicy(path string, policy MergePolicy) (*Metadata, error) {
+	entries, iptc, truncErr := readEntries(path)
+	if truncErr != nil && !errors.Is(truncErr, tiff.ErrTruncated) {
+		return nil, truncErr
+	}
+
+	m := &Metadata{Path: path, Entries: entries, Policy: policy}
+	if packet, ok := entries[tiff.XMLPacket]; ok {
+		data := packet.Value.Bytes
+		if packet.Value.String != nil {
+			data = []byte(*packet.Value.String)
+		}
+		embedded, err := ParseXMP(data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the XMLPacket entry: %w", err)
+		}
+		m.Embedded = embedded
+	}
+	if len(iptc) > 0 {
+		parsed, err := ParseIPTC(iptc)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the IPTC entry: %w", err)
+		}
+		m.IPTC = parsed
+	}
+
+	for _, candidate := range SidecarPaths(path) {
+		data, err := os.ReadFile(candidate)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sidecar, err := ParseXMP(data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the sidecar %s: %w", candidate, err)
+		}
+		m.Sidecar, m.SidecarPath = sidecar, candidate
+		break
+	}
+
+	return m, truncErr
+}
+
+// readEntries reads the entries of the file listed in tiff.Defaults, from the IFDs the file has among IFD#0, the Exif
+// and the GPSInfo IFDs, and its XMLPacket, returning the raw value of its IPTC entry too, which may be typed LONG.
+func readEntries(path string) (map[tiff.EntryID]tiff.Entry, []byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	p, err := tiff.NewParser(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	p.WithMapping(map[tiff.EntryID]tiff.Group{tiff.XMLPacket: tiff.Group_IFD0, tiff.IPTC: tiff.Group_IFD0})
+	groups := []tiff.Group{tiff.Group_IFD0}
+	for _, group := range []tiff.Group{tiff.Group_Exif, tiff.Group_GPSInfo} {
+		ok, err := p.HasGroup(group)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			groups = append(groups, group)
+		}
+	}
+
+	ids := []tiff.EntryID{tiff.XMLPacket, tiff.IPTC}
+	for id, group := range tiff.Defaults {
+		if slices.Contains(groups, group) {
+			ids = append(ids, id)
+		}
+	}
+
+	entries, err := p.Parse(ids...)
+	if err != nil {
+		return entries, nil, err
+	}
+	var iptc []byte
+	if entry, ok := entries[tiff.IPTC]; ok {
+		if iptc, err = p.ReadRawValue(entry); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return entries, iptc, nil
+}
+
+// SidecarPaths returns the paths where the XMP sidecar of the file at path may be, in the order they are looked up:
+// next to the file with .xmp appended to its name (e.g. "IMG_0001.CR2.xmp", as written by darktable), then with .xmp
+// replacing its extension (e.g. "IMG_0001.xmp", as written by Lightroom), each in lower and then in upper case.
+func SidecarPaths(path string) []string {
+	names := []string{path}
+	if ext := filepath.Ext(path); ext != "" {
+		names = append(names, strings.TrimSuffix(path, ext))
+	}
+
+	var paths []string
+	for _, name := range names {
+		for _, ext := range []string{".xmp", ".XMP"} {
+			paths = append(paths, name+ext)
+		}
+	}
+
+	return paths
+}
+
+// XMP returns the properties of the embedded packet and of the sidecar, merged according to the policy: when both set
+// a property, the values of the one preferred replace those of the other (e.g. all the keywords of dc:subject).
+func (m *Metadata) XMP() XMP {
+	first, second := m.Embedded, m.Sidecar
+	if m.Policy == MergePolicy_PreferEmbedded {
+		first, second = second, first
+	}
+
+	merged := make(XMP, len(first)+len(second))
+	maps.Copy(merged, first)
+	maps.Copy(merged, second)
+
+	return merged
+}
+
+// Get returns the first value of an XMP property (e.g. "xmp:Rating"), looked up according to the policy: in the
+// sidecar, then in the embedded packet and in the entries of the file holding the same information (e.g. Rating), or
+// the other way round.
+func (m *Metadata) Get(name string) (string, bool) {
+	embedded := func() (string, bool) {
+		if value, ok := m.Embedded.Get(name); ok {
+			return value, true
+		}
+		return m.entryValue(name)
+	}
+
+	if m.Policy == MergePolicy_PreferEmbedded {
+		if value, ok := embedded(); ok {
+			return value, true
+		}
+		return m.Sidecar.Get(name)
+	}
+	if value, ok := m.Sidecar.Get(name); ok {
+		return value, true
+	}
+
+	return embedded()
+}
+
+// entryValue returns the value of the entry of the file holding the same information as an XMP property, if any.
+func (m *Metadata) entryValue(name string) (string, bool) {
+	id, ok := xmpEntries[name]
+	if !ok {
+		return "", false
+	}
+	entry, ok := m.Entries[id]
+	if !ok {
+		return "", false
+	}
+	if value := strings.TrimSpace(entry.Text()); value != "" {
+		return value, true
+	}
+
+	return "", false
+}
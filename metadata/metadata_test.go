@@ -0,0 +1,125 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeFile writes to dir a TIFF file with the given Artist and Rating, and XMP packet if not empty.
+func writeFile(t *testing.T, dir, name, artist string, rating uint16, xmp []byte) string {
+	t.Helper()
+
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	ifd := b.AddIFD().
+		WithString(uint16(tiff.Artist), artist).
+		WithUints16(uint16(tiff.Rating), rating)
+	if len(xmp) > 0 {
+		ifd.WithBytes(uint16(tiff.XMLPacket), test.TypeByte, xmp...)
+	}
+	ifd.WithSubIFD(uint16(tiff.Exif)).WithString(uint16(tiff.DateTimeOriginal), "2021:11:19 12:21:10")
+
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(path, b.Bytes(), 0o644))
+
+	return path
+}
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "IMG_0001.CR2", "Jane Doe", 1, packet(`xmp:Rating="2" xmp:Label="Red"`, ""))
+	sidecar := filepath.Join(dir, "IMG_0001.xmp")
+	assert.NoError(t, os.WriteFile(sidecar, packet(`xmp:Rating="5"`, `
+    <dc:subject><rdf:Bag><rdf:li>cat</rdf:li></rdf:Bag></dc:subject>`), 0o644))
+
+	m, err := Read(path)
+	assert.NoError(t, err)
+	assert.Equal(t, sidecar, m.SidecarPath)
+	assert.Contains(t, m.Entries, tiff.DateTimeOriginal)
+	assert.Equal(t, XMP{"xmp:Rating": {"5"}, "xmp:Label": {"Red"}, "dc:subject": {"cat"}}, m.XMP())
+
+	tests := []struct {
+		name   string
+		policy MergePolicy
+		want   map[string]string
+	}{
+		{
+			name:   "prefer sidecar",
+			policy: MergePolicy_PreferSidecar,
+			want:   map[string]string{"xmp:Rating": "5", "xmp:Label": "Red", "dc:creator": "Jane Doe", "dc:subject": "cat"},
+		},
+		{
+			name:   "prefer embedded",
+			policy: MergePolicy_PreferEmbedded,
+			want:   map[string]string{"xmp:Rating": "2", "xmp:Label": "Red", "dc:creator": "Jane Doe", "dc:subject": "cat"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ReadWithPolicy(path, tt.policy)
+			assert.NoError(t, err)
+			for name, want := range tt.want {
+				got, ok := m.Get(name)
+				assert.True(t, ok, name)
+				assert.Equal(t, want, got, name)
+			}
+			_, ok := m.Get("dc:rights")
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestRead_Entries(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "image.tif", "Jane Doe", 3, nil)
+
+	m, err := ReadWithPolicy(path, MergePolicy_PreferEmbedded)
+	assert.NoError(t, err)
+	assert.Empty(t, m.SidecarPath)
+	assert.Empty(t, m.XMP())
+
+	rating, ok := m.Get("xmp:Rating")
+	assert.True(t, ok)
+	assert.Equal(t, "3", rating)
+
+	// the sidecar only wins over the entries of the file when it is preferred
+	assert.NoError(t, os.WriteFile(path+".xmp", packet(`xmp:Rating="4"`, ""), 0o644))
+	for policy, want := range map[MergePolicy]string{MergePolicy_PreferEmbedded: "3", MergePolicy_PreferSidecar: "4"} {
+		m, err := ReadWithPolicy(path, policy)
+		assert.NoError(t, err)
+		rating, _ := m.Get("xmp:Rating")
+		assert.Equal(t, want, rating)
+	}
+}
+
+func TestRead_InvalidSidecar(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "image.tif", "Jane Doe", 3, nil)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "image.xmp"), []byte("<x:xmpmeta"), 0o644))
+
+	_, err := Read(path)
+	assert.ErrorContains(t, err, "image.xmp")
+}
+
+func TestSidecarPaths(t *testing.T) {
+	assert.Equal(t, []string{"a/IMG.CR2.xmp", "a/IMG.CR2.XMP", "a/IMG.xmp", "a/IMG.XMP"}, SidecarPaths("a/IMG.CR2"))
+	assert.Equal(t, []string{"IMG.xmp", "IMG.XMP"}, SidecarPaths("IMG"))
+
+	// the sidecar named after the whole file name wins, whatever its case
+	dir := t.TempDir()
+	path := writeFile(t, dir, "IMG.CR2", "Jane Doe", 1, nil)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "IMG.xmp"), packet(`xmp:Rating="2"`, ""), 0o644))
+	sidecar := filepath.Join(dir, "IMG.CR2.XMP")
+	assert.NoError(t, os.WriteFile(sidecar, packet(`xmp:Rating="5"`, ""), 0o644))
+
+	m, err := Read(path)
+	assert.NoError(t, err)
+	assert.Equal(t, sidecar, m.SidecarPath)
+	assert.Equal(t, XMP{"xmp:Rating": {"5"}}, m.XMP())
+}
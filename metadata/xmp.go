@@ -0,0 +1,200 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	rdfNamespace = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+)
+
+// xmpPrefixes maps the URIs of well-known XMP namespaces to their usual prefix, so that properties are named the same
+// whatever prefix packets declare for them.
+var xmpPrefixes = map[string]string{
+	"http://purl.org/dc/elements/1.1/":             "dc",
+	"http://ns.adobe.com/xap/1.0/":                 "xmp",
+	"http://ns.adobe.com/xap/1.0/mm/":              "xmpMM",
+	"http://ns.adobe.com/xap/1.0/rights/":          "xmpRights",
+	"http://ns.adobe.com/photoshop/1.0/":           "photoshop",
+	"http://ns.adobe.com/tiff/1.0/":                "tiff",
+	"http://ns.adobe.com/exif/1.0/":                "exif",
+	"http://ns.adobe.com/exif/1.0/aux/":            "aux",
+	"http://cipa.jp/exif/1.0/":                     "exifEX",
+	"http://ns.adobe.com/camera-raw-settings/1.0/": "crs",
+	"http://ns.adobe.com/lightroom/1.0/":           "lr",
+	"http://iptc.org/std/Iptc4xmpCore/1.0/xmlns/":  "Iptc4xmpCore",
+	"http://iptc.org/std/Iptc4xmpExt/2008-02-29/":  "Iptc4xmpExt",
+	"http://www.digikam.org/ns/1.0/":               "digiKam",
+	"http://www.microsoft.com/photo/1.0/":          "MicrosoftPhoto",
+}
+
+// XMP holds the simple properties of an XMP packet (e.g. "xmp:Rating") and the items of its arrays (e.g.
+// "dc:subject"), keyed by their qualified name. Well-known namespaces are named after their usual prefix (e.g. "dc"),
+// and others after the prefix the packet declares. Structures (e.g. "xmpMM:History") are not read.
+type XMP map[string][]string
+
+// Get returns the first value of a property (e.g. the default language of "dc:title"), if it is set.
+func (x XMP) Get(name string) (string, bool) {
+	if values := x[name]; len(values) > 0 {
+		return values[0], true
+	}
+
+	return "", false
+}
+
+// ParseXMP parses an XMP packet, either embedded in a file (e.g. in its XMLPacket entry) or read from a sidecar file.
+func ParseXMP(data []byte) (XMP, error) {
+	x := make(XMP)
+	prefixes := make(map[string]string) // URI -> prefix declared by the packet
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			return x, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid XMP packet: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		declarePrefixes(prefixes, start)
+		if start.Name.Space == rdfNamespace && start.Name.Local == "Description" {
+			if err := x.readDescription(d, start, prefixes); err != nil {
+				return nil, fmt.Errorf("invalid XMP packet: %w", err)
+			}
+		}
+	}
+}
+
+// declarePrefixes records the namespaces an element declares.
+func declarePrefixes(prefixes map[string]string, start xml.StartElement) {
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" {
+			prefixes[attr.Value] = attr.Name.Local
+		}
+	}
+}
+
+// qualifiedName returns the name of a property, prefixed with the usual prefix of its namespace if it is well known,
+// or with the prefix the packet declares for it otherwise.
+func qualifiedName(prefixes map[string]string, name xml.Name) string {
+	prefix, ok := xmpPrefixes[name.Space]
+	if !ok {
+		if prefix, ok = prefixes[name.Space]; !ok {
+			prefix = name.Space
+		}
+	}
+
+	return prefix + ":" + name.Local
+}
+
+// readDescription reads the properties of an rdf:Description element: its attributes and its child elements.
+func (x XMP) readDescription(d *xml.Decoder, start xml.StartElement, prefixes map[string]string) error {
+	for _, attr := range start.Attr {
+		switch attr.Name.Space {
+		case "", "xmlns", rdfNamespace, xmlNamespace:
+		default:
+			x[qualifiedName(prefixes, attr.Name)] = []string{attr.Value}
+		}
+	}
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			declarePrefixes(prefixes, t)
+			values, err := readProperty(d, t)
+			if err != nil {
+				return err
+			}
+			if len(values) > 0 {
+				x[qualifiedName(prefixes, t.Name)] = values
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// readProperty reads the values of a property element: its text, its rdf:resource attribute, or the items of the
+// rdf:Seq, rdf:Bag or rdf:Alt array it holds. Structures are skipped.
+func readProperty(d *xml.Decoder, start xml.StartElement) ([]string, error) {
+	for _, attr := range start.Attr {
+		if attr.Name.Space == rdfNamespace && attr.Name.Local == "resource" {
+			return []string{attr.Value}, d.Skip()
+		}
+	}
+
+	var text strings.Builder
+	var items []string
+	isArray := false
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			text.Write(t)
+		case xml.StartElement:
+			switch {
+			case t.Name.Space == rdfNamespace && (t.Name.Local == "Seq" || t.Name.Local == "Bag" || t.Name.Local == "Alt"):
+				isArray = true // its items follow
+			case t.Name.Space == rdfNamespace && t.Name.Local == "li":
+				item, err := readText(d)
+				if err != nil {
+					return nil, err
+				}
+				if item != "" {
+					items = append(items, item)
+				}
+			default: // structure
+				if err := d.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		case xml.EndElement:
+			if t.Name == start.Name {
+				if isArray {
+					return items, nil
+				}
+				if value := strings.TrimSpace(text.String()); value != "" {
+					return []string{value}, nil
+				}
+				return nil, nil
+			}
+		}
+	}
+}
+
+// readText reads the text of an element up to its end, skipping its child elements.
+func readText(d *xml.Decoder) (string, error) {
+	var text strings.Builder
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			text.Write(t)
+		case xml.StartElement:
+			if err := d.Skip(); err != nil {
+				return "", err
+			}
+		case xml.EndElement:
+			return strings.TrimSpace(text.String()), nil
+		}
+	}
+}
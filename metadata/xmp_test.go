@@ -0,0 +1,80 @@
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// packet wraps the content of an rdf:Description in an XMP packet.
+func packet(attrs, content string) []byte {
+	return []byte(`<?xpacket begin="" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+ <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+  <rdf:Description rdf:about=""
+    xmlns:xmp="http://ns.adobe.com/xap/1.0/"
+    xmlns:dc="http://purl.org/dc/elements/1.1/"
+    xmlns:lightroom="http://ns.adobe.com/lightroom/1.0/"
+    xmlns:my="http://example.com/ns/"
+    ` + attrs + `>` + content + `
+  </rdf:Description>
+ </rdf:RDF>
+</x:xmpmeta>
+<?xpacket end="w"?>`)
+}
+
+func TestParseXMP(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    XMP
+		wantErr bool
+	}{
+		{
+			name: "attributes",
+			data: packet(`xmp:Rating="3" xmp:Label="Red"`, ""),
+			want: XMP{"xmp:Rating": {"3"}, "xmp:Label": {"Red"}},
+		},
+		{
+			name: "elements",
+			data: packet("", `<xmp:Rating>4</xmp:Rating><xmp:BaseURL rdf:resource="http://example.com/"/>`),
+			want: XMP{"xmp:Rating": {"4"}, "xmp:BaseURL": {"http://example.com/"}},
+		},
+		{
+			name: "arrays",
+			data: packet("", `
+    <dc:subject><rdf:Bag><rdf:li>cat</rdf:li><rdf:li>dog</rdf:li></rdf:Bag></dc:subject>
+    <dc:title><rdf:Alt><rdf:li xml:lang="x-default">Pets</rdf:li></rdf:Alt></dc:title>
+    <dc:creator><rdf:Seq><rdf:li>Jane Doe</rdf:li></rdf:Seq></dc:creator>`),
+			want: XMP{"dc:subject": {"cat", "dog"}, "dc:title": {"Pets"}, "dc:creator": {"Jane Doe"}},
+		},
+		{
+			name: "prefixes",
+			data: packet(`lightroom:hierarchicalSubject="Animals|Cats" my:Flag="1"`, ""),
+			want: XMP{"lr:hierarchicalSubject": {"Animals|Cats"}, "my:Flag": {"1"}},
+		},
+		{
+			name: "structures",
+			data: packet(`xmp:Rating="2"`, `
+    <my:History><rdf:Seq><rdf:li><rdf:Description my:Action="saved"/></rdf:li></rdf:Seq></my:History>`),
+			want: XMP{"xmp:Rating": {"2"}},
+		},
+		{
+			name:    "invalid",
+			data:    []byte(`<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF`),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseXMP(tt.data)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package test
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+)
+
+// TIFF data types, as defined by the TIFF 6.0 specification.
+const (
+	TypeByte      uint16 = 1
+	TypeASCII     uint16 = 2
+	TypeShort     uint16 = 3
+	TypeLong      uint16 = 4
+	TypeRational  uint16 = 5
+	TypeSByte     uint16 = 6
+	TypeUndefined uint16 = 7
+	TypeSShort    uint16 = 8
+	TypeSLong     uint16 = 9
+	TypeSRational uint16 = 10
+	TypeFloat     uint16 = 11
+	TypeDouble    uint16 = 12
+	TypeUTF8      uint16 = 129 // Exif 3.0
+)
+
+// ByteOrder can both put and append values (e.g. binary.LittleEndian, binary.BigEndian).
+type ByteOrder interface {
+	binary.ByteOrder
+	binary.AppendByteOrder
+}
+
+// TIFFBuilder builds synthetic TIFF files, for testing purposes.
+type TIFFBuilder struct {
+	byteOrder ByteOrder
+	ifds      []*IFDBuilder
+}
+
+// IFDBuilder builds an IFD of a synthetic TIFF file.
+type IFDBuilder struct {
+	byteOrder ByteOrder
+	fields    []field
+}
+
+type field struct {
+	id       uint16
+	dataType uint16
+	count    uint32
+	data     []byte
+	sub      *IFDBuilder // IFD the field points to, if any
+	blobs    [][]byte    // data areas the field points to, if any
+}
+
+func NewTIFFBuilder(byteOrder ByteOrder) *TIFFBuilder {
+	return &TIFFBuilder{byteOrder: byteOrder}
+}
+
+// AddIFD appends a new IFD to the main IFD chain and returns it.
+func (b *TIFFBuilder) AddIFD() *IFDBuilder {
+	ifd := &IFDBuilder{byteOrder: b.byteOrder}
+	b.ifds = append(b.ifds, ifd)
+
+	return ifd
+}
+
+// Bytes returns the content of the TIFF file: header first, then each IFD followed by its data areas and sub-IFDs.
+func (b *TIFFBuilder) Bytes() []byte {
+	buffer := make([]byte, 8)
+	if b.byteOrder.String() == binary.LittleEndian.String() {
+		copy(buffer, "II")
+	} else {
+		copy(buffer, "MM")
+	}
+	b.byteOrder.PutUint16(buffer[2:4], 42)
+
+	next := 4 // position of the pointer to the next IFD
+	for _, ifd := range b.ifds {
+		offset := ifd.write(&buffer)
+		b.byteOrder.PutUint32(buffer[next:], uint32(offset))
+		next = offset + 2 + len(ifd.fields)*12
+	}
+
+	return buffer
+}
+
+// WithField adds a field holding the given raw (already encoded) data.
+func (ifd *IFDBuilder) WithField(id, dataType uint16, count uint32, data []byte) *IFDBuilder {
+	ifd.fields = append(ifd.fields, field{id: id, dataType: dataType, count: count, data: data})
+
+	return ifd
+}
+
+func (ifd *IFDBuilder) WithString(id uint16, value string) *IFDBuilder {
+	data := append([]byte(value), 0)
+
+	return ifd.WithField(id, TypeASCII, uint32(len(data)), data)
+}
+
+// WithStrings adds an ASCII field holding several NUL-terminated strings.
+func (ifd *IFDBuilder) WithStrings(id uint16, values ...string) *IFDBuilder {
+	var data []byte
+	for _, value := range values {
+		data = append(append(data, value...), 0)
+	}
+
+	return ifd.WithField(id, TypeASCII, uint32(len(data)), data)
+}
+
+func (ifd *IFDBuilder) WithBytes(id uint16, dataType uint16, values ...byte) *IFDBuilder {
+	return ifd.WithField(id, dataType, uint32(len(values)), values)
+}
+
+func (ifd *IFDBuilder) WithUints16(id uint16, values ...uint16) *IFDBuilder {
+	data := make([]byte, 0, 2*len(values))
+	for _, value := range values {
+		data = ifd.byteOrder.AppendUint16(data, value)
+	}
+
+	return ifd.WithField(id, TypeShort, uint32(len(values)), data)
+}
+
+func (ifd *IFDBuilder) WithInts16(id uint16, values ...int16) *IFDBuilder {
+	data := make([]byte, 0, 2*len(values))
+	for _, value := range values {
+		data = ifd.byteOrder.AppendUint16(data, uint16(value))
+	}
+
+	return ifd.WithField(id, TypeSShort, uint32(len(values)), data)
+}
+
+func (ifd *IFDBuilder) WithUints32(id uint16, values ...uint32) *IFDBuilder {
+	return ifd.WithField(id, TypeLong, uint32(len(values)), ifd.uints32(values...))
+}
+
+func (ifd *IFDBuilder) WithInts32(id uint16, values ...int32) *IFDBuilder {
+	data := make([]byte, 0, 4*len(values))
+	for _, value := range values {
+		data = ifd.byteOrder.AppendUint32(data, uint32(value))
+	}
+
+	return ifd.WithField(id, TypeSLong, uint32(len(values)), data)
+}
+
+// WithURationals adds a field holding unsigned rationals, given as numerator, denominator pairs.
+func (ifd *IFDBuilder) WithURationals(id uint16, values ...uint32) *IFDBuilder {
+	return ifd.WithField(id, TypeRational, uint32(len(values)/2), ifd.uints32(values...))
+}
+
+// WithRationals adds a field holding signed rationals, given as numerator, denominator pairs.
+func (ifd *IFDBuilder) WithRationals(id uint16, values ...int32) *IFDBuilder {
+	data := make([]byte, 0, 4*len(values))
+	for _, value := range values {
+		data = ifd.byteOrder.AppendUint32(data, uint32(value))
+	}
+
+	return ifd.WithField(id, TypeSRational, uint32(len(values)/2), data)
+}
+
+func (ifd *IFDBuilder) WithFloats32(id uint16, values ...float32) *IFDBuilder {
+	data := make([]byte, 0, 4*len(values))
+	for _, value := range values {
+		data = ifd.byteOrder.AppendUint32(data, math.Float32bits(value))
+	}
+
+	return ifd.WithField(id, TypeFloat, uint32(len(values)), data)
+}
+
+func (ifd *IFDBuilder) WithFloats64(id uint16, values ...float64) *IFDBuilder {
+	data := make([]byte, 0, 8*len(values))
+	for _, value := range values {
+		data = ifd.byteOrder.AppendUint64(data, math.Float64bits(value))
+	}
+
+	return ifd.WithField(id, TypeDouble, uint32(len(values)), data)
+}
+
+// WithSubIFD adds a field pointing to a new IFD (e.g. Exif, GPSInfo) and returns the latter.
+func (ifd *IFDBuilder) WithSubIFD(id uint16) *IFDBuilder {
+	sub := &IFDBuilder{byteOrder: ifd.byteOrder}
+	ifd.fields = append(ifd.fields, field{id: id, dataType: TypeLong, count: 1, sub: sub})
+
+	return sub
+}
+
+// WithData adds a pair of fields pointing to data areas (e.g. StripOffsets and StripByteCounts): the first one holds the
+// offsets of the data areas, the second one their lengths.
+func (ifd *IFDBuilder) WithData(offsetsID, lengthsID uint16, blobs ...[]byte) *IFDBuilder {
+	lengths := make([]uint32, len(blobs))
+	for i, blob := range blobs {
+		lengths[i] = uint32(len(blob))
+	}
+	ifd.fields = append(ifd.fields, field{id: offsetsID, dataType: TypeLong, count: uint32(len(blobs)), blobs: blobs})
+
+	return ifd.WithUints32(lengthsID, lengths...)
+}
+
+func (ifd *IFDBuilder) uints32(values ...uint32) []byte {
+	data := make([]byte, 0, 4*len(values))
+	for _, value := range values {
+		data = ifd.byteOrder.AppendUint32(data, value)
+	}
+
+	return data
+}
+
+// write appends the IFD (and all data it points to) to the buffer, returning the offset of the IFD.
+func (ifd *IFDBuilder) write(buffer *[]byte) int {
+	align(buffer)
+	start := len(*buffer)
+
+	fields := make([]field, len(ifd.fields))
+	copy(fields, ifd.fields)
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].id < fields[j].id })
+
+	*buffer = append(*buffer, make([]byte, 2+len(fields)*12+4)...)
+	ifd.byteOrder.PutUint16((*buffer)[start:], uint16(len(fields)))
+
+	for i, f := range fields {
+		pos := start + 2 + i*12
+		ifd.byteOrder.PutUint16((*buffer)[pos:], f.id)
+		ifd.byteOrder.PutUint16((*buffer)[pos+2:], f.dataType)
+		ifd.byteOrder.PutUint32((*buffer)[pos+4:], f.count)
+
+		data := f.data
+		if f.sub != nil {
+			data = ifd.uints32(uint32(f.sub.write(buffer)))
+		} else if f.blobs != nil {
+			offsets := make([]uint32, len(f.blobs))
+			for j, blob := range f.blobs {
+				align(buffer)
+				offsets[j] = uint32(len(*buffer))
+				*buffer = append(*buffer, blob...)
+			}
+			data = ifd.uints32(offsets...)
+		}
+
+		if len(data) <= 4 {
+			copy((*buffer)[pos+8:pos+12], data)
+		} else {
+			align(buffer)
+			ifd.byteOrder.PutUint32((*buffer)[pos+8:], uint32(len(*buffer)))
+			*buffer = append(*buffer, data...)
+		}
+	}
+
+	return start
+}
+
+// align pads the buffer so that its length is a multiple of 2, as the TIFF specification requires values to begin on
+// word boundaries.
+func align(buffer *[]byte) {
+	if len(*buffer)%2 != 0 {
+		*buffer = append(*buffer, 0)
+	}
+}
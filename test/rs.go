@@ -3,17 +3,15 @@ package test
 import (
 	"encoding/binary"
 	"errors"
-	"fmt"
 	"io"
 )
 
-// BytesReadSeeker provides utilities to read and write on a byte buffer, for testing purposes.
+// BytesReadSeeker provides utilities to read and write on a byte buffer, for testing purposes. It behaves like a file
+// (or a bytes.Reader): reads start at the current offset and advance it, and return io.EOF at the end of the buffer.
 type BytesReadSeeker struct {
 	byteOrder binary.AppendByteOrder
 	buffer    []byte
 	offset    int64
-
-	io.ReadSeeker
 }
 
 func NewBytesReadSeeker() *BytesReadSeeker {
@@ -29,6 +27,15 @@ func (brs *BytesReadSeeker) WithString(value string) *BytesReadSeeker {
 	return brs
 }
 
+// WithStrings appends NUL-terminated strings, as stored by ASCII entries holding several strings.
+func (brs *BytesReadSeeker) WithStrings(values ...string) *BytesReadSeeker {
+	for _, value := range values {
+		brs.buffer = append(append(brs.buffer, value...), 0)
+	}
+
+	return brs
+}
+
 func (brs *BytesReadSeeker) WithUints16(values ...uint16) *BytesReadSeeker {
 	for _, value := range values {
 		brs.buffer = brs.byteOrder.AppendUint16(brs.buffer, value)
@@ -45,33 +52,66 @@ func (brs *BytesReadSeeker) WithUints32(values ...uint32) *BytesReadSeeker {
 	return brs
 }
 
+func (brs *BytesReadSeeker) WithInts16(values ...int16) *BytesReadSeeker {
+	for _, value := range values {
+		brs.buffer = brs.byteOrder.AppendUint16(brs.buffer, uint16(value))
+	}
+
+	return brs
+}
+
+func (brs *BytesReadSeeker) WithInts32(values ...int32) *BytesReadSeeker {
+	for _, value := range values {
+		brs.buffer = brs.byteOrder.AppendUint32(brs.buffer, uint32(value))
+	}
+
+	return brs
+}
+
+// WithURationals appends unsigned rationals, given as numerator, denominator pairs.
+func (brs *BytesReadSeeker) WithURationals(values ...uint32) *BytesReadSeeker {
+	return brs.WithUints32(values...)
+}
+
+// WithRationals appends signed rationals, given as numerator, denominator pairs.
+func (brs *BytesReadSeeker) WithRationals(values ...int32) *BytesReadSeeker {
+	return brs.WithInts32(values...)
+}
+
 func (brs *BytesReadSeeker) Read(p []byte) (int, error) {
 	if p == nil {
 		return 0, errors.New("destination cannot be nil")
 	}
 
-	if len(p) >= len(brs.buffer) {
-		n := copy(p, brs.buffer)
-		return n, nil
+	if brs.offset >= int64(len(brs.buffer)) {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
 	}
 
-	n := copy(p, brs.buffer[0:len(p)])
+	n := copy(p, brs.buffer[brs.offset:])
+	brs.offset += int64(n)
+
 	return n, nil
 }
 
 func (brs *BytesReadSeeker) Seek(offset int64, whence int) (int64, error) {
-	if whence != io.SeekStart {
-		return 0, errors.New("can only seek from start")
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += brs.offset
+	case io.SeekEnd:
+		offset += int64(len(brs.buffer))
+	default:
+		return 0, errors.New("invalid whence")
 	}
 
 	if offset < 0 {
 		return 0, errors.New("negative offset not allowed")
 	}
 
-	if offset >= int64(len(brs.buffer)) {
-		return 0, fmt.Errorf("offset %d exceeds buffer length %d", offset, len(brs.buffer))
-	}
-
+	// seeking past the end is allowed, as with files: the next read returns io.EOF
 	brs.offset = offset
 
 	return brs.offset, nil
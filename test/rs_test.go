@@ -0,0 +1,46 @@
+package test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytesReadSeeker(t *testing.T) {
+	brs := NewBytesReadSeeker().WithString("abcdef")
+	buffer := make([]byte, 4)
+
+	n, err := brs.Read(buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd", string(buffer[:n]))
+
+	// reads advance the offset
+	n, err = brs.Read(buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, "ef", string(buffer[:n]))
+
+	_, err = brs.Read(buffer)
+	assert.Equal(t, io.EOF, err)
+
+	offset, err := brs.Seek(-3, io.SeekEnd)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), offset)
+
+	offset, err = brs.Seek(-2, io.SeekCurrent)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), offset)
+
+	_, err = io.ReadFull(brs, buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, "bcde", string(buffer))
+
+	_, err = brs.Seek(-1, io.SeekStart)
+	assert.Error(t, err)
+
+	// seeking past the end is allowed
+	_, err = brs.Seek(10, io.SeekStart)
+	assert.NoError(t, err)
+	_, err = io.ReadFull(brs, buffer)
+	assert.Equal(t, io.EOF, err)
+}
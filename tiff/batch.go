@@ -0,0 +1,124 @@
+package tiff
+
+import (
+	"context"
+	"iter"
+	"os"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// Result is the outcome of parsing one file of a batch.
+type Result struct {
+	Entries map[EntryID]Entry // entries found, which may be partial if Err is set (see Parse)
+	Err     error             // error met opening or parsing the file
+}
+
+// ParseBatch opens and parses the files at the given paths concurrently, looking for the given entries, with at most
+// workers files open at once (runtime.NumCPU() if workers <= 0). Errors are isolated: the error met with a file is
+// returned in its Result, and does not stop the batch.
+//
+// When ctx is canceled, ParseBatch stops opening files and returns the results of the files parsed so far, together
+// with the error of ctx.
+func ParseBatch(ctx context.Context, paths []string, ids []EntryID, workers int) (map[string]Result, error) {
+	res := make(map[string]Result, len(paths))
+	for r := range parseBatch(ctx, slices.Values(paths), ids, workers) {
+		res[r.path] = r.Result
+	}
+
+	return res, ctx.Err()
+}
+
+// ParseStream is the streaming variant of ParseBatch, for collections too large to hold all results in memory: it
+// returns an iterator yielding the path and result of each file as soon as it is parsed, so that results can be
+// processed (e.g. written to a database) while other files are being parsed. Paths are pulled from paths as workers
+// become available, so they can be produced lazily too (e.g. by walking a directory, skipping the files processed by a
+// previous, interrupted scan).
+//
+// Breaking out of the loop stops the workers. When ctx is canceled, the iterator stops after yielding the results of
+// the files being parsed: callers tell an interrupted scan from a complete one by checking ctx.Err().
+func ParseStream(ctx context.Context, paths iter.Seq[string], ids []EntryID, workers int) iter.Seq2[string, Result] {
+	return func(yield func(string, Result) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		results := parseBatch(ctx, paths, ids, workers)
+		defer func() {
+			cancel()
+			for range results {
+				// wait for the workers to finish the files they are parsing
+			}
+		}()
+
+		for r := range results {
+			if !yield(r.path, r.Result) {
+				return
+			}
+		}
+	}
+}
+
+// pathResult is the result of parsing the file at path.
+type pathResult struct {
+	path string
+	Result
+}
+
+// parseBatch starts workers parsing the files at the given paths, returning a channel receiving the result of each
+// file as soon as it is parsed. Files are handed to workers one at a time, so that no more files are opened than the
+// consumer of the channel can keep up with. The channel is closed once all files are parsed, or once ctx is canceled
+// and the files being parsed are done.
+func parseBatch(ctx context.Context, paths iter.Seq[string], ids []EntryID, workers int) <-chan pathResult {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	jobs := make(chan string)
+	go func() {
+		defer close(jobs)
+		for path := range paths {
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case jobs <- path:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	results := make(chan pathResult)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				entries, err := parseFile(path, ids)
+				results <- pathResult{path: path, Result: Result{Entries: entries, Err: err}}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// parseFile opens and parses the file at the given path, looking for the given entries.
+func parseFile(path string, ids []EntryID) (map[EntryID]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	p, err := NewParser(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Parse(ids...)
+}
@@ -0,0 +1,63 @@
+package tiff
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBatch(t *testing.T) {
+	notTIFF := filepath.Join(t.TempDir(), "notes.txt")
+	assert.NoError(t, os.WriteFile(notTIFF, []byte("not a TIFF file"), 0o644))
+	missing := filepath.Join(t.TempDir(), "missing.cr2")
+	paths := []string{"testdata/image.cr2", "testdata/image.orf", notTIFF, missing}
+
+	results, err := ParseBatch(context.Background(), paths, []EntryID{Make, Model}, 2)
+	assert.NoError(t, err)
+	assert.Len(t, results, 4)
+
+	cr2 := results["testdata/image.cr2"]
+	assert.NoError(t, cr2.Err)
+	assert.Equal(t, "Canon EOS 7D", *cr2.Entries[Model].Value.String)
+
+	orf := results["testdata/image.orf"]
+	assert.NoError(t, orf.Err)
+	assert.Contains(t, orf.Entries, Make)
+
+	assert.Error(t, results[notTIFF].Err)
+	assert.ErrorIs(t, results[missing].Err, os.ErrNotExist)
+}
+
+func TestParseBatch_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := ParseBatch(ctx, []string{"testdata/image.cr2", "testdata/image.orf"}, []EntryID{Make}, 0)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, results)
+}
+
+func TestParseStream(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.cr2")
+	paths := slices.Values([]string{"testdata/image.cr2", missing, "testdata/image.orf"})
+
+	results := make(map[string]Result)
+	for path, result := range ParseStream(context.Background(), paths, []EntryID{Model}, 2) {
+		results[path] = result
+	}
+	assert.Len(t, results, 3)
+	assert.Equal(t, "Canon EOS 7D", *results["testdata/image.cr2"].Entries[Model].Value.String)
+	assert.ErrorIs(t, results[missing].Err, os.ErrNotExist)
+
+	// breaking out of the loop stops the workers
+	count := 0
+	for range ParseStream(context.Background(), paths, []EntryID{Model}, 1) {
+		count++
+		break
+	}
+	assert.Equal(t, 1, count)
+}
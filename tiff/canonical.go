@@ -0,0 +1,74 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"maps"
+	"slices"
+)
+
+// canonicalMagic starts canonical encodings, followed by the version of the encoding.
+const (
+	canonicalMagic   = "TIFFC"
+	canonicalVersion = 1
+)
+
+// CanonicalBytes returns a stable encoding of entries (see SortedEntries.CanonicalBytes), e.g. to hash them.
+func CanonicalBytes(entries map[EntryID]Entry) []byte {
+	sorted := make(SortedEntries, 0, len(entries))
+	for _, id := range slices.Sorted(maps.Keys(entries)) {
+		sorted = append(sorted, entries[id])
+	}
+
+	return sorted.CanonicalBytes()
+}
+
+// CanonicalBytes returns a stable, typed encoding of the entries, suitable for hashing or as a cache key: the same
+// entries always give the same bytes, whatever the order they are in (e.g. that of a map) and however they are
+// formatted elsewhere. Each entry is encoded, by increasing ID, as its ID, group and value kind followed by its value in
+// big-endian order, prefixed with its length. Where entries are stored in the file (their offsets) and the data type
+// they are declared with are left out, so that moving them around or declaring them differently without changing their
+// values (e.g. to add other entries) does not change the encoding. The encoding starts with its version, which changes
+// whenever the encoding does.
+func (s SortedEntries) CanonicalBytes() []byte {
+	sorted := slices.SortedFunc(slices.Values(s), func(a, b Entry) int { return int(a.ID) - int(b.ID) })
+
+	var b bytes.Buffer
+	b.WriteString(canonicalMagic)
+	b.WriteByte(canonicalVersion)
+	for _, e := range sorted {
+		appendCanonical(&b, e)
+	}
+
+	return b.Bytes()
+}
+
+// appendCanonical appends the canonical encoding of an entry to b (see SortedEntries.CanonicalBytes).
+func appendCanonical(b *bytes.Buffer, e Entry) {
+	b.Write(binary.BigEndian.AppendUint16(nil, uint16(e.ID)))
+	b.WriteByte(byte(e.Group))
+	appendCanonicalValue(b, e.Value)
+}
+
+// appendCanonicalValue appends the canonical encoding of a value to b: its kind followed by the value, prefixed with its
+// length.
+func appendCanonicalValue(b *bytes.Buffer, v EntryValue) {
+	b.WriteByte(byte(v.Kind()))
+
+	var value []byte
+	switch v := v.Interface().(type) {
+	case nil:
+	case string:
+		value = []byte(v)
+	case []string:
+		for _, s := range v {
+			value = binary.BigEndian.AppendUint32(value, uint32(len(s)))
+			value = append(value, s...)
+		}
+	default:
+		// fixed-size values, and slices of them
+		value, _ = binary.Append(nil, binary.BigEndian, v)
+	}
+	b.Write(binary.BigEndian.AppendUint32(nil, uint32(len(value))))
+	b.Write(value)
+}
@@ -0,0 +1,50 @@
+package tiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalBytes(t *testing.T) {
+	width := uint16(5184)
+	make_ := "Canon"
+	entries := map[EntryID]Entry{
+		Make:       {ID: Make, Group: Group_IFD0, RawValue: 200, Value: EntryValue{String: &make_}},
+		ImageWidth: {ID: ImageWidth, Group: Group_IFD0, Value: EntryValue{Uint16: &width}},
+		Artist:     {ID: Artist, Group: Group_IFD0, Value: EntryValue{String: &make_, Strings: []string{"A", "BC"}}},
+	}
+
+	want := []byte("TIFFC\x01" +
+		"\x01\x00\x00\x03\x00\x00\x00\x02\x14\x40" + // ImageWidth
+		"\x01\x0f\x00\x02\x00\x00\x00\x05Canon" + // Make
+		"\x01\x3b\x00\x15\x00\x00\x00\x0b\x00\x00\x00\x01A\x00\x00\x00\x02BC") // Artist
+	assert.Equal(t, want, CanonicalBytes(entries))
+
+	// offsets are left out
+	moved := entries[Make]
+	moved.RawValue = 300
+	entries[Make] = moved
+	assert.Equal(t, want, CanonicalBytes(entries))
+
+	sorted := SortedEntries{entries[Artist], entries[Make], entries[ImageWidth]}
+	assert.Equal(t, want, sorted.CanonicalBytes())
+}
+
+func TestCanonicalBytes_CR2(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	ids := []EntryID{Make, Model, ExposureTime, BitsPerSample, DateTimeOriginal, GPSVersionID}
+
+	entries, err := p.Parse(ids...)
+	assert.NoError(t, err)
+	sorted, err := p.ParseSorted(ids...)
+	assert.NoError(t, err)
+	assert.Equal(t, CanonicalBytes(entries), sorted.CanonicalBytes())
+
+	// other entries give other bytes
+	other, err := p.Parse(Make, Model, ExposureTime, BitsPerSample, DateTimeOriginal)
+	assert.NoError(t, err)
+	assert.NotEqual(t, CanonicalBytes(entries), CanonicalBytes(other))
+}
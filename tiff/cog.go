@@ -0,0 +1,118 @@
+package tiff
+
+import "fmt"
+
+// cogOverviewThreshold is the size, in pixels, above which the images of cloud-optimized GeoTIFFs should have
+// overviews, as GDAL recommends.
+const cogOverviewThreshold = 512
+
+// ValidateCOG checks, on top of Validate, that the file follows the layout of cloud-optimized GeoTIFFs (COG), which lets
+// clients read any part of it with a few range requests:
+//
+//   - the IFDs of all pages lie at the start of the file, before any image data
+//   - all images are tiled
+//   - overviews (the pages following the full-resolution image) are ordered from the largest to the smallest
+//   - image data is stored from the smallest overview to the full-resolution image, with increasing tile offsets
+//
+// Unmet requirements are reported as errors, so that Valid tells whether the file is a COG. Images larger than 512
+// pixels without overviews are reported as warnings. Transparency masks are ignored.
+func (p *Parser) ValidateCOG() Report {
+	report := p.Validate()
+	if !report.Valid() {
+		return report
+	}
+	v := &validator{parser: p, report: report}
+
+	pages, err := p.Pages()
+	if err != nil {
+		v.errorf(p.firstIFDOffset, 0, "cannot read IFDs: %v", err)
+		return v.report
+	}
+
+	type cogPage struct {
+		page      Page
+		name      string
+		offsets   []uint32 // of its tiles or strips, except empty ones
+		firstData int64    // offset of its first tile or strip, -1 if it has none
+	}
+	var images []cogPage
+	firstData := int64(-1) // of all images
+	for _, page := range pages {
+		if subfileType, ok := page.Uint(NewSubfileType); ok && subfileType&subfileType_Mask != 0 {
+			continue
+		}
+		img := cogPage{page: page, name: fmt.Sprintf("IFD#%d", page.Index), firstData: -1}
+		id, lengthsID := StripOffsets, StripByteCounts
+		if page.Tiled() {
+			id, lengthsID = TileOffsets, TileByteCounts
+		}
+		offsets, lengths, err := page.chunks(id, lengthsID)
+		if err != nil {
+			v.errorf(page.Offset, id, "%s: %v", img.name, err)
+			continue
+		}
+		for i, offset := range offsets {
+			if offset == 0 && lengths[i] == 0 {
+				continue // sparse tile
+			}
+			img.offsets = append(img.offsets, offset)
+			if img.firstData < 0 || int64(offset) < img.firstData {
+				img.firstData = int64(offset)
+			}
+		}
+		if img.firstData >= 0 && (firstData < 0 || img.firstData < firstData) {
+			firstData = img.firstData
+		}
+		images = append(images, img)
+	}
+	if len(images) == 0 {
+		v.errorf(p.firstIFDOffset, 0, "no image found")
+		return v.report
+	}
+
+	for _, page := range pages {
+		if firstData >= 0 && page.Offset > firstData {
+			v.errorf(page.Offset, 0, "IFD#%d lies after image data (at offset %d): all IFDs must be at the start of the file", page.Index, firstData)
+		}
+	}
+
+	for i, img := range images {
+		if !img.page.Tiled() {
+			v.errorf(img.page.Offset, 0, "%s is not tiled", img.name)
+		}
+		for j := 1; j < len(img.offsets); j++ {
+			if img.offsets[j] < img.offsets[j-1] {
+				v.errorf(img.page.Offset, TileOffsets, "%s: tile or strip #%d (at offset %d) is stored before the previous one (at offset %d)", img.name, j, img.offsets[j], img.offsets[j-1])
+				break
+			}
+		}
+		if i == 0 {
+			continue
+		}
+
+		previous := images[i-1]
+		width, _ := img.page.Width()
+		height, _ := img.page.Height()
+		previousWidth, _ := previous.page.Width()
+		previousHeight, _ := previous.page.Height()
+		if width > previousWidth || height > previousHeight || (width == previousWidth && height == previousHeight) {
+			v.errorf(img.page.Offset, 0, "%s (%dx%d) is not smaller than %s (%dx%d): overviews must be ordered from the largest to the smallest",
+				img.name, width, height, previous.name, previousWidth, previousHeight)
+		}
+		if img.firstData >= 0 && previous.firstData >= 0 && img.firstData > previous.firstData {
+			v.errorf(img.page.Offset, 0, "image data of %s (at offset %d) must be stored before that of %s (at offset %d)",
+				img.name, img.firstData, previous.name, previous.firstData)
+		}
+	}
+
+	if len(images) == 1 {
+		width, _ := images[0].page.Width()
+		height, _ := images[0].page.Height()
+		if width > cogOverviewThreshold || height > cogOverviewThreshold {
+			v.warnf(images[0].page.Offset, 0, "%s (%dx%d) has no overviews: images larger than %d pixels should have some",
+				images[0].name, width, height, cogOverviewThreshold)
+		}
+	}
+
+	return v.report
+}
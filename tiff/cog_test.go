@@ -0,0 +1,163 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// cogImage builds a synthetic cloud-optimized GeoTIFF made of square images of the given sizes, split in tiles of 16x16
+// pixels holding one byte each: the IFDs of all images come first, then the tiles of the last image, up to those of
+// the first one (or the other way round if largestFirst is true).
+func cogImage(largestFirst bool, sizes ...int) []byte {
+	const entries = 7
+	bo := binary.LittleEndian
+	tiles := func(size int) int {
+		n := (size + 15) / 16
+		return n * n
+	}
+	arrays := func(size int) int {
+		if tiles(size) == 1 {
+			return 0
+		}
+		return 8 * tiles(size)
+	}
+
+	pos := 8
+	ifdOffsets := make([]int, len(sizes))
+	for i, size := range sizes {
+		ifdOffsets[i] = pos
+		pos += 2 + entries*EntryLength + 4 + arrays(size)
+	}
+	order := make([]int, len(sizes))
+	for i := range order {
+		order[i] = len(sizes) - 1 - i
+	}
+	if largestFirst {
+		slices.Reverse(order)
+	}
+	dataOffsets := make([]int, len(sizes))
+	for _, i := range order {
+		dataOffsets[i] = pos
+		pos += tiles(sizes[i])
+	}
+
+	buf := make([]byte, pos)
+	copy(buf, "II")
+	bo.PutUint16(buf[2:], 42)
+	bo.PutUint32(buf[4:], uint32(ifdOffsets[0]))
+	for i, size := range sizes {
+		n := uint32(tiles(size))
+		offsets, counts := uint32(dataOffsets[i]), uint32(1)
+		if n > 1 {
+			offsets = uint32(ifdOffsets[i] + 2 + entries*EntryLength + 4)
+			counts = offsets + 4*n
+			for j := uint32(0); j < n; j++ {
+				bo.PutUint32(buf[offsets+4*j:], uint32(dataOffsets[i])+j)
+				bo.PutUint32(buf[counts+4*j:], 1)
+			}
+		}
+		subfileType := uint32(0)
+		if i > 0 {
+			subfileType = subfileType_ReducedResolution
+		}
+
+		table := buf[ifdOffsets[i]:]
+		bo.PutUint16(table, entries)
+		for j, e := range []struct {
+			id    EntryID
+			dt    DataType
+			count uint32
+			value uint32
+		}{
+			{NewSubfileType, DataType_ULong, 1, subfileType},
+			{ImageWidth, DataType_UShort, 1, uint32(size)},
+			{ImageHeight, DataType_UShort, 1, uint32(size)},
+			{TileWidth, DataType_UShort, 1, 16},
+			{TileLength, DataType_UShort, 1, 16},
+			{TileOffsets, DataType_ULong, n, offsets},
+			{TileByteCounts, DataType_ULong, n, counts},
+		} {
+			entry := table[2+j*EntryLength:]
+			bo.PutUint16(entry, uint16(e.id))
+			bo.PutUint16(entry[2:], uint16(e.dt))
+			bo.PutUint32(entry[4:], e.count)
+			bo.PutUint32(entry[8:], e.value)
+		}
+		if i+1 < len(sizes) {
+			bo.PutUint32(table[2+entries*EntryLength:], uint32(ifdOffsets[i+1]))
+		}
+	}
+
+	return buf
+}
+
+func TestParser_ValidateCOG(t *testing.T) {
+	stripped := test.NewTIFFBuilder(binary.LittleEndian)
+	stripped.AddIFD().
+		WithUints16(uint16(ImageWidth), 2).
+		WithUints16(uint16(ImageHeight), 1).
+		WithData(uint16(StripOffsets), uint16(StripByteCounts), []byte{1, 2})
+	stripped.AddIFD().
+		WithUints16(uint16(ImageWidth), 1).
+		WithUints16(uint16(ImageHeight), 1).
+		WithData(uint16(StripOffsets), uint16(StripByteCounts), []byte{3})
+
+	tests := []struct {
+		name  string
+		data  []byte
+		valid bool
+		want  []string
+	}{
+		{"COG", cogImage(false, 600, 300, 150), true, nil},
+		{"small image without overviews", cogImage(false, 500), true, nil},
+		{"large image without overviews", cogImage(false, 600), true, []string{
+			"IFD#0 (600x600) has no overviews: images larger than 512 pixels should have some",
+		}},
+		{"full-resolution data first", cogImage(true, 600, 300, 150), false, []string{
+			"image data of IFD#1 (at offset 16962) must be stored before that of IFD#0 (at offset 15518)",
+			"image data of IFD#2 (at offset 17323) must be stored before that of IFD#1 (at offset 16962)",
+		}},
+		{"overviews out of order", cogImage(false, 300, 600), false, []string{
+			"IFD#1 (600x600) is not smaller than IFD#0 (300x300): overviews must be ordered from the largest to the smallest",
+		}},
+		{"strips", stripped.Bytes(), false, []string{
+			"IFD#1 lies after image data (at offset 62): all IFDs must be at the start of the file",
+			"IFD#0 is not tiled",
+			"IFD#1 is not tiled",
+			"image data of IFD#1 (at offset 118) must be stored before that of IFD#0 (at offset 62)",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(bytes.NewReader(tt.data))
+			assert.NoError(t, err)
+
+			report := p.ValidateCOG()
+			var got []string
+			for _, issue := range report.Issues {
+				got = append(got, issue.Message)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.valid, report.Valid())
+		})
+	}
+}
+
+func TestParser_ValidateCOG_Invalid(t *testing.T) {
+	data := cogImage(false, 600, 300)
+	p, err := NewParser(bytes.NewReader(data[:len(data)-100]))
+	assert.NoError(t, err)
+
+	// structural errors are reported as such, without checking the layout
+	report := p.ValidateCOG()
+	assert.False(t, report.Valid())
+	for _, issue := range report.Issues {
+		assert.Contains(t, issue.Message, "ends after the end of the file")
+	}
+}
@@ -0,0 +1,195 @@
+package tiff
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Composite is a value derived from several entries rather than read from a single one, like the composite tags of
+// exiftool (e.g. GPSPosition, from GPSLatitude, GPSLongitude and their references).
+type Composite struct {
+	Name     string
+	Requires []EntryID                                    // entries it is derived from, some of them optional
+	Compute  func(entries map[EntryID]Entry) (any, error) // returns an error if the entries it needs are missing
+}
+
+// Entries several composites are derived from
+var (
+	sensorEntries = []EntryID{FocalLength, FocalLengthIn35mmFilm, FocalPlaneXResolution, FocalPlaneYResolution,
+		FocalPlaneResolutionUnit, ImageWidth, ImageHeight, ExifImageWidth, ExifImageHeight}
+	exposureEntries = []EntryID{FNumber, ApertureValue, ExposureTime, ShutterSpeedValue}
+)
+
+// builtinComposites lists the composites known to the library, by name.
+var builtinComposites = []Composite{
+	{
+		Name:     "CircleOfConfusion",
+		Requires: sensorEntries,
+		Compute:  float(ReadCircleOfConfusion),
+	},
+	{
+		Name:     "GPSPosition",
+		Requires: []EntryID{GPSLatitude, GPSLatitudeRef, GPSLongitude, GPSLongitudeRef},
+		Compute: func(entries map[EntryID]Entry) (any, error) {
+			latitude, longitude, err := ReadGPSPosition(entries)
+			if err != nil {
+				return nil, err
+			}
+			return []float64{latitude, longitude}, nil
+		},
+	},
+	{
+		Name:     "HyperfocalDistance",
+		Requires: append(slices.Clone(sensorEntries), FNumber, ApertureValue),
+		Compute:  float(ReadHyperfocalDistance),
+	},
+	{
+		Name:     "LightValue",
+		Requires: append(slices.Clone(exposureEntries), ISO),
+		Compute:  float(ReadLightValue),
+	},
+	{
+		Name:     "ShutterSpeed",
+		Requires: []EntryID{ExposureTime, ShutterSpeedValue},
+		Compute:  float(ReadExposureTime),
+	},
+}
+
+// float adapts a function reading a number from entries to Composite.Compute.
+func float(read func(entries map[EntryID]Entry) (float64, error)) func(map[EntryID]Entry) (any, error) {
+	return func(entries map[EntryID]Entry) (any, error) {
+		value, err := read(entries)
+		if err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+}
+
+// CompositeRegistry is a set of composites, to which applications add their own (see AddComposite), e.g. to compute
+// an AspectRatio without forking the library. It is safe for concurrent use, and its zero value is empty.
+type CompositeRegistry struct {
+	mu         sync.RWMutex
+	composites []Composite // sorted by name
+}
+
+// DefaultComposites holds the composites known to the library, and those applications add to it: they are exported
+// with the entries they are derived from (see Parser.ExifTool and Formatter), unless parsers are given another registry
+// (see WithComposites).
+var DefaultComposites = NewCompositeRegistry()
+
+// NewCompositeRegistry returns a registry holding the composites known to the library (see Composites).
+func NewCompositeRegistry() *CompositeRegistry {
+	return &CompositeRegistry{composites: slices.Clone(builtinComposites)}
+}
+
+// AddComposite adds a composite named name, computed by compute from entries, which requires lists (e.g. ImageWidth
+// and ImageHeight for an AspectRatio). compute returns an error when the entries it needs are missing or invalid, in
+// which case the composite is left out of exports. It returns an error if the registry already has a composite with
+// that name.
+func (r *CompositeRegistry) AddComposite(name string, requires []EntryID, compute func(entries map[EntryID]Entry) (any, error)) error {
+	if name == "" || compute == nil {
+		return errors.New("a composite needs a name and a function computing it")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, found := slices.BinarySearchFunc(r.composites, name, func(c Composite, name string) int {
+		return strings.Compare(c.Name, name)
+	})
+	if found {
+		return fmt.Errorf("composite %s already exists", name)
+	}
+	r.composites = slices.Insert(r.composites, i, Composite{Name: name, Requires: slices.Clone(requires), Compute: compute})
+
+	return nil
+}
+
+// Composites returns the composites of the registry, sorted by name.
+func (r *CompositeRegistry) Composites() []Composite {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Clone(r.composites)
+}
+
+// Entries returns the entries the composites of the registry are derived from, sorted, e.g. to parse them all.
+func (r *CompositeRegistry) Entries() []EntryID {
+	var ids []EntryID
+	for _, c := range r.Composites() {
+		ids = append(ids, c.Requires...)
+	}
+	slices.Sort(ids)
+
+	return slices.Compact(ids)
+}
+
+// Read computes the composite with the given name (e.g. "LightValue") from entries.
+func (r *CompositeRegistry) Read(entries map[EntryID]Entry, name string) (any, error) {
+	composites := r.Composites()
+	i := slices.IndexFunc(composites, func(c Composite) bool { return c.Name == name })
+	if i < 0 {
+		return nil, fmt.Errorf("unknown composite %q", name)
+	}
+
+	return composites[i].Compute(entries)
+}
+
+// ReadAll computes the composites of the registry that can be derived from entries, by name, leaving out those whose
+// entries are missing or invalid.
+func (r *CompositeRegistry) ReadAll(entries map[EntryID]Entry) map[string]any {
+	values := make(map[string]any)
+	for _, c := range r.Composites() {
+		if value, err := c.Compute(entries); err == nil {
+			values[c.Name] = value
+		}
+	}
+
+	return values
+}
+
+// Composites returns the composites of DefaultComposites, sorted by name: those known to the library are
+// CircleOfConfusion (in millimeters), GPSPosition (latitude and longitude, in degrees), HyperfocalDistance (in meters),
+// LightValue (in EV) and ShutterSpeed (in seconds).
+func Composites() []Composite {
+	return DefaultComposites.Composites()
+}
+
+// CompositeEntries returns the entries the composites of DefaultComposites are derived from (see
+// CompositeRegistry.Entries).
+func CompositeEntries() []EntryID {
+	return DefaultComposites.Entries()
+}
+
+// ReadComposite computes the composite of DefaultComposites with the given name (e.g. "LightValue") from entries.
+func ReadComposite(entries map[EntryID]Entry, name string) (any, error) {
+	return DefaultComposites.Read(entries, name)
+}
+
+// ReadComposites computes the composites of DefaultComposites that can be derived from entries (see
+// CompositeRegistry.ReadAll).
+func ReadComposites(entries map[EntryID]Entry) map[string]any {
+	return DefaultComposites.ReadAll(entries)
+}
+
+// WithComposites makes the parser export the composites of the given registry with entries (see ExifTool), rather
+// than those of DefaultComposites; an empty registry (new(CompositeRegistry)) leaves composites out. A nil registry
+// restores the default.
+func (p *Parser) WithComposites(r *CompositeRegistry) *Parser {
+	p.composites = r
+
+	return p
+}
+
+// compositeRegistry returns the registry of the composites the parser exports.
+func (p *Parser) compositeRegistry() *CompositeRegistry {
+	if p.composites == nil {
+		return DefaultComposites
+	}
+
+	return p.composites
+}
@@ -0,0 +1,112 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadComposites(t *testing.T) {
+	rational := func(id EntryID, numerator, denominator uint32) Entry {
+		return Entry{ID: id, Value: EntryValue{URational: &URational{numerator, denominator}}}
+	}
+	short := func(id EntryID, value uint16) Entry {
+		return Entry{ID: id, Value: EntryValue{Uint16: &value}}
+	}
+	dms := func(id EntryID, degrees uint32) Entry {
+		return Entry{ID: id, Value: EntryValue{URationals: []URational{{degrees, 1}, {30, 1}, {0, 1}}}}
+	}
+	ref := func(id EntryID, value string) Entry {
+		return Entry{ID: id, Value: EntryValue{String: &value}}
+	}
+
+	entries := map[EntryID]Entry{
+		FocalLength:           rational(FocalLength, 50, 1),
+		FocalLengthIn35mmFilm: short(FocalLengthIn35mmFilm, 75),
+		FNumber:               rational(FNumber, 8, 1),
+		ExposureTime:          rational(ExposureTime, 1, 125),
+		ISO:                   short(ISO, 200),
+		GPSLatitude:           dms(GPSLatitude, 45),
+		GPSLongitude:          dms(GPSLongitude, 9),
+		GPSLongitudeRef:       ref(GPSLongitudeRef, "W"),
+	}
+
+	values := ReadComposites(entries)
+	assert.Len(t, values, 5)
+	assert.InDelta(t, 0.02003, values["CircleOfConfusion"], 0.00001)
+	assert.Equal(t, []float64{45.5, -9.5}, values["GPSPosition"])
+	assert.InDelta(t, 15.60, values["HyperfocalDistance"], 0.01)
+	assert.InDelta(t, 11.97, values["LightValue"], 0.01)
+	assert.InDelta(t, 0.008, values["ShutterSpeed"], 0.00001)
+
+	// composites whose entries are missing are left out
+	delete(entries, FocalLengthIn35mmFilm)
+	delete(entries, ISO)
+	values = ReadComposites(entries)
+	assert.NotContains(t, values, "CircleOfConfusion")
+	assert.NotContains(t, values, "HyperfocalDistance")
+	assert.InDelta(t, 12.97, values["LightValue"], 0.01) // at ISO 100
+
+	_, err := ReadComposite(entries, "CircleOfConfusion")
+	assert.Error(t, err)
+	_, err = ReadComposite(entries, "Unknown")
+	assert.ErrorContains(t, err, "unknown composite")
+}
+
+func TestCompositeEntries(t *testing.T) {
+	ids := CompositeEntries()
+	assert.IsIncreasing(t, ids)
+	for _, c := range Composites() {
+		assert.Subset(t, ids, c.Requires, c.Name)
+		for _, id := range c.Requires {
+			assert.Contains(t, Defaults, id, "%s requires an unknown entry", c.Name)
+		}
+	}
+}
+
+func TestCompositeRegistry(t *testing.T) {
+	aspectRatio := func(entries map[EntryID]Entry) (any, error) {
+		width, height, err := ReadImageSize(entries)
+		if err != nil {
+			return nil, err
+		}
+		return float64(width) / float64(height), nil
+	}
+
+	r := NewCompositeRegistry()
+	assert.NoError(t, r.AddComposite("AspectRatio", []EntryID{ImageWidth, ImageHeight}, aspectRatio))
+	assert.ErrorContains(t, r.AddComposite("AspectRatio", nil, aspectRatio), "already exists")
+	assert.ErrorContains(t, r.AddComposite("LightValue", nil, aspectRatio), "already exists")
+	assert.Error(t, r.AddComposite("", nil, aspectRatio))
+	assert.Error(t, r.AddComposite("Nothing", nil, nil))
+
+	var names []string
+	for _, c := range r.Composites() {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"AspectRatio", "CircleOfConfusion", "GPSPosition", "HyperfocalDistance", "LightValue", "ShutterSpeed"}, names)
+	assert.Contains(t, r.Entries(), ImageWidth)
+	assert.Len(t, Composites(), 5, "DefaultComposites is left as is")
+
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	entries, err := p.Parse(ImageWidth, ImageHeight, ExposureTime)
+	assert.NoError(t, err)
+
+	value, err := r.Read(entries, "AspectRatio")
+	assert.NoError(t, err)
+	assert.InDelta(t, 1.5, value, 0.01)
+
+	exported := p.WithComposites(r).ExifTool(entries)
+	assert.Equal(t, json.Number("1.5"), exported["Composite:AspectRatio"])
+	assert.Equal(t, "1/40", exported["Composite:ShutterSpeed"])
+
+	exported = p.WithComposites(new(CompositeRegistry)).ExifTool(entries)
+	assert.NotContains(t, exported, "Composite:ShutterSpeed")
+
+	exported = p.WithComposites(nil).ExifTool(entries)
+	assert.NotContains(t, exported, "Composite:AspectRatio")
+	assert.Contains(t, exported, "Composite:ShutterSpeed")
+}
@@ -1,5 +1,7 @@
 package tiff
 
+import "fmt"
+
 type (
 	// Group enumerates known (sub-)IFD: an Image File Directory (IFD) is a physical group of entries.
 	Group uint8
@@ -10,6 +12,9 @@ const (
 	Group_IFD1
 	Group_Exif
 	Group_GPSInfo
+	Group_SubIFD    // IFDs pointed to by SubIFDs (e.g. full resolution images of DNG files)
+	Group_Image     // IFDs of the main chain after IFD#1 (e.g. raw images of CR2 files)
+	Group_MakerNote // IFDs of maker notes, whose structure is vendor-specific
 
 	// intelByteOrder is the TIFF standard value to indicate Intel byte ordering (aka little-endian)
 	intelByteOrder = 0x4949
@@ -27,21 +32,122 @@ const (
 	orfMagicNumberLittleEndian = 0x524F
 )
 
+func (g Group) String() string {
+	switch g {
+	case Group_IFD0:
+		return "IFD0"
+	case Group_IFD1:
+		return "IFD1"
+	case Group_Exif:
+		return "Exif"
+	case Group_GPSInfo:
+		return "GPSInfo"
+	case Group_SubIFD:
+		return "SubIFD"
+	case Group_Image:
+		return "Image"
+	case Group_MakerNote:
+		return "MakerNote"
+	default:
+		if name, ok := customGroupName(g); ok {
+			return name
+		}
+		return fmt.Sprintf("Group(%d)", int(g))
+	}
+}
+
 // Defaults maps IFD entries to the Group they belong to (e.g. IFD#0, Exif, GPSInfo), so that a `Parser` will know where to look for them.
 var Defaults = map[EntryID]Group{
-	ImageWidth:         Group_IFD0,
-	ImageHeight:        Group_IFD0,
-	BitsPerSample:      Group_IFD0,
-	Compression:        Group_IFD0,
-	Make:               Group_IFD0,
-	Model:              Group_IFD0,
-	Exif:               Group_IFD0,
-	GPSInfo:            Group_IFD0,
-	ExposureTime:       Group_Exif,
-	FNumber:            Group_Exif,
-	ISO:                Group_Exif,
-	DateTimeOriginal:   Group_Exif,
-	OffsetTimeOriginal: Group_Exif,
-	GPSLatitude:        Group_GPSInfo,
-	GPSLongitude:       Group_GPSInfo,
+	NewSubfileType:            Group_IFD0,
+	ImageWidth:                Group_IFD0,
+	ImageHeight:               Group_IFD0,
+	BitsPerSample:             Group_IFD0,
+	Compression:               Group_IFD0,
+	PhotometricInterpretation: Group_IFD0,
+	DocumentName:              Group_IFD0,
+	ImageDescription:          Group_IFD0,
+	Make:                      Group_IFD0,
+	Model:                     Group_IFD0,
+	StripOffsets:              Group_IFD0,
+	Orientation:               Group_IFD0,
+	SamplesPerPixel:           Group_IFD0,
+	RowsPerStrip:              Group_IFD0,
+	StripByteCounts:           Group_IFD0,
+	XResolution:               Group_IFD0,
+	YResolution:               Group_IFD0,
+	PlanarConfiguration:       Group_IFD0,
+	ResolutionUnit:            Group_IFD0,
+	Software:                  Group_IFD0,
+	Artist:                    Group_IFD0,
+	Predictor:                 Group_IFD0,
+	ColorMap:                  Group_IFD0,
+	TileWidth:                 Group_IFD0,
+	TileLength:                Group_IFD0,
+	TileOffsets:               Group_IFD0,
+	TileByteCounts:            Group_IFD0,
+	SubIFDs:                   Group_IFD0,
+	InkSet:                    Group_IFD0,
+	ExtraSamples:              Group_IFD0,
+	SampleFormat:              Group_IFD0,
+	JPEGTables:                Group_IFD0,
+	Rating:                    Group_IFD0,
+	RatingPercent:             Group_IFD0,
+	Copyright:                 Group_IFD0,
+	Exif:                      Group_IFD0,
+	GPSInfo:                   Group_IFD0,
+	XPKeywords:                Group_IFD0,
+	ExposureTime:              Group_Exif,
+	FNumber:                   Group_Exif,
+	ISO:                       Group_Exif,
+	ExifVersion:               Group_Exif,
+	DateTimeOriginal:          Group_Exif,
+	OffsetTime:                Group_Exif,
+	OffsetTimeOriginal:        Group_Exif,
+	ComponentsConfiguration:   Group_Exif,
+	ShutterSpeedValue:         Group_Exif,
+	ApertureValue:             Group_Exif,
+	BrightnessValue:           Group_Exif,
+	ExposureBiasValue:         Group_Exif,
+	FocalLength:               Group_Exif,
+	MakerNotes:                Group_Exif,
+	FlashpixVersion:           Group_Exif,
+	ExifImageWidth:            Group_Exif,
+	ExifImageHeight:           Group_Exif,
+	FocalPlaneXResolution:     Group_Exif,
+	FocalPlaneYResolution:     Group_Exif,
+	FocalPlaneResolutionUnit:  Group_Exif,
+	FocalLengthIn35mmFilm:     Group_Exif,
+	ImageUniqueID:             Group_Exif,
+	CameraOwnerName:           Group_Exif,
+	BodySerialNumber:          Group_Exif,
+	LensSerialNumber:          Group_Exif,
+	GPSVersionID:              Group_GPSInfo,
+	GPSLatitudeRef:            Group_GPSInfo,
+	GPSLatitude:               Group_GPSInfo,
+	GPSLongitudeRef:           Group_GPSInfo,
+	GPSLongitude:              Group_GPSInfo,
+	GPSSpeedRef:               Group_GPSInfo,
+	GPSSpeed:                  Group_GPSInfo,
+	GPSTrackRef:               Group_GPSInfo,
+	GPSTrack:                  Group_GPSInfo,
+	GPSImgDirectionRef:        Group_GPSInfo,
+	GPSImgDirection:           Group_GPSInfo,
+	YCbCrCoefficients:         Group_IFD0,
+	YCbCrSubSampling:          Group_IFD0,
+	YCbCrPositioning:          Group_IFD0,
+	ReferenceBlackWhite:       Group_IFD0,
+	BlackLevel:                Group_SubIFD,
+	WhiteLevel:                Group_SubIFD,
+	ColorMatrix1:              Group_IFD0,
+	ColorMatrix2:              Group_IFD0,
+	CameraCalibration1:        Group_IFD0,
+	CameraCalibration2:        Group_IFD0,
+	AsShotNeutral:             Group_IFD0,
+	BaselineExposure:          Group_IFD0,
+	OriginalRawFileName:       Group_IFD0,
+	OpcodeList1:               Group_SubIFD,
+	OpcodeList2:               Group_SubIFD,
+	OpcodeList3:               Group_SubIFD,
+	GDALMetadata:              Group_IFD0,
+	GDALNoData:                Group_IFD0,
 }
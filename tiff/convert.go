@@ -0,0 +1,130 @@
+package tiff
+
+import (
+	"fmt"
+	"math"
+)
+
+// Int64s returns the integer value(s) of the entry as int64, whatever their integer DataType (signed or unsigned). It
+// returns an error if the entry does not hold an integer value.
+func (e Entry) Int64s() ([]int64, error) {
+	v := e.Value
+	var res []int64
+	switch {
+	case v.Byte != nil:
+		res = append(res, int64(int8(*v.Byte)))
+	case v.SBytes != nil:
+		for _, x := range v.SBytes {
+			res = append(res, int64(x))
+		}
+	case v.Int16 != nil:
+		res = append(res, int64(*v.Int16))
+	case v.Ints16 != nil:
+		for _, x := range v.Ints16 {
+			res = append(res, int64(x))
+		}
+	case v.Int32 != nil:
+		res = append(res, int64(*v.Int32))
+	case v.Ints32 != nil:
+		for _, x := range v.Ints32 {
+			res = append(res, int64(x))
+		}
+	case v.Int64 != nil:
+		res = append(res, *v.Int64)
+	case v.Ints64 != nil:
+		res = v.Ints64
+	case v.Uint64 != nil, v.Uints64 != nil:
+		values, _ := e.Uint64s()
+		for _, x := range values {
+			if x > math.MaxInt64 {
+				return nil, fmt.Errorf("%w: entry 0x%X holds value %d, larger than int64", ErrOutOfRange, e.ID, x)
+			}
+			res = append(res, int64(x))
+		}
+	default:
+		values, err := e.Uints()
+		if err != nil {
+			return nil, fmt.Errorf("entry 0x%X does not hold an integer value", e.ID)
+		}
+		for _, x := range values {
+			res = append(res, int64(x))
+		}
+	}
+
+	return res, nil
+}
+
+// Uint64s returns the integer value(s) of the entry as uint64, whatever their integer DataType (signed or unsigned).
+// It returns an error if the entry does not hold an integer value, or an error matching ErrOutOfRange if one of its
+// values is negative.
+func (e Entry) Uint64s() ([]uint64, error) {
+	switch v := e.Value; {
+	case v.Uint64 != nil:
+		return []uint64{*v.Uint64}, nil
+	case v.Uints64 != nil:
+		return v.Uints64, nil
+	}
+	values, err := e.Int64s()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]uint64, len(values))
+	for i, x := range values {
+		if x < 0 {
+			return nil, fmt.Errorf("%w: entry 0x%X holds negative value %d", ErrOutOfRange, e.ID, x)
+		}
+		res[i] = uint64(x)
+	}
+
+	return res, nil
+}
+
+// toInt converts n to an int, returning an error matching ErrOutOfRange if it overflows it (e.g. on 32-bit platforms).
+func toInt(n uint64) (int, error) {
+	if n > math.MaxInt {
+		return 0, fmt.Errorf("%w: %d overflows int", ErrOutOfRange, n)
+	}
+
+	return int(n), nil
+}
+
+// byteSize returns the size, in bytes, of count values of size bytes each, returning an error matching ErrOutOfRange
+// if it overflows an int.
+func byteSize(count uint64, size int) (int, error) {
+	if size > 0 && count > math.MaxUint64/uint64(size) {
+		return 0, fmt.Errorf("%w: %d values of %d bytes overflow int", ErrOutOfRange, count, size)
+	}
+
+	return toInt(count * uint64(size))
+}
+
+// addOffset returns base + offset, returning an error matching ErrOutOfRange if it overflows an int64 or is negative.
+func addOffset(base int64, offset uint64) (int64, error) {
+	if offset > math.MaxInt64 || (base > 0 && int64(offset) > math.MaxInt64-base) {
+		return 0, fmt.Errorf("%w: offset %d from %d overflows int64", ErrOutOfRange, offset, base)
+	}
+	res := base + int64(offset)
+	if res < 0 {
+		return 0, fmt.Errorf("%w: offset %d from %d is negative", ErrOutOfRange, offset, base)
+	}
+
+	return res, nil
+}
+
+// readUint reads the value of an entry holding a single, non-negative integer.
+func readUint(entries map[EntryID]Entry, id EntryID) (uint64, error) {
+	entry, ok := entries[id]
+	if !ok {
+		return 0, fmt.Errorf("entry 0x%X not found", id)
+	}
+	values, err := entry.Uint64s()
+	if err != nil {
+		return 0, err
+	}
+	if len(values) != 1 {
+		return 0, fmt.Errorf("invalid %s %v", id.Name(), values)
+	}
+
+	return values[0], nil
+}
@@ -0,0 +1,99 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntry_Int64s(t *testing.T) {
+	i16, u32, i64 := int16(-2), uint32(math.MaxUint32), int64(-3)
+
+	tests := []struct {
+		name      string
+		value     EntryValue
+		want      []int64
+		wantUints []uint64
+		err       error // of Uint64s
+	}{
+		{"unsigned", EntryValue{Uint32: &u32}, []int64{math.MaxUint32}, []uint64{math.MaxUint32}, nil},
+		{"unsigned list", EntryValue{Uints16: []uint16{1, 2}}, []int64{1, 2}, []uint64{1, 2}, nil},
+		{"signed", EntryValue{Int16: &i16}, []int64{-2}, nil, ErrOutOfRange},
+		{"signed list", EntryValue{Ints32: []int32{3, math.MinInt32}}, []int64{3, math.MinInt32}, nil, ErrOutOfRange},
+		{"signed bytes", EntryValue{SBytes: []int8{1, 2}}, []int64{1, 2}, []uint64{1, 2}, nil},
+		{"64-bit unsigned list", EntryValue{Uints64: []uint64{1 << 40}}, []int64{1 << 40}, []uint64{1 << 40}, nil},
+		{"64-bit signed", EntryValue{Int64: &i64}, []int64{-3}, nil, ErrOutOfRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := Entry{ID: ImageWidth, Value: tt.value}
+			got, err := entry.Int64s()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			uints, err := entry.Uint64s()
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.wantUints, uints)
+		})
+	}
+
+	f := float32(1.5)
+	_, err := Entry{ID: ImageWidth, Value: EntryValue{Float32: &f}}.Int64s()
+	assert.Error(t, err)
+
+	// beyond int64, only Uint64s can tell
+	huge := Entry{ID: ImageWidth, Value: EntryValue{Uints64: []uint64{math.MaxUint64}}}
+	_, err = huge.Int64s()
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	uints, err := huge.Uint64s()
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{math.MaxUint64}, uints)
+}
+
+func TestEntry_Uints_64Bits(t *testing.T) {
+	offsets, err := Entry{ID: StripOffsets, Value: EntryValue{Uints64: []uint64{8, math.MaxUint32}}}.Uints()
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{8, math.MaxUint32}, offsets)
+
+	_, err = Entry{ID: StripOffsets, Value: EntryValue{Uints64: []uint64{8, 1 << 32}}}.Uints()
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
+
+func TestByteSize(t *testing.T) {
+	n, err := byteSize(math.MaxUint32, 8)
+	assert.NoError(t, err)
+	assert.Equal(t, 8*math.MaxUint32, n)
+
+	_, err = byteSize(math.MaxUint64/2, 4)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	_, err = toInt(math.MaxUint64)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
+
+func TestAddOffset(t *testing.T) {
+	n, err := addOffset(100, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(120), n)
+
+	_, err = addOffset(math.MaxInt64, 1)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	_, err = addOffset(-100, 20)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
+
+func TestParse_RationalsCountOverflow(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().WithURationals(uint16(XResolution), 72, 1, 72, 1)
+	data := b.Bytes()
+	binary.LittleEndian.PutUint32(data[8+2+4:], math.MaxUint32/2+2) // count of XResolution
+
+	p, err := NewParser(bytes.NewReader(data))
+	assert.NoError(t, err)
+	_, err = p.Parse(XResolution)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
@@ -0,0 +1,238 @@
+package tiff
+
+import "sort"
+
+// TagInfo documents a known entry, e.g. to render tooltips or check values against their specification.
+type TagInfo struct {
+	ID          EntryID
+	Name        string            // name, as used by exiftool (see EntryID.Name)
+	Group       Group             // group of the IFD the entry is usually found in
+	Description string            // what the entry holds
+	Unit        string            // unit of the value (e.g. "seconds"), if any
+	Values      map[uint32]string // allowed values and their meaning, for enumerations
+	Min, Max    float64           // allowed range of numeric values, if Max > Min
+	Types       []DataType        // allowed data types, if checked (see WithStrictTypes)
+	Count       uint32            // required number of values, 0 if any number is allowed
+	Spec        string            // specification defining the entry, e.g. "TIFF 6.0" or "Exif 2.32"
+}
+
+// Allows tells whether a numeric value is allowed: it must be one of Values, or within Min and Max, if they are set.
+func (t TagInfo) Allows(value float64) bool {
+	if len(t.Values) > 0 {
+		_, ok := t.Values[uint32(value)]
+		return ok && float64(uint32(value)) == value
+	}
+	if t.Max > t.Min {
+		return value >= t.Min && value <= t.Max
+	}
+
+	return true
+}
+
+// tagDoc documents a known entry (see TagInfo).
+type tagDoc struct {
+	group       Group
+	description string
+	unit        string
+	values      map[uint32]string
+	min, max    float64
+	spec        string
+}
+
+const (
+	specTIFF = "TIFF 6.0"
+	specExif = "Exif 2.32"
+	specDNG  = "DNG 1.6"
+	specGDAL = "GDAL"
+
+	specMicrosoft = "Microsoft Windows Property System"
+)
+
+var (
+	resolutionUnits = map[uint32]string{1: "none", 2: "inches", 3: "centimeters"}
+	sampleFormats   = map[uint32]string{1: "unsigned integer", 2: "signed integer", 3: "floating point", 4: "undefined"}
+	refNorth        = "T (true north) or M (magnetic north)"
+)
+
+// docs documents known entries.
+var docs = map[EntryID]tagDoc{
+	NewSubfileType: {group: Group_IFD0, spec: specTIFF,
+		description: "Kind of image: bit 0 is set for reduced-resolution images (e.g. previews), bit 1 for pages of multi-page images, bit 2 for transparency masks"},
+	ImageWidth:  {group: Group_IFD0, spec: specTIFF, unit: "pixels", description: "Number of columns of the image"},
+	ImageHeight: {group: Group_IFD0, spec: specTIFF, unit: "pixels", description: "Number of rows of the image"},
+	BitsPerSample: {group: Group_IFD0, spec: specTIFF, unit: "bits", min: 1, max: 64,
+		description: "Number of bits per component (sample) of each pixel"},
+	Compression: {group: Group_IFD0, spec: specTIFF, description: "Compression scheme of the image data", values: map[uint32]string{
+		1: "uncompressed", 2: "CCITT 1D", 3: "CCITT Group 3", 4: "CCITT Group 4", 5: "LZW", 6: "JPEG (old-style)", 7: "JPEG",
+		8: "Adobe Deflate", 32773: "PackBits", 32946: "Deflate", 34892: "lossy JPEG (DNG)",
+	}},
+	PhotometricInterpretation: {group: Group_IFD0, spec: specTIFF, description: "Color space of the image data", values: map[uint32]string{
+		0: "WhiteIsZero", 1: "BlackIsZero", 2: "RGB", 3: "palette", 4: "transparency mask", 5: "CMYK", 6: "YCbCr", 8: "CIELab",
+		32803: "color filter array", 34892: "linear raw",
+	}},
+	DocumentName:     {group: Group_IFD0, spec: specTIFF, description: "Name of the document the image was scanned from"},
+	ImageDescription: {group: Group_IFD0, spec: specTIFF, description: "Title or description of the image"},
+	Make:             {group: Group_IFD0, spec: specTIFF, description: "Manufacturer of the camera or scanner"},
+	Model:            {group: Group_IFD0, spec: specTIFF, description: "Model of the camera or scanner"},
+	StripOffsets:     {group: Group_IFD0, spec: specTIFF, unit: "bytes", description: "Offset of each strip of the image data"},
+	Orientation: {group: Group_IFD0, spec: specTIFF, description: "Transform to apply to display the image upright", values: map[uint32]string{
+		1: "horizontal (normal)", 2: "mirror horizontal", 3: "rotate 180", 4: "mirror vertical",
+		5: "mirror horizontal and rotate 270 CW", 6: "rotate 90 CW", 7: "mirror horizontal and rotate 90 CW", 8: "rotate 270 CW",
+	}},
+	SamplesPerPixel: {group: Group_IFD0, spec: specTIFF, min: 1, max: 65535, description: "Number of components (samples) of each pixel"},
+	RowsPerStrip:    {group: Group_IFD0, spec: specTIFF, unit: "rows", description: "Number of rows of each strip, except the last one"},
+	StripByteCounts: {group: Group_IFD0, spec: specTIFF, unit: "bytes", description: "Length of each (compressed) strip of the image data"},
+	XResolution:     {group: Group_IFD0, spec: specTIFF, unit: "pixels per ResolutionUnit", description: "Horizontal resolution of the image"},
+	YResolution:     {group: Group_IFD0, spec: specTIFF, unit: "pixels per ResolutionUnit", description: "Vertical resolution of the image"},
+	PlanarConfiguration: {group: Group_IFD0, spec: specTIFF, description: "How the components of each pixel are stored",
+		values: map[uint32]string{1: "chunky (contiguous)", 2: "planar (separate planes)"}},
+	ResolutionUnit: {group: Group_IFD0, spec: specTIFF, description: "Unit of XResolution and YResolution", values: resolutionUnits},
+	Software:       {group: Group_IFD0, spec: specTIFF, description: "Name and version of the software or firmware that created the image"},
+	DateTime:       {group: Group_IFD0, spec: specTIFF, description: "Date and time the file was last modified (YYYY:MM:DD HH:MM:SS)"},
+	Artist:         {group: Group_IFD0, spec: specTIFF, description: "Name of the creator of the image"},
+	HostComputer:   {group: Group_IFD0, spec: specTIFF, description: "Computer or operating system used to create the image"},
+	Predictor: {group: Group_IFD0, spec: specTIFF, description: "Prediction applied to the image data before compression",
+		values: map[uint32]string{1: "none", 2: "horizontal differencing", 3: "floating point"}},
+	ColorMap:       {group: Group_IFD0, spec: specTIFF, description: "Color palette of palette images: red, then green, then blue values"},
+	TileWidth:      {group: Group_IFD0, spec: specTIFF, unit: "pixels", description: "Number of columns of each tile (a multiple of 16)"},
+	TileLength:     {group: Group_IFD0, spec: specTIFF, unit: "pixels", description: "Number of rows of each tile (a multiple of 16)"},
+	TileOffsets:    {group: Group_IFD0, spec: specTIFF, unit: "bytes", description: "Offset of each tile of the image data"},
+	TileByteCounts: {group: Group_IFD0, spec: specTIFF, unit: "bytes", description: "Length of each (compressed) tile of the image data"},
+	SubIFDs: {group: Group_IFD0, spec: "Adobe PageMaker 6.0 TIFF Technical Notes",
+		description: "Offsets of child IFDs, e.g. holding reduced-resolution images or the raw image of DNG files"},
+	InkSet: {group: Group_IFD0, spec: specTIFF, description: "Set of inks of separated (e.g. CMYK) images",
+		values: map[uint32]string{1: "CMYK", 2: "not CMYK"}},
+	ExtraSamples: {group: Group_IFD0, spec: specTIFF, description: "Meaning of the extra components of each pixel",
+		values: map[uint32]string{0: "unspecified", 1: "associated alpha", 2: "unassociated alpha"}},
+	SampleFormat: {group: Group_IFD0, spec: specTIFF, description: "How to interpret the components of each pixel", values: sampleFormats},
+	JPEGTables:   {group: Group_IFD0, spec: "TIFF Technical Note 2", description: "Quantization and Huffman tables shared by all JPEG-compressed strips or tiles"},
+	XMLPacket:    {group: Group_IFD0, spec: "XMP", description: "XMP metadata"},
+	Rating: {group: Group_IFD0, spec: specMicrosoft, min: 0, max: 5,
+		description: "Rating of the image, in stars (0 if unrated), as set by Windows Explorer or Adobe Bridge"},
+	RatingPercent: {group: Group_IFD0, spec: specMicrosoft, unit: "percent", min: 0, max: 100,
+		description: "Rating of the image, in percent: Windows maps 1 to 5 stars to 1, 25, 50, 75 and 99"},
+	Copyright:         {group: Group_IFD0, spec: specTIFF, description: "Copyright notice of the image"},
+	IPTC:              {group: Group_IFD0, spec: "IPTC-NAA", description: "IPTC metadata"},
+	PhotoshopSettings: {group: Group_IFD0, spec: "Photoshop", description: "Photoshop image resources"},
+	Exif:              {group: Group_IFD0, spec: specExif, description: "Offset of the Exif IFD"},
+	GPSInfo:           {group: Group_IFD0, spec: specExif, description: "Offset of the GPS IFD"},
+	XPKeywords: {group: Group_IFD0, spec: specMicrosoft,
+		description: "Keywords of the image, separated by semicolons, in UTF-16 as set by Windows Explorer"},
+
+	ExposureTime: {group: Group_Exif, spec: specExif, unit: "seconds", description: "Exposure time"},
+	FNumber:      {group: Group_Exif, spec: specExif, description: "F-number of the lens (focal length divided by aperture diameter)"},
+	ISO:          {group: Group_Exif, spec: specExif, description: "Sensitivity of the sensor, as an ISO speed rating"},
+	ExifVersion:  {group: Group_Exif, spec: specExif, description: "Version of the Exif standard the file follows, as 4 ASCII digits (e.g. 0232)"},
+	DateTimeOriginal: {group: Group_Exif, spec: specExif,
+		description: "Date and time the image was taken (YYYY:MM:DD HH:MM:SS), in the time zone given by OffsetTimeOriginal"},
+	OffsetTime:         {group: Group_Exif, spec: specExif, description: "Time zone of DateTime, as an offset from UTC (e.g. +02:00)"},
+	OffsetTimeOriginal: {group: Group_Exif, spec: specExif, description: "Time zone of DateTimeOriginal, as an offset from UTC (e.g. +02:00)"},
+	ComponentsConfiguration: {group: Group_Exif, spec: specExif,
+		description: "Order of the components of compressed images (e.g. 1 2 3 0 for Y Cb Cr), 0 meaning none"},
+	ShutterSpeedValue: {group: Group_Exif, spec: specExif, unit: "APEX", description: "Shutter speed, as an APEX value: the exposure time is 2^-ShutterSpeedValue seconds"},
+	ApertureValue:     {group: Group_Exif, spec: specExif, unit: "APEX", description: "Aperture of the lens, as an APEX value: the f-number is 2^(ApertureValue/2)"},
+	BrightnessValue:   {group: Group_Exif, spec: specExif, unit: "APEX", description: "Brightness of the subject, as an APEX value"},
+	ExposureBiasValue: {group: Group_Exif, spec: specExif, unit: "EV", description: "Exposure compensation"},
+	FocalLength:       {group: Group_Exif, spec: specExif, unit: "millimeters", description: "Actual focal length of the lens"},
+	FocalPlaneXResolution: {group: Group_Exif, spec: specExif, unit: "pixels per FocalPlaneResolutionUnit",
+		description: "Number of pixels of the sensor per FocalPlaneResolutionUnit, horizontally"},
+	FocalPlaneYResolution: {group: Group_Exif, spec: specExif, unit: "pixels per FocalPlaneResolutionUnit",
+		description: "Number of pixels of the sensor per FocalPlaneResolutionUnit, vertically"},
+	FocalPlaneResolutionUnit: {group: Group_Exif, spec: specExif, description: "Unit of FocalPlaneXResolution and FocalPlaneYResolution",
+		values: map[uint32]string{1: "none", 2: "inches", 3: "centimeters", 4: "millimeters", 5: "micrometers"}},
+	FocalLengthIn35mmFilm: {group: Group_Exif, spec: specExif, unit: "millimeters",
+		description: "Focal length of the lens giving the same angle of view on 35mm film (0 if unknown)"},
+	ImageUniqueID: {group: Group_Exif, spec: specExif,
+		description: "Identifier assigned uniquely to the image, as 32 hexadecimal digits (a 128-bit number)"},
+	CameraOwnerName:  {group: Group_Exif, spec: specExif, description: "Name of the owner of the camera"},
+	BodySerialNumber: {group: Group_Exif, spec: specExif, description: "Serial number of the body of the camera"},
+	LensSerialNumber: {group: Group_Exif, spec: specExif, description: "Serial number of the lens"},
+	MakerNotes:       {group: Group_Exif, spec: specExif, description: "Vendor-specific data, whose structure depends on the manufacturer"},
+	FlashpixVersion:  {group: Group_Exif, spec: specExif, description: "Version of the Flashpix format supported, as 4 ASCII digits (e.g. 0100)"},
+	ExifImageWidth: {group: Group_Exif, spec: specExif, unit: "pixels",
+		description: "Width of the compressed image, once decoded (e.g. of the JPEG image holding the Exif metadata)"},
+	ExifImageHeight: {group: Group_Exif, spec: specExif, unit: "pixels",
+		description: "Height of the compressed image, once decoded (e.g. of the JPEG image holding the Exif metadata)"},
+
+	GPSVersionID:       {group: Group_GPSInfo, spec: specExif, description: "Version of the GPS IFD (e.g. 2.3.0.0)"},
+	GPSLatitudeRef:     {group: Group_GPSInfo, spec: specExif, description: "Hemisphere of GPSLatitude: N (north) or S (south)"},
+	GPSLatitude:        {group: Group_GPSInfo, spec: specExif, unit: "degrees, minutes, seconds", description: "Latitude, north or south of the equator as given by GPSLatitudeRef"},
+	GPSLongitudeRef:    {group: Group_GPSInfo, spec: specExif, description: "Hemisphere of GPSLongitude: E (east) or W (west)"},
+	GPSLongitude:       {group: Group_GPSInfo, spec: specExif, unit: "degrees, minutes, seconds", description: "Longitude, east or west of the Greenwich meridian as given by GPSLongitudeRef"},
+	GPSSpeedRef:        {group: Group_GPSInfo, spec: specExif, description: "Unit of GPSSpeed: K (km/h), M (mph) or N (knots)"},
+	GPSSpeed:           {group: Group_GPSInfo, spec: specExif, unit: "GPSSpeedRef", description: "Speed of the GPS receiver"},
+	GPSTrackRef:        {group: Group_GPSInfo, spec: specExif, description: "Reference of GPSTrack: " + refNorth},
+	GPSTrack:           {group: Group_GPSInfo, spec: specExif, unit: "degrees", min: 0, max: 359.99, description: "Direction of movement of the GPS receiver"},
+	GPSImgDirectionRef: {group: Group_GPSInfo, spec: specExif, description: "Reference of GPSImgDirection: " + refNorth},
+	GPSImgDirection:    {group: Group_GPSInfo, spec: specExif, unit: "degrees", min: 0, max: 359.99, description: "Direction the camera was pointing to"},
+
+	BlackLevel:         {group: Group_SubIFD, spec: specDNG, description: "Zero light encoding level of the raw samples"},
+	WhiteLevel:         {group: Group_SubIFD, spec: specDNG, description: "Fully saturated encoding level of the raw samples"},
+	ColorMatrix1:       {group: Group_IFD0, spec: specDNG, description: "Matrix converting XYZ values to reference camera native color space values, under the first calibration illuminant"},
+	ColorMatrix2:       {group: Group_IFD0, spec: specDNG, description: "Matrix converting XYZ values to reference camera native color space values, under the second calibration illuminant"},
+	CameraCalibration1: {group: Group_IFD0, spec: specDNG, description: "Matrix converting reference camera native color space values to individual camera values, under the first calibration illuminant"},
+	CameraCalibration2: {group: Group_IFD0, spec: specDNG, description: "Matrix converting reference camera native color space values to individual camera values, under the second calibration illuminant"},
+	AsShotNeutral:      {group: Group_IFD0, spec: specDNG, description: "Selected white balance at the time of capture, as the coordinates of a perfectly neutral color in camera native color space"},
+	BaselineExposure:   {group: Group_IFD0, spec: specDNG, unit: "EV", description: "Exposure compensation to apply to the raw image to get the intended exposure"},
+	CameraSerialNumber: {group: Group_IFD0, spec: specDNG, description: "Serial number of the camera"},
+	DNGPrivateData:     {group: Group_IFD0, spec: specDNG, description: "Private data of the software that created the file, usually a copy of the maker note of the original raw file"},
+	OriginalRawFileName: {group: Group_IFD0, spec: specDNG,
+		description: "Name of the raw file the DNG file was converted from"},
+	OpcodeList1: {group: Group_SubIFD, spec: specDNG, description: "Opcodes to apply to the raw image, as read from the file"},
+	OpcodeList2: {group: Group_SubIFD, spec: specDNG, description: "Opcodes to apply to the raw image, after mapping it to linear values"},
+	OpcodeList3: {group: Group_SubIFD, spec: specDNG, description: "Opcodes to apply to the raw image, after demosaicing"},
+
+	GDALMetadata: {group: Group_IFD0, spec: specGDAL, description: "Metadata of the dataset and of its bands (e.g. statistics, scale and offset), as an XML document"},
+	GDALNoData:   {group: Group_IFD0, spec: specGDAL, description: "Value of the samples holding no data, as text"},
+
+	ThumbnailOffset: {group: Group_IFD1, spec: specExif, unit: "bytes", description: "Offset of the JPEG thumbnail (aka JPEGInterchangeFormat, or PreviewImageStart in IFD#0)"},
+	ThumbnailLength: {group: Group_IFD1, spec: specExif, unit: "bytes", description: "Length of the JPEG thumbnail (aka JPEGInterchangeFormatLength, or PreviewImageLength in IFD#0)"},
+
+	YCbCrCoefficients: {group: Group_IFD0, spec: specTIFF, description: "Coefficients converting RGB values to YCbCr values"},
+	YCbCrSubSampling:  {group: Group_IFD0, spec: specTIFF, description: "Subsampling factors of the chroma components, horizontally and vertically"},
+	YCbCrPositioning: {group: Group_IFD0, spec: specTIFF, description: "Position of the chroma components relatively to the luma components",
+		values: map[uint32]string{1: "centered", 2: "co-sited"}},
+	ReferenceBlackWhite: {group: Group_IFD0, spec: specTIFF, description: "Reference black and white values of each component"},
+}
+
+// Describe returns the documentation of a known entry, or false if the entry is unknown.
+func Describe(id EntryID) (TagInfo, bool) {
+	doc, ok := docs[id]
+	if !ok {
+		return TagInfo{}, false
+	}
+
+	info := TagInfo{
+		ID:          id,
+		Name:        id.Name(),
+		Group:       doc.group,
+		Description: doc.description,
+		Unit:        doc.unit,
+		Values:      doc.values,
+		Min:         doc.min,
+		Max:         doc.max,
+		Spec:        doc.spec,
+	}
+	if spec, ok := typeSpecs[id]; ok {
+		info.Types, info.Count = spec.types, spec.count
+	}
+
+	return info, true
+}
+
+// Tags returns the documentation of all known entries, sorted by group, then by ID.
+func Tags() []TagInfo {
+	res := make([]TagInfo, 0, len(docs))
+	for id := range docs {
+		info, _ := Describe(id)
+		res = append(res, info)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Group != res[j].Group {
+			return res[i].Group < res[j].Group
+		}
+		return res[i].ID < res[j].ID
+	})
+
+	return res
+}
@@ -0,0 +1,57 @@
+package tiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribe(t *testing.T) {
+	info, ok := Describe(Orientation)
+	assert.True(t, ok)
+	assert.Equal(t, "Orientation", info.Name)
+	assert.Equal(t, Group_IFD0, info.Group)
+	assert.Equal(t, "TIFF 6.0", info.Spec)
+	assert.Equal(t, []DataType{DataType_UShort}, info.Types)
+	assert.Equal(t, uint32(1), info.Count)
+	assert.Equal(t, "rotate 90 CW", info.Values[6])
+
+	_, ok = Describe(EntryID(0xfffe))
+	assert.False(t, ok)
+}
+
+func TestDescribe_AllKnownEntries(t *testing.T) {
+	for id := range names {
+		info, ok := Describe(id)
+		if assert.True(t, ok, "%s is not documented", id.Name()) {
+			assert.NotEmpty(t, info.Description, id.Name())
+			assert.NotEmpty(t, info.Spec, id.Name())
+		}
+	}
+	assert.Len(t, Tags(), len(docs))
+}
+
+func TestTagInfo_Allows(t *testing.T) {
+	orientation, _ := Describe(Orientation)
+	track, _ := Describe(GPSTrack)
+	width, _ := Describe(ImageWidth)
+
+	cases := []struct {
+		name  string
+		info  TagInfo
+		value float64
+		want  bool
+	}{
+		{"allowed value", orientation, 8, true},
+		{"unknown value", orientation, 9, false},
+		{"fractional value", orientation, 1.5, false},
+		{"within range", track, 181.5, true},
+		{"out of range", track, 360, false},
+		{"unconstrained", width, 12345, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, c.info.Allows(c.value))
+		})
+	}
+}
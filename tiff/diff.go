@@ -0,0 +1,88 @@
+package tiff
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// ChangeKind tells how an entry changed between two parses of a file (see Diff).
+type ChangeKind int
+
+const (
+	ChangeKind_Added ChangeKind = iota
+	ChangeKind_Removed
+	ChangeKind_Modified
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeKind_Added:
+		return "added"
+	case ChangeKind_Removed:
+		return "removed"
+	case ChangeKind_Modified:
+		return "modified"
+	default:
+		return fmt.Sprintf("ChangeKind(%d)", int(k))
+	}
+}
+
+// Change is a change of an entry between two parses of a file.
+type Change struct {
+	Kind   ChangeKind
+	ID     EntryID
+	Before Entry // zero if the entry was added
+	After  Entry // zero if the entry was removed
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeKind_Added:
+		return fmt.Sprintf("%s added: %v", c.ID.Name(), c.After.Value.Interface())
+	case ChangeKind_Removed:
+		return fmt.Sprintf("%s removed: %v", c.ID.Name(), c.Before.Value.Interface())
+	default:
+		return fmt.Sprintf("%s modified: %v -> %v", c.ID.Name(), c.Before.Value.Interface(), c.After.Value.Interface())
+	}
+}
+
+// Diff compares the entries of two parses of a file (e.g. before and after it was edited), returning the changes that
+// turn before into after, by increasing ID: entries that were added, removed, or whose value, value kind or group
+// changed. Entries that only moved within the file (i.e. whose offsets changed) are left out, as CanonicalBytes does,
+// so that watchers only react to actual edits of metadata.
+func Diff(before, after map[EntryID]Entry) []Change {
+	ids := slices.Sorted(maps.Keys(before))
+	for id := range after {
+		if _, ok := before[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	var res []Change
+	for _, id := range ids {
+		b, inBefore := before[id]
+		a, inAfter := after[id]
+		switch {
+		case !inBefore:
+			res = append(res, Change{Kind: ChangeKind_Added, ID: id, After: a})
+		case !inAfter:
+			res = append(res, Change{Kind: ChangeKind_Removed, ID: id, Before: b})
+		case !sameEntry(b, a):
+			res = append(res, Change{Kind: ChangeKind_Modified, ID: id, Before: b, After: a})
+		}
+	}
+
+	return res
+}
+
+// sameEntry tells whether two entries have the same canonical encoding.
+func sameEntry(a, b Entry) bool {
+	var ba, bb bytes.Buffer
+	appendCanonical(&ba, a)
+	appendCanonical(&bb, b)
+
+	return bytes.Equal(ba.Bytes(), bb.Bytes())
+}
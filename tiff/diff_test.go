@@ -0,0 +1,63 @@
+package tiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	width, height, newWidth := uint16(5184), uint16(3456), uint32(5184)
+	make_, model, newModel := "Canon", "Canon EOS 7D", "Canon EOS 7D Mark II"
+	before := map[EntryID]Entry{
+		Make:        {ID: Make, Group: Group_IFD0, RawValue: 200, Value: EntryValue{String: &make_}},
+		Model:       {ID: Model, Group: Group_IFD0, RawValue: 210, Value: EntryValue{String: &model}},
+		ImageWidth:  {ID: ImageWidth, Group: Group_IFD0, Value: EntryValue{Uint16: &width}},
+		ImageHeight: {ID: ImageHeight, Group: Group_IFD0, Value: EntryValue{Uint16: &height}},
+	}
+	after := map[EntryID]Entry{
+		Make:       {ID: Make, Group: Group_IFD0, RawValue: 300, Value: EntryValue{String: &make_}},
+		Model:      {ID: Model, Group: Group_IFD0, RawValue: 310, Value: EntryValue{String: &newModel}},
+		ImageWidth: {ID: ImageWidth, Group: Group_IFD0, Value: EntryValue{Uint32: &newWidth}},
+		Artist:     {ID: Artist, Group: Group_IFD0, RawValue: 330, Value: EntryValue{String: &make_}},
+	}
+
+	changes := Diff(before, after)
+	assert.Equal(t, []Change{
+		{Kind: ChangeKind_Modified, ID: ImageWidth, Before: before[ImageWidth], After: after[ImageWidth]},
+		{Kind: ChangeKind_Removed, ID: ImageHeight, Before: before[ImageHeight]},
+		{Kind: ChangeKind_Modified, ID: Model, Before: before[Model], After: after[Model]},
+		{Kind: ChangeKind_Added, ID: Artist, After: after[Artist]},
+	}, changes)
+	assert.Equal(t, "Model modified: Canon EOS 7D -> Canon EOS 7D Mark II", changes[2].String())
+	assert.Equal(t, "Artist added: Canon", changes[3].String())
+
+	assert.Empty(t, Diff(before, before))
+	assert.Empty(t, Diff(nil, nil))
+}
+
+func TestDiff_CR2(t *testing.T) {
+	ids := []EntryID{Make, Model, ExposureTime, BitsPerSample, DateTimeOriginal}
+	parse := func() map[EntryID]Entry {
+		p, err := NewParser(bytes.NewReader(cr2Image))
+		assert.NoError(t, err)
+		entries, err := p.Parse(ids...)
+		assert.NoError(t, err)
+		return entries
+	}
+
+	before, after := parse(), parse()
+	assert.Empty(t, Diff(before, after))
+
+	model := "Canon EOS 7D Mark II"
+	edited := after[Model]
+	edited.Value = EntryValue{String: &model}
+	after[Model] = edited
+	delete(after, ExposureTime)
+
+	changes := Diff(before, after)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, Change{Kind: ChangeKind_Modified, ID: Model, Before: before[Model], After: edited}, changes[0])
+	assert.Equal(t, Change{Kind: ChangeKind_Removed, ID: ExposureTime, Before: before[ExposureTime]}, changes[1])
+}
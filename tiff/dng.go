@@ -0,0 +1,99 @@
+package tiff
+
+import (
+	"fmt"
+	"math"
+)
+
+// Matrix represents a matrix of float64 values, stored in row-major order (as DNG does).
+type Matrix struct {
+	Rows   int
+	Cols   int
+	Values []float64
+}
+
+// At returns the value at the given row and column.
+func (m Matrix) At(row, col int) float64 {
+	return m.Values[row*m.Cols+col]
+}
+
+// ReadColorMatrix reads a DNG color matrix (ColorMatrix1 or ColorMatrix2), which maps XYZ values to reference camera
+// native color space values: it has one row per color plane and 3 columns.
+func ReadColorMatrix(entries map[EntryID]Entry, id EntryID) (Matrix, error) {
+	values, err := readFloats(entries, id)
+	if err != nil {
+		return Matrix{}, err
+	}
+	if len(values) == 0 || len(values)%3 != 0 {
+		return Matrix{}, fmt.Errorf("entry 0x%X: expected a multiple of 3 values, got %d", id, len(values))
+	}
+
+	return Matrix{Rows: len(values) / 3, Cols: 3, Values: values}, nil
+}
+
+// ReadCameraCalibration reads a DNG camera calibration matrix (CameraCalibration1 or CameraCalibration2), which is a
+// square matrix with one row and one column per color plane.
+func ReadCameraCalibration(entries map[EntryID]Entry, id EntryID) (Matrix, error) {
+	values, err := readFloats(entries, id)
+	if err != nil {
+		return Matrix{}, err
+	}
+	n := int(math.Sqrt(float64(len(values))))
+	if n == 0 || n*n != len(values) {
+		return Matrix{}, fmt.Errorf("entry 0x%X: expected a square matrix, got %d values", id, len(values))
+	}
+
+	return Matrix{Rows: n, Cols: n, Values: values}, nil
+}
+
+// ReadAsShotNeutral reads the selected white balance at time of capture, encoded as the coordinates of a perfectly
+// neutral color in linear reference space values (one per color plane).
+func ReadAsShotNeutral(entries map[EntryID]Entry) ([]float64, error) {
+	return readFloats(entries, AsShotNeutral)
+}
+
+// ReadBaselineExposure reads the amount (in EV units) by which to move the zero point of the exposure compensation.
+func ReadBaselineExposure(entries map[EntryID]Entry) (float64, error) {
+	values, err := readFloats(entries, BaselineExposure)
+	if err != nil {
+		return 0, err
+	}
+	if len(values) != 1 {
+		return 0, fmt.Errorf("entry 0x%X: expected 1 value, got %d", BaselineExposure, len(values))
+	}
+
+	return values[0], nil
+}
+
+// ReadWhiteLevel reads the fully saturated encoding level for the raw samples (one per sample).
+func ReadWhiteLevel(entries map[EntryID]Entry) ([]float64, error) {
+	return readFloats(entries, WhiteLevel)
+}
+
+// ReadBlackLevel reads the zero light encoding level(s) for the raw samples, in the order given by BlackLevelRepeatDim.
+func ReadBlackLevel(entries map[EntryID]Entry) ([]float64, error) {
+	return readFloats(entries, BlackLevel)
+}
+
+// readFloats looks up an entry and returns its numeric value(s) as float64.
+func readFloats(entries map[EntryID]Entry, id EntryID) ([]float64, error) {
+	entry, ok := entries[id]
+	if !ok {
+		return nil, fmt.Errorf("entry 0x%X not found", id)
+	}
+
+	return entry.Floats()
+}
+
+// readFloat reads the value of an entry holding a single, finite number.
+func readFloat(entries map[EntryID]Entry, id EntryID) (float64, error) {
+	values, err := readFloats(entries, id)
+	if err != nil {
+		return 0, err
+	}
+	if len(values) != 1 || math.IsNaN(values[0]) || math.IsInf(values[0], 0) {
+		return 0, fmt.Errorf("invalid %s %v", id.Name(), values)
+	}
+
+	return values[0], nil
+}
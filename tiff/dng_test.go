@@ -0,0 +1,93 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_DNGColorCalibration(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	// like DNG files, IFD#0 describes a preview and the raw image is described by a SubIFD
+	ifd0 := b.AddIFD().
+		WithUints32(uint16(NewSubfileType), 1).
+		WithRationals(uint16(ColorMatrix1), 9, 10, -2, 10, 0, 1, -4, 10, 12, 10, 2, 10, 0, 1, 1, 10, 5, 10).
+		WithRationals(uint16(CameraCalibration1), 1, 1, 0, 1, 0, 1, 0, 1, 1, 1, 0, 1, 0, 1, 0, 1, 1, 1).
+		WithURationals(uint16(AsShotNeutral), 1, 2, 1, 1, 3, 4).
+		WithRationals(uint16(BaselineExposure), -1, 4)
+	ifd0.WithSubIFD(uint16(SubIFDs)).
+		WithUints32(uint16(NewSubfileType), 0).
+		WithUints16(uint16(BlackLevel), 256).
+		WithFloats32(uint16(WhiteLevel), 4095, 4095)
+
+	p, err := NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	entries, err := p.Parse(ColorMatrix1, CameraCalibration1, AsShotNeutral, BaselineExposure, BlackLevel, WhiteLevel)
+	assert.NoError(t, err)
+
+	cm, err := ReadColorMatrix(entries, ColorMatrix1)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, cm.Rows)
+	assert.Equal(t, 3, cm.Cols)
+	assert.InDelta(t, 0.9, cm.At(0, 0), 1e-9)
+	assert.InDelta(t, -0.4, cm.At(1, 0), 1e-9)
+	assert.InDelta(t, 0.5, cm.At(2, 2), 1e-9)
+
+	cc, err := ReadCameraCalibration(entries, CameraCalibration1)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, cc.Rows)
+	assert.Equal(t, []float64{1, 0, 0, 0, 1, 0, 0, 0, 1}, cc.Values)
+
+	neutral, err := ReadAsShotNeutral(entries)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0.5, 1, 0.75}, neutral)
+
+	exposure, err := ReadBaselineExposure(entries)
+	assert.NoError(t, err)
+	assert.Equal(t, -0.25, exposure)
+
+	black, err := ReadBlackLevel(entries)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{256}, black)
+
+	white, err := ReadWhiteLevel(entries)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{4095, 4095}, white)
+
+	_, err = ReadColorMatrix(entries, ColorMatrix2)
+	assert.Error(t, err)
+}
+
+func TestParse_DNGRawEntriesWithoutSubIFD(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().WithUints16(uint16(BlackLevel), 256)
+
+	p, err := NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	entries, err := p.Parse(BlackLevel, WhiteLevel)
+	assert.NoError(t, err)
+	black, err := ReadBlackLevel(entries)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{256}, black)
+	assert.NotContains(t, entries, WhiteLevel)
+}
+
+func TestParser_readFloats64(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.BigEndian)
+	b.AddIFD().WithFloats64(uint16(BaselineExposure), 1.5)
+
+	p, err := NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	entries, err := p.Parse(BaselineExposure)
+	assert.NoError(t, err)
+
+	value := entries[BaselineExposure].Value.Float64
+	assert.NotNil(t, value)
+	assert.Equal(t, 1.5, *value)
+}
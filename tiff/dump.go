@@ -0,0 +1,129 @@
+package tiff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxDumpSize is the number of bytes of a value DumpEntry prints at most.
+const maxDumpSize = 256
+
+// dataTypeNames maps data types to their name in the TIFF, Exif and BigTIFF specifications.
+var dataTypeNames = map[DataType]string{
+	DataType_UByte:          "BYTE",
+	DataType_String:         "ASCII",
+	DataType_UShort:         "SHORT",
+	DataType_ULong:          "LONG",
+	DataType_URational:      "RATIONAL",
+	DataType_Byte:           "SBYTE",
+	DataType_UByte_Sequence: "UNDEFINED",
+	DataType_Short:          "SSHORT",
+	DataType_Long:           "SLONG",
+	DataType_Rational:       "SRATIONAL",
+	DataType_Float:          "FLOAT",
+	DataType_Double:         "DOUBLE",
+	DataType_IFD:            "IFD",
+	DataType_ULong8:         "LONG8",
+	DataType_Long8:          "SLONG8",
+	DataType_IFD8:           "IFD8",
+	DataType_UTF8:           "UTF-8",
+}
+
+// DumpEntry prints an entry for debugging: the 12 bytes it is made of, as written in the file, an annotated breakdown
+// of its ID, data type, count and value (or offset of its value) and, when its value does not fit in the entry, a
+// hexdump of the bytes of the file holding it (up to 256 bytes).
+func (p *Parser) DumpEntry(w io.Writer, e Entry) error {
+	raw := make([]byte, EntryLength)
+	p.byteOrder.PutUint16(raw, uint16(e.ID))
+	p.byteOrder.PutUint16(raw[2:], uint16(e.DataType))
+	p.byteOrder.PutUint32(raw[4:], e.Length)
+	p.byteOrder.PutUint32(raw[8:], e.RawValue)
+
+	typeName, ok := dataTypeNames[e.DataType]
+	if !ok {
+		typeName = "unknown"
+	}
+	size := int64(e.DataType.Size()) * int64(e.Length)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "entry 0x%04x (%s) of the %s IFD", uint16(e.ID), e.ID.Name(), e.Group)
+	if e.IFDOffset != 0 {
+		fmt.Fprintf(&b, " at offset %d", e.IFDOffset)
+	}
+	fmt.Fprintf(&b, "\n  raw    % x\n", raw)
+	fmt.Fprintf(&b, "  id     % x  0x%04x\n", raw[:2], uint16(e.ID))
+	fmt.Fprintf(&b, "  type   % x  %d (%s, %d bytes per value)\n", raw[2:4], e.DataType, typeName, e.DataType.Size())
+	fmt.Fprintf(&b, "  count  % x  %d (%d bytes)\n", raw[4:8], e.Length, size)
+	inline := size <= 4
+	if inline {
+		fmt.Fprintf(&b, "  value  % x  inline\n", raw[8:])
+	} else {
+		fmt.Fprintf(&b, "  offset % x  %d\n", raw[8:], e.RawValue)
+	}
+	if value := e.Value.Interface(); value != nil {
+		fmt.Fprintf(&b, "  decoded %s\n", truncate(fmt.Sprintf("%v", value), 80))
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return err
+	}
+	if inline || e.DataType.Size() == 0 {
+		return nil
+	}
+
+	data, err := p.readChunk(e.RawValue, uint32(min(size, maxDumpSize)))
+	if err != nil {
+		return fmt.Errorf("cannot read value at offset %d: %w", e.RawValue, err)
+	}
+	if err := hexdump(w, int64(e.RawValue), data); err != nil {
+		return err
+	}
+	if size > maxDumpSize {
+		_, err = fmt.Fprintf(w, "  ... %d more bytes\n", size-maxDumpSize)
+	}
+
+	return err
+}
+
+// hexdump prints data like `hexdump -C` does, 16 bytes per line, each line starting with its offset in the file.
+func hexdump(w io.Writer, offset int64, data []byte) error {
+	for start := 0; start < len(data); start += 16 {
+		line := data[start:min(start+16, len(data))]
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "  %08x ", offset+int64(start))
+		for i := 0; i < 16; i++ {
+			if i == 8 {
+				b.WriteByte(' ')
+			}
+			if i < len(line) {
+				fmt.Fprintf(&b, " %02x", line[i])
+			} else {
+				b.WriteString("   ")
+			}
+		}
+		b.WriteString("  |")
+		for _, c := range line {
+			if c < 0x20 || c > 0x7e {
+				c = '.'
+			}
+			b.WriteByte(c)
+		}
+		b.WriteString("|\n")
+
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+
+	return s
+}
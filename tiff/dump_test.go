@@ -0,0 +1,58 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_DumpEntry(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().
+		WithUints16(uint16(Orientation), 6).
+		WithString(uint16(Model), "Canon EOS 7D")
+	p, err := NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+	entries, err := p.Parse(Orientation, Model)
+	assert.NoError(t, err)
+
+	var out bytes.Buffer
+	assert.NoError(t, p.DumpEntry(&out, entries[Orientation]))
+	assert.Equal(t, strings.Join([]string{
+		"entry 0x0112 (Orientation) of the IFD0 IFD at offset 8",
+		"  raw    12 01 03 00 01 00 00 00 06 00 00 00",
+		"  id     12 01  0x0112",
+		"  type   03 00  3 (SHORT, 2 bytes per value)",
+		"  count  01 00 00 00  1 (2 bytes)",
+		"  value  06 00 00 00  inline",
+		"  decoded 6",
+		"",
+	}, "\n"), out.String())
+
+	out.Reset()
+	model := entries[Model]
+	assert.NoError(t, p.DumpEntry(&out, model))
+	lines := strings.Split(out.String(), "\n")
+	assert.Equal(t, "  type   02 00  2 (ASCII, 1 bytes per value)", lines[3])
+	assert.Equal(t, "  count  0d 00 00 00  13 (13 bytes)", lines[4])
+	assert.Contains(t, lines[5], "  offset ")
+	assert.Equal(t, "  decoded Canon EOS 7D", lines[6])
+	assert.Regexp(t, `^  [0-9a-f]{8}  43 61 6e 6f 6e 20 45 4f  53 20 37 44 00 +\|Canon EOS 7D\.\|$`, lines[7])
+}
+
+func TestParser_DumpEntry_LongValue(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	entries, err := p.Parse(MakerNotes)
+	assert.NoError(t, err)
+
+	var out bytes.Buffer
+	assert.NoError(t, p.DumpEntry(&out, entries[MakerNotes]))
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	assert.Len(t, lines, 7+maxDumpSize/16+1)
+	assert.Regexp(t, `^  \.\.\. \d+ more bytes$`, lines[len(lines)-1])
+}
@@ -0,0 +1,68 @@
+package tiff
+
+import "slices"
+
+// DuplicatePolicy tells which entries ParseAll returns when an entry is found in several IFDs (e.g. ImageWidth in IFD#0
+// and in a SubIFD).
+type DuplicatePolicy int
+
+const (
+	DuplicatePolicy_PreferFirst      DuplicatePolicy = iota // the first entry found, in the order IFDs are visited by Find
+	DuplicatePolicy_PreferGroupOrder                        // the entry of the group that comes first in the order given to WithDuplicatePolicy
+	DuplicatePolicy_CollectAll                              // all entries, in the order IFDs are visited by Find
+)
+
+// WithDuplicatePolicy sets the policy ParseAll follows when an entry is found in several IFDs (by default,
+// DuplicatePolicy_PreferFirst). With DuplicatePolicy_PreferGroupOrder, groups are preferred in the given order: groups
+// that are not listed come last.
+func (p *Parser) WithDuplicatePolicy(policy DuplicatePolicy, groupOrder ...Group) *Parser {
+	p.duplicatePolicy = policy
+	p.groupOrder = groupOrder
+
+	return p
+}
+
+// ParseAll looks for the entries matching the given IDs in all IFDs (main chain, Exif, GPSInfo and SubIFDs), whatever
+// group they are mapped to, returning the entries found for each ID according to the duplicate policy of the parser:
+// only one entry per ID, unless the policy is DuplicatePolicy_CollectAll. The Group and IFDOffset of entries tell where
+// they were found.
+func (p *Parser) ParseAll(ids ...EntryID) (map[EntryID][]Entry, error) {
+	wanted := newWanted(ids...)
+
+	found, err := p.Find(func(e Entry) bool { return wanted.Contains(e.ID) })
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[EntryID][]Entry)
+	for _, entry := range found {
+		res[entry.ID] = append(res[entry.ID], entry)
+	}
+
+	for id, entries := range res {
+		switch p.duplicatePolicy {
+		case DuplicatePolicy_PreferFirst:
+			res[id] = entries[:1]
+		case DuplicatePolicy_PreferGroupOrder:
+			best := 0
+			for i, entry := range entries {
+				if p.groupRank(entry.Group) < p.groupRank(entries[best].Group) {
+					best = i
+				}
+			}
+			res[id] = entries[best : best+1]
+		}
+	}
+
+	return res, nil
+}
+
+// groupRank returns the position of a group in the group order of the parser (after all listed groups if it is not
+// listed).
+func (p *Parser) groupRank(group Group) int {
+	if i := slices.Index(p.groupOrder, group); i >= 0 {
+		return i
+	}
+
+	return len(p.groupOrder)
+}
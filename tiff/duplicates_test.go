@@ -0,0 +1,63 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAll(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	ifd := b.AddIFD().WithUints32(uint16(ImageWidth), 256).WithString(uint16(Make), "Canon")
+	ifd.WithSubIFD(uint16(SubIFDs)).WithUints32(uint16(ImageWidth), 4096)
+	b.AddIFD().WithUints32(uint16(ImageWidth), 160)
+
+	widths := func(entries []Entry) []uint32 {
+		var res []uint32
+		for _, entry := range entries {
+			res = append(res, *entry.Value.Uint32)
+		}
+		return res
+	}
+
+	tests := []struct {
+		name       string
+		policy     DuplicatePolicy
+		groupOrder []Group
+		want       []uint32
+	}{
+		{"prefer first", DuplicatePolicy_PreferFirst, nil, []uint32{256}},
+		{"prefer group order", DuplicatePolicy_PreferGroupOrder, []Group{Group_SubIFD, Group_IFD0}, []uint32{4096}},
+		{"prefer group order, unlisted groups", DuplicatePolicy_PreferGroupOrder, []Group{Group_IFD1}, []uint32{160}},
+		{"collect all", DuplicatePolicy_CollectAll, nil, []uint32{256, 4096, 160}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(bytes.NewReader(b.Bytes()))
+			assert.NoError(t, err)
+
+			entries, err := p.WithDuplicatePolicy(tt.policy, tt.groupOrder...).ParseAll(ImageWidth, Make, Model)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, widths(entries[ImageWidth]))
+			assert.Len(t, entries[Make], 1)
+			assert.Equal(t, Group_IFD0, entries[Make][0].Group)
+			assert.NotContains(t, entries, Model)
+		})
+	}
+}
+
+func TestParseAll_CR2(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+
+	entries, err := p.WithDuplicatePolicy(DuplicatePolicy_CollectAll).ParseAll(StripOffsets)
+	assert.NoError(t, err)
+	assert.Len(t, entries[StripOffsets], 3)
+	for _, entry := range entries[StripOffsets] {
+		assert.NotZero(t, entry.IFDOffset)
+	}
+}
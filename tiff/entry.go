@@ -2,6 +2,7 @@ package tiff
 
 import (
 	"fmt"
+	"math"
 )
 
 type EntryID uint16
@@ -13,33 +14,125 @@ const (
 
 	// IFD #0
 
-	ImageWidth    EntryID = 0x100
-	ImageHeight   EntryID = 0x101
-	BitsPerSample EntryID = 0x102
-	Compression   EntryID = 0x103
-	Make          EntryID = 0x10f
-	Model         EntryID = 0x110
-	Exif          EntryID = 0x8769
-	GPSInfo       EntryID = 0x8825
+	NewSubfileType            EntryID = 0xfe
+	ImageWidth                EntryID = 0x100
+	ImageHeight               EntryID = 0x101
+	BitsPerSample             EntryID = 0x102
+	Compression               EntryID = 0x103
+	PhotometricInterpretation EntryID = 0x106
+	DocumentName              EntryID = 0x10d
+	ImageDescription          EntryID = 0x10e
+	Make                      EntryID = 0x10f
+	Model                     EntryID = 0x110
+	StripOffsets              EntryID = 0x111
+	Orientation               EntryID = 0x112
+	SamplesPerPixel           EntryID = 0x115
+	RowsPerStrip              EntryID = 0x116
+	StripByteCounts           EntryID = 0x117
+	XResolution               EntryID = 0x11a
+	YResolution               EntryID = 0x11b
+	PlanarConfiguration       EntryID = 0x11c
+	ResolutionUnit            EntryID = 0x128
+	Software                  EntryID = 0x131
+	DateTime                  EntryID = 0x132
+	Artist                    EntryID = 0x13b
+	HostComputer              EntryID = 0x13c
+	Predictor                 EntryID = 0x13d
+	ColorMap                  EntryID = 0x140
+	TileWidth                 EntryID = 0x142
+	TileLength                EntryID = 0x143
+	TileOffsets               EntryID = 0x144
+	TileByteCounts            EntryID = 0x145
+	SubIFDs                   EntryID = 0x14a
+	InkSet                    EntryID = 0x14c
+	ExtraSamples              EntryID = 0x152
+	SampleFormat              EntryID = 0x153
+	JPEGTables                EntryID = 0x15b
+	XMLPacket                 EntryID = 0x2bc
+	Rating                    EntryID = 0x4746 // Microsoft
+	RatingPercent             EntryID = 0x4749 // Microsoft
+	Copyright                 EntryID = 0x8298
+	IPTC                      EntryID = 0x83bb
+	PhotoshopSettings         EntryID = 0x8649
+	Exif                      EntryID = 0x8769
+	GPSInfo                   EntryID = 0x8825
+	XPKeywords                EntryID = 0x9c9e // Microsoft
 
 	// Exif sub-IFD
 
-	ExposureTime       EntryID = 0x829a
-	FNumber            EntryID = 0x829d
-	ISO                EntryID = 0x8827
-	DateTimeOriginal   EntryID = 0x9003
-	OffsetTimeOriginal EntryID = 0x9011
-	MakerNotes         EntryID = 0x927c
+	ExposureTime             EntryID = 0x829a
+	FNumber                  EntryID = 0x829d
+	ISO                      EntryID = 0x8827
+	ExifVersion              EntryID = 0x9000
+	DateTimeOriginal         EntryID = 0x9003
+	OffsetTime               EntryID = 0x9010
+	OffsetTimeOriginal       EntryID = 0x9011
+	ComponentsConfiguration  EntryID = 0x9101
+	ShutterSpeedValue        EntryID = 0x9201 // APEX, see ReadExposureTime
+	ApertureValue            EntryID = 0x9202 // APEX, see ReadFNumber
+	BrightnessValue          EntryID = 0x9203 // APEX
+	ExposureBiasValue        EntryID = 0x9204 // APEX
+	FocalLength              EntryID = 0x920a
+	MakerNotes               EntryID = 0x927c
+	FlashpixVersion          EntryID = 0xa000
+	ExifImageWidth           EntryID = 0xa002 // aka PixelXDimension, see ReadImageSize
+	ExifImageHeight          EntryID = 0xa003 // aka PixelYDimension, see ReadImageSize
+	FocalPlaneXResolution    EntryID = 0xa20e
+	FocalPlaneYResolution    EntryID = 0xa20f
+	FocalPlaneResolutionUnit EntryID = 0xa210
+	FocalLengthIn35mmFilm    EntryID = 0xa405
+	ImageUniqueID            EntryID = 0xa420
+	CameraOwnerName          EntryID = 0xa430
+	BodySerialNumber         EntryID = 0xa431
+	LensSerialNumber         EntryID = 0xa435
 
 	// GPSInfo sub-IFD
 
-	GPSLatitude  EntryID = 0x0002
-	GPSLongitude EntryID = 0x0004
+	GPSVersionID       EntryID = 0x0000
+	GPSLatitudeRef     EntryID = 0x0001
+	GPSLatitude        EntryID = 0x0002
+	GPSLongitudeRef    EntryID = 0x0003
+	GPSLongitude       EntryID = 0x0004
+	GPSSpeedRef        EntryID = 0x000c
+	GPSSpeed           EntryID = 0x000d
+	GPSTrackRef        EntryID = 0x000e
+	GPSTrack           EntryID = 0x000f
+	GPSImgDirectionRef EntryID = 0x0010
+	GPSImgDirection    EntryID = 0x0011
+
+	// DNG
+
+	BlackLevel          EntryID = 0xc61a // in the raw IFD, usually a SubIFD of IFD #0
+	WhiteLevel          EntryID = 0xc61d // in the raw IFD, usually a SubIFD of IFD #0
+	ColorMatrix1        EntryID = 0xc621
+	ColorMatrix2        EntryID = 0xc622
+	CameraCalibration1  EntryID = 0xc623
+	CameraCalibration2  EntryID = 0xc624
+	AsShotNeutral       EntryID = 0xc628
+	BaselineExposure    EntryID = 0xc62a
+	CameraSerialNumber  EntryID = 0xc62f
+	DNGPrivateData      EntryID = 0xc634 // usually holds a copy of the maker note of the original raw file
+	OriginalRawFileName EntryID = 0xc68b
+	OpcodeList1         EntryID = 0xc740 // in the raw IFD, usually a SubIFD of IFD #0
+	OpcodeList2         EntryID = 0xc741 // in the raw IFD, usually a SubIFD of IFD #0
+	OpcodeList3         EntryID = 0xc74e // in the raw IFD, usually a SubIFD of IFD #0
+
+	// GDAL (any page of GeoTIFF files)
+
+	GDALMetadata EntryID = 0xa480 // see Page.GDAL
+	GDALNoData   EntryID = 0xa481 // see Page.GDAL
 
 	// Position depends on actual format
 
 	ThumbnailOffset EntryID = 0x0201 // in IFD #1 (PreviewImageStart if in IFD #0)
 	ThumbnailLength EntryID = 0x0202 // in IFD #1 (PreviewImageLength if in IFD #0)
+
+	// YCbCr images (any IFD)
+
+	YCbCrCoefficients   EntryID = 0x0211
+	YCbCrSubSampling    EntryID = 0x0212
+	YCbCrPositioning    EntryID = 0x0213
+	ReferenceBlackWhite EntryID = 0x0214
 )
 
 const (
@@ -53,32 +146,94 @@ const (
 	DataType_Short
 	DataType_Long
 	DataType_Rational
+	DataType_Float
+	DataType_Double
+	DataType_IFD
+
+	// BigTIFF only
+
+	DataType_ULong8 DataType = 16
+	DataType_Long8  DataType = 17
+	DataType_IFD8   DataType = 18
+
+	// Exif 3.0
+
+	DataType_UTF8 DataType = 129 // like DataType_String, but encoded in UTF-8 rather than ASCII
 )
 
+// dataTypeSizes maps the known data types to the size in bytes of each of their values.
+var dataTypeSizes = map[DataType]int{
+	DataType_UByte:          1,
+	DataType_String:         1,
+	DataType_UShort:         2,
+	DataType_ULong:          4,
+	DataType_URational:      8,
+	DataType_Byte:           1,
+	DataType_UByte_Sequence: 1,
+	DataType_Short:          2,
+	DataType_Long:           4,
+	DataType_Rational:       8,
+	DataType_Float:          4,
+	DataType_Double:         8,
+	DataType_IFD:            4,
+	DataType_ULong8:         8,
+	DataType_Long8:          8,
+	DataType_IFD8:           8,
+	DataType_UTF8:           1,
+}
+
+// Size returns the size in bytes of a value of the data type, or 0 if the data type is unknown.
+func (dt DataType) Size() int {
+	return dataTypeSizes[dt]
+}
+
 type URational struct {
 	Numerator   uint32
 	Denominator uint32
 }
 
+// Float64 returns the value of the rational as a float64.
+func (r URational) Float64() float64 {
+	return float64(r.Numerator) / float64(r.Denominator)
+}
+
 type Rational struct {
 	Numerator   int32
 	Denominator int32
 }
 
+// Float64 returns the value of the rational as a float64.
+func (r Rational) Float64() float64 {
+	return float64(r.Numerator) / float64(r.Denominator)
+}
+
 type EntryValue struct {
-	UByte     *byte
-	String    *string
-	Uint16    *uint16
-	Uints16   []uint16
-	Uint32    *uint32
-	Uints32   []uint32
-	URational *URational
-	Byte      *byte
-	Int16     *int16
-	Ints16    []int16
-	Int32     *int32
-	Ints32    []int32
-	Rational  *Rational
+	UByte      *byte
+	String     *string
+	Uint16     *uint16
+	Uints16    []uint16
+	Uint32     *uint32
+	Uints32    []uint32
+	URational  *URational
+	URationals []URational
+	Byte       *byte
+	Bytes      []byte
+	Int16      *int16
+	Ints16     []int16
+	Int32      *int32
+	Ints32     []int32
+	Rational   *Rational
+	Rationals  []Rational
+	Float32    *float32
+	Floats32   []float32
+	Float64    *float64
+	Floats64   []float64
+	Strings    []string // all strings of an ASCII value holding several of them (String holds the first one)
+	SBytes     []int8   // signed bytes, when there are several of them
+	Uint64     *uint64  // LONG8 and IFD8 (BigTIFF)
+	Uints64    []uint64
+	Int64      *int64 // SLONG8 (BigTIFF)
+	Ints64     []int64
 }
 
 // Entry represents an IFD entry
@@ -88,58 +243,186 @@ type Entry struct {
 	Length   uint32
 	RawValue uint32 // value of the entry or offset to read the value from, depending on DataType and Length
 	Value    EntryValue
+	Skipped  bool // whether Value was left empty, the value being too large (see WithSkipValuesLargerThan)
+
+	Group     Group // group the entry was read from
+	IFDOffset int64 // offset of the IFD the entry was read from
+}
+
+// entryDataTypeNames lists the names of data types printed by Entry.String.
+var entryDataTypeNames = map[DataType]string{
+	DataType_UByte:          "unsigned byte",
+	DataType_String:         "string",
+	DataType_UTF8:           "UTF-8 string",
+	DataType_UShort:         "unsigned short 16bits",
+	DataType_ULong:          "unsigned long 32bits",
+	DataType_URational:      "unsigned rational",
+	DataType_Byte:           "signed byte",
+	DataType_UByte_Sequence: "unsigned byte sequence",
+	DataType_Short:          "signed short 16bits",
+	DataType_Long:           "signed long 32bits",
+	DataType_Rational:       "signed rational",
+	DataType_Float:          "float 32bits",
+	DataType_IFD:            "IFD offset",
+	DataType_Double:         "double 64bits",
+	DataType_ULong8:         "unsigned long 64bits",
+	DataType_IFD8:           "unsigned long 64bits",
+	DataType_Long8:          "signed long 64bits",
 }
 
 func (e Entry) String() string {
-	dt := "UNKNOWN"
+	if e.Skipped {
+		return fmt.Sprintf("ID: 0x%X\nDataType: %s\nLength: %d\nValue: %d bytes at offset %d, not decoded\n",
+			e.ID, dataTypeNames[e.DataType], e.Length, int64(e.DataType.Size())*int64(e.Length), e.RawValue)
+	}
+	dt, ok := entryDataTypeNames[e.DataType]
+	if !ok {
+		dt = "UNKNOWN"
+	}
+
+	// values are formatted according to the field holding them rather than to DataType, as normalized values (see
+	// WithNormalization) may be held by another field than the one DataType implies
 	value := "not yet implemented"
-	switch DataType(e.DataType) {
-	case DataType_UByte:
-		dt = "unsigned byte"
-		value = fmt.Sprintf("%d", *e.Value.UByte)
-	case DataType_String:
-		dt = "string"
-		value = *e.Value.String
-	case DataType_UShort:
-		dt = "unsigned short 16bits"
-		if e.Length == 1 {
-			value = fmt.Sprintf("%d", *e.Value.Uint16)
+	v := e.Value
+	switch v.Kind() {
+	case ValueKind_UByte:
+		value = fmt.Sprintf("%d", *v.UByte)
+	case ValueKind_Byte:
+		value = fmt.Sprintf("%d", int8(*v.Byte))
+	case ValueKind_Bytes:
+		if e.DataType == DataType_UByte_Sequence {
+			value = fmt.Sprintf("% X", v.Bytes)
 		} else {
-			value = fmt.Sprintf("%v", e.Value.Uints16)
+			value = fmt.Sprintf("%v", v.Bytes)
 		}
-	case DataType_ULong:
-		dt = "unsigned long 32bits"
-		if e.Length == 1 {
-			value = fmt.Sprintf("%d", *e.Value.Uint32)
-		} else {
-			value = fmt.Sprintf("%v", e.Value.Uints32)
+	case ValueKind_String, ValueKind_Strings:
+		value = *v.String
+	case ValueKind_URational:
+		value = fmt.Sprintf("%d / %d", v.URational.Numerator, v.URational.Denominator)
+	case ValueKind_Rational:
+		value = fmt.Sprintf("%d / %d", v.Rational.Numerator, v.Rational.Denominator)
+	case ValueKind_Float32:
+		value = fmt.Sprintf("%g", *v.Float32)
+	case ValueKind_Float64:
+		value = fmt.Sprintf("%g", *v.Float64)
+	case ValueKind_None:
+	default:
+		value = fmt.Sprintf("%v", v.Interface())
+	}
+
+	return fmt.Sprintf("ID: 0x%X\nDataType: %s\nLength: %d\nValue: %s\n", e.ID, dt, e.Length, value)
+}
+
+// Floats returns the numeric value(s) of the entry as float64, whatever their integer, rational or floating point DataType.
+// It returns an error if the entry does not hold a numeric value.
+func (e Entry) Floats() ([]float64, error) {
+	v := e.Value
+	var res []float64
+	switch {
+	case v.UByte != nil:
+		res = append(res, float64(*v.UByte))
+	case v.Uint16 != nil:
+		res = append(res, float64(*v.Uint16))
+	case v.Uints16 != nil:
+		for _, x := range v.Uints16 {
+			res = append(res, float64(x))
 		}
-	case DataType_URational:
-		dt = "unsigned rational"
-		value = fmt.Sprintf("%d / %d", e.Value.URational.Numerator, e.Value.URational.Denominator)
-	case DataType_Byte:
-		dt = "signed byte"
-		value = fmt.Sprintf("%d", *e.Value.Byte)
-	case DataType_UByte_Sequence:
-		dt = "unsigned byte sequence"
-	case DataType_Short:
-		dt = "signed short 16bits"
-		if e.Length == 1 {
-			value = fmt.Sprintf("%d", *e.Value.Int16)
-		} else {
-			value = fmt.Sprintf("%v", e.Value.Ints16)
+	case v.Uint32 != nil:
+		res = append(res, float64(*v.Uint32))
+	case v.Uints32 != nil:
+		for _, x := range v.Uints32 {
+			res = append(res, float64(x))
 		}
-	case DataType_Long:
-		dt = "signed long 32bits"
-		if e.Length == 1 {
-			value = fmt.Sprintf("%d", *e.Value.Int32)
-		} else {
-			value = fmt.Sprintf("%v", e.Value.Ints32)
+	case v.URational != nil:
+		res = append(res, v.URational.Float64())
+	case v.URationals != nil:
+		for _, x := range v.URationals {
+			res = append(res, x.Float64())
+		}
+	case v.Byte != nil:
+		res = append(res, float64(int8(*v.Byte)))
+	case v.Int16 != nil:
+		res = append(res, float64(*v.Int16))
+	case v.Ints16 != nil:
+		for _, x := range v.Ints16 {
+			res = append(res, float64(x))
+		}
+	case v.Int32 != nil:
+		res = append(res, float64(*v.Int32))
+	case v.Ints32 != nil:
+		for _, x := range v.Ints32 {
+			res = append(res, float64(x))
+		}
+	case v.Rational != nil:
+		res = append(res, v.Rational.Float64())
+	case v.Rationals != nil:
+		for _, x := range v.Rationals {
+			res = append(res, x.Float64())
 		}
-	case DataType_Rational:
-		dt = "signed rational"
-		value = fmt.Sprintf("%d / %d", e.Value.Rational.Numerator, e.Value.Rational.Denominator)
+	case v.Float32 != nil:
+		res = append(res, float64(*v.Float32))
+	case v.Floats32 != nil:
+		for _, x := range v.Floats32 {
+			res = append(res, float64(x))
+		}
+	case v.Float64 != nil:
+		res = append(res, *v.Float64)
+	case v.Floats64 != nil:
+		res = append(res, v.Floats64...)
+	case v.Uint64 != nil:
+		res = append(res, float64(*v.Uint64))
+	case v.Uints64 != nil:
+		for _, x := range v.Uints64 {
+			res = append(res, float64(x))
+		}
+	case v.Int64 != nil:
+		res = append(res, float64(*v.Int64))
+	case v.Ints64 != nil:
+		for _, x := range v.Ints64 {
+			res = append(res, float64(x))
+		}
+	default:
+		return nil, fmt.Errorf("entry 0x%X does not hold a numeric value", e.ID)
 	}
 
-	return fmt.Sprintf("ID: 0x%X\nDataType: %s\nLength: %d\nValue: %s\n", e.ID, dt, e.Length, value)
+	return res, nil
+}
+
+// Uints returns the unsigned integer value(s) of the entry as uint32, whatever their unsigned integer DataType.
+// It returns an error if the entry does not hold an unsigned integer value, or an error matching ErrOutOfRange if one
+// of its 64-bit values does not fit in 32 bits (see Uint64s).
+func (e Entry) Uints() ([]uint32, error) {
+	v := e.Value
+	var res []uint32
+	switch {
+	case v.UByte != nil:
+		res = append(res, uint32(*v.UByte))
+	case v.Uint16 != nil:
+		res = append(res, uint32(*v.Uint16))
+	case v.Uints16 != nil:
+		res = make([]uint32, len(v.Uints16))
+		for i, x := range v.Uints16 {
+			res[i] = uint32(x)
+		}
+	case v.Uint32 != nil:
+		res = append(res, *v.Uint32)
+	case v.Uints32 != nil:
+		res = v.Uints32
+	case v.Uint64 != nil, v.Uints64 != nil:
+		values := v.Uints64
+		if v.Uint64 != nil {
+			values = []uint64{*v.Uint64}
+		}
+		res = make([]uint32, len(values))
+		for i, x := range values {
+			if x > math.MaxUint32 {
+				return nil, fmt.Errorf("%w: entry 0x%X holds value %d, larger than 32 bits", ErrOutOfRange, e.ID, x)
+			}
+			res[i] = uint32(x)
+		}
+	default:
+		return nil, fmt.Errorf("entry 0x%X does not hold an unsigned integer value", e.ID)
+	}
+
+	return res, nil
 }
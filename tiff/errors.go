@@ -0,0 +1,56 @@
+package tiff
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrTruncated is matched (see errors.Is) by the errors returned when the end of the file is reached before all
+// entries could be read.
+var ErrTruncated = errors.New("truncated file")
+
+// TruncatedError tells where reading stopped because the end of the file was reached. Parse returns it together with
+// the entries read before that point.
+type TruncatedError struct {
+	Group  Group // group of the IFD being read
+	Offset int64 // offset of the IFD entry being read
+	Err    error // error returned by the reader
+}
+
+func (e *TruncatedError) Error() string {
+	return fmt.Sprintf("truncated file: reading stopped at offset %d of the %s IFD: %v", e.Offset, e.Group, e.Err)
+}
+
+// Is makes TruncatedError match ErrTruncated.
+func (e *TruncatedError) Is(target error) bool {
+	return target == ErrTruncated
+}
+
+func (e *TruncatedError) Unwrap() error {
+	return e.Err
+}
+
+// truncated wraps err in a TruncatedError if it was caused by reaching the end of the file.
+func truncated(err error, group Group, offset int64) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return &TruncatedError{Group: group, Offset: offset, Err: err}
+	}
+
+	return err
+}
+
+// ErrLimitExceeded is matched (see errors.Is) by the errors returned when a file exceeds a limit set with
+// WithMaxValueSize or WithMaxEntriesPerIFD.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// ErrBadDataType is matched (see errors.Is) by the errors returned in strict mode (see WithStrictTypes) when the data
+// type or count of a known entry does not match its specification.
+var ErrBadDataType = errors.New("bad data type")
+
+// ErrNoPreview is matched (see errors.Is) by the errors returned when a file holds no suitable preview.
+var ErrNoPreview = errors.New("no preview")
+
+// ErrOutOfRange is matched (see errors.Is) by the errors returned when a value does not fit the integer type it is
+// converted to: it overflows it, or it is negative where an unsigned value is expected.
+var ErrOutOfRange = errors.New("value out of range")
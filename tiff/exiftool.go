@@ -0,0 +1,167 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ExifToolKey returns the key of an entry following exiftool's "group:name" convention, e.g. "EXIF:DateTimeOriginal" or
+// "GPS:GPSLatitude". Unknown entries are named after their ID, as exiftool does (e.g. "EXIF:Exif_0xc5e0").
+func ExifToolKey(id EntryID, group Group) string {
+	prefix := "EXIF"
+	if group == Group_GPSInfo {
+		prefix = "GPS"
+	}
+	name := id.Name()
+	if name == "" {
+		name = fmt.Sprintf("Exif_0x%04x", uint16(id))
+	}
+
+	return prefix + ":" + name
+}
+
+// ExifToolValue returns the value of an entry, formatted like exiftool does in its JSON output: numbers are returned
+// as such, lists of values as a string of space-separated values and binary data as a placeholder. A few entries are
+// formatted as exiftool prints them, e.g. ExposureTime ("1/200"), FNumber (5.6), their APEX counterparts
+// ShutterSpeedValue and ApertureValue, and GPS coordinates (`52 deg 22' 12.34"`).
+func ExifToolValue(e Entry) any {
+	if s := e.Value.String; s != nil {
+		return strings.TrimRight(*s, " \x00")
+	}
+	if e.Value.Bytes != nil {
+		if printable(e.Value.Bytes) {
+			return strings.TrimRight(string(e.Value.Bytes), " \x00")
+		}
+		return fmt.Sprintf("(Binary data %d bytes, use -b option to extract)", len(e.Value.Bytes))
+	}
+
+	values, err := e.Floats()
+	if err != nil || len(values) == 0 {
+		return nil
+	}
+
+	switch e.ID {
+	case ShutterSpeedValue:
+		values[0] = ApexToSeconds(values[0])
+		fallthrough
+	case ExposureTime:
+		if v := values[0]; v > 0 && v < 0.25001 {
+			return fmt.Sprintf("1/%d", int(0.5+1/v))
+		}
+		return json.Number(strings.TrimSuffix(fmt.Sprintf("%.1f", values[0]), ".0"))
+	case ApertureValue:
+		values[0] = ApexToFNumber(values[0])
+		fallthrough
+	case FNumber:
+		return json.Number(fmt.Sprintf("%.1f", values[0]))
+	case GPSLatitude, GPSLongitude:
+		if len(values) == 3 {
+			return fmt.Sprintf(`%d deg %d' %.2f"`, int(values[0]), int(values[1]), values[2])
+		}
+	}
+
+	if len(values) == 1 {
+		return exifToolNumber(values[0])
+	}
+	formatted := make([]string, len(values))
+	for i, v := range values {
+		formatted[i] = string(exifToolNumber(v))
+	}
+
+	return strings.Join(formatted, " ")
+}
+
+// ExifTool returns the entries keyed following exiftool's naming convention (see ExifToolKey), with values formatted
+// by ExifToolValue, together with the composites that can be derived from them (see WithComposites), keyed as exiftool
+// does (e.g. "Composite:LightValue"). Marshalled to JSON, the result can be consumed like the output of
+// `exiftool -j -G`.
+func (p *Parser) ExifTool(entries map[EntryID]Entry) map[string]any {
+	res := make(map[string]any, len(entries))
+	for id, entry := range entries {
+		res[ExifToolKey(id, p.mapping[id])] = ExifToolValue(entry)
+	}
+	for name, value := range p.compositeRegistry().ReadAll(entries) {
+		res["Composite:"+name] = exifToolComposite(name, value)
+	}
+
+	return res
+}
+
+// exifToolComposite formats the value of a composite like ExifToolValue does: ShutterSpeed like ExposureTime, numbers
+// and lists of numbers as such, and other values as they are.
+func exifToolComposite(name string, value any) any {
+	switch v := value.(type) {
+	case float64:
+		if name == "ShutterSpeed" {
+			return ExifToolValue(Entry{ID: ExposureTime, Value: EntryValue{Float64: &v}})
+		}
+		return exifToolNumber(v)
+	case []float64:
+		formatted := make([]string, len(v))
+		for i, f := range v {
+			formatted[i] = string(exifToolNumber(f))
+		}
+		return strings.Join(formatted, " ")
+	}
+
+	return value
+}
+
+// ExifToolJSON parses a whole file held in memory, returning the given entries (by default, all entries of Defaults
+// whose group the file has) marshalled to JSON like the output of `exiftool -j -G` (see ExifTool). It needs neither files nor readers, e.g. to
+// show the metadata of an image in the browser before uploading it (see cmd/tiffwasm). If data is truncated (e.g. only
+// the start of the file was read), it returns the entries read so far together with an error matching ErrTruncated.
+func ExifToolJSON(data []byte, ids ...EntryID) ([]byte, error) {
+	p, err := NewParser(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		// files without an Exif or GPSInfo IFD are valid, but parsing entries of a missing IFD fails
+		missing := make(map[Group]bool)
+		for _, group := range []Group{Group_Exif, Group_GPSInfo} {
+			if ok, err := p.HasGroup(group); err == nil && !ok {
+				missing[group] = true
+			}
+		}
+		for id, group := range p.mapping {
+			if !missing[group] {
+				ids = append(ids, id)
+			}
+		}
+	}
+	entries, err := p.Parse(ids...)
+	if err != nil && !errors.Is(err, ErrTruncated) {
+		return nil, err
+	}
+	res, jsonErr := json.Marshal(p.ExifTool(entries))
+	if jsonErr != nil {
+		return nil, jsonErr
+	}
+
+	return res, err
+}
+
+// exifToolNumber formats a number without trailing zeros, using up to 15 significant digits as exiftool does.
+func exifToolNumber(v float64) json.Number {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return json.Number(fmt.Sprintf("%d", int64(v)))
+	}
+
+	return json.Number(fmt.Sprintf("%.15g", v))
+}
+
+// printable tells whether data only holds printable ASCII characters (possibly NUL-terminated).
+func printable(data []byte) bool {
+	for _, b := range strings.TrimRight(string(data), "\x00") {
+		if b < 0x20 || b > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,102 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExifToolKey(t *testing.T) {
+	assert.Equal(t, "EXIF:DateTimeOriginal", ExifToolKey(DateTimeOriginal, Group_Exif))
+	assert.Equal(t, "EXIF:Make", ExifToolKey(Make, Group_IFD0))
+	assert.Equal(t, "GPS:GPSLatitude", ExifToolKey(GPSLatitude, Group_GPSInfo))
+	assert.Equal(t, "EXIF:Exif_0xc5e0", ExifToolKey(0xc5e0, Group_IFD0))
+}
+
+func TestExifToolValue(t *testing.T) {
+	uint16Value := uint16(5184)
+	make_ := "OLYMPUS CORPORATION    "
+
+	tests := []struct {
+		name  string
+		entry Entry
+		want  any
+	}{
+		{"string", Entry{ID: Make, Value: EntryValue{String: &make_}}, "OLYMPUS CORPORATION"},
+		{"integer", Entry{ID: ImageWidth, Value: EntryValue{Uint16: &uint16Value}}, json.Number("5184")},
+		{"list", Entry{ID: BitsPerSample, Value: EntryValue{Uints16: []uint16{8, 8, 8}}}, "8 8 8"},
+		{"rational", Entry{ID: BaselineExposure, Value: EntryValue{Rational: &Rational{Numerator: -1, Denominator: 4}}}, json.Number("-0.25")},
+		{"short exposure time", Entry{ID: ExposureTime, Value: EntryValue{URational: &URational{Numerator: 1, Denominator: 200}}}, "1/200"},
+		{"long exposure time", Entry{ID: ExposureTime, Value: EntryValue{URational: &URational{Numerator: 30, Denominator: 1}}}, json.Number("30")},
+		{"f-number", Entry{ID: FNumber, Value: EntryValue{URational: &URational{Numerator: 28, Denominator: 5}}}, json.Number("5.6")},
+		{"shutter speed", Entry{ID: ShutterSpeedValue, Value: EntryValue{Rational: &Rational{Numerator: 7643856, Denominator: 1000000}}}, "1/200"},
+		{"aperture", Entry{ID: ApertureValue, Value: EntryValue{URational: &URational{Numerator: 4970854, Denominator: 1000000}}}, json.Number("5.6")},
+		{
+			"GPS coordinate",
+			Entry{ID: GPSLatitude, Value: EntryValue{URationals: []URational{{52, 1}, {22, 1}, {1234, 100}}}},
+			`52 deg 22' 12.34"`,
+		},
+		{"text bytes", Entry{ID: 0x9000, Value: EntryValue{Bytes: []byte("0231")}}, "0231"},
+		{"binary bytes", Entry{ID: MakerNotes, Value: EntryValue{Bytes: []byte{0, 1, 2}}}, "(Binary data 3 bytes, use -b option to extract)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExifToolValue(tt.entry))
+		})
+	}
+}
+
+func TestExifTool_CR2(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	entries, err := p.Parse(Make, DateTimeOriginal, ExposureTime, BitsPerSample)
+	assert.NoError(t, err)
+
+	data, err := json.Marshal(p.ExifTool(entries))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"EXIF:Make": "Canon",
+		"EXIF:DateTimeOriginal": "2021:11:19 12:21:10",
+		"EXIF:ExposureTime": "1/40",
+		"EXIF:BitsPerSample": "8 8 8",
+		"Composite:ShutterSpeed": "1/40"
+	}`, string(data))
+}
+
+func TestExifToolJSON(t *testing.T) {
+	data, err := ExifToolJSON(cr2Image, Make, ExposureTime)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"EXIF:Make": "Canon", "EXIF:ExposureTime": "1/40", "Composite:ShutterSpeed": "1/40"}`, string(data))
+
+	data, err = ExifToolJSON(orfImage)
+	assert.NoError(t, err)
+	var all map[string]any
+	assert.NoError(t, json.Unmarshal(data, &all))
+	assert.Equal(t, "E-M10MarkII", all["EXIF:Model"])
+	assert.Contains(t, all, "GPS:GPSVersionID")
+
+	// e.g. only the start of the file was read
+	data, err = ExifToolJSON(cr2Image[:800], Make, ExposureTime)
+	assert.ErrorIs(t, err, ErrTruncated)
+	assert.JSONEq(t, `{"EXIF:Make": "Canon"}`, string(data))
+
+	_, err = ExifToolJSON([]byte("not a TIFF"))
+	assert.Error(t, err)
+}
+
+func TestExifToolJSON_IFD0Only(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().WithString(uint16(Make), "Canon")
+
+	data, err := ExifToolJSON(b.Bytes())
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"EXIF:Make": "Canon"}`, string(data))
+
+	_, err = ExifToolJSON(b.Bytes(), GPSLatitude)
+	assert.EqualError(t, err, "GPSInfo IFD not found")
+}
@@ -0,0 +1,125 @@
+package tiff
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// exiv2Groups maps groups to their name in exiv2 keys.
+var exiv2Groups = map[Group]string{
+	Group_IFD0:    "Image",
+	Group_IFD1:    "Thumbnail",
+	Group_Exif:    "Photo",
+	Group_GPSInfo: "GPSInfo",
+	Group_SubIFD:  "SubImage1",
+	Group_Image:   "Image2",
+}
+
+// exiv2Names maps entries whose exiv2 name differs from their exiftool name (see EntryID.Name) to the former.
+var exiv2Names = map[EntryID]string{
+	NewSubfileType:     "NewSubfileType",
+	ImageHeight:        "ImageLength",
+	DateTime:           "DateTime",
+	SubIFDs:            "SubIFDs",
+	XMLPacket:          "XMLPacket",
+	IPTC:               "IPTCNAA",
+	PhotoshopSettings:  "ImageResources",
+	CameraSerialNumber: "CameraSerialNumber",
+	Exif:               "ExifTag",
+	GPSInfo:            "GPSTag",
+	ISO:                "ISOSpeedRatings",
+	CameraOwnerName:    "CameraOwnerName",
+	MakerNotes:         "MakerNote",
+	ExifImageWidth:     "PixelXDimension",
+	ExifImageHeight:    "PixelYDimension",
+	ThumbnailOffset:    "JPEGInterchangeFormat",
+	ThumbnailLength:    "JPEGInterchangeFormatLength",
+}
+
+// Exiv2Key returns the key of an entry following exiv2's "Exif.<group>.<name>" convention, e.g. "Exif.Image.Make" or
+// "Exif.Photo.ISOSpeedRatings". Unknown entries are named after their ID, as exiv2 does (e.g. "Exif.Image.0xc5e0").
+func Exiv2Key(id EntryID, group Group) string {
+	name, ok := exiv2Names[id]
+	if !ok {
+		name = id.Name()
+	}
+	if name == "" {
+		name = fmt.Sprintf("0x%04x", uint16(id))
+	}
+
+	return "Exif." + exiv2Groups[group] + "." + name
+}
+
+// ParseExiv2Key returns the entry ID and group identified by an exiv2 key (see Exiv2Key).
+func ParseExiv2Key(key string) (EntryID, Group, error) {
+	parts := strings.Split(key, ".")
+	if len(parts) != 3 || parts[0] != "Exif" {
+		return 0, 0, fmt.Errorf("invalid exiv2 key: %s", key)
+	}
+
+	group, ok := Group(0), false
+	for g, name := range exiv2Groups {
+		if name == parts[1] {
+			group, ok = g, true
+			break
+		}
+	}
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown exiv2 group: %s", parts[1])
+	}
+
+	if strings.HasPrefix(parts[2], "0x") {
+		id, err := strconv.ParseUint(parts[2][2:], 16, 16)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid exiv2 key: %s", key)
+		}
+		return EntryID(id), group, nil
+	}
+	for id := range names {
+		if Exiv2Key(id, group) == key {
+			return id, group, nil
+		}
+	}
+
+	return 0, 0, fmt.Errorf("unknown exiv2 key: %s", key)
+}
+
+// ParseByKey parses the TIFF file, returning any entry found in it that matches the given exiv2 keys (e.g.
+// "Exif.Photo.FNumber"), keyed by them. It returns an error if any of the keys is invalid, but not if the entries are
+// not found. Like Parse, it returns the entries read so far if the file is truncated. Entries of IFD#1
+// ("Exif.Thumbnail.*") are not supported yet, and never found.
+func (p *Parser) ParseByKey(keys ...string) (map[string]Entry, error) {
+	groups := make(map[EntryID]Group, len(keys))
+	byID := make(map[EntryID]string, len(keys))
+	for _, key := range keys {
+		id, group, err := ParseExiv2Key(key)
+		if err != nil {
+			return nil, err
+		}
+		groups[id], byID[id] = group, key
+	}
+
+	entries, err := p.parse(groups)
+	if err != nil && !errors.Is(err, ErrTruncated) {
+		return nil, err
+	}
+
+	res := make(map[string]Entry, len(entries))
+	for id, entry := range entries {
+		res[byID[id]] = entry
+	}
+
+	return res, err
+}
+
+// Exiv2 returns the entries keyed following exiv2's naming convention (see Exiv2Key).
+func (p *Parser) Exiv2(entries map[EntryID]Entry) map[string]Entry {
+	res := make(map[string]Entry, len(entries))
+	for id, entry := range entries {
+		res[Exiv2Key(id, p.mapping[id])] = entry
+	}
+
+	return res
+}
@@ -0,0 +1,59 @@
+package tiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExiv2Key(t *testing.T) {
+	tests := []struct {
+		key   string
+		id    EntryID
+		group Group
+	}{
+		{"Exif.Image.Make", Make, Group_IFD0},
+		{"Exif.Image.ImageLength", ImageHeight, Group_IFD0},
+		{"Exif.Photo.ISOSpeedRatings", ISO, Group_Exif},
+		{"Exif.Photo.FNumber", FNumber, Group_Exif},
+		{"Exif.GPSInfo.GPSLatitude", GPSLatitude, Group_GPSInfo},
+		{"Exif.Thumbnail.JPEGInterchangeFormat", ThumbnailOffset, Group_IFD1},
+		{"Exif.Image.0xc5e0", 0xc5e0, Group_IFD0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			assert.Equal(t, tt.key, Exiv2Key(tt.id, tt.group))
+
+			id, group, err := ParseExiv2Key(tt.key)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.id, id)
+			assert.Equal(t, tt.group, group)
+		})
+	}
+
+	for _, key := range []string{"Exif.Image", "Iptc.Image.Make", "Exif.Maker.Make", "Exif.Image.Unknown", "Exif.Image.0xzz"} {
+		_, _, err := ParseExiv2Key(key)
+		assert.Error(t, err, key)
+	}
+}
+
+func TestParseByKey(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+
+	entries, err := p.ParseByKey("Exif.Image.Make", "Exif.Photo.ExposureTime", "Exif.Photo.DateTimeOriginal")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "Canon", *entries["Exif.Image.Make"].Value.String)
+	assert.EqualValues(t, 40, entries["Exif.Photo.ExposureTime"].Value.URational.Denominator)
+	assert.Equal(t, entries, p.Exiv2(map[EntryID]Entry{
+		Make:             entries["Exif.Image.Make"],
+		ExposureTime:     entries["Exif.Photo.ExposureTime"],
+		DateTimeOriginal: entries["Exif.Photo.DateTimeOriginal"],
+	}))
+
+	_, err = p.ParseByKey("Exif.Photo.Nope")
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package tiff
+
+import (
+	"fmt"
+	"math"
+)
+
+// APEX (Additive System of Photographic Exposure) values are logarithmic: ShutterSpeedValue (Tv), ApertureValue (Av),
+// BrightnessValue (Bv) and ExposureBiasValue are all in EV, with Av + Tv = Bv + Sv.
+
+// ApexToSeconds converts a shutter speed APEX value (Tv) to an exposure time, in seconds.
+func ApexToSeconds(tv float64) float64 {
+	return math.Pow(2, -tv)
+}
+
+// SecondsToApex converts an exposure time, in seconds, to a shutter speed APEX value (Tv).
+func SecondsToApex(seconds float64) float64 {
+	return -math.Log2(seconds)
+}
+
+// ApexToFNumber converts an aperture APEX value (Av) to an f-number.
+func ApexToFNumber(av float64) float64 {
+	return math.Pow(2, av/2)
+}
+
+// FNumberToApex converts an f-number to an aperture APEX value (Av).
+func FNumberToApex(fNumber float64) float64 {
+	return 2 * math.Log2(fNumber)
+}
+
+// ReadExposureTime reads the exposure time, in seconds, from ExposureTime or, if it is missing, from ShutterSpeedValue.
+func ReadExposureTime(entries map[EntryID]Entry) (float64, error) {
+	if _, ok := entries[ExposureTime]; ok {
+		return readPositiveFloat(entries, ExposureTime)
+	}
+	tv, err := readFloat(entries, ShutterSpeedValue)
+	if err != nil {
+		return 0, err
+	}
+
+	return ApexToSeconds(tv), nil
+}
+
+// ReadFNumber reads the f-number of the lens from FNumber or, if it is missing, from ApertureValue.
+func ReadFNumber(entries map[EntryID]Entry) (float64, error) {
+	if _, ok := entries[FNumber]; ok {
+		return readPositiveFloat(entries, FNumber)
+	}
+	av, err := readFloat(entries, ApertureValue)
+	if err != nil {
+		return 0, err
+	}
+
+	return ApexToFNumber(av), nil
+}
+
+// ReadBrightness reads the brightness of the subject, in EV, from BrightnessValue.
+func ReadBrightness(entries map[EntryID]Entry) (float64, error) {
+	return readFloat(entries, BrightnessValue)
+}
+
+// ReadExposureBias reads the exposure compensation, in EV, from ExposureBiasValue.
+func ReadExposureBias(entries map[EntryID]Entry) (float64, error) {
+	return readFloat(entries, ExposureBiasValue)
+}
+
+// ReadLightValue computes the light value of the scene, in EV at ISO 100, from the f-number (see ReadFNumber), the
+// exposure time (see ReadExposureTime) and the ISO speed (ISO, 100 if it is missing): unlike the exposure value, it
+// tells how bright the scene was, whatever the sensitivity of the sensor.
+func ReadLightValue(entries map[EntryID]Entry) (float64, error) {
+	fNumber, err := ReadFNumber(entries)
+	if err != nil {
+		return 0, err
+	}
+	seconds, err := ReadExposureTime(entries)
+	if err != nil {
+		return 0, err
+	}
+	iso := 100.0
+	if _, ok := entries[ISO]; ok {
+		values, err := readFloats(entries, ISO)
+		if err != nil {
+			return 0, err
+		}
+		if len(values) == 0 || values[0] <= 0 {
+			return 0, fmt.Errorf("invalid %s %v", ISO.Name(), values)
+		}
+		iso = values[0]
+	}
+
+	return math.Log2(fNumber * fNumber * 100 / (seconds * iso)), nil
+}
+
+// readPositiveFloat reads the value of an entry holding a single, finite and positive number.
+func readPositiveFloat(entries map[EntryID]Entry, id EntryID) (float64, error) {
+	value, err := readFloat(entries, id)
+	if err != nil {
+		return 0, err
+	}
+	if value <= 0 {
+		return 0, fmt.Errorf("invalid %s %v", id.Name(), value)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,69 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApex(t *testing.T) {
+	assert.InDelta(t, 1.0/128, ApexToSeconds(7), 1e-9)
+	assert.InDelta(t, 7, SecondsToApex(1.0/128), 1e-9)
+	assert.InDelta(t, 2, ApexToSeconds(-1), 1e-9)
+	assert.InDelta(t, 4, ApexToFNumber(4), 1e-9)
+	assert.InDelta(t, 4, FNumberToApex(4), 1e-9)
+	assert.InDelta(t, 1, ApexToFNumber(0), 1e-9)
+}
+
+func TestReadExposure(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().WithSubIFD(uint16(Exif)).
+		WithRationals(uint16(ShutterSpeedValue), 8, 1).
+		WithURationals(uint16(ApertureValue), 5, 1).
+		WithRationals(uint16(BrightnessValue), -3, 2).
+		WithRationals(uint16(ExposureBiasValue), -2, 3)
+
+	p, err := NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+	entries, err := p.Parse(ExposureTime, FNumber, ShutterSpeedValue, ApertureValue, BrightnessValue, ExposureBiasValue)
+	assert.NoError(t, err)
+
+	exposureTime, err := ReadExposureTime(entries)
+	assert.NoError(t, err)
+	assert.InDelta(t, 1.0/256, exposureTime, 1e-9)
+
+	fNumber, err := ReadFNumber(entries)
+	assert.NoError(t, err)
+	assert.InDelta(t, 5.66, fNumber, 0.01)
+
+	brightness, err := ReadBrightness(entries)
+	assert.NoError(t, err)
+	assert.Equal(t, -1.5, brightness)
+
+	bias, err := ReadExposureBias(entries)
+	assert.NoError(t, err)
+	assert.InDelta(t, -0.67, bias, 0.01)
+}
+
+func TestReadExposure_PrefersDirectEntries(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	entries, err := p.Parse(ExposureTime, FNumber, ShutterSpeedValue, ApertureValue)
+	assert.NoError(t, err)
+
+	exposureTime, err := ReadExposureTime(entries)
+	assert.NoError(t, err)
+	assert.Equal(t, entries[ExposureTime].Value.URational.Float64(), exposureTime)
+}
+
+func TestReadExposureTime_Invalid(t *testing.T) {
+	_, err := ReadExposureTime(map[EntryID]Entry{})
+	assert.Error(t, err)
+
+	zero := URational{Numerator: 0, Denominator: 1}
+	_, err = ReadExposureTime(map[EntryID]Entry{ExposureTime: {ID: ExposureTime, Value: EntryValue{URational: &zero}}})
+	assert.ErrorContains(t, err, "invalid ExposureTime")
+}
@@ -0,0 +1,24 @@
+package tiff
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// SyncFileTime sets the modification time of the file at path to the time the image was taken (see CaptureTime), e.g.
+// to restore it after a copy lost it. Its access time is left as it is. Truncated files are synced as long as their
+// DateTimeOriginal can be read.
+func SyncFileTime(path string) error {
+	entries, err := parseFile(path, []EntryID{DateTimeOriginal, OffsetTimeOriginal, OffsetTime})
+	if err != nil && !errors.Is(err, ErrTruncated) {
+		return err
+	}
+	t, err := CaptureTime(entries)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return os.Chtimes(path, time.Time{}, t)
+}
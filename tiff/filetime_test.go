@@ -0,0 +1,47 @@
+package tiff
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncFileTime(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, data, 0o644))
+		return path
+	}
+	modTime := func(path string) time.Time {
+		info, err := os.Stat(path)
+		assert.NoError(t, err)
+		return info.ModTime()
+	}
+
+	cr2 := write("image.cr2", cr2Image)
+	assert.NoError(t, SyncFileTime(cr2))
+	assert.True(t, modTime(cr2).Equal(time.Date(2021, 11, 19, 12, 21, 10, 0, time.UTC)), modTime(cr2))
+
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().WithSubIFD(uint16(Exif)).
+		WithString(uint16(DateTimeOriginal), "2023:06:01 08:30:00").
+		WithString(uint16(OffsetTimeOriginal), "+02:00")
+	zoned := write("zoned.tif", b.Bytes())
+	assert.NoError(t, SyncFileTime(zoned))
+	assert.True(t, modTime(zoned).Equal(time.Date(2023, 6, 1, 6, 30, 0, 0, time.UTC)), modTime(zoned))
+
+	b = test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().WithString(uint16(Make), "Canon")
+	undated := write("undated.tif", b.Bytes())
+	before := modTime(undated)
+	assert.Error(t, SyncFileTime(undated))
+	assert.Equal(t, before, modTime(undated))
+
+	assert.Error(t, SyncFileTime(filepath.Join(dir, "missing.cr2")))
+}
@@ -0,0 +1,34 @@
+package tiff
+
+import (
+	"sort"
+)
+
+// Find scans all IFDs of the file (main chain, Exif, GPSInfo and SubIFDs) and returns the entries for which match
+// returns true, e.g. all ASCII entries containing a serial number. Entries are returned in the order their IFDs are
+// visited (each IFD of the main chain followed by its sub-IFDs), then by ID. Maker notes are not scanned, as their
+// content is not parsed.
+func (p *Parser) Find(match func(Entry) bool) ([]Entry, error) {
+	var res []Entry
+	err := p.walk(func(_ int64, _ Group, entries map[EntryID]Entry) error {
+		ids := make([]EntryID, 0, len(entries))
+		for id := range entries {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+		for _, id := range ids {
+			entry := entries[id]
+			if match(entry) {
+				res = append(res, entry)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
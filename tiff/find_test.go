@@ -0,0 +1,62 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFind(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+
+	found, err := p.Find(func(e Entry) bool {
+		return e.DataType == DataType_String && strings.HasPrefix(*e.Value.String, "2021:11:19")
+	})
+	assert.NoError(t, err)
+
+	var ids []EntryID
+	for _, entry := range found {
+		ids = append(ids, entry.ID)
+	}
+	assert.Equal(t, []EntryID{DateTime, DateTimeOriginal, 0x9004}, ids)
+	assert.Equal(t, Group_IFD0, found[0].Group)
+	assert.EqualValues(t, 16, found[0].IFDOffset)
+	assert.Equal(t, Group_Exif, found[1].Group)
+	assert.EqualValues(t, 446, found[1].IFDOffset)
+
+	found, err = p.Find(func(e Entry) bool { return e.ID == StripOffsets })
+	assert.NoError(t, err)
+	var groups []Group
+	for _, entry := range found {
+		groups = append(groups, entry.Group)
+	}
+	assert.Equal(t, []Group{Group_IFD0, Group_Image, Group_Image}, groups)
+}
+
+func TestFind_SubIFDs(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.BigEndian)
+	ifd := b.AddIFD().WithUints32(uint16(ImageWidth), 256)
+	ifd.WithSubIFD(uint16(SubIFDs)).WithUints32(uint16(ImageWidth), 4096)
+	ifd.WithSubIFD(uint16(GPSInfo)).WithURationals(uint16(GPSLatitude), 52, 1, 22, 1, 1234, 100)
+
+	p, err := NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	found, err := p.Find(func(e Entry) bool { return e.ID == ImageWidth || e.ID == GPSLatitude })
+	assert.NoError(t, err)
+	assert.Len(t, found, 3)
+	assert.Equal(t, Group_IFD0, found[0].Group)
+	assert.EqualValues(t, 256, *found[0].Value.Uint32)
+	assert.Equal(t, Group_GPSInfo, found[1].Group)
+	assert.Equal(t, Group_SubIFD, found[2].Group)
+	assert.EqualValues(t, 4096, *found[2].Value.Uint32)
+
+	found, err = p.Find(func(Entry) bool { return false })
+	assert.NoError(t, err)
+	assert.Empty(t, found)
+}
@@ -0,0 +1,133 @@
+package tiff
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DateTimeLayout is the layout of Exif dates (e.g. DateTimeOriginal), to format or parse them with package time.
+const DateTimeLayout = "2006:01:02 15:04:05"
+
+// Text returns the value of the entry as text, formatted as by ExifToolValue (e.g. "1/200" for an ExposureTime).
+func (e Entry) Text() string {
+	value := ExifToolValue(e)
+	if value == nil {
+		return ""
+	}
+
+	return fmt.Sprint(value)
+}
+
+// CaptureTime returns the time at which the image was taken, read from the DateTimeOriginal entry. Its time zone is
+// read from OffsetTimeOriginal or, if missing, from OffsetTime (e.g. "+02:00"): if both are missing, the time is
+// returned in UTC.
+func CaptureTime(entries map[EntryID]Entry) (time.Time, error) {
+	entry, ok := entries[DateTimeOriginal]
+	if !ok || entry.Value.String == nil {
+		return time.Time{}, errors.New("missing DateTimeOriginal")
+	}
+	value := strings.TrimSpace(*entry.Value.String)
+
+	for _, id := range []EntryID{OffsetTimeOriginal, OffsetTime} {
+		if offset, ok := entries[id]; ok && offset.Value.String != nil && strings.TrimSpace(*offset.Value.String) != "" {
+			return time.Parse(DateTimeLayout+"-07:00", value+strings.TrimSpace(*offset.Value.String))
+		}
+	}
+
+	return time.Parse(DateTimeLayout, value)
+}
+
+// columnName returns the name of an entry, or its ID if it is unknown.
+func columnName(id EntryID) string {
+	if name := id.Name(); name != "" {
+		return name
+	}
+
+	return fmt.Sprintf("0x%04X", uint16(id))
+}
+
+// WriteCSV writes a header with the names of the given columns (e.g. "Make", "DateTimeOriginal"), followed by one
+// record per element of rows (e.g. the entries of each file of a batch). Values are formatted by Entry.Text, and
+// missing entries are left empty.
+func WriteCSV(w io.Writer, rows []map[EntryID]Entry, columns ...EntryID) error {
+	cw := csv.NewWriter(w)
+
+	record := make([]string, len(columns))
+	for i, id := range columns {
+		record[i] = columnName(id)
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+
+	for _, entries := range rows {
+		for i, id := range columns {
+			record[i] = ""
+			if entry, ok := entries[id]; ok {
+				record[i] = entry.Text()
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// Formatter formats entries according to a text/template pattern, in which entries are referred to by name, e.g.
+// "{{.DateTimeOriginal}}_{{.Model}}", as are the composites of DefaultComposites that can be derived from them (e.g.
+// "{{.LightValue}}"). Missing entries are replaced by an empty string. Besides the standard functions, patterns can use:
+//
+//   - date: formats an Exif date with a Go layout, e.g. {{date "2006-01-02" .DateTimeOriginal}}
+//   - clean: replaces characters that are not allowed in file names (/\:*?"<>|) with underscores
+type Formatter struct {
+	tmpl *template.Template
+}
+
+// NewFormatter parses a pattern, returning a Formatter.
+func NewFormatter(pattern string) (*Formatter, error) {
+	tmpl, err := template.New("format").
+		Option("missingkey=zero").
+		Funcs(template.FuncMap{
+			"date": func(layout, value string) (string, error) {
+				t, err := time.Parse(DateTimeLayout, value)
+				if err != nil {
+					return "", err
+				}
+				return t.Format(layout), nil
+			},
+			"clean": strings.NewReplacer("/", "_", "\\", "_", ":", "_", "*", "_", "?", "_", `"`, "_", "<", "_", ">", "_", "|", "_").Replace,
+		}).
+		Parse(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Formatter{tmpl: tmpl}, nil
+}
+
+// Format formats entries according to the formatter's pattern.
+func (f *Formatter) Format(entries map[EntryID]Entry) (string, error) {
+	data := make(map[string]string, len(entries))
+	for name, value := range ReadComposites(entries) {
+		data[name] = fmt.Sprint(exifToolComposite(name, value))
+	}
+	for id, entry := range entries {
+		data[columnName(id)] = entry.Text()
+	}
+
+	var sb strings.Builder
+	if err := f.tmpl.Execute(&sb, data); err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
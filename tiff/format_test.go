@@ -0,0 +1,116 @@
+package tiff
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteCSV(t *testing.T) {
+	var rows []map[EntryID]Entry
+	for _, image := range [][]byte{cr2Image, orfImage} {
+		p, err := NewParser(bytes.NewReader(image))
+		assert.NoError(t, err)
+		entries, err := p.Parse(Make, ExposureTime, DateTimeOriginal)
+		assert.NoError(t, err)
+		rows = append(rows, entries)
+	}
+	rows = append(rows, map[EntryID]Entry{})
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteCSV(&buf, rows, Make, ExposureTime, DateTimeOriginal, 0xc5e0))
+	assert.Equal(t, strings.Join([]string{
+		"Make,ExposureTime,DateTimeOriginal,0xC5E0",
+		"Canon,1/40,2021:11:19 12:21:10,",
+		"OLYMPUS CORPORATION,1/200,2016:08:12 13:32:54,",
+		",,,",
+		"",
+	}, "\n"), buf.String())
+}
+
+func TestFormatter(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	entries, err := p.Parse(Make, Model, DateTimeOriginal, ExposureTime)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"{{.DateTimeOriginal}}_{{.Make}}", "2021:11:19 12:21:10_Canon"},
+		{`{{date "20060102_150405" .DateTimeOriginal}}`, "20211119_122110"},
+		{"{{clean .DateTimeOriginal}}", "2021_11_19 12_21_10"},
+		{"{{.Make}}-{{.ISO}}", "Canon-"},
+		{"{{.ShutterSpeed}}", "1/40"}, // composite
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			f, err := NewFormatter(tt.pattern)
+			assert.NoError(t, err)
+			got, err := f.Format(entries)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err = NewFormatter("{{.Make")
+	assert.Error(t, err)
+
+	f, err := NewFormatter(`{{date "2006" .Make}}`)
+	assert.NoError(t, err)
+	_, err = f.Format(entries)
+	assert.Error(t, err, "not a date")
+}
+
+func TestCaptureTime(t *testing.T) {
+	str := func(id EntryID, value string) Entry {
+		return Entry{ID: id, DataType: DataType_String, Length: uint32(len(value) + 1), Value: EntryValue{String: &value}}
+	}
+
+	tests := []struct {
+		name    string
+		entries map[EntryID]Entry
+		want    string
+	}{
+		{
+			name:    "no offset",
+			entries: map[EntryID]Entry{DateTimeOriginal: str(DateTimeOriginal, "2021:11:19 12:21:10")},
+			want:    "2021-11-19T12:21:10Z",
+		},
+		{
+			name: "OffsetTimeOriginal",
+			entries: map[EntryID]Entry{
+				DateTimeOriginal:   str(DateTimeOriginal, "2021:11:19 12:21:10"),
+				OffsetTimeOriginal: str(OffsetTimeOriginal, "+01:00"),
+				OffsetTime:         str(OffsetTime, "+02:00"),
+			},
+			want: "2021-11-19T12:21:10+01:00",
+		},
+		{
+			name: "OffsetTime",
+			entries: map[EntryID]Entry{
+				DateTimeOriginal: str(DateTimeOriginal, "2021:11:19 12:21:10"),
+				OffsetTime:       str(OffsetTime, "-05:00"),
+			},
+			want: "2021-11-19T12:21:10-05:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CaptureTime(tt.entries)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.Format(time.RFC3339))
+		})
+	}
+
+	_, err := CaptureTime(map[EntryID]Entry{})
+	assert.Error(t, err)
+	_, err = CaptureTime(map[EntryID]Entry{DateTimeOriginal: str(DateTimeOriginal, "not a date")})
+	assert.Error(t, err)
+}
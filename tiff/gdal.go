@@ -0,0 +1,138 @@
+package tiff
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GDALItem is an item of the GDALMetadata entry.
+type GDALItem struct {
+	Name   string
+	Value  string
+	Sample int    // band the item applies to, starting at 0, or -1 if it applies to the whole dataset
+	Role   string // e.g. "scale", "offset", "description" or "unittype" for band properties, empty for metadata items
+	Domain string // metadata domain (e.g. "IMAGE_STRUCTURE"), empty for the default one
+}
+
+// GDALInfo holds the metadata GDAL writes in its private entries of GeoTIFF files.
+type GDALInfo struct {
+	NoData *float64 // value of the samples holding no data, shared by all bands, nil if GDALNoData is missing
+	Items  []GDALItem
+}
+
+// GDALBand holds the properties and metadata of a band (aka sample) of a GeoTIFF file.
+type GDALBand struct {
+	Description string
+	Unit        string  // unit of the values, e.g. "m" or "ft"
+	Scale       float64 // real values are Scale * sample + Offset
+	Offset      float64
+	Metadata    map[string]string // items of the default domain
+}
+
+// GDAL reads the metadata GDAL writes in the GDALNoData and GDALMetadata entries of the page: the nodata value, and
+// the metadata of the dataset and of its bands, such as their statistics. It returns false if the page has neither
+// entry, and an error if they cannot be parsed.
+func (pg Page) GDAL() (GDALInfo, bool, error) {
+	var info GDALInfo
+	noData, hasNoData := pg.Entries[GDALNoData]
+	metadata, hasMetadata := pg.Entries[GDALMetadata]
+	if !hasNoData && !hasMetadata {
+		return info, false, nil
+	}
+
+	if hasNoData && noData.Value.String != nil {
+		text := strings.TrimSpace(*noData.Value.String)
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return info, true, fmt.Errorf("invalid GDAL nodata value %q", text)
+		}
+		info.NoData = &value
+	}
+	if hasMetadata && metadata.Value.String != nil {
+		items, err := parseGDALMetadata(*metadata.Value.String)
+		if err != nil {
+			return info, true, err
+		}
+		info.Items = items
+	}
+
+	return info, true, nil
+}
+
+// Dataset returns the items of the default domain that apply to the whole dataset, by name.
+func (g GDALInfo) Dataset() map[string]string {
+	res := make(map[string]string)
+	for _, item := range g.Items {
+		if item.Sample < 0 && item.Role == "" && item.Domain == "" {
+			res[item.Name] = item.Value
+		}
+	}
+
+	return res
+}
+
+// Band returns the properties and metadata of a band, given its index (starting at 0). Scale is 1 and Offset 0 when
+// they are not set.
+func (g GDALInfo) Band(sample int) GDALBand {
+	band := GDALBand{Scale: 1, Metadata: make(map[string]string)}
+	for _, item := range g.Items {
+		if item.Sample != sample || item.Domain != "" {
+			continue
+		}
+		switch item.Role {
+		case "":
+			band.Metadata[item.Name] = item.Value
+		case "description":
+			band.Description = item.Value
+		case "unittype":
+			band.Unit = item.Value
+		case "scale":
+			if v, err := strconv.ParseFloat(strings.TrimSpace(item.Value), 64); err == nil {
+				band.Scale = v
+			}
+		case "offset":
+			if v, err := strconv.ParseFloat(strings.TrimSpace(item.Value), 64); err == nil {
+				band.Offset = v
+			}
+		}
+	}
+
+	return band
+}
+
+// parseGDALMetadata parses the XML document of the GDALMetadata entry, e.g.
+//
+//	<GDALMetadata>
+//	  <Item name="STATISTICS_MEAN" sample="0">12.5</Item>
+//	  <Item name="SCALE" sample="0" role="scale">0.01</Item>
+//	</GDALMetadata>
+func parseGDALMetadata(text string) ([]GDALItem, error) {
+	var doc struct {
+		Items []struct {
+			Name   string  `xml:"name,attr"`
+			Sample *string `xml:"sample,attr"`
+			Role   string  `xml:"role,attr"`
+			Domain string  `xml:"domain,attr"`
+			Value  string  `xml:",chardata"`
+		} `xml:"Item"`
+	}
+	if err := xml.Unmarshal([]byte(strings.TrimRight(text, "\x00")), &doc); err != nil {
+		return nil, fmt.Errorf("invalid GDAL metadata: %w", err)
+	}
+
+	items := make([]GDALItem, len(doc.Items))
+	for i, item := range doc.Items {
+		items[i] = GDALItem{Name: item.Name, Value: item.Value, Sample: -1, Role: item.Role, Domain: item.Domain}
+		if item.Sample != nil {
+			sample, err := strconv.Atoi(*item.Sample)
+			if err != nil || sample < 0 {
+				return nil, fmt.Errorf("invalid sample %q of GDAL metadata item %s", *item.Sample, item.Name)
+			}
+			items[i].Sample = sample
+		}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,97 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const gdalMetadata = `<GDALMetadata>
+  <Item name="AREA_OR_POINT">Area</Item>
+  <Item name="COMPRESSION" domain="IMAGE_STRUCTURE">DEFLATE</Item>
+  <Item name="STATISTICS_MEAN" sample="0">12.5</Item>
+  <Item name="DESCRIPTION" sample="0" role="description">Elevation</Item>
+  <Item name="UNITTYPE" sample="0" role="unittype">m</Item>
+  <Item name="SCALE" sample="0" role="scale">0.01</Item>
+  <Item name="OFFSET" sample="0" role="offset">-100</Item>
+  <Item name="STATISTICS_MEAN" sample="1">3</Item>
+</GDALMetadata>
+`
+
+func gdalPage(t *testing.T, build func(ifd *test.IFDBuilder)) Page {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	build(b.AddIFD().WithUints16(uint16(ImageWidth), 1))
+
+	p, err := NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+	page, err := p.FirstPage()
+	assert.NoError(t, err)
+
+	return page
+}
+
+func TestPage_GDAL(t *testing.T) {
+	page := gdalPage(t, func(ifd *test.IFDBuilder) {
+		ifd.WithString(uint16(GDALMetadata), gdalMetadata).WithString(uint16(GDALNoData), "-9999")
+	})
+
+	info, ok, err := page.GDAL()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	if assert.NotNil(t, info.NoData) {
+		assert.Equal(t, -9999.0, *info.NoData)
+	}
+	assert.Len(t, info.Items, 8)
+	assert.Equal(t, GDALItem{Name: "COMPRESSION", Value: "DEFLATE", Sample: -1, Domain: "IMAGE_STRUCTURE"}, info.Items[1])
+	assert.Equal(t, map[string]string{"AREA_OR_POINT": "Area"}, info.Dataset())
+
+	assert.Equal(t, GDALBand{
+		Description: "Elevation",
+		Unit:        "m",
+		Scale:       0.01,
+		Offset:      -100,
+		Metadata:    map[string]string{"STATISTICS_MEAN": "12.5"},
+	}, info.Band(0))
+	assert.Equal(t, GDALBand{Scale: 1, Metadata: map[string]string{"STATISTICS_MEAN": "3"}}, info.Band(1))
+}
+
+func TestPage_GDAL_NoData(t *testing.T) {
+	page := gdalPage(t, func(ifd *test.IFDBuilder) {
+		ifd.WithString(uint16(GDALNoData), "nan")
+	})
+
+	info, ok, err := page.GDAL()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, math.IsNaN(*info.NoData))
+	assert.Empty(t, info.Items)
+}
+
+func TestPage_GDAL_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(ifd *test.IFDBuilder)
+	}{
+		{"nodata", func(ifd *test.IFDBuilder) { ifd.WithString(uint16(GDALNoData), "none") }},
+		{"metadata", func(ifd *test.IFDBuilder) { ifd.WithString(uint16(GDALMetadata), "<GDALMetadata><Item>") }},
+		{"sample", func(ifd *test.IFDBuilder) {
+			ifd.WithString(uint16(GDALMetadata), `<GDALMetadata><Item name="A" sample="x">1</Item></GDALMetadata>`)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok, err := gdalPage(t, tt.build).GDAL()
+			assert.True(t, ok)
+			assert.Error(t, err)
+		})
+	}
+
+	_, ok, err := gdalPage(t, func(ifd *test.IFDBuilder) {}).GDAL()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
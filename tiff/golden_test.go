@@ -0,0 +1,175 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files of testdata/golden")
+
+// goldenFixtures builds tiny files emulating the layouts of various camera formats. Their expected content is stored in
+// testdata/golden/<name>.json: run `go test ./tiff -run TestGolden -update` to regenerate it after a change.
+var goldenFixtures = map[string]func() []byte{
+	// Nikon NEF: big-endian, preview and raw image in SubIFDs, maker note with its own TIFF header
+	"nef": func() []byte {
+		b := test.NewTIFFBuilder(binary.BigEndian)
+		ifd0 := b.AddIFD().
+			WithUints32(uint16(NewSubfileType), 1).
+			WithUints32(uint16(ImageWidth), 160).
+			WithUints32(uint16(ImageHeight), 120).
+			WithString(uint16(Make), "NIKON CORPORATION").
+			WithString(uint16(Model), "NIKON D850")
+		ifd0.WithSubIFD(uint16(SubIFDs)).
+			WithUints32(uint16(NewSubfileType), 1).
+			WithData(0x201, 0x202, []byte{0xff, 0xd8, 0xff, 0xd9}) // JPEGInterchangeFormat(Length)
+		exif := ifd0.WithSubIFD(uint16(Exif)).
+			WithURationals(uint16(ExposureTime), 1, 250).
+			WithURationals(uint16(FNumber), 56, 10).
+			WithUints16(uint16(ISO), 400).
+			WithString(uint16(DateTimeOriginal), "2024:05:01 10:00:00")
+		exif.WithBytes(uint16(MakerNotes), test.TypeUndefined, append([]byte("Nikon\x00\x02\x10\x00\x00MM\x00\x2a\x00\x00\x00\x08"), 0, 0)...)
+		return b.Bytes()
+	},
+	// Sony ARW: little-endian, IFD#0 describes a preview, IFD#1 a JPEG thumbnail, the raw image is in a SubIFD
+	"arw": func() []byte {
+		b := test.NewTIFFBuilder(binary.LittleEndian)
+		ifd0 := b.AddIFD().
+			WithUints32(uint16(NewSubfileType), 1).
+			WithUints16(uint16(Compression), 6).
+			WithString(uint16(Make), "SONY").
+			WithString(uint16(Model), "ILCE-7M3")
+		ifd0.WithSubIFD(uint16(SubIFDs)).
+			WithUints32(uint16(NewSubfileType), 0).
+			WithUints32(uint16(ImageWidth), 6048).
+			WithUints32(uint16(ImageHeight), 4024).
+			WithUints16(uint16(BitsPerSample), 14).
+			WithUints16(uint16(Compression), 32767).
+			WithData(uint16(StripOffsets), uint16(StripByteCounts), []byte{1, 2, 3, 4, 5, 6, 7, 8})
+		ifd0.WithSubIFD(uint16(Exif)).
+			WithURationals(uint16(ExposureTime), 1, 60).
+			WithString(uint16(OffsetTimeOriginal), "+01:00")
+		b.AddIFD().
+			WithUints16(uint16(Compression), 6).
+			WithData(uint16(ThumbnailOffset), uint16(ThumbnailLength), []byte{0xff, 0xd8, 0xff, 0xd9})
+		return b.Bytes()
+	},
+	// Adobe DNG: DNGVersion in IFD#0, raw image with DNG-specific tags in a SubIFD
+	"dng": func() []byte {
+		b := test.NewTIFFBuilder(binary.LittleEndian)
+		ifd0 := b.AddIFD().
+			WithUints32(uint16(NewSubfileType), 1).
+			WithString(uint16(Make), "Canon").
+			WithBytes(0xc612, test.TypeByte, 1, 4, 0, 0). // DNGVersion
+			WithRationals(uint16(ColorMatrix1), 6722, 10000, -635, 10000, -963, 10000).
+			WithURationals(uint16(AsShotNeutral), 473, 1000, 1, 1, 661, 1000).
+			WithRationals(uint16(BaselineExposure), -1, 4).
+			WithString(uint16(CameraSerialNumber), "012345678")
+		ifd0.WithSubIFD(uint16(SubIFDs)).
+			WithUints32(uint16(NewSubfileType), 0).
+			WithUints32(uint16(ImageWidth), 2).
+			WithUints32(uint16(ImageHeight), 2).
+			WithUints16(uint16(BitsPerSample), 16).
+			WithUints16(uint16(BlackLevel), 512).
+			WithUints16(uint16(WhiteLevel), 16383).
+			WithData(uint16(StripOffsets), uint16(StripByteCounts), make([]byte, 8))
+		return b.Bytes()
+	},
+	// IFD whose entries are not sorted by ID, as some editors write them
+	"unsorted": func() []byte {
+		b := test.NewTIFFBuilder(binary.LittleEndian)
+		b.AddIFD().
+			WithUints16(uint16(ImageWidth), 2).
+			WithUints16(uint16(ImageHeight), 2).
+			WithString(uint16(Make), "Olympus")
+		data := b.Bytes()
+		// swap the first and last entries
+		first, last := data[10:10+EntryLength], data[10+2*EntryLength:10+3*EntryLength]
+		swapped := bytes.Clone(first)
+		copy(first, last)
+		copy(last, swapped)
+		return data
+	},
+	// Panasonic RW2: TIFF-like, with its own magic number (not supported)
+	"rw2": func() []byte {
+		return []byte{'I', 'I', 0x55, 0x00, 0x18, 0x00, 0x00, 0x00}
+	},
+	// BigTIFF: 64-bit offsets, with its own magic number (not supported)
+	"bigtiff": func() []byte {
+		return []byte{'I', 'I', 0x2b, 0x00, 0x08, 0x00, 0x00, 0x00, 0x10, 0, 0, 0, 0, 0, 0, 0}
+	},
+}
+
+// golden is the expected content of a fixture.
+type golden struct {
+	Error   string        `json:"error,omitempty"` // error returned by NewParser or Find
+	Entries []goldenEntry `json:"entries,omitempty"`
+	Issues  []string      `json:"issues,omitempty"` // issues reported by Validate
+}
+
+type goldenEntry struct {
+	Group    string `json:"group"`
+	IFD      int64  `json:"ifd"`
+	ID       string `json:"id"`
+	Name     string `json:"name,omitempty"`
+	DataType uint16 `json:"type"`
+	Length   uint32 `json:"count"`
+	Value    any    `json:"value"`
+}
+
+func readGolden(data []byte) golden {
+	p, err := NewParser(bytes.NewReader(data))
+	if err != nil {
+		return golden{Error: err.Error()}
+	}
+
+	var res golden
+	entries, err := p.Find(func(Entry) bool { return true })
+	if err != nil {
+		return golden{Error: err.Error()}
+	}
+	for _, entry := range entries {
+		res.Entries = append(res.Entries, goldenEntry{
+			Group:    entry.Group.String(),
+			IFD:      entry.IFDOffset,
+			ID:       fmt.Sprintf("0x%04x", uint16(entry.ID)),
+			Name:     entry.ID.Name(),
+			DataType: uint16(entry.DataType),
+			Length:   entry.Length,
+			Value:    entry.Value.Interface(),
+		})
+	}
+	for _, issue := range p.Validate().Issues {
+		res.Issues = append(res.Issues, issue.String())
+	}
+
+	return res
+}
+
+func TestGolden(t *testing.T) {
+	for name, build := range goldenFixtures {
+		t.Run(name, func(t *testing.T) {
+			got, err := json.MarshalIndent(readGolden(build()), "", "  ")
+			assert.NoError(t, err)
+			got = append(got, '\n')
+
+			path := filepath.Join("testdata", "golden", name+".json")
+			if *update {
+				assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+				assert.NoError(t, os.WriteFile(path, got, 0o644))
+			}
+
+			want, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, string(want), string(got))
+		})
+	}
+}
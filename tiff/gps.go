@@ -0,0 +1,126 @@
+package tiff
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Direction is a direction, in degrees from 0 (north) to 360, clockwise.
+type Direction struct {
+	Degrees  float64
+	Magnetic bool // relative to the magnetic north rather than to the true north
+}
+
+// True returns the direction relative to the true north, given the magnetic declination at the location where it was
+// measured (in degrees, positive east of the true north). Directions that are already relative to the true north are
+// returned as they are.
+func (d Direction) True(declination float64) float64 {
+	if !d.Magnetic {
+		return d.Degrees
+	}
+
+	return math.Mod(d.Degrees+declination+360, 360)
+}
+
+// speedUnits maps the values of GPSSpeedRef to their unit, in km/h.
+var speedUnits = map[string]float64{
+	"K": 1,        // km/h
+	"M": 1.609344, // mph
+	"N": 1.852,    // knots
+}
+
+// ReadGPSSpeed reads the speed of the GPS receiver, in km/h, converted from the unit given by GPSSpeedRef (km/h if it
+// is missing).
+func ReadGPSSpeed(entries map[EntryID]Entry) (float64, error) {
+	speed, err := readFloat(entries, GPSSpeed)
+	if err != nil {
+		return 0, err
+	}
+
+	unit := readGPSRef(entries, GPSSpeedRef, "K")
+	factor, ok := speedUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown GPSSpeedRef %q", unit)
+	}
+
+	return speed * factor, nil
+}
+
+// ReadGPSPosition reads the position of the GPS receiver from GPSLatitude and GPSLongitude (in degrees, minutes and
+// seconds), in decimal degrees, negative south of the equator and west of the Greenwich meridian as given by
+// GPSLatitudeRef and GPSLongitudeRef (north and east if they are missing).
+func ReadGPSPosition(entries map[EntryID]Entry) (latitude, longitude float64, err error) {
+	if latitude, err = readGPSCoordinate(entries, GPSLatitude, GPSLatitudeRef, "N", "S", 90); err != nil {
+		return 0, 0, err
+	}
+	if longitude, err = readGPSCoordinate(entries, GPSLongitude, GPSLongitudeRef, "E", "W", 180); err != nil {
+		return 0, 0, err
+	}
+
+	return latitude, longitude, nil
+}
+
+// readGPSCoordinate reads a coordinate in degrees, minutes and seconds, negated if its reference is negative.
+func readGPSCoordinate(entries map[EntryID]Entry, id, refID EntryID, positive, negative string, limit float64) (float64, error) {
+	values, err := readFloats(entries, id)
+	if err != nil {
+		return 0, err
+	}
+	if len(values) != 3 {
+		return 0, fmt.Errorf("invalid %s %v", id.Name(), values)
+	}
+	value := values[0] + values[1]/60 + values[2]/3600
+	if math.IsNaN(value) || value > limit {
+		return 0, fmt.Errorf("invalid %s %v", id.Name(), values)
+	}
+
+	switch ref := readGPSRef(entries, refID, positive); ref {
+	case positive:
+		return value, nil
+	case negative:
+		return -value, nil
+	default:
+		return 0, fmt.Errorf("unknown %s %q", refID.Name(), ref)
+	}
+}
+
+// ReadGPSTrack reads the direction of movement of the GPS receiver, relative to the north given by GPSTrackRef (true
+// north if it is missing).
+func ReadGPSTrack(entries map[EntryID]Entry) (Direction, error) {
+	return readGPSDirection(entries, GPSTrack, GPSTrackRef)
+}
+
+// ReadGPSImgDirection reads the direction the camera was pointing to, relative to the north given by
+// GPSImgDirectionRef (true north if it is missing).
+func ReadGPSImgDirection(entries map[EntryID]Entry) (Direction, error) {
+	return readGPSDirection(entries, GPSImgDirection, GPSImgDirectionRef)
+}
+
+func readGPSDirection(entries map[EntryID]Entry, id, refID EntryID) (Direction, error) {
+	degrees, err := readFloat(entries, id)
+	if err != nil {
+		return Direction{}, err
+	}
+
+	switch ref := readGPSRef(entries, refID, "T"); ref {
+	case "T":
+		return Direction{Degrees: degrees}, nil
+	case "M":
+		return Direction{Degrees: degrees, Magnetic: true}, nil
+	default:
+		return Direction{}, fmt.Errorf("unknown %s %q", refID.Name(), ref)
+	}
+}
+
+// readGPSRef reads the value of a GPS reference entry (e.g. "K" for GPSSpeedRef), returning def if it is missing or
+// empty.
+func readGPSRef(entries map[EntryID]Entry, id EntryID, def string) string {
+	if entry, ok := entries[id]; ok && entry.Value.String != nil {
+		if ref := strings.ToUpper(strings.TrimSpace(*entry.Value.String)); ref != "" {
+			return ref
+		}
+	}
+
+	return def
+}
@@ -0,0 +1,135 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadGPS(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().WithSubIFD(uint16(GPSInfo)).
+		WithString(uint16(GPSSpeedRef), "M").
+		WithURationals(uint16(GPSSpeed), 60, 1).
+		WithString(uint16(GPSTrackRef), "T").
+		WithURationals(uint16(GPSTrack), 181, 2).
+		WithString(uint16(GPSImgDirectionRef), "M").
+		WithURationals(uint16(GPSImgDirection), 350, 1)
+
+	p, err := NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+	entries, err := p.Parse(GPSSpeedRef, GPSSpeed, GPSTrackRef, GPSTrack, GPSImgDirectionRef, GPSImgDirection)
+	assert.NoError(t, err)
+
+	speed, err := ReadGPSSpeed(entries)
+	assert.NoError(t, err)
+	assert.InDelta(t, 96.56, speed, 0.01)
+
+	track, err := ReadGPSTrack(entries)
+	assert.NoError(t, err)
+	assert.Equal(t, Direction{Degrees: 90.5}, track)
+	assert.Equal(t, 90.5, track.True(15))
+
+	direction, err := ReadGPSImgDirection(entries)
+	assert.NoError(t, err)
+	assert.Equal(t, Direction{Degrees: 350, Magnetic: true}, direction)
+	assert.InDelta(t, 5, direction.True(15), 1e-9)
+	assert.InDelta(t, 340, direction.True(-10), 1e-9)
+}
+
+func TestReadGPSSpeed(t *testing.T) {
+	speed := URational{Numerator: 12, Denominator: 1}
+	knots := "N"
+	unknown := "X"
+
+	tests := []struct {
+		name    string
+		entries map[EntryID]Entry
+		want    float64
+		wantErr bool
+	}{
+		{name: "missing", wantErr: true},
+		{name: "no reference", entries: map[EntryID]Entry{GPSSpeed: {Value: EntryValue{URational: &speed}}}, want: 12},
+		{name: "knots", entries: map[EntryID]Entry{
+			GPSSpeed:    {Value: EntryValue{URational: &speed}},
+			GPSSpeedRef: {Value: EntryValue{String: &knots}},
+		}, want: 22.224},
+		{name: "unknown reference", entries: map[EntryID]Entry{
+			GPSSpeed:    {Value: EntryValue{URational: &speed}},
+			GPSSpeedRef: {Value: EntryValue{String: &unknown}},
+		}, wantErr: true},
+		{name: "invalid value", entries: map[EntryID]Entry{
+			GPSSpeed: {ID: GPSSpeed, Value: EntryValue{URational: &URational{Numerator: 1}}},
+		}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadGPSSpeed(tt.entries)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+func TestReadGPSPosition(t *testing.T) {
+	dms := func(id EntryID, degrees, minutes, seconds uint32) Entry {
+		return Entry{ID: id, Value: EntryValue{URationals: []URational{{degrees, 1}, {minutes, 1}, {seconds, 100}}}}
+	}
+	ref := func(id EntryID, value string) Entry {
+		return Entry{ID: id, Value: EntryValue{String: &value}}
+	}
+
+	tests := []struct {
+		name                string
+		entries             map[EntryID]Entry
+		latitude, longitude float64
+		wantErr             bool
+	}{
+		{
+			name:      "no references",
+			entries:   map[EntryID]Entry{GPSLatitude: dms(GPSLatitude, 52, 22, 1234), GPSLongitude: dms(GPSLongitude, 4, 53, 0)},
+			latitude:  52.370094,
+			longitude: 4.883333,
+		},
+		{
+			name: "south west",
+			entries: map[EntryID]Entry{
+				GPSLatitude:     dms(GPSLatitude, 33, 51, 2442),
+				GPSLatitudeRef:  ref(GPSLatitudeRef, "S"),
+				GPSLongitude:    dms(GPSLongitude, 70, 40, 0),
+				GPSLongitudeRef: ref(GPSLongitudeRef, "w"),
+			},
+			latitude:  -33.856783,
+			longitude: -70.666667,
+		},
+		{name: "missing longitude", entries: map[EntryID]Entry{GPSLatitude: dms(GPSLatitude, 52, 22, 0)}, wantErr: true},
+		{
+			name:    "unknown reference",
+			entries: map[EntryID]Entry{GPSLatitude: dms(GPSLatitude, 52, 22, 0), GPSLatitudeRef: ref(GPSLatitudeRef, "X"), GPSLongitude: dms(GPSLongitude, 4, 53, 0)},
+			wantErr: true,
+		},
+		{
+			name:    "out of range",
+			entries: map[EntryID]Entry{GPSLatitude: dms(GPSLatitude, 91, 0, 0), GPSLongitude: dms(GPSLongitude, 4, 53, 0)},
+			wantErr: true,
+		},
+		{
+			name:    "not degrees, minutes and seconds",
+			entries: map[EntryID]Entry{GPSLatitude: {ID: GPSLatitude, Value: EntryValue{URational: &URational{52, 1}}}, GPSLongitude: dms(GPSLongitude, 4, 53, 0)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latitude, longitude, err := ReadGPSPosition(tt.entries)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.InDelta(t, tt.latitude, latitude, 1e-6)
+			assert.InDelta(t, tt.longitude, longitude, 1e-6)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package tiff
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// WithGroups restricts the IFDs the parser reads to those of the given groups (e.g. Group_IFD0 and Group_Exif), as a
+// hard guarantee that the others (e.g. GPS data or maker notes) never enter memory: their tables and values are not
+// even read, and the parser behaves as if the file did not have them. IFD#0 is always read, as the other IFDs are
+// reached from it, and so are the IFDs reached from excluded ones not (e.g. those of the main chain after IFD#1 when
+// Group_IFD1 is excluded). Excluding Group_MakerNote also leaves out the MakerNotes entry of the Exif IFD, which holds
+// the maker note. Calling it without groups lifts the restriction.
+func (p *Parser) WithGroups(groups ...Group) *Parser {
+	p.groups = slices.Clone(groups)
+
+	return p
+}
+
+// follows tells whether the parser reads the IFDs of a group (see WithGroups).
+func (p *Parser) follows(group Group) bool {
+	return len(p.groups) == 0 || group == Group_IFD0 || slices.Contains(p.groups, group)
+}
+
+// excludes tells whether the parser leaves out an entry of an IFD of the given group, without reading it: the maker
+// note, unless the parser reads Group_MakerNote (see WithGroups).
+func (p *Parser) excludes(group Group, id EntryID) bool {
+	return group == Group_Exif && id == MakerNotes && !p.follows(Group_MakerNote)
+}
+
+// errNotFollowed returns the error of reading an IFD of a group the parser does not read (see WithGroups).
+func errNotFollowed(group Group) error {
+	return fmt.Errorf("IFDs of group %s are not read (see WithGroups)", group)
+}
+
+// HasGroup tells whether the file has an IFD of the given group (e.g. whether it has GPS data at all), reading as
+// little of it as possible (see EntryCount).
+func (p *Parser) HasGroup(group Group) (bool, error) {
+	if group == Group_MakerNote {
+		_, ok, err := p.makerNote()
+		return ok, err
+	}
+	offsets, err := p.groupOffsets(group)
+
+	return len(offsets) > 0, err
+}
+
+// EntryCount returns the number of entries of the IFD of the given group, or 0 if the file has none; for groups made
+// of several IFDs (Group_SubIFD, Group_Image), the total number of entries of those IFDs. It only reads the headers of
+// IFDs (their number of entries and the offset of the next IFD) and, to find sub-IFDs, the entries of IFD#0 up to the
+// one pointing to them, rather than decoding any value. Exif, GPSInfo and SubIFD groups are those of the IFDs pointed
+// to by IFD#0. Groups created with RegisterGroup are not supported.
+func (p *Parser) EntryCount(group Group) (int, error) {
+	if group == Group_MakerNote {
+		m, ok, err := p.makerNote()
+		if !ok || err != nil {
+			return 0, err
+		}
+		count, err := m.Parser(p.reader).readEntriesCount(m.Offset - m.Base)
+		return int(count), truncated(err, Group_MakerNote, m.Offset)
+	}
+
+	offsets, err := p.groupOffsets(group)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, offset := range offsets {
+		count, err := p.readEntriesCount(offset)
+		if err != nil {
+			return 0, truncated(err, group, offset)
+		}
+		total += int(count)
+	}
+
+	return total, nil
+}
+
+// groupOffsets returns the offsets of the IFDs of a group, other than Group_MakerNote, or none if the parser does not
+// read them (see WithGroups).
+func (p *Parser) groupOffsets(group Group) ([]int64, error) {
+	if !p.follows(group) || (group == Group_Image && !p.follows(Group_IFD1)) {
+		return nil, nil
+	}
+
+	switch group {
+	case Group_IFD0:
+		return []int64{p.firstIFDOffset}, nil
+	case Group_IFD1, Group_Image:
+		chain, err := p.chainOffsets()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case group == Group_IFD1 && len(chain) > 1:
+			return chain[1:2], nil
+		case group == Group_Image && len(chain) > 2:
+			return chain[2:], nil
+		default:
+			return nil, nil
+		}
+	case Group_Exif, Group_GPSInfo, Group_SubIFD:
+		for _, pointer := range subIFDPointers {
+			if pointer.group != group {
+				continue
+			}
+			entries, err := p.collect(p.firstIFDOffset, Group_IFD0, newWanted(pointer.id))
+			if err != nil {
+				return nil, err
+			}
+			entry, ok := entries[pointer.id]
+			if !ok {
+				return nil, nil
+			}
+			values, err := entry.Uints()
+			if err != nil {
+				return nil, err
+			}
+			var offsets []int64
+			for _, v := range values {
+				if v != 0 {
+					offsets = append(offsets, int64(v))
+				}
+			}
+			return offsets, nil
+		}
+	}
+
+	return nil, fmt.Errorf("cannot look up IFDs of group %s", group)
+}
+
+// chainOffsets follows the main IFD chain, only reading the number of entries and the offset of the next IFD of each
+// IFD. It returns an error if the chain loops.
+func (p *Parser) chainOffsets() ([]int64, error) {
+	var offsets []int64
+	seen := make(map[int64]bool)
+	buffer := p.scratch[:4]
+
+	for offset := p.firstIFDOffset; offset != 0; {
+		if seen[offset] {
+			return nil, fmt.Errorf("IFD chain loops back to offset %d", offset)
+		}
+		seen[offset] = true
+		offsets = append(offsets, offset)
+
+		count, err := p.readEntriesCount(offset)
+		if err != nil {
+			return nil, truncated(err, pageGroup(len(offsets)-1), offset)
+		}
+		next := offset + 2 + int64(count)*EntryLength
+		if _, err := p.reader.Seek(next, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(p.reader, buffer); err != nil {
+			return nil, truncated(err, pageGroup(len(offsets)-1), next)
+		}
+		offset = int64(p.byteOrder.Uint32(buffer))
+	}
+
+	return offsets, nil
+}
+
+// makerNote returns the maker note of the file (see MakerNote), telling whether there is one that can be read. Only
+// truncation errors are returned.
+func (p *Parser) makerNote() (MakerNote, bool, error) {
+	m, err := p.MakerNote()
+	if errors.Is(err, ErrTruncated) {
+		return MakerNote{}, false, err
+	}
+
+	return m, err == nil, nil
+}
@@ -0,0 +1,118 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_EntryCount(t *testing.T) {
+	builder := test.NewTIFFBuilder(binary.LittleEndian)
+	builder.AddIFD().WithString(uint16(Make), "Canon").
+		WithSubIFD(uint16(SubIFDs)).WithUints32(uint16(ImageWidth), 640)
+	noGPS := builder.Bytes()
+
+	tests := []struct {
+		name  string
+		data  []byte
+		group Group
+		want  int
+	}{
+		{name: "CR2 IFD#0", data: cr2Image, group: Group_IFD0, want: 18},
+		{name: "CR2 GPS", data: cr2Image, group: Group_GPSInfo, want: 1},
+		{name: "CR2 IFD#1", data: cr2Image, group: Group_IFD1, want: 2},
+		{name: "CR2 images", data: cr2Image, group: Group_Image, want: 20},
+		{name: "CR2 maker note", data: cr2Image, group: Group_MakerNote, want: 41},
+		{name: "ORF without IFD#1", data: orfImage, group: Group_IFD1, want: 0},
+		{name: "no GPS", data: noGPS, group: Group_GPSInfo, want: 0},
+		{name: "no maker note", data: noGPS, group: Group_MakerNote, want: 0},
+		{name: "SubIFD", data: noGPS, group: Group_SubIFD, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(bytes.NewReader(tt.data))
+			assert.NoError(t, err)
+
+			count, err := p.EntryCount(tt.group)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, count)
+
+			has, err := p.HasGroup(tt.group)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want > 0, has)
+		})
+	}
+}
+
+func TestParser_EntryCount_Errors(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image[:48760]))
+	assert.NoError(t, err)
+	_, err = p.EntryCount(Group_IFD1)
+	assert.ErrorIs(t, err, ErrTruncated)
+
+	_, err = p.EntryCount(RegisterGroup("EntryCountTest"))
+	assert.Error(t, err)
+}
+
+// seekRecorder records the offsets a file is read from.
+type seekRecorder struct {
+	io.ReadSeeker
+	offsets []int64
+}
+
+func (s *seekRecorder) Seek(offset int64, whence int) (int64, error) {
+	n, err := s.ReadSeeker.Seek(offset, whence)
+	s.offsets = append(s.offsets, n)
+
+	return n, err
+}
+
+func TestParser_WithGroups(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	gpsOffsets, err := p.groupOffsets(Group_GPSInfo)
+	assert.NoError(t, err)
+	assert.Len(t, gpsOffsets, 1)
+	m, err := p.MakerNote()
+	assert.NoError(t, err)
+
+	r := &seekRecorder{ReadSeeker: bytes.NewReader(cr2Image)}
+	p, err = NewParser(r)
+	assert.NoError(t, err)
+	p.WithGroups(Group_IFD0, Group_Exif)
+
+	entries, err := p.Parse(Make, ExposureTime, GPSVersionID, MakerNotes)
+	assert.NoError(t, err)
+	assert.Contains(t, entries, Make)
+	assert.Contains(t, entries, ExposureTime)
+	assert.NotContains(t, entries, GPSVersionID)
+	assert.NotContains(t, entries, MakerNotes)
+
+	has, err := p.HasGroup(Group_GPSInfo)
+	assert.NoError(t, err)
+	assert.False(t, has)
+	_, err = p.MakerNote()
+	assert.Error(t, err)
+
+	infos, err := p.ListIDs()
+	assert.NoError(t, err)
+	for _, info := range infos {
+		assert.Contains(t, []Group{Group_IFD0, Group_Exif}, info.Group)
+		assert.NotEqual(t, MakerNotes, info.ID)
+	}
+	pages, err := p.Pages()
+	assert.NoError(t, err)
+	assert.Len(t, pages, 1)
+
+	assert.NotContains(t, r.offsets, gpsOffsets[0])
+	assert.NotContains(t, r.offsets, m.Offset)
+
+	p.WithGroups()
+	entries, err = p.Parse(GPSVersionID)
+	assert.NoError(t, err)
+	assert.Contains(t, entries, GPSVersionID)
+}
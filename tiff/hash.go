@@ -0,0 +1,41 @@
+package tiff
+
+import (
+	"hash"
+	"io"
+)
+
+// ImageDataHash writes the image data of all pages of the main IFD chain to h, i.e. their strips or tiles, in order. The
+// image data is hashed as it is stored in the file (i.e. still compressed) and metadata is ignored, so that files with
+// identical images but different metadata (e.g. edited tags) give the same hash. Pages without image data are skipped.
+func (p *Parser) ImageDataHash(h hash.Hash) error {
+	pages, err := p.Pages()
+	if err != nil {
+		return err
+	}
+
+	for _, page := range pages {
+		offsetsID, lengthsID := StripOffsets, StripByteCounts
+		if page.Tiled() {
+			offsetsID, lengthsID = TileOffsets, TileByteCounts
+		}
+		if _, ok := page.Entries[offsetsID]; !ok {
+			continue
+		}
+
+		offsets, lengths, err := page.chunks(offsetsID, lengthsID)
+		if err != nil {
+			return err
+		}
+		for i := range offsets {
+			if _, err := p.reader.Seek(int64(offsets[i]), io.SeekStart); err != nil {
+				return err
+			}
+			if _, err := io.CopyN(h, p.reader, int64(lengths[i])); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package tiff
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageDataHash(t *testing.T) {
+	build := func(description string, byteOrder test.ByteOrder, strips ...[]byte) []byte {
+		b := test.NewTIFFBuilder(byteOrder)
+		b.AddIFD().
+			WithString(uint16(ImageDescription), description).
+			WithData(uint16(StripOffsets), uint16(StripByteCounts), strips...)
+		b.AddIFD().
+			WithUints16(uint16(TileWidth), 16).
+			WithData(uint16(TileOffsets), uint16(TileByteCounts), []byte{7, 8, 9})
+		b.AddIFD().
+			WithString(uint16(ImageDescription), "no image data")
+
+		return b.Bytes()
+	}
+	hash := func(data []byte) []byte {
+		p, err := NewParser(bytes.NewReader(data))
+		assert.NoError(t, err)
+		h := sha256.New()
+		assert.NoError(t, p.ImageDataHash(h))
+		return h.Sum(nil)
+	}
+
+	want := sha256.Sum256([]byte{1, 2, 3, 4, 5, 7, 8, 9})
+	original := hash(build("original", binary.LittleEndian, []byte{1, 2, 3}, []byte{4, 5}))
+	assert.Equal(t, want[:], original)
+	assert.Equal(t, original, hash(build("a much longer description, moving the strips", binary.BigEndian, []byte{1, 2, 3}, []byte{4, 5})))
+	assert.NotEqual(t, original, hash(build("original", binary.LittleEndian, []byte{1, 2, 3}, []byte{4, 6})))
+}
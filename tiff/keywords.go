@@ -0,0 +1,39 @@
+package tiff
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// ReadXPKeywords reads the keywords set by Windows Explorer in XPKeywords, which holds them as UTF-16 little-endian
+// text (whatever the byte order of the file), separated by semicolons. Empty keywords are left out.
+func ReadXPKeywords(entries map[EntryID]Entry) ([]string, error) {
+	entry, ok := entries[XPKeywords]
+	if !ok {
+		return nil, fmt.Errorf("entry 0x%X not found", XPKeywords)
+	}
+	data := entry.Value.Bytes
+	if entry.Value.UByte != nil {
+		data = []byte{*entry.Value.UByte}
+	}
+	if len(data)%2 != 0 {
+		return nil, fmt.Errorf("invalid XPKeywords of %d bytes", len(data))
+	}
+
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(data[2*i:])
+	}
+	text := strings.TrimRight(string(utf16.Decode(units)), "\x00")
+
+	var keywords []string
+	for _, keyword := range strings.Split(text, ";") {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+
+	return keywords, nil
+}
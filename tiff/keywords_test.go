@@ -0,0 +1,58 @@
+package tiff
+
+import (
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadXPKeywords(t *testing.T) {
+	ucs2 := func(s string) []byte {
+		var data []byte
+		for _, unit := range utf16.Encode([]rune(s)) {
+			data = binary.LittleEndian.AppendUint16(data, unit)
+		}
+		return data
+	}
+
+	tests := []struct {
+		name    string
+		entries map[EntryID]Entry
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "keywords",
+			entries: map[EntryID]Entry{XPKeywords: {ID: XPKeywords, Value: EntryValue{Bytes: ucs2("cat; dog;;Zürich\x00")}}},
+			want:    []string{"cat", "dog", "Zürich"},
+		},
+		{
+			name:    "empty",
+			entries: map[EntryID]Entry{XPKeywords: {ID: XPKeywords, Value: EntryValue{Bytes: ucs2("\x00")}}},
+		},
+		{
+			name:    "odd length",
+			entries: map[EntryID]Entry{XPKeywords: {ID: XPKeywords, Value: EntryValue{Bytes: []byte{'c', 0, 'a'}}}},
+			wantErr: true,
+		},
+		{
+			name:    "missing",
+			entries: map[EntryID]Entry{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadXPKeywords(tt.entries)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
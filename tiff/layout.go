@@ -0,0 +1,148 @@
+package tiff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// RegionKind tells what a region of a TIFF file holds.
+type RegionKind int
+
+const (
+	Region_Header    RegionKind = iota // TIFF header
+	Region_IFD                         // IFD table: number of entries, entries and offset of the next IFD
+	Region_Value                       // value of an entry that does not fit in the entry itself
+	Region_MakerNote                   // value of the MakerNotes entry
+	Region_Thumbnail                   // embedded (JPEG) thumbnail or preview, pointed to by ThumbnailOffset
+	Region_ImageData                   // strips or tiles
+)
+
+func (k RegionKind) String() string {
+	switch k {
+	case Region_Header:
+		return "header"
+	case Region_IFD:
+		return "IFD"
+	case Region_Value:
+		return "value"
+	case Region_MakerNote:
+		return "maker note"
+	case Region_Thumbnail:
+		return "thumbnail"
+	case Region_ImageData:
+		return "image data"
+	default:
+		return fmt.Sprintf("RegionKind(%d)", int(k))
+	}
+}
+
+// Region is a range of bytes of a TIFF file.
+type Region struct {
+	Kind      RegionKind
+	Offset    int64
+	Length    int64
+	IFDOffset int64   // offset of the IFD the region belongs to (0 for the header)
+	Entry     EntryID // entry pointing to the region (0 for the header and IFD tables)
+}
+
+// End returns the offset of the first byte after the region.
+func (r Region) End() int64 {
+	return r.Offset + r.Length
+}
+
+// Layout returns the regions of the file that are referenced by its structure, sorted by offset: the header, the tables
+// of all IFDs (main chain, Exif, GPSInfo and SubIFDs), the values of their entries, maker notes, thumbnails and image
+// data. Contiguous strips or tiles of the same IFD are merged in a single region. Bytes that are not part of any region
+// are not referenced by the file (e.g. padding, or data left behind by an editor).
+func (p *Parser) Layout() ([]Region, error) {
+	l := &layout{parser: p}
+	l.regions = append(l.regions, Region{Kind: Region_Header, Offset: 0, Length: 8})
+	if err := p.walk(func(offset int64, _ Group, entries map[EntryID]Entry) error {
+		return l.addIFD(offset, entries)
+	}); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(l.regions, func(i, j int) bool { return l.regions[i].Offset < l.regions[j].Offset })
+
+	return l.regions, nil
+}
+
+// layout collects the regions of a file.
+type layout struct {
+	parser  *Parser
+	regions []Region
+}
+
+// addIFD adds the regions of an IFD.
+func (l *layout) addIFD(offset int64, entries map[EntryID]Entry) error {
+	count, err := l.parser.readEntriesCount(offset)
+	if err != nil {
+		return err
+	}
+	l.regions = append(l.regions, Region{Kind: Region_IFD, Offset: offset, Length: 2 + int64(count)*EntryLength + 4, IFDOffset: offset})
+
+	for id, entry := range entries {
+		size := int64(entry.DataType.Size()) * int64(entry.Length)
+		if size <= 4 {
+			continue
+		}
+		kind := Region_Value
+		if id == MakerNotes {
+			kind = Region_MakerNote
+		}
+		l.regions = append(l.regions, Region{Kind: kind, Offset: int64(entry.RawValue), Length: size, IFDOffset: offset, Entry: id})
+	}
+
+	page := Page{Offset: offset, Entries: entries}
+	if thumbnailOffset, ok := page.Uint(ThumbnailOffset); ok {
+		if length, ok := page.Uint(ThumbnailLength); ok {
+			l.regions = append(l.regions, Region{Kind: Region_Thumbnail, Offset: int64(thumbnailOffset), Length: int64(length), IFDOffset: offset, Entry: ThumbnailOffset})
+		}
+	}
+
+	for _, ids := range [][2]EntryID{{StripOffsets, StripByteCounts}, {TileOffsets, TileByteCounts}} {
+		if _, ok := entries[ids[0]]; !ok {
+			continue
+		}
+		offsets, lengths, err := page.chunks(ids[0], ids[1])
+		if err != nil {
+			return err
+		}
+		l.addImageData(offset, ids[0], offsets, lengths)
+	}
+
+	return nil
+}
+
+// addImageData adds the regions of strips or tiles, merging contiguous ones.
+func (l *layout) addImageData(ifdOffset int64, id EntryID, offsets, lengths []uint32) {
+	var current *Region
+	for i := range offsets {
+		if current != nil && current.End() == int64(offsets[i]) {
+			current.Length += int64(lengths[i])
+			continue
+		}
+		l.regions = append(l.regions, Region{Kind: Region_ImageData, Offset: int64(offsets[i]), Length: int64(lengths[i]), IFDOffset: ifdOffset, Entry: id})
+		current = &l.regions[len(l.regions)-1]
+	}
+}
+
+// readEntriesCount reads the number of entries of the IFD starting at the given offset.
+func (p *Parser) readEntriesCount(offset int64) (uint16, error) {
+	if _, err := p.reader.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	buffer := make([]byte, 2)
+	if _, err := io.ReadFull(p.reader, buffer); err != nil {
+		return 0, err
+	}
+
+	count := p.byteOrder.Uint16(buffer)
+	if err := p.checkEntriesCount(int(count)); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
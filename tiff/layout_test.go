@@ -0,0 +1,59 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayout_CR2(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+
+	regions, err := p.Layout()
+	assert.NoError(t, err)
+
+	assert.Equal(t, Region{Kind: Region_Header, Offset: 0, Length: 8}, regions[0])
+	assert.Equal(t, Region{Kind: Region_IFD, Offset: 16, Length: 222, IFDOffset: 16}, regions[1])
+
+	count := make(map[RegionKind]int)
+	for i, r := range regions {
+		count[r.Kind]++
+		if i > 0 {
+			assert.LessOrEqual(t, regions[i-1].Offset, r.Offset)
+		}
+		if r.Kind == Region_MakerNote {
+			assert.Equal(t, Region{Kind: Region_MakerNote, Offset: 984, Length: 45494, IFDOffset: 446, Entry: MakerNotes}, r)
+		}
+	}
+	assert.Equal(t, 6, count[Region_IFD], "4 pages, Exif and GPSInfo")
+	assert.Equal(t, 1, count[Region_MakerNote])
+	assert.Equal(t, 1, count[Region_Thumbnail])
+	assert.Equal(t, 3, count[Region_ImageData])
+}
+
+func TestLayout_MergesContiguousImageData(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().
+		WithString(uint16(ImageDescription), "strips").
+		WithData(uint16(StripOffsets), uint16(StripByteCounts), []byte{1, 2}, []byte{3, 4}, []byte{5, 6, 7, 8}) // even lengths, as the builder word-aligns data
+
+	p, err := NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+	regions, err := p.Layout()
+	assert.NoError(t, err)
+
+	var data []Region
+	for _, r := range regions {
+		if r.Kind == Region_ImageData {
+			data = append(data, r)
+		}
+	}
+	assert.Len(t, data, 1)
+	assert.EqualValues(t, 8, data[0].Length)
+	assert.Equal(t, StripOffsets, data[0].Entry)
+	assert.Equal(t, "image data", data[0].Kind.String())
+}
@@ -0,0 +1,109 @@
+package tiff
+
+import (
+	"fmt"
+	"math"
+)
+
+// fullFrameDiagonal is the diagonal of a 35mm film frame (36x24mm), in millimeters.
+var fullFrameDiagonal = math.Hypot(36, 24)
+
+// focalPlaneUnits maps the values of FocalPlaneResolutionUnit to their length, in millimeters.
+var focalPlaneUnits = map[uint64]float64{
+	2: 25.4,  // inches
+	3: 10,    // centimeters
+	4: 1,     // millimeters
+	5: 0.001, // micrometers
+}
+
+// ReadFocalLengthIn35mm reads the focal length of the lens giving the same angle of view on 35mm film, in millimeters,
+// from FocalLengthIn35mmFilm or, if it is missing or 0 (unknown), computes it from FocalLength and the size of the
+// sensor (see ReadSensorSize).
+func ReadFocalLengthIn35mm(entries map[EntryID]Entry) (float64, error) {
+	if _, ok := entries[FocalLengthIn35mmFilm]; ok {
+		if value, err := readFloat(entries, FocalLengthIn35mmFilm); err == nil && value > 0 {
+			return value, nil
+		}
+	}
+
+	focalLength, err := readPositiveFloat(entries, FocalLength)
+	if err != nil {
+		return 0, err
+	}
+	width, height, err := ReadSensorSize(entries)
+	if err != nil {
+		return 0, err
+	}
+
+	return focalLength * fullFrameDiagonal / math.Hypot(width, height), nil
+}
+
+// ReadSensorSize computes the size of the sensor, in millimeters, from the dimensions of the image (see ReadImageSize)
+// and the resolution of the focal plane (FocalPlaneXResolution, FocalPlaneYResolution and FocalPlaneResolutionUnit,
+// inches if it is missing). FocalPlaneXResolution is used for both dimensions if FocalPlaneYResolution is missing.
+func ReadSensorSize(entries map[EntryID]Entry) (width, height float64, err error) {
+	unit := uint64(2)
+	if _, ok := entries[FocalPlaneResolutionUnit]; ok {
+		if unit, err = readUint(entries, FocalPlaneResolutionUnit); err != nil {
+			return 0, 0, err
+		}
+	}
+	mm, ok := focalPlaneUnits[unit]
+	if !ok {
+		return 0, 0, fmt.Errorf("unsupported FocalPlaneResolutionUnit %d", unit)
+	}
+
+	xResolution, err := readPositiveFloat(entries, FocalPlaneXResolution)
+	if err != nil {
+		return 0, 0, err
+	}
+	yResolution := xResolution
+	if _, ok := entries[FocalPlaneYResolution]; ok {
+		if yResolution, err = readPositiveFloat(entries, FocalPlaneYResolution); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	pixelsX, pixelsY, err := ReadImageSize(entries)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return float64(pixelsX) / xResolution * mm, float64(pixelsY) / yResolution * mm, nil
+}
+
+// ReadCircleOfConfusion computes the diameter of the circle of confusion, in millimeters, as exiftool does: the
+// diagonal of the sensor divided by 1440, the sensor being scaled down from 35mm film by the ratio of the focal length
+// in 35mm (see ReadFocalLengthIn35mm) to FocalLength.
+func ReadCircleOfConfusion(entries map[EntryID]Entry) (float64, error) {
+	focalLength, err := readPositiveFloat(entries, FocalLength)
+	if err != nil {
+		return 0, err
+	}
+	focalLengthIn35mm, err := ReadFocalLengthIn35mm(entries)
+	if err != nil {
+		return 0, err
+	}
+
+	return fullFrameDiagonal / 1440 * focalLength / focalLengthIn35mm, nil
+}
+
+// ReadHyperfocalDistance computes the hyperfocal distance, in meters, from FocalLength, the f-number (see ReadFNumber)
+// and the circle of confusion (see ReadCircleOfConfusion): focusing at that distance keeps everything from half of it
+// to infinity acceptably sharp.
+func ReadHyperfocalDistance(entries map[EntryID]Entry) (float64, error) {
+	focalLength, err := readPositiveFloat(entries, FocalLength)
+	if err != nil {
+		return 0, err
+	}
+	fNumber, err := ReadFNumber(entries)
+	if err != nil {
+		return 0, err
+	}
+	coc, err := ReadCircleOfConfusion(entries)
+	if err != nil {
+		return 0, err
+	}
+
+	return focalLength * focalLength / (fNumber * coc) / 1000, nil
+}
@@ -0,0 +1,94 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFocalLengthIn35mm_CR2(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	entries, err := p.Parse(FocalLength, FocalLengthIn35mmFilm, FocalPlaneXResolution, FocalPlaneYResolution,
+		FocalPlaneResolutionUnit, ImageWidth, ImageHeight)
+	assert.NoError(t, err)
+
+	width, height, err := ReadSensorSize(entries)
+	assert.NoError(t, err)
+	assert.InDelta(t, 23.04, width, 0.01)
+	assert.InDelta(t, 15.11, height, 0.01)
+
+	// the EOS 7D has a crop factor of 1.6
+	focalLength, err := ReadFocalLengthIn35mm(entries)
+	assert.NoError(t, err)
+	assert.InDelta(t, 26.7, focalLength, 0.1)
+}
+
+func TestReadFocalLengthIn35mm(t *testing.T) {
+	tests := []struct {
+		name    string
+		build   func(ifd *test.IFDBuilder)
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "from FocalLengthIn35mmFilm",
+			build: func(ifd *test.IFDBuilder) {
+				ifd.WithURationals(uint16(FocalLength), 50, 1).WithUints16(uint16(FocalLengthIn35mmFilm), 75)
+			},
+			want: 75,
+		},
+		{
+			name: "computed when FocalLengthIn35mmFilm is unknown",
+			build: func(ifd *test.IFDBuilder) {
+				// a 36x24mm sensor, in millimeters
+				ifd.WithURationals(uint16(FocalLength), 50, 1).
+					WithUints16(uint16(FocalLengthIn35mmFilm), 0).
+					WithURationals(uint16(FocalPlaneXResolution), 100, 1).
+					WithUints16(uint16(FocalPlaneResolutionUnit), 4)
+			},
+			want: 50,
+		},
+		{
+			name: "unsupported unit",
+			build: func(ifd *test.IFDBuilder) {
+				ifd.WithURationals(uint16(FocalLength), 50, 1).
+					WithURationals(uint16(FocalPlaneXResolution), 100, 1).
+					WithUints16(uint16(FocalPlaneResolutionUnit), 1)
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing focal plane resolution",
+			build: func(ifd *test.IFDBuilder) {
+				ifd.WithURationals(uint16(FocalLength), 50, 1)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := test.NewTIFFBuilder(binary.LittleEndian)
+			ifd0 := b.AddIFD().WithUints16(uint16(ImageWidth), 3600).WithUints16(uint16(ImageHeight), 2400)
+			tt.build(ifd0.WithSubIFD(uint16(Exif)))
+
+			p, err := NewParser(bytes.NewReader(b.Bytes()))
+			assert.NoError(t, err)
+			entries, err := p.Parse(ImageWidth, ImageHeight, FocalLength, FocalLengthIn35mmFilm, FocalPlaneXResolution,
+				FocalPlaneResolutionUnit)
+			assert.NoError(t, err)
+
+			got, err := ReadFocalLengthIn35mm(entries)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package tiff
+
+import "fmt"
+
+// WithMaxValueSize limits the size, in bytes, of the buffers the parser allocates from lengths read from the file:
+// values of entries, thumbnails, strips and tiles. Reading anything larger fails with an error matching
+// ErrLimitExceeded, before any allocation. A size of 0 (the default) means no limit.
+func (p *Parser) WithMaxValueSize(n int64) *Parser {
+	p.maxValueSize = n
+
+	return p
+}
+
+// WithMaxEntriesPerIFD limits the number of entries of the IFDs the parser reads: reading a larger IFD fails with an
+// error matching ErrLimitExceeded. A number of 0 (the default) means no limit.
+func (p *Parser) WithMaxEntriesPerIFD(n int) *Parser {
+	p.maxEntriesPerIFD = n
+
+	return p
+}
+
+// WithSkipValuesLargerThan makes Parse (and its variants, such as ParseEach and ParseAll) skip decoding values larger
+// than n bytes, such as maker notes of tens of megabytes or full-size JPEG previews: their entries are still returned,
+// with their data type, count and offset, but with an empty Value and Skipped set, so that they can be streamed later
+// with ValueReader if needed. Unlike WithMaxValueSize, this is not an error. A size of 0 (the default) means that all
+// values are decoded.
+func (p *Parser) WithSkipValuesLargerThan(n int64) *Parser {
+	p.skipValueSize = n
+
+	return p
+}
+
+// skipsValue tells whether the value of an encoded entry is larger than the size set with WithSkipValuesLargerThan.
+func (p *Parser) skipsValue(buffer []byte) bool {
+	dt := DataType(p.byteOrder.Uint16(buffer[2:4]))
+	length := p.byteOrder.Uint32(buffer[4:8])
+
+	return p.skipValueSize > 0 && int64(dt.Size())*int64(length) > p.skipValueSize
+}
+
+// skippedEntry decodes an entry, but not its value (see WithSkipValuesLargerThan).
+func (p *Parser) skippedEntry(buffer []byte) Entry {
+	return Entry{
+		ID:       EntryID(p.byteOrder.Uint16(buffer[:2])),
+		DataType: DataType(p.byteOrder.Uint16(buffer[2:4])),
+		Length:   p.byteOrder.Uint32(buffer[4:8]),
+		RawValue: p.byteOrder.Uint32(buffer[8:12]),
+		Skipped:  true,
+	}
+}
+
+// checkValueSize returns an error if a buffer of the given size exceeds the limit set with WithMaxValueSize.
+func (p *Parser) checkValueSize(size int64) error {
+	if p.maxValueSize > 0 && size > p.maxValueSize {
+		return fmt.Errorf("%w: %d bytes to read, at most %d allowed", ErrLimitExceeded, size, p.maxValueSize)
+	}
+
+	return nil
+}
+
+// checkEntriesCount returns an error if an IFD holding the given number of entries exceeds the limit set with
+// WithMaxEntriesPerIFD.
+func (p *Parser) checkEntriesCount(count int) error {
+	if p.maxEntriesPerIFD > 0 && count > p.maxEntriesPerIFD {
+		return fmt.Errorf("%w: IFD holds %d entries, at most %d allowed", ErrLimitExceeded, count, p.maxEntriesPerIFD)
+	}
+
+	return nil
+}
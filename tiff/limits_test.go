@@ -0,0 +1,77 @@
+package tiff
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMaxValueSize(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	p.WithMaxValueSize(8)
+
+	// Make ("Canon", 6 bytes) fits, Model ("Canon EOS 7D", 13 bytes) does not
+	entries, err := p.Parse(Make)
+	assert.NoError(t, err)
+	assert.Contains(t, entries, Make)
+
+	_, err = p.Parse(Model)
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+
+	_, err = p.ReadThumbnail()
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+
+	_, err = p.WithMaxValueSize(0).ReadThumbnail()
+	assert.NoError(t, err)
+}
+
+func TestWithMaxEntriesPerIFD(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	p.WithMaxEntriesPerIFD(5)
+
+	_, err = p.Parse(ImageWidth)
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+
+	_, err = p.Pages()
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+
+	assert.False(t, p.Validate().Valid())
+
+	_, err = p.WithMaxEntriesPerIFD(1000).Pages()
+	assert.NoError(t, err)
+}
+
+func TestWithSkipValuesLargerThan(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	p.WithSkipValuesLargerThan(1024).WithMaxValueSize(1024)
+
+	entries, err := p.Parse(Model, MakerNotes)
+	assert.NoError(t, err)
+	assert.Equal(t, "Canon EOS 7D", *entries[Model].Value.String)
+	assert.False(t, entries[Model].Skipped)
+
+	makerNote := entries[MakerNotes]
+	assert.True(t, makerNote.Skipped)
+	assert.Nil(t, makerNote.Value.Bytes)
+	assert.Greater(t, makerNote.Length, uint32(1024))
+	assert.Contains(t, makerNote.String(), "not decoded")
+
+	// the skipped value can be streamed later
+	r, err := p.ValueReader(makerNote)
+	assert.NoError(t, err)
+	streamed, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	raw, err := p.WithMaxValueSize(0).ReadRawValue(makerNote)
+	assert.NoError(t, err)
+	assert.Equal(t, raw, streamed)
+
+	entries, err = p.WithSkipValuesLargerThan(0).Parse(MakerNotes)
+	assert.NoError(t, err)
+	assert.False(t, entries[MakerNotes].Skipped)
+	assert.Equal(t, raw, entries[MakerNotes].Value.Bytes)
+}
@@ -0,0 +1,58 @@
+package tiff
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// ReadImageUniqueID reads the ImageUniqueID of the Exif IFD, which identifies the image whatever the name of the file
+// holding it (e.g. to track the copies and versions of an image in an asset-management system), as 32 hexadecimal
+// digits in lower case. IDs that are not 128-bit hexadecimal numbers are invalid.
+func ReadImageUniqueID(entries map[EntryID]Entry) (string, error) {
+	id, err := readText(entries, ImageUniqueID)
+	if err != nil {
+		return "", err
+	}
+	if decoded, err := hex.DecodeString(id); err != nil || len(decoded) != 16 {
+		return "", fmt.Errorf("invalid ImageUniqueID %q", id)
+	}
+
+	return strings.ToLower(id), nil
+}
+
+// ReadOriginalRawFileName reads the name of the raw file a DNG file was converted from (e.g. "IMG_0001.CR2"), from
+// OriginalRawFileName. The DNG specification allows it to be stored as bytes rather than ASCII, e.g. to hold a UTF-8
+// name.
+func ReadOriginalRawFileName(entries map[EntryID]Entry) (string, error) {
+	return readText(entries, OriginalRawFileName)
+}
+
+// ReadDocumentName reads the name of the document the image was scanned from, from DocumentName.
+func ReadDocumentName(entries map[EntryID]Entry) (string, error) {
+	return readText(entries, DocumentName)
+}
+
+// readText reads the value of an entry holding text, either as ASCII or as bytes, without its trailing NULs and
+// surrounding spaces. Empty values are invalid.
+func readText(entries map[EntryID]Entry, id EntryID) (string, error) {
+	entry, ok := entries[id]
+	if !ok {
+		return "", fmt.Errorf("entry 0x%X not found", id)
+	}
+
+	var text string
+	switch {
+	case entry.Value.String != nil:
+		text = *entry.Value.String
+	case entry.Value.Bytes != nil:
+		text = string(entry.Value.Bytes)
+	case entry.Value.UByte != nil:
+		text = string([]byte{*entry.Value.UByte})
+	}
+	if text = strings.TrimSpace(strings.TrimRight(text, "\x00")); text == "" {
+		return "", fmt.Errorf("invalid %s: empty", id.Name())
+	}
+
+	return text, nil
+}
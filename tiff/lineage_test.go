@@ -0,0 +1,64 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineage(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	ifd := b.AddIFD().
+		WithString(uint16(DocumentName), "Archive box 12").
+		WithBytes(uint16(OriginalRawFileName), test.TypeByte, []byte("IMG_0001.CR2\x00")...)
+	ifd.WithSubIFD(uint16(Exif)).WithString(uint16(ImageUniqueID), "0123456789ABCDEF0123456789ABCDEF")
+
+	p, err := NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+	entries, err := p.Parse(DocumentName, OriginalRawFileName, ImageUniqueID)
+	assert.NoError(t, err)
+
+	name, err := ReadDocumentName(entries)
+	assert.NoError(t, err)
+	assert.Equal(t, "Archive box 12", name)
+	name, err = ReadOriginalRawFileName(entries)
+	assert.NoError(t, err)
+	assert.Equal(t, "IMG_0001.CR2", name)
+	id, err := ReadImageUniqueID(entries)
+	assert.NoError(t, err)
+	assert.Equal(t, "0123456789abcdef0123456789abcdef", id)
+}
+
+func TestReadImageUniqueID(t *testing.T) {
+	text := func(s string) map[EntryID]Entry {
+		return map[EntryID]Entry{ImageUniqueID: {ID: ImageUniqueID, Value: EntryValue{String: &s}}}
+	}
+
+	tests := []struct {
+		name    string
+		entries map[EntryID]Entry
+		want    string
+		wantErr bool
+	}{
+		{name: "valid", entries: text("00000000000000000000000000c0ffee\x00"), want: "00000000000000000000000000c0ffee"},
+		{name: "too short", entries: text("c0ffee"), wantErr: true},
+		{name: "not hexadecimal", entries: text("0000000000000000000000000000000g"), wantErr: true},
+		{name: "empty", entries: text("   "), wantErr: true},
+		{name: "missing", entries: map[EntryID]Entry{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadImageUniqueID(tt.entries)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
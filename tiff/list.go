@@ -0,0 +1,88 @@
+package tiff
+
+import "fmt"
+
+// EntryInfo describes an entry of the file, without its value.
+type EntryInfo struct {
+	Group     Group
+	IFDOffset int64 // offset of the IFD the entry belongs to
+	ID        EntryID
+	DataType  DataType
+	Length    uint32
+}
+
+// ListIDs scans the tables of all IFDs (main chain, Exif, GPSInfo and SubIFDs) the parser reads (see WithGroups) and
+// describes their entries, in the order they are written, without reading their values: only the offsets of sub-IFDs
+// are read, when there are several of them. It is much cheaper than Find when only the presence of entries matters.
+func (p *Parser) ListIDs() ([]EntryInfo, error) {
+	var res []EntryInfo
+	seen := make(map[int64]bool)
+
+	var listIFD func(offset int64, group Group) (int64, error)
+	listIFD = func(offset int64, group Group) (int64, error) {
+		seen[offset] = true
+
+		table, next, err := p.readTable(offset)
+		if err != nil {
+			return 0, err
+		}
+
+		type subIFD struct {
+			offset int64
+			group  Group
+		}
+		var subIFDs []subIFD
+		for i := 0; i < len(table)/EntryLength; i++ {
+			buffer := table[i*EntryLength : (i+1)*EntryLength]
+			info := EntryInfo{
+				Group:     group,
+				IFDOffset: offset,
+				ID:        EntryID(p.byteOrder.Uint16(buffer[:2])),
+				DataType:  DataType(p.byteOrder.Uint16(buffer[2:4])),
+				Length:    p.byteOrder.Uint32(buffer[4:8]),
+			}
+			if p.excludes(group, info.ID) {
+				continue
+			}
+			res = append(res, info)
+
+			for _, pointer := range subIFDPointers {
+				if info.ID != pointer.id || !p.follows(pointer.group) {
+					continue
+				}
+				raw, err := p.readArray(DataType_ULong, info.Length, p.byteOrder.Uint32(buffer[8:12]))
+				if err != nil {
+					return 0, err
+				}
+				offsets := decodeArray(raw, 4, p.byteOrder.Uint32)
+				for _, subOffset := range offsets {
+					subIFDs = append(subIFDs, subIFD{offset: int64(subOffset), group: pointer.group})
+				}
+			}
+		}
+
+		for _, sub := range subIFDs {
+			if seen[sub.offset] {
+				continue
+			}
+			if _, err := listIFD(sub.offset, sub.group); err != nil {
+				return 0, err
+			}
+		}
+
+		return next, nil
+	}
+
+	for offset, index := p.firstIFDOffset, 0; offset != 0 && p.follows(pageGroup(index)); index++ {
+		if seen[offset] {
+			return nil, fmt.Errorf("IFD chain loops back to offset %d", offset)
+		}
+		next, err := listIFD(offset, pageGroup(index))
+		if err != nil {
+			return nil, err
+		}
+		offset = next
+	}
+
+	return res, nil
+}
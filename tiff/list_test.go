@@ -0,0 +1,59 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListIDs(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+
+	infos, err := p.ListIDs()
+	assert.NoError(t, err)
+
+	// same entries as Find, which reads values
+	entries, err := p.Find(func(Entry) bool { return true })
+	assert.NoError(t, err)
+	assert.Len(t, infos, len(entries))
+	for _, entry := range entries {
+		assert.Contains(t, infos, EntryInfo{
+			Group:     entry.Group,
+			IFDOffset: entry.IFDOffset,
+			ID:        entry.ID,
+			DataType:  entry.DataType,
+			Length:    entry.Length,
+		})
+	}
+
+	assert.Equal(t, EntryInfo{Group: Group_IFD0, IFDOffset: 16, ID: ImageWidth, DataType: DataType_UShort, Length: 1}, infos[0])
+}
+
+func TestListIDs_SubIFDs(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	ifd := b.AddIFD().WithUints32(uint16(ImageWidth), 256)
+	ifd.WithSubIFD(uint16(SubIFDs)).WithUints32(uint16(ImageWidth), 4096)
+	// an unknown data type does not prevent listing
+	b.AddIFD().WithField(uint16(Make), 99, 3, []byte{0, 0, 0, 0})
+
+	p, err := NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	infos, err := p.ListIDs()
+	assert.NoError(t, err)
+	assert.Len(t, infos, 4)
+	assert.Equal(t, []EntryID{ImageWidth, SubIFDs, ImageWidth, Make}, []EntryID{infos[0].ID, infos[1].ID, infos[2].ID, infos[3].ID})
+	assert.Equal(t, []Group{Group_IFD0, Group_IFD0, Group_SubIFD, Group_IFD1}, []Group{infos[0].Group, infos[1].Group, infos[2].Group, infos[3].Group})
+	assert.Equal(t, EntryInfo{Group: Group_IFD1, IFDOffset: infos[3].IFDOffset, ID: Make, DataType: 99, Length: 3}, infos[3])
+
+	data := b.Bytes()
+	binary.LittleEndian.PutUint32(data[8+2+2*EntryLength:], 8) // IFD#0 points back to itself
+	p, err = NewParser(bytes.NewReader(data))
+	assert.NoError(t, err)
+	_, err = p.ListIDs()
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package tiff
+
+import (
+	"errors"
+	"slices"
+)
+
+// MainImage returns the page describing the full-resolution image of the file, which is not necessarily IFD#0: raw files
+// usually describe a preview there (e.g. a reduced-resolution thumbnail in NEF and DNG files, a full-size JPEG in CR2
+// files), and the raw image itself in a SubIFD or in a later IFD of the main chain. Its dimensions and strips or tiles
+// can then be read with Width, Height, ReadStrips or ReadTiles.
+//
+// It looks in the main IFD chain and the SubIFDs of its IFDs for images (i.e. IFDs with strips or tiles) that
+// NewSubfileType does not mark as reduced-resolution versions of another image, nor as transparency masks, preferring
+// those it explicitly marks as full-resolution, then the largest ones, then the first ones. The IFD the vendor of the
+// camera tells (e.g. in the header of CR2 files, see Vendor) comes first.
+func (p *Parser) MainImage() (Page, error) {
+	pages, err := p.Pages()
+	if err != nil {
+		return Page{}, err
+	}
+
+	var candidates []Page
+	for _, page := range pages {
+		candidates = append(candidates, page)
+		subIFDs, err := p.SubIFDs(page)
+		if err != nil {
+			return Page{}, err
+		}
+		candidates = append(candidates, subIFDs...)
+	}
+
+	if rawIFD := vendorProfiles[p.Vendor()].rawIFD; rawIFD != nil {
+		if offset, ok := rawIFD(p); ok {
+			if i := slices.IndexFunc(candidates, func(pg Page) bool { return pg.Offset == offset }); i >= 0 {
+				return candidates[i], nil
+			}
+		}
+	}
+
+	candidates = slices.DeleteFunc(candidates, func(pg Page) bool {
+		if subfileType, ok := pg.Uint(NewSubfileType); ok && subfileType&(subfileType_ReducedResolution|subfileType_Mask) != 0 {
+			return true
+		}
+		_, strips := pg.Entries[StripOffsets]
+		_, tiles := pg.Entries[TileOffsets]
+		return !strips && !tiles
+	})
+	if len(candidates) == 0 {
+		return Page{}, errors.New("no full-resolution image found")
+	}
+
+	// stable, so that the first of equal candidates wins
+	slices.SortStableFunc(candidates, func(a, b Page) int {
+		_, aMarked := a.Uint(NewSubfileType)
+		_, bMarked := b.Uint(NewSubfileType)
+		if aMarked != bMarked {
+			if aMarked {
+				return -1
+			}
+			return 1
+		}
+		if aPixels, bPixels := a.pixels(), b.pixels(); aPixels != bPixels {
+			if aPixels > bPixels {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	})
+
+	return candidates[0], nil
+}
+
+// pixels returns the number of pixels of the page's image, or 0 if its dimensions are unknown.
+func (pg Page) pixels() uint64 {
+	width, _ := pg.Width()
+	height, _ := pg.Height()
+
+	return uint64(width) * uint64(height)
+}
@@ -0,0 +1,77 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_MainImage(t *testing.T) {
+	strip := []byte{1, 2, 3, 4}
+	image := func(ifd *test.IFDBuilder, subfileType int, width, height uint32) *test.IFDBuilder {
+		if subfileType >= 0 {
+			ifd.WithUints32(uint16(NewSubfileType), uint32(subfileType))
+		}
+		return ifd.WithUints32(uint16(ImageWidth), width).
+			WithUints32(uint16(ImageHeight), height).
+			WithData(uint16(StripOffsets), uint16(StripByteCounts), strip)
+	}
+
+	// IFD#0 is a thumbnail, the raw image is in a SubIFD (as in DNG and NEF files)
+	dng := test.NewTIFFBuilder(binary.LittleEndian)
+	ifd0 := image(dng.AddIFD(), 1, 256, 171)
+	image(ifd0.WithSubIFD(uint16(SubIFDs)), 0, 6000, 4000)
+
+	// without NewSubfileType, the largest image wins
+	unmarked := test.NewTIFFBuilder(binary.LittleEndian)
+	image(unmarked.AddIFD(), -1, 160, 120)
+	image(unmarked.AddIFD(), -1, 640, 480)
+	image(unmarked.AddIFD(), -1, 320, 240)
+
+	// explicitly marked images win over larger, unmarked ones; masks are ignored
+	marked := test.NewTIFFBuilder(binary.LittleEndian)
+	image(marked.AddIFD(), -1, 8000, 6000)
+	image(marked.AddIFD(), 0, 640, 480)
+	image(marked.AddIFD(), subfileType_Mask, 9000, 6000)
+
+	noImage := test.NewTIFFBuilder(binary.LittleEndian)
+	noImage.AddIFD().WithString(uint16(Make), "Canon")
+
+	tests := []struct {
+		name    string
+		data    []byte
+		width   uint32
+		offset  int64
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{name: "SubIFD", data: dng.Bytes(), width: 6000, wantErr: assert.NoError},
+		{name: "largest", data: unmarked.Bytes(), width: 640, wantErr: assert.NoError},
+		{name: "marked", data: marked.Bytes(), width: 640, wantErr: assert.NoError},
+		{name: "CR2", data: cr2Image, offset: 48966, wantErr: assert.NoError},
+		{name: "ORF", data: orfImage, width: 4640, offset: 8, wantErr: assert.NoError},
+		{name: "no image", data: noImage.Bytes(), wantErr: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(bytes.NewReader(tt.data))
+			assert.NoError(t, err)
+
+			page, err := p.MainImage()
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			width, _ := page.Width()
+			assert.Equal(t, tt.width, width)
+			if tt.offset != 0 {
+				assert.Equal(t, tt.offset, page.Offset)
+			}
+			strips, err := p.ReadStrips(page)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, strips)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package tiff
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxMakerNoteEntries is the largest number of entries of a plausible maker note IFD.
+const maxMakerNoteEntries = 1000
+
+// MakerNote describes how to read the IFD of a maker note, as detected by DetectMakerNote.
+type MakerNote struct {
+	Vendor    string           // vendor whose signature starts the maker note (e.g. "Nikon"), empty if there is none
+	ByteOrder binary.ByteOrder // byte order of the maker note, which may differ from the one of the file
+	Offset    int64            // position of the IFD in the file
+	Base      int64            // position in the file that offsets of the maker note are relative to
+}
+
+// Parser returns a parser reading the IFD of the maker note from r (the file it was detected in): its offsets, and
+// those of its entries, are relative to Base.
+func (m MakerNote) Parser(r io.ReadSeeker) *Parser {
+	return NewParserRaw(r, m.ByteOrder, m.Offset-m.Base).WithOffsetBase(m.Base)
+}
+
+// Entries reads the entries of the IFD of the maker note from r (the file it was detected in), in the MakerNote group.
+// Their IFDOffset is relative to Base, like their offsets.
+func (m MakerNote) Entries(r io.ReadSeeker) (map[EntryID]Entry, error) {
+	entries, _, err := m.Parser(r).readIFD(m.Offset-m.Base, Group_MakerNote)
+
+	return entries, err
+}
+
+// makerNoteSignature describes the layout of maker notes starting with a vendor-specific prefix. Positions are relative
+// to the start of the maker note.
+type makerNoteSignature struct {
+	vendor    string
+	prefix    string
+	byteOrder int64            // position of a byte order mark ("II" or "MM"), -1 if there is none
+	order     binary.ByteOrder // fixed byte order, if any
+	ifd       int64            // position of the IFD or, if pointer is set, of the offset of the IFD (relative to base)
+	pointer   bool
+	base      int64 // position that offsets are relative to, -1 if they are relative to the TIFF header of the file
+}
+
+var makerNoteSignatures = []makerNoteSignature{
+	{vendor: "Nikon", prefix: "Nikon\x00\x02", byteOrder: 10, ifd: 14, pointer: true, base: 10}, // embedded TIFF header
+	{vendor: "Nikon", prefix: "Nikon\x00\x01", byteOrder: -1, ifd: 8, base: -1},
+	{vendor: "Olympus", prefix: "OLYMPUS\x00", byteOrder: 8, ifd: 12, base: 0},
+	{vendor: "Olympus", prefix: "OM SYSTEM\x00", byteOrder: 12, ifd: 16, base: 0},
+	{vendor: "Olympus", prefix: "OLYMP\x00", byteOrder: -1, ifd: 8, base: -1},
+	{vendor: "Fujifilm", prefix: "FUJIFILM", byteOrder: -1, order: binary.LittleEndian, ifd: 8, pointer: true, base: 0},
+	{vendor: "Panasonic", prefix: "Panasonic\x00", byteOrder: -1, ifd: 12, base: -1},
+	{vendor: "Pentax", prefix: "PENTAX \x00", byteOrder: 8, ifd: 10, base: 0},
+	{vendor: "Sony", prefix: "SONY DSC \x00", byteOrder: -1, ifd: 12, base: -1},
+	{vendor: "Sony", prefix: "SONY CAM \x00", byteOrder: -1, ifd: 12, base: -1},
+	{vendor: "Apple", prefix: "Apple iOS\x00", byteOrder: 12, ifd: 14, base: 0},
+}
+
+// MakerNote detects the layout of the maker note of the file (see DetectMakerNote), which is the value of the
+// MakerNotes entry of the Exif IFD.
+func (p *Parser) MakerNote() (MakerNote, error) {
+	if !p.follows(Group_MakerNote) {
+		return MakerNote{}, errNotFollowed(Group_MakerNote)
+	}
+
+	// read without Parse, which reads maker notes for groups declared with WithVendorIFD
+	ifd0, err := p.collect(p.firstIFDOffset, Group_IFD0, newWanted(Exif))
+	if err != nil {
+		return MakerNote{}, err
+	}
+	exif, ok := ifd0[Exif]
+	if !ok {
+		return MakerNote{}, errors.New("maker note not found")
+	}
+	entries, err := p.collect(int64(exif.RawValue), Group_Exif, newWanted(MakerNotes))
+	if err != nil {
+		return MakerNote{}, err
+	}
+	entry, ok := entries[MakerNotes]
+	if !ok {
+		return MakerNote{}, errors.New("maker note not found")
+	}
+	if entry.Length <= 4 {
+		return MakerNote{}, fmt.Errorf("maker note of %d bytes is too short", entry.Length)
+	}
+
+	return DetectMakerNote(p.reader, p.byteOrder, int64(entry.RawValue), int64(entry.Length))
+}
+
+// DetectMakerNote detects the layout of a maker note of the given length starting at offset in r, whose byte order is
+// byteOrder, so that its IFD can be handed to a vendor-specific decoder (see MakerNote.Parser and MakerNote.Entries).
+//
+// Like exiftool, it first looks for a known vendor signature, which tells where the IFD starts, which byte order it
+// uses and which position its offsets are relative to. Maker notes without signature (e.g. Canon's) start with their
+// IFD, in the byte order of the file if it is plausible, or in the other one otherwise. Finally, as editors often move
+// maker notes without fixing their offsets, the base is checked against the values of the IFD: if they fit in the
+// maker note when read relatively to the start of the maker note or to the TIFF header rather than to the expected
+// base, the best fitting base is used.
+func DetectMakerNote(r io.ReadSeeker, byteOrder binary.ByteOrder, offset, length int64) (MakerNote, error) {
+	head := make([]byte, min(length, 32))
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return MakerNote{}, err
+	}
+	if _, err := io.ReadFull(r, head); err != nil {
+		return MakerNote{}, err
+	}
+
+	res := MakerNote{ByteOrder: byteOrder, Offset: offset}
+	sig, ok := findMakerNoteSignature(head)
+	if ok {
+		res.Vendor = sig.vendor
+		if sig.base >= 0 {
+			res.Base = offset + sig.base
+		}
+		switch {
+		case sig.order != nil:
+			res.ByteOrder = sig.order
+		case sig.byteOrder >= 0:
+			order, err := readEndianness(head[sig.byteOrder : sig.byteOrder+2])
+			if err != nil {
+				return MakerNote{}, fmt.Errorf("%s maker note: %w", sig.vendor, err)
+			}
+			res.ByteOrder = order
+		}
+		res.Offset = offset + sig.ifd
+		if sig.pointer {
+			ifdOffset, err := addOffset(res.Base, uint64(res.ByteOrder.Uint32(head[sig.ifd:sig.ifd+4])))
+			if err != nil {
+				return MakerNote{}, fmt.Errorf("%s maker note: %w", sig.vendor, err)
+			}
+			res.Offset = ifdOffset
+		}
+	}
+
+	entries, err := readMakerNoteIFD(r, res.ByteOrder, res.Offset, offset+length)
+	if err != nil && !ok {
+		// no signature: the maker note may use the other byte order
+		other := otherByteOrder(byteOrder)
+		if entries, err = readMakerNoteIFD(r, other, res.Offset, offset+length); err == nil {
+			res.ByteOrder = other
+		}
+	}
+	if err != nil {
+		return MakerNote{}, err
+	}
+
+	// keep the expected base, unless another one fits the values of the IFD better
+	best := fittingValues(entries, res.Base, offset, length)
+	for _, base := range []int64{offset, 0} {
+		if n := fittingValues(entries, base, offset, length); n > best {
+			res.Base, best = base, n
+		}
+	}
+
+	return res, nil
+}
+
+// findMakerNoteSignature returns the signature starting the maker note, if any.
+func findMakerNoteSignature(head []byte) (makerNoteSignature, bool) {
+	for _, sig := range makerNoteSignatures {
+		if strings.HasPrefix(string(head), sig.prefix) && int64(len(head)) >= max(sig.ifd+4, sig.byteOrder+2) {
+			return sig, true
+		}
+	}
+
+	return makerNoteSignature{}, false
+}
+
+// readMakerNoteIFD reads the entries of the IFD starting at offset, returning an error if they do not look like those
+// of a maker note IFD: their number must be plausible and fit before end, and most of them must have a known data type.
+func readMakerNoteIFD(r io.ReadSeeker, byteOrder binary.ByteOrder, offset, end int64) ([]RawEntry, error) {
+	buffer := make([]byte, 2)
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(r, buffer); err != nil {
+		return nil, err
+	}
+	count := int64(byteOrder.Uint16(buffer))
+	if count == 0 || count > maxMakerNoteEntries || offset+2+count*EntryLength > end {
+		return nil, fmt.Errorf("no plausible IFD at offset %d of the maker note (%d entries)", offset, count)
+	}
+
+	p := NewParserRaw(r, byteOrder, offset)
+	entries, _, err := p.ReadRawIFD(offset)
+	if err != nil {
+		return nil, err
+	}
+	known := 0
+	for _, e := range entries {
+		if e.DataType.Size() > 0 {
+			known++
+		}
+	}
+	if 2*known < len(entries) {
+		return nil, fmt.Errorf("no plausible IFD at offset %d of the maker note (unknown data types)", offset)
+	}
+
+	return entries, nil
+}
+
+// fittingValues counts the entries whose value, when its offset is relative to base, lies within the maker note.
+func fittingValues(entries []RawEntry, base, offset, length int64) int {
+	n := 0
+	for _, e := range entries {
+		if e.Inline() {
+			continue
+		}
+		start := base + int64(e.RawValue)
+		if start >= offset && start+e.Size() <= offset+length {
+			n++
+		}
+	}
+
+	return n
+}
+
+func otherByteOrder(byteOrder binary.ByteOrder) binary.ByteOrder {
+	if byteOrder == binary.ByteOrder(binary.BigEndian) {
+		return binary.LittleEndian
+	}
+
+	return binary.BigEndian
+}
@@ -0,0 +1,113 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// makerNoteIFD returns an IFD holding a single Make entry, whose value is written right after the IFD and whose offset
+// is valueOffset.
+func makerNoteIFD(byteOrder binary.ByteOrder, valueOffset uint32) []byte {
+	ifd := make([]byte, 2+EntryLength+4)
+	byteOrder.PutUint16(ifd, 1)
+	byteOrder.PutUint16(ifd[2:], uint16(Make))
+	byteOrder.PutUint16(ifd[4:], uint16(DataType_String))
+	byteOrder.PutUint32(ifd[6:], 8)
+	byteOrder.PutUint32(ifd[10:], valueOffset)
+
+	return append(ifd, "Vendor\x00\x00"...)
+}
+
+func TestDetectMakerNote(t *testing.T) {
+	const offset = 64 // position of the maker note in the file
+	const ifdSize = 2 + EntryLength + 4
+
+	tests := []struct {
+		name string
+		note []byte
+		want MakerNote
+	}{
+		{
+			name: "no signature",
+			note: makerNoteIFD(binary.LittleEndian, offset+ifdSize),
+			want: MakerNote{ByteOrder: binary.LittleEndian, Offset: offset, Base: 0},
+		},
+		{
+			name: "no signature, other byte order",
+			note: makerNoteIFD(binary.BigEndian, offset+ifdSize),
+			want: MakerNote{ByteOrder: binary.BigEndian, Offset: offset, Base: 0},
+		},
+		{
+			name: "no signature, offsets relative to the maker note",
+			note: makerNoteIFD(binary.LittleEndian, ifdSize),
+			want: MakerNote{ByteOrder: binary.LittleEndian, Offset: offset, Base: offset},
+		},
+		{
+			name: "embedded TIFF header",
+			note: append([]byte("Nikon\x00\x02\x10\x00\x00MM\x00\x2a\x00\x00\x00\x08"), makerNoteIFD(binary.BigEndian, 8+ifdSize)...),
+			want: MakerNote{Vendor: "Nikon", ByteOrder: binary.BigEndian, Offset: offset + 18, Base: offset + 10},
+		},
+		{
+			name: "byte order mark",
+			note: append([]byte("OLYMPUS\x00MM\x03\x00"), makerNoteIFD(binary.BigEndian, 12+ifdSize)...),
+			want: MakerNote{Vendor: "Olympus", ByteOrder: binary.BigEndian, Offset: offset + 12, Base: offset},
+		},
+		{
+			name: "signature, offsets relative to the file",
+			note: append([]byte("SONY DSC \x00\x00\x00"), makerNoteIFD(binary.LittleEndian, offset+12+ifdSize)...),
+			want: MakerNote{Vendor: "Sony", ByteOrder: binary.LittleEndian, Offset: offset + 12, Base: 0},
+		},
+		{
+			name: "signature, offsets relative to the maker note instead of the file",
+			note: append([]byte("OLYMP\x00\x01\x00"), makerNoteIFD(binary.LittleEndian, 8+ifdSize)...),
+			want: MakerNote{Vendor: "Olympus", ByteOrder: binary.LittleEndian, Offset: offset + 8, Base: offset},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := bytes.NewReader(append(make([]byte, offset), tt.note...))
+
+			m, err := DetectMakerNote(r, binary.LittleEndian, offset, int64(len(tt.note)))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, m)
+
+			entries, err := m.Entries(r)
+			assert.NoError(t, err)
+			if assert.Contains(t, entries, Make) {
+				assert.Equal(t, "Vendor", *entries[Make].Value.String)
+				assert.Equal(t, Group_MakerNote, entries[Make].Group)
+			}
+		})
+	}
+}
+
+func TestDetectMakerNote_Invalid(t *testing.T) {
+	note := bytes.Repeat([]byte{0xff}, 32)
+
+	_, err := DetectMakerNote(bytes.NewReader(note), binary.LittleEndian, 0, int64(len(note)))
+	assert.Error(t, err)
+}
+
+func TestParser_MakerNote(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+
+	entries, err := p.Parse(MakerNotes)
+	assert.NoError(t, err)
+
+	m, err := p.MakerNote()
+	assert.NoError(t, err)
+	assert.Equal(t, MakerNote{ByteOrder: binary.LittleEndian, Offset: int64(entries[MakerNotes].RawValue), Base: 0}, m)
+
+	raw, _, err := m.Parser(bytes.NewReader(cr2Image)).ReadRawIFD(m.Offset - m.Base)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, raw)
+
+	notes, err := m.Entries(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	assert.Len(t, notes, len(raw))
+}
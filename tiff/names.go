@@ -0,0 +1,112 @@
+package tiff
+
+// names maps known entries to their name, as used by exiftool (see https://exiftool.org/TagNames/EXIF.html).
+var names = map[EntryID]string{
+	NewSubfileType:            "SubfileType",
+	ImageWidth:                "ImageWidth",
+	ImageHeight:               "ImageHeight",
+	BitsPerSample:             "BitsPerSample",
+	Compression:               "Compression",
+	PhotometricInterpretation: "PhotometricInterpretation",
+	DocumentName:              "DocumentName",
+	ImageDescription:          "ImageDescription",
+	Make:                      "Make",
+	Model:                     "Model",
+	StripOffsets:              "StripOffsets",
+	Orientation:               "Orientation",
+	SamplesPerPixel:           "SamplesPerPixel",
+	RowsPerStrip:              "RowsPerStrip",
+	StripByteCounts:           "StripByteCounts",
+	XResolution:               "XResolution",
+	YResolution:               "YResolution",
+	PlanarConfiguration:       "PlanarConfiguration",
+	ResolutionUnit:            "ResolutionUnit",
+	Software:                  "Software",
+	DateTime:                  "ModifyDate",
+	Artist:                    "Artist",
+	HostComputer:              "HostComputer",
+	Predictor:                 "Predictor",
+	ColorMap:                  "ColorMap",
+	TileWidth:                 "TileWidth",
+	TileLength:                "TileLength",
+	TileOffsets:               "TileOffsets",
+	TileByteCounts:            "TileByteCounts",
+	SubIFDs:                   "SubIFD",
+	InkSet:                    "InkSet",
+	ExtraSamples:              "ExtraSamples",
+	SampleFormat:              "SampleFormat",
+	JPEGTables:                "JPEGTables",
+	XMLPacket:                 "ApplicationNotes",
+	Rating:                    "Rating",
+	RatingPercent:             "RatingPercent",
+	Copyright:                 "Copyright",
+	IPTC:                      "IPTC-NAA",
+	PhotoshopSettings:         "PhotoshopSettings",
+	Exif:                      "ExifOffset",
+	GPSInfo:                   "GPSInfo",
+	XPKeywords:                "XPKeywords",
+	ExposureTime:              "ExposureTime",
+	FNumber:                   "FNumber",
+	ISO:                       "ISO",
+	ExifVersion:               "ExifVersion",
+	DateTimeOriginal:          "DateTimeOriginal",
+	OffsetTime:                "OffsetTime",
+	OffsetTimeOriginal:        "OffsetTimeOriginal",
+	ComponentsConfiguration:   "ComponentsConfiguration",
+	ShutterSpeedValue:         "ShutterSpeedValue",
+	ApertureValue:             "ApertureValue",
+	BrightnessValue:           "BrightnessValue",
+	ExposureBiasValue:         "ExposureCompensation",
+	FocalLength:               "FocalLength",
+	MakerNotes:                "MakerNotes",
+	FlashpixVersion:           "FlashpixVersion",
+	ExifImageWidth:            "ExifImageWidth",
+	ExifImageHeight:           "ExifImageHeight",
+	FocalPlaneXResolution:     "FocalPlaneXResolution",
+	FocalPlaneYResolution:     "FocalPlaneYResolution",
+	FocalPlaneResolutionUnit:  "FocalPlaneResolutionUnit",
+	FocalLengthIn35mmFilm:     "FocalLengthIn35mmFormat",
+	ImageUniqueID:             "ImageUniqueID",
+	CameraOwnerName:           "OwnerName",
+	BodySerialNumber:          "BodySerialNumber",
+	LensSerialNumber:          "LensSerialNumber",
+	GPSVersionID:              "GPSVersionID",
+	GPSLatitudeRef:            "GPSLatitudeRef",
+	GPSLatitude:               "GPSLatitude",
+	GPSLongitudeRef:           "GPSLongitudeRef",
+	GPSLongitude:              "GPSLongitude",
+	GPSSpeedRef:               "GPSSpeedRef",
+	GPSSpeed:                  "GPSSpeed",
+	GPSTrackRef:               "GPSTrackRef",
+	GPSTrack:                  "GPSTrack",
+	GPSImgDirectionRef:        "GPSImgDirectionRef",
+	GPSImgDirection:           "GPSImgDirection",
+	BlackLevel:                "BlackLevel",
+	WhiteLevel:                "WhiteLevel",
+	ColorMatrix1:              "ColorMatrix1",
+	ColorMatrix2:              "ColorMatrix2",
+	CameraCalibration1:        "CameraCalibration1",
+	CameraCalibration2:        "CameraCalibration2",
+	AsShotNeutral:             "AsShotNeutral",
+	BaselineExposure:          "BaselineExposure",
+	CameraSerialNumber:        "SerialNumber",
+	DNGPrivateData:            "DNGPrivateData",
+	OriginalRawFileName:       "OriginalRawFileName",
+	OpcodeList1:               "OpcodeList1",
+	OpcodeList2:               "OpcodeList2",
+	OpcodeList3:               "OpcodeList3",
+	GDALMetadata:              "GDALMetadata",
+	GDALNoData:                "GDALNoData",
+	ThumbnailOffset:           "ThumbnailOffset",
+	ThumbnailLength:           "ThumbnailLength",
+	YCbCrCoefficients:         "YCbCrCoefficients",
+	YCbCrSubSampling:          "YCbCrSubSampling",
+	YCbCrPositioning:          "YCbCrPositioning",
+	ReferenceBlackWhite:       "ReferenceBlackWhite",
+}
+
+// Name returns the name of a known entry (e.g. "DateTimeOriginal"), as used by exiftool, or an empty string if the
+// entry is unknown.
+func (id EntryID) Name() string {
+	return names[id]
+}
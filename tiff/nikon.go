@@ -0,0 +1,126 @@
+package tiff
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"strconv"
+)
+
+// Entries of Nikon maker notes. Their IDs collide with those of other IFDs, so they are not listed in Defaults.
+const (
+	nikonSerialNumber EntryID = 0x001d
+	nikonShotInfo     EntryID = 0x0091
+	nikonColorBalance EntryID = 0x0097
+	nikonLensData     EntryID = 0x0098
+	nikonShutterCount EntryID = 0x00a7
+)
+
+// NikonTables holds the two substitution tables of the cipher Nikon uses to encrypt sections of its maker notes
+// (`xlat` in dcraw and exiftool).
+//
+// They are not distributed with this package, which is therefore limited to the cipher: they were extracted from
+// Nikon's software and are only published as part of the sources of dcraw, LibRaw and exiftool, under licenses of
+// their own (exiftool's being the GPL or the Artistic License). Callers load them from a copy of one of these sources
+// with ParseNikonTables.
+type NikonTables [2][256]byte
+
+// nikonByte matches the bytes of the xlat table in the sources of dcraw and exiftool, both written as 0xNN.
+var nikonByte = regexp.MustCompile(`0x[0-9a-fA-F]{2}\b`)
+
+// ParseNikonTables extracts the substitution tables of Nikon's cipher from the source of dcraw (dcraw.c) or of
+// exiftool (lib/Image/ExifTool/Nikon.pm): the 512 bytes following the first occurrence of "xlat".
+func ParseNikonTables(source []byte) (*NikonTables, error) {
+	start := bytes.Index(source, []byte("xlat"))
+	if start < 0 {
+		return nil, errors.New("xlat table not found")
+	}
+	matches := nikonByte.FindAll(source[start:], 2*256)
+	if len(matches) < 2*256 {
+		return nil, fmt.Errorf("xlat table holds %d bytes, not %d", len(matches), 2*256)
+	}
+
+	var tables NikonTables
+	for i, m := range matches {
+		b, err := strconv.ParseUint(string(m[2:]), 16, 8)
+		if err != nil {
+			return nil, err
+		}
+		tables[i/256][i%256] = byte(b)
+	}
+
+	return &tables, nil
+}
+
+// nikonEncrypted lists the encrypted sections of Nikon maker notes, with the first version using encryption. Their
+// version is stored in clear in their first 4 bytes.
+var nikonEncrypted = map[EntryID]string{
+	nikonShotInfo:     "0200",
+	nikonColorBalance: "0200",
+	nikonLensData:     "0201",
+}
+
+// DecryptNikon returns a copy of the entries of a Nikon maker note (see MakerNote.Entries) whose encrypted sections
+// (ShotInfo, ColorBalance and LensData) are decrypted, using the serial number and the shutter count of the camera,
+// also read from entries, as keys. entries is left untouched, so that decrypting them again gives the same result.
+// Sections whose version predates encryption are returned as they are.
+func (m MakerNote) DecryptNikon(entries map[EntryID]Entry, tables *NikonTables) (map[EntryID]Entry, error) {
+	if m.Vendor != "Nikon" {
+		return nil, fmt.Errorf("cannot decrypt a maker note of vendor %q", m.Vendor)
+	}
+	if tables == nil {
+		return nil, errors.New("missing Nikon substitution tables")
+	}
+
+	serial, ok := entries[nikonSerialNumber]
+	if !ok || serial.Value.String == nil {
+		return nil, errors.New("serial number not found")
+	}
+	count, ok := entries[nikonShutterCount]
+	if !ok || count.Value.Uint32 == nil {
+		return nil, errors.New("shutter count not found")
+	}
+
+	res := maps.Clone(entries)
+	for id, minVersion := range nikonEncrypted {
+		entry, ok := entries[id]
+		if !ok || len(entry.Value.Bytes) <= 4 || string(entry.Value.Bytes[:4]) < minVersion {
+			continue
+		}
+		entry.Value.Bytes = bytes.Clone(entry.Value.Bytes)
+		NikonDecrypt(entry.Value.Bytes[4:], nikonSerial(*serial.Value.String), *count.Value.Uint32, tables)
+		res[id] = entry
+	}
+
+	return res, nil
+}
+
+// nikonSerial returns the serial number used as key: the serial number itself when it is numeric, 0x60 otherwise (as
+// exiftool does for most models).
+func nikonSerial(serial string) uint32 {
+	if n, err := strconv.ParseUint(serial, 10, 32); err == nil {
+		return uint32(n)
+	}
+
+	return 0x60
+}
+
+// NikonDecrypt decrypts data in place with Nikon's cipher, given the serial number and the shutter count of the camera.
+// As the cipher XORs data with a key stream, it also encrypts data.
+func NikonDecrypt(data []byte, serial, shutterCount uint32, tables *NikonTables) {
+	var key byte
+	for i := 0; i < 4; i++ {
+		key ^= byte(shutterCount >> (i * 8))
+	}
+
+	ci := tables[0][byte(serial)]
+	cj := tables[1][key]
+	ck := byte(0x60)
+	for i := range data {
+		cj += ci * ck
+		ck++
+		data[i] ^= cj
+	}
+}
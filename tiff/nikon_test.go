@@ -0,0 +1,88 @@
+package tiff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// identityTables are not Nikon's tables, but make the key stream easy to compute by hand.
+func identityTables() *NikonTables {
+	var tables NikonTables
+	for i := 0; i < 256; i++ {
+		tables[0][i], tables[1][i] = byte(i), byte(i)
+	}
+
+	return &tables
+}
+
+func TestNikonDecrypt(t *testing.T) {
+	data := []byte{0, 0, 0x42}
+
+	// ci = 3, cj = 1^2^3^4 = 4, then cj += ci * 0x60, cj += ci * 0x61...
+	NikonDecrypt(data, 3, 0x01020304, identityTables())
+	assert.Equal(t, []byte{0x24, 0x47, 0x42 ^ 0x6d}, data)
+
+	NikonDecrypt(data, 3, 0x01020304, identityTables())
+	assert.Equal(t, []byte{0, 0, 0x42}, data)
+}
+
+func TestMakerNote_DecryptNikon(t *testing.T) {
+	serial, count := "3", uint32(0x01020304)
+	entries := map[EntryID]Entry{
+		nikonSerialNumber: {Value: EntryValue{String: &serial}},
+		nikonShutterCount: {Value: EntryValue{Uint32: &count}},
+		nikonShotInfo:     {Value: EntryValue{Bytes: []byte("0210\x24\x47")}},
+		nikonLensData:     {Value: EntryValue{Bytes: []byte("0100\x24\x47")}}, // not encrypted
+	}
+
+	m := MakerNote{Vendor: "Nikon"}
+	for range 2 {
+		decrypted, err := m.DecryptNikon(entries, identityTables())
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("0210\x00\x00"), decrypted[nikonShotInfo].Value.Bytes)
+		assert.Equal(t, []byte("0100\x24\x47"), decrypted[nikonLensData].Value.Bytes)
+		assert.Equal(t, serial, *decrypted[nikonSerialNumber].Value.String)
+		assert.Equal(t, []byte("0210\x24\x47"), entries[nikonShotInfo].Value.Bytes, "entries are left untouched")
+	}
+
+	_, err := MakerNote{Vendor: "Canon"}.DecryptNikon(entries, identityTables())
+	assert.Error(t, err)
+	_, err = m.DecryptNikon(entries, nil)
+	assert.Error(t, err)
+	_, err = m.DecryptNikon(map[EntryID]Entry{}, identityTables())
+	assert.Error(t, err)
+}
+
+func TestParseNikonTables(t *testing.T) {
+	// the layouts of dcraw.c and of exiftool's Nikon.pm, with made-up values
+	var c, perl strings.Builder
+	c.WriteString("static const uchar xlat[2][256] = {\n  { ")
+	perl.WriteString("my @xlat = (\n    [ ")
+	for i := 0; i < 512; i++ {
+		if i == 256 {
+			c.WriteString(" },\n  { ")
+			perl.WriteString(" ],\n    [ ")
+		}
+		fmt.Fprintf(&c, "0x%02x,", byte(i*7))
+		fmt.Fprintf(&perl, "0x%02x,", byte(i*7))
+	}
+	c.WriteString(" } };")
+	perl.WriteString(" ]\n);")
+
+	for _, source := range []string{c.String(), perl.String()} {
+		tables, err := ParseNikonTables([]byte("/* 0xff before the table is ignored */\n" + source))
+		assert.NoError(t, err)
+		assert.Equal(t, byte(0), tables[0][0])
+		assert.Equal(t, byte(7), tables[0][1])
+		assert.Equal(t, byte(257*7%256), tables[1][1])
+		assert.Equal(t, byte(511*7%256), tables[1][255])
+	}
+
+	_, err := ParseNikonTables([]byte("no table"))
+	assert.Error(t, err)
+	_, err = ParseNikonTables([]byte("xlat = { 0x01, 0x02 }"))
+	assert.Error(t, err)
+}
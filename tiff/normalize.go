@@ -0,0 +1,126 @@
+package tiff
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Format is the flavor of TIFF a file is written in, as told by its magic number.
+type Format int
+
+const (
+	Format_TIFF Format = iota // standard TIFF, including TIFF-based raw formats such as CR2, NEF or DNG
+	Format_ORF                // Olympus raw (ORF), with its own magic number
+)
+
+func (f Format) String() string {
+	switch f {
+	case Format_ORF:
+		return "ORF"
+	default:
+		return "TIFF"
+	}
+}
+
+// formatOf returns the format of a file given its magic number, as read in its byte order.
+func formatOf(magicNumber uint16) Format {
+	if magicNumber == orfMagicNumberBigEndian || magicNumber == orfMagicNumberLittleEndian {
+		return Format_ORF
+	}
+
+	return Format_TIFF
+}
+
+// Format returns the format of the file (Format_TIFF for parsers created with NewParserRaw).
+func (p *Parser) Format() Format {
+	return p.format
+}
+
+// WithNormalization makes the parser return clean values, comparable across vendors, for the entries whose data type,
+// count or padding varies between writers:
+//   - ImageWidth always comes back as a Uint32, whether it is declared as a SHORT (as Canon does) or a LONG (as Olympus
+//     does), and so do the other dimensions, strips and tiles, while BitsPerSample comes back as Uints16, even when it
+//     holds a single value;
+//   - strings are trimmed (see WithTrimming), e.g. the Make of Olympus cameras ("OLYMPUS CORPORATION").
+//
+// Other values are returned as they are declared.
+func (p *Parser) WithNormalization(normalize bool) *Parser {
+	p.normalize = normalize
+	p.trim = normalize
+
+	return p
+}
+
+// WithTrimming makes the parser trim the padding firmwares add to ASCII values: trailing whitespace, and the empty
+// strings that runs of NULs would otherwise add to Strings (e.g. "Canon\x00\x00\x00EOS\x00" holds "Canon" and "EOS").
+// It is enabled by WithNormalization, and can be disabled again after it. Use ReadRawValue to get the value as it is
+// written in the file.
+func (p *Parser) WithTrimming(trim bool) *Parser {
+	p.trim = trim
+
+	return p
+}
+
+// trimStrings trims the padding of the strings of an ASCII value (see WithTrimming), returning at least one string.
+func trimStrings(values []string) []string {
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimRightFunc(v, unicode.IsSpace); v != "" {
+			res = append(res, v)
+		}
+	}
+	if len(res) == 0 {
+		res = append(res, "")
+	}
+
+	return res
+}
+
+// canonicalKinds lists the canonical kind of the values of entries whose data type or count varies between writers.
+var canonicalKinds = map[EntryID]ValueKind{
+	ImageWidth:      ValueKind_Uint32,
+	ImageHeight:     ValueKind_Uint32,
+	ExifImageWidth:  ValueKind_Uint32,
+	ExifImageHeight: ValueKind_Uint32,
+	RowsPerStrip:    ValueKind_Uint32,
+	TileWidth:       ValueKind_Uint32,
+	TileLength:      ValueKind_Uint32,
+	StripOffsets:    ValueKind_Uints32,
+	StripByteCounts: ValueKind_Uints32,
+	TileOffsets:     ValueKind_Uints32,
+	TileByteCounts:  ValueKind_Uints32,
+	BitsPerSample:   ValueKind_Uints16,
+}
+
+// normalizeValue converts the value of an entry to its canonical kind (see WithNormalization), returning it unchanged if
+// it has none, or if it cannot be converted without losing information.
+func normalizeValue(id EntryID, value EntryValue) EntryValue {
+	kind, ok := canonicalKinds[id]
+	if !ok || value.Kind() == kind {
+		return value
+	}
+
+	values, err := Entry{ID: id, Value: value}.Uints()
+	if err != nil {
+		return value
+	}
+	switch kind {
+	case ValueKind_Uint32:
+		if len(values) == 1 {
+			return EntryValue{Uint32: &values[0]}
+		}
+	case ValueKind_Uints32:
+		return EntryValue{Uints32: values}
+	case ValueKind_Uints16:
+		res := make([]uint16, len(values))
+		for i, v := range values {
+			if v > 0xffff {
+				return value
+			}
+			res[i] = uint16(v)
+		}
+		return EntryValue{Uints16: res}
+	}
+
+	return value
+}
@@ -0,0 +1,118 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_Format(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	assert.Equal(t, Format_TIFF, p.Format())
+
+	p, err = NewParser(bytes.NewReader(orfImage))
+	assert.NoError(t, err)
+	assert.Equal(t, Format_ORF, p.Format())
+	assert.Equal(t, "ORF", p.Format().String())
+}
+
+func TestParser_WithNormalization(t *testing.T) {
+	tests := []struct {
+		name          string
+		image         []byte
+		width, height uint32
+		bitsPerSample []uint16
+		make          string
+	}{
+		{"CR2", cr2Image, 5184, 3456, []uint16{8, 8, 8}, "Canon"},
+		{"ORF", orfImage, 4640, 3472, []uint16{16}, "OLYMPUS CORPORATION"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(bytes.NewReader(tt.image))
+			assert.NoError(t, err)
+			entries, err := p.WithNormalization(true).Parse(ImageWidth, ImageHeight, BitsPerSample, StripOffsets, Make)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.width, *entries[ImageWidth].Value.Uint32)
+			assert.Equal(t, tt.height, *entries[ImageHeight].Value.Uint32)
+			assert.Equal(t, tt.bitsPerSample, entries[BitsPerSample].Value.Uints16)
+			assert.Len(t, entries[StripOffsets].Value.Uints32, 1)
+			assert.Equal(t, tt.make, *entries[Make].Value.String)
+		})
+	}
+}
+
+func TestParser_WithNormalization_String(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().
+		WithUints16(uint16(ImageWidth), 640).
+		WithUints16(uint16(BitsPerSample), 8)
+
+	p, err := NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+	entries, err := p.WithNormalization(true).Parse(ImageWidth, BitsPerSample)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "ID: 0x100\nDataType: unsigned short 16bits\nLength: 1\nValue: 640\n", entries[ImageWidth].String())
+	assert.Equal(t, "ID: 0x102\nDataType: unsigned short 16bits\nLength: 1\nValue: [8]\n", entries[BitsPerSample].String())
+}
+
+func TestNormalizeValue(t *testing.T) {
+	u16, u32 := uint16(8), uint32(70000)
+
+	assert.Equal(t, EntryValue{Uints16: []uint16{8}}, normalizeValue(BitsPerSample, EntryValue{Uint16: &u16}))
+	// does not fit
+	assert.Equal(t, EntryValue{Uint32: &u32}, normalizeValue(BitsPerSample, EntryValue{Uint32: &u32}))
+	// no canonical kind
+	assert.Equal(t, EntryValue{Uint16: &u16}, normalizeValue(Orientation, EntryValue{Uint16: &u16}))
+	// not an unsigned integer
+	f := float32(1)
+	assert.Equal(t, EntryValue{Float32: &f}, normalizeValue(ImageWidth, EntryValue{Float32: &f}))
+}
+
+func TestParser_WithTrimming(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(orfImage))
+	assert.NoError(t, err)
+
+	entries, err := p.WithTrimming(true).Parse(Make, Model, Software)
+	assert.NoError(t, err)
+	assert.Equal(t, "OLYMPUS CORPORATION", *entries[Make].Value.String)
+	assert.Equal(t, "E-M10MarkII", *entries[Model].Value.String)
+	assert.Equal(t, "Version 1.1", *entries[Software].Value.String)
+
+	raw, err := p.ReadRawValue(entries[Make])
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("OLYMPUS CORPORATION    \x00"), raw)
+
+	// normalization trims too, unless told otherwise
+	entries, err = p.WithNormalization(true).WithTrimming(false).Parse(Make)
+	assert.NoError(t, err)
+	assert.Equal(t, "OLYMPUS CORPORATION    ", *entries[Make].Value.String)
+}
+
+func TestTrimStrings(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   []string
+	}{
+		{"single", []string{"Canon"}, []string{"Canon"}},
+		{"trailing spaces", []string{"OLYMPUS CORPORATION    "}, []string{"OLYMPUS CORPORATION"}},
+		{"trailing whitespace", []string{"Model\t\r\n"}, []string{"Model"}},
+		{"leading spaces", []string{"  Model"}, []string{"  Model"}},
+		{"embedded NULs", []string{"Canon", "", "", "EOS "}, []string{"Canon", "EOS"}},
+		{"only padding", []string{" ", ""}, []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, trimStrings(tt.values))
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package tiff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Defaults of ObjectReader
+const (
+	defaultObjectBlockSize = 64 << 10
+	defaultObjectMaxBlocks = 64
+)
+
+// RangeReader reads a range of a remote object, e.g. with a GetObject request and a Range header (S3), or with
+// ObjectHandle.NewRangeReader (GCS).
+type RangeReader interface {
+	ReadRange(ctx context.Context, offset, length int64) ([]byte, error)
+}
+
+// RangeReaderFunc is a function implementing RangeReader.
+type RangeReaderFunc func(ctx context.Context, offset, length int64) ([]byte, error)
+
+func (f RangeReaderFunc) ReadRange(ctx context.Context, offset, length int64) ([]byte, error) {
+	return f(ctx, offset, length)
+}
+
+// ObjectReader adapts a RangeReader to the io.ReaderAt expected by NewSectionParser, so that the metadata of a remote
+// object can be read without downloading it:
+//
+//	obj := tiff.NewObjectReader(ctx, tiff.RangeReaderFunc(func(ctx context.Context, offset, length int64) ([]byte, error) {
+//		rng := fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+//		out, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: &bucket, Key: &key, Range: &rng})
+//		if err != nil {
+//			return nil, err
+//		}
+//		defer out.Body.Close()
+//		return io.ReadAll(out.Body)
+//	}), size)
+//	p, err := tiff.NewSectionParser(obj, 0, obj.Size())
+//
+// Objects are read in blocks (64 KiB by default), which are cached (up to 64 by default): as the header, the IFD
+// tables and the values they point to usually lie close to one another, reading ahead this way typically serves a
+// whole IFD with a single request, rather than one request per entry. Misses spanning several blocks are fetched with
+// a single request. It is safe for concurrent use.
+type ObjectReader struct {
+	ctx    context.Context
+	reader RangeReader
+	size   int64
+
+	blockSize int64
+	maxBlocks int
+
+	mu       sync.Mutex
+	blocks   map[int64][]byte // cached blocks, by offset
+	order    []int64          // offsets of the cached blocks, from the oldest to the newest
+	requests int
+}
+
+// NewObjectReader returns a reader of the remote object of the given size, read with r. The context is passed to r.
+func NewObjectReader(ctx context.Context, r RangeReader, size int64) *ObjectReader {
+	return &ObjectReader{
+		ctx:       ctx,
+		reader:    r,
+		size:      size,
+		blockSize: defaultObjectBlockSize,
+		maxBlocks: defaultObjectMaxBlocks,
+		blocks:    make(map[int64][]byte),
+	}
+}
+
+// WithBlockSize sets the size of the blocks the object is read in, i.e. the smallest range read with each request, and
+// how many of them are cached.
+func (o *ObjectReader) WithBlockSize(size int64, maxBlocks int) *ObjectReader {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.blockSize, o.maxBlocks = max(size, 1), max(maxBlocks, 1)
+	clear(o.blocks)
+	o.order = o.order[:0]
+
+	return o
+}
+
+// Size returns the size of the object.
+func (o *ObjectReader) Size() int64 {
+	return o.size
+}
+
+// Requests returns the number of ranges read so far, e.g. to monitor costs.
+func (o *ObjectReader) Requests() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.requests
+}
+
+func (o *ObjectReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	n := 0
+	for n < len(p) {
+		pos := off + int64(n)
+		if pos >= o.size {
+			return n, io.EOF
+		}
+		start := pos - pos%o.blockSize
+		block, ok := o.blocks[start]
+		if !ok {
+			var err error
+			if block, err = o.fetch(start, off+int64(len(p))); err != nil {
+				return n, err
+			}
+		}
+		n += copy(p[n:], block[pos-start:])
+	}
+
+	return n, nil
+}
+
+// Prefetch reads the blocks covering the given ranges that are not cached yet (see Parser.Prefetch), with a single
+// request per range of consecutive blocks.
+func (o *ObjectReader) Prefetch(ranges []Range) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, r := range ranges {
+		end := min(r.Offset+r.Length, o.size)
+		for pos := max(r.Offset, 0) - max(r.Offset, 0)%o.blockSize; pos < end; pos += o.blockSize {
+			if _, ok := o.blocks[pos]; ok {
+				continue
+			}
+			if _, err := o.fetch(pos, end); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// fetch reads, with a single request, the block starting at the given offset and the following ones up to end (if
+// they are not cached already), caching them. It returns the first block.
+func (o *ObjectReader) fetch(start, end int64) ([]byte, error) {
+	stop := start + o.blockSize
+	for stop < min(end, o.size) {
+		if _, ok := o.blocks[stop]; ok {
+			break
+		}
+		stop += o.blockSize
+	}
+	stop = min(stop, o.size)
+
+	o.requests++
+	data, err := o.reader.ReadRange(o.ctx, start, stop-start)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %d bytes at offset %d: %w", stop-start, start, err)
+	}
+	if int64(len(data)) != stop-start {
+		return nil, fmt.Errorf("read %d bytes at offset %d, expected %d", len(data), start, stop-start)
+	}
+
+	for offset := start; offset < stop; offset += o.blockSize {
+		if len(o.order) >= o.maxBlocks {
+			delete(o.blocks, o.order[0])
+			o.order = o.order[1:]
+		}
+		o.blocks[offset] = data[offset-start : min(offset+o.blockSize, stop)-start]
+		o.order = append(o.order, offset)
+	}
+
+	return data[:min(o.blockSize, stop-start)], nil
+}
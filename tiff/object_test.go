@@ -0,0 +1,91 @@
+package tiff
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// objectStore serves ranges of data, recording them.
+type objectStore struct {
+	data   []byte
+	ranges [][2]int64
+	err    error
+}
+
+func (s *objectStore) ReadRange(_ context.Context, offset, length int64) ([]byte, error) {
+	s.ranges = append(s.ranges, [2]int64{offset, length})
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.data[offset : offset+length], nil
+}
+
+func TestObjectReader_Parse(t *testing.T) {
+	store := &objectStore{data: cr2Image}
+	obj := NewObjectReader(context.Background(), store, int64(len(cr2Image)))
+
+	p, err := NewSectionParser(obj, 0, obj.Size())
+	assert.NoError(t, err)
+	entries, err := p.Parse(Make, Model, ExposureTime, DateTimeOriginal)
+	assert.NoError(t, err)
+	assert.Equal(t, "Canon EOS 7D", *entries[Model].Value.String)
+	assert.Contains(t, entries, ExposureTime)
+
+	// the header, IFD#0 and the Exif IFD lie in the first block
+	assert.Equal(t, [][2]int64{{0, 64 << 10}}, store.ranges)
+	assert.Equal(t, 1, obj.Requests())
+}
+
+func TestObjectReader_ReadAt(t *testing.T) {
+	data := make([]byte, 100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	store := &objectStore{data: data}
+	obj := NewObjectReader(context.Background(), store, int64(len(data))).WithBlockSize(16, 3)
+
+	buffer := make([]byte, 40)
+	n, err := obj.ReadAt(buffer, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 40, n)
+	assert.Equal(t, data[10:50], buffer)
+	// blocks 0 to 3 in a single request
+	assert.Equal(t, [][2]int64{{0, 64}}, store.ranges)
+
+	// block 0 was evicted, to keep 3 blocks at most
+	_, err = obj.ReadAt(buffer[:4], 60)
+	assert.NoError(t, err)
+	assert.Equal(t, data[60:64], buffer[:4])
+	_, err = obj.ReadAt(buffer[:4], 2)
+	assert.NoError(t, err)
+	assert.Equal(t, data[2:6], buffer[:4])
+	assert.Equal(t, [][2]int64{{0, 64}, {0, 16}}, store.ranges)
+
+	// the last block is shorter
+	n, err = obj.ReadAt(buffer, 90)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, 10, n)
+	assert.Equal(t, data[90:], buffer[:n])
+	assert.Equal(t, [2]int64{80, 20}, store.ranges[2])
+
+	_, err = obj.ReadAt(buffer, -1)
+	assert.Error(t, err)
+}
+
+func TestObjectReader_Error(t *testing.T) {
+	failure := errors.New("access denied")
+	obj := NewObjectReader(context.Background(), &objectStore{err: failure}, 100)
+
+	_, err := NewSectionParser(obj, 0, obj.Size())
+	assert.ErrorIs(t, err, failure)
+
+	short := RangeReaderFunc(func(context.Context, int64, int64) ([]byte, error) {
+		return []byte{1, 2}, nil
+	})
+	_, err = NewObjectReader(context.Background(), short, 100).ReadAt(make([]byte, 4), 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,449 @@
+package tiff
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+type (
+	// OpcodeID identifies a DNG opcode.
+	OpcodeID uint32
+
+	// Opcode represents a decoded DNG opcode: each implementation embeds the OpcodeHeader and holds the typed parameters
+	// of the opcode.
+	Opcode interface {
+		Header() OpcodeHeader
+	}
+)
+
+const (
+	Opcode_WarpRectilinear OpcodeID = iota + 1
+	Opcode_WarpFisheye
+	Opcode_FixVignetteRadial
+	Opcode_FixBadPixelsConstant
+	Opcode_FixBadPixelsList
+	Opcode_TrimBounds
+	Opcode_MapTable
+	Opcode_MapPolynomial
+	Opcode_GainMap
+	Opcode_DeltaPerRow
+	Opcode_DeltaPerColumn
+	Opcode_ScalePerRow
+	Opcode_ScalePerColumn
+)
+
+const (
+	// OpcodeFlag_Optional indicates that the opcode may be skipped by readers that do not support it.
+	OpcodeFlag_Optional uint32 = 1
+	// OpcodeFlag_SkipIfPreview indicates that the opcode may be skipped when rendering preview-quality images.
+	OpcodeFlag_SkipIfPreview uint32 = 2
+)
+
+// OpcodeHeader holds the fields common to all opcodes.
+type OpcodeHeader struct {
+	ID      OpcodeID
+	Version uint32 // DNG spec version the opcode was introduced with, e.g. 0x01030000 for 1.3.0.0
+	Flags   uint32
+}
+
+// Header returns the header of the opcode.
+func (h OpcodeHeader) Header() OpcodeHeader {
+	return h
+}
+
+// Optional tells whether the opcode may be skipped by readers that do not support it.
+func (h OpcodeHeader) Optional() bool {
+	return h.Flags&OpcodeFlag_Optional != 0
+}
+
+// OpcodeArea is the area of the image an opcode applies to, together with the planes and pitch to use.
+type OpcodeArea struct {
+	Top      uint32
+	Left     uint32
+	Bottom   uint32
+	Right    uint32
+	Plane    uint32
+	Planes   uint32
+	RowPitch uint32
+	ColPitch uint32
+}
+
+// WarpRectilinearCoefficients are the radial (Kr) and tangential (Kt) distortion coefficients for one plane.
+type WarpRectilinearCoefficients struct {
+	Kr [4]float64
+	Kt [2]float64
+}
+
+// WarpRectilinear corrects geometric distortion and lateral chromatic aberration for rectilinear lenses.
+type WarpRectilinear struct {
+	OpcodeHeader
+	Planes  []WarpRectilinearCoefficients // one per plane, or a single one applying to all planes
+	CenterX float64                       // normalized optical center
+	CenterY float64                       // normalized optical center
+}
+
+// WarpFisheye unwraps an image captured with a fisheye lens.
+type WarpFisheye struct {
+	OpcodeHeader
+	Planes  [][4]float64 // radial coefficients, one set per plane
+	CenterX float64
+	CenterY float64
+}
+
+// FixVignetteRadial corrects vignetting using a radial gain function.
+type FixVignetteRadial struct {
+	OpcodeHeader
+	K       [5]float64
+	CenterX float64
+	CenterY float64
+}
+
+// FixBadPixelsConstant fixes pixels whose raw value equals a constant.
+type FixBadPixelsConstant struct {
+	OpcodeHeader
+	Constant   uint32
+	BayerPhase uint32
+}
+
+// BadPixel is the position of a bad pixel.
+type BadPixel struct {
+	Row    uint32
+	Column uint32
+}
+
+// BadRect is a rectangle of bad pixels.
+type BadRect struct {
+	Top    uint32
+	Left   uint32
+	Bottom uint32
+	Right  uint32
+}
+
+// FixBadPixelsList fixes a list of bad pixels and rectangles.
+type FixBadPixelsList struct {
+	OpcodeHeader
+	BayerPhase uint32
+	Points     []BadPixel
+	Rects      []BadRect
+}
+
+// TrimBounds trims the image to the given rectangle.
+type TrimBounds struct {
+	OpcodeHeader
+	Top    uint32
+	Left   uint32
+	Bottom uint32
+	Right  uint32
+}
+
+// MapTable maps a range of samples through a lookup table.
+type MapTable struct {
+	OpcodeHeader
+	Area  OpcodeArea
+	Table []uint16
+}
+
+// MapPolynomial maps a range of samples through a polynomial, given as coefficients of increasing degree.
+type MapPolynomial struct {
+	OpcodeHeader
+	Area         OpcodeArea
+	Coefficients []float64
+}
+
+// GainMap multiplies a range of samples by a 2D map of gains, typically used for lens shading correction.
+type GainMap struct {
+	OpcodeHeader
+	Area      OpcodeArea
+	PointsV   uint32
+	PointsH   uint32
+	SpacingV  float64
+	SpacingH  float64
+	OriginV   float64
+	OriginH   float64
+	MapPlanes uint32
+	Gains     []float32 // PointsV * PointsH * MapPlanes gains, row-major with planes interleaved
+}
+
+// Gain returns the gain at the given map point and plane.
+func (g GainMap) Gain(v, h, plane uint32) float32 {
+	return g.Gains[(v*g.PointsH+h)*g.MapPlanes+plane]
+}
+
+// DeltaPerRow adds a per-row delta to a range of samples.
+type DeltaPerRow struct {
+	OpcodeHeader
+	Area   OpcodeArea
+	Deltas []float32
+}
+
+// DeltaPerColumn adds a per-column delta to a range of samples.
+type DeltaPerColumn struct {
+	OpcodeHeader
+	Area   OpcodeArea
+	Deltas []float32
+}
+
+// ScalePerRow multiplies a range of samples by a per-row scale.
+type ScalePerRow struct {
+	OpcodeHeader
+	Area   OpcodeArea
+	Scales []float32
+}
+
+// ScalePerColumn multiplies a range of samples by a per-column scale.
+type ScalePerColumn struct {
+	OpcodeHeader
+	Area   OpcodeArea
+	Scales []float32
+}
+
+// UnknownOpcode is an opcode this parser does not know about: its parameters are returned as they are.
+type UnknownOpcode struct {
+	OpcodeHeader
+	Params []byte
+}
+
+// ReadOpcodeList reads and decodes a DNG opcode list entry (OpcodeList1, OpcodeList2 or OpcodeList3).
+func ReadOpcodeList(entries map[EntryID]Entry, id EntryID) ([]Opcode, error) {
+	entry, ok := entries[id]
+	if !ok {
+		return nil, fmt.Errorf("entry 0x%X not found", id)
+	}
+	if entry.Value.Bytes == nil {
+		return nil, fmt.Errorf("entry 0x%X is not a byte sequence", id)
+	}
+
+	return ParseOpcodeList(entry.Value.Bytes)
+}
+
+// ParseOpcodeList decodes a serialized DNG opcode list which, regardless of the byte order of the file, is always
+// big-endian.
+func ParseOpcodeList(data []byte) ([]Opcode, error) {
+	r := &opcodeReader{data: data}
+	count := r.uint32()
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	opcodes := make([]Opcode, 0, min(count, uint32(len(data)/16)))
+	for i := uint32(0); i < count; i++ {
+		header := OpcodeHeader{
+			ID:      OpcodeID(r.uint32()),
+			Version: r.uint32(),
+			Flags:   r.uint32(),
+		}
+		params := r.bytes(r.uint32())
+		if r.err != nil {
+			return nil, fmt.Errorf("opcode #%d: %w", i, r.err)
+		}
+
+		opcode, err := decodeOpcode(header, params)
+		if err != nil {
+			return nil, fmt.Errorf("opcode #%d (ID %d): %w", i, header.ID, err)
+		}
+		opcodes = append(opcodes, opcode)
+	}
+
+	return opcodes, nil
+}
+
+// decodeOpcode decodes the parameters of a single opcode.
+func decodeOpcode(header OpcodeHeader, params []byte) (Opcode, error) {
+	r := &opcodeReader{data: params}
+	var opcode Opcode
+
+	switch header.ID {
+	case Opcode_WarpRectilinear:
+		op := WarpRectilinear{OpcodeHeader: header}
+		n := r.count(48)
+		for i := uint32(0); i < n; i++ {
+			var c WarpRectilinearCoefficients
+			for j := range c.Kr {
+				c.Kr[j] = r.float64()
+			}
+			for j := range c.Kt {
+				c.Kt[j] = r.float64()
+			}
+			op.Planes = append(op.Planes, c)
+		}
+		op.CenterX, op.CenterY = r.float64(), r.float64()
+		opcode = op
+	case Opcode_WarpFisheye:
+		op := WarpFisheye{OpcodeHeader: header}
+		n := r.count(32)
+		for i := uint32(0); i < n; i++ {
+			var k [4]float64
+			for j := range k {
+				k[j] = r.float64()
+			}
+			op.Planes = append(op.Planes, k)
+		}
+		op.CenterX, op.CenterY = r.float64(), r.float64()
+		opcode = op
+	case Opcode_FixVignetteRadial:
+		op := FixVignetteRadial{OpcodeHeader: header}
+		for j := range op.K {
+			op.K[j] = r.float64()
+		}
+		op.CenterX, op.CenterY = r.float64(), r.float64()
+		opcode = op
+	case Opcode_FixBadPixelsConstant:
+		opcode = FixBadPixelsConstant{OpcodeHeader: header, Constant: r.uint32(), BayerPhase: r.uint32()}
+	case Opcode_FixBadPixelsList:
+		op := FixBadPixelsList{OpcodeHeader: header, BayerPhase: r.uint32()}
+		points, rects := r.uint32(), r.uint32()
+		if uint64(points)*8+uint64(rects)*16 > uint64(r.remaining()) {
+			return nil, errors.New("bad pixel list exceeds opcode parameters")
+		}
+		for i := uint32(0); i < points; i++ {
+			op.Points = append(op.Points, BadPixel{Row: r.uint32(), Column: r.uint32()})
+		}
+		for i := uint32(0); i < rects; i++ {
+			op.Rects = append(op.Rects, BadRect{Top: r.uint32(), Left: r.uint32(), Bottom: r.uint32(), Right: r.uint32()})
+		}
+		opcode = op
+	case Opcode_TrimBounds:
+		opcode = TrimBounds{OpcodeHeader: header, Top: r.uint32(), Left: r.uint32(), Bottom: r.uint32(), Right: r.uint32()}
+	case Opcode_MapTable:
+		op := MapTable{OpcodeHeader: header, Area: r.area()}
+		n := r.count(2)
+		for i := uint32(0); i < n; i++ {
+			op.Table = append(op.Table, r.uint16())
+		}
+		opcode = op
+	case Opcode_MapPolynomial:
+		op := MapPolynomial{OpcodeHeader: header, Area: r.area()}
+		degree := r.uint32()
+		if (uint64(degree)+1)*8 > uint64(r.remaining()) {
+			return nil, errors.New("polynomial degree exceeds opcode parameters")
+		}
+		for i := uint64(0); i <= uint64(degree); i++ {
+			op.Coefficients = append(op.Coefficients, r.float64())
+		}
+		opcode = op
+	case Opcode_GainMap:
+		op := GainMap{
+			OpcodeHeader: header,
+			Area:         r.area(),
+			PointsV:      r.uint32(),
+			PointsH:      r.uint32(),
+			SpacingV:     r.float64(),
+			SpacingH:     r.float64(),
+			OriginV:      r.float64(),
+			OriginH:      r.float64(),
+			MapPlanes:    r.uint32(),
+		}
+		n := uint64(op.PointsV) * uint64(op.PointsH) * uint64(op.MapPlanes)
+		if n*4 > uint64(r.remaining()) {
+			return nil, errors.New("gain map exceeds opcode parameters")
+		}
+		op.Gains = r.floats32(uint32(n))
+		opcode = op
+	case Opcode_DeltaPerRow:
+		area := r.area()
+		opcode = DeltaPerRow{OpcodeHeader: header, Area: area, Deltas: r.floats32(r.count(4))}
+	case Opcode_DeltaPerColumn:
+		area := r.area()
+		opcode = DeltaPerColumn{OpcodeHeader: header, Area: area, Deltas: r.floats32(r.count(4))}
+	case Opcode_ScalePerRow:
+		area := r.area()
+		opcode = ScalePerRow{OpcodeHeader: header, Area: area, Scales: r.floats32(r.count(4))}
+	case Opcode_ScalePerColumn:
+		area := r.area()
+		opcode = ScalePerColumn{OpcodeHeader: header, Area: area, Scales: r.floats32(r.count(4))}
+	default:
+		return UnknownOpcode{OpcodeHeader: header, Params: params}, nil
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return opcode, nil
+}
+
+// opcodeReader reads big-endian values from a serialized opcode list, remembering the first error it encounters so
+// that callers can check it once after a sequence of reads.
+type opcodeReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *opcodeReader) remaining() int {
+	return len(r.data) - r.pos
+}
+
+func (r *opcodeReader) bytes(n uint32) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if uint64(n) > uint64(r.remaining()) {
+		r.err = errors.New("unexpected end of opcode list")
+		return nil
+	}
+	res := r.data[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+
+	return res
+}
+
+func (r *opcodeReader) uint16() uint16 {
+	if b := r.bytes(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *opcodeReader) uint32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *opcodeReader) float64() float64 {
+	if b := r.bytes(8); b != nil {
+		return math.Float64frombits(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+func (r *opcodeReader) floats32(n uint32) []float32 {
+	b := r.bytes(4 * n)
+	if b == nil {
+		return nil
+	}
+	res := make([]float32, n)
+	for i := range res {
+		res[i] = math.Float32frombits(binary.BigEndian.Uint32(b[4*i:]))
+	}
+
+	return res
+}
+
+// count reads a count of items of the given size, making sure that they fit in the remaining data.
+func (r *opcodeReader) count(size int) uint32 {
+	n := r.uint32()
+	if r.err == nil && uint64(n)*uint64(size) > uint64(r.remaining()) {
+		r.err = errors.New("item count exceeds opcode parameters")
+		return 0
+	}
+
+	return n
+}
+
+func (r *opcodeReader) area() OpcodeArea {
+	return OpcodeArea{
+		Top:      r.uint32(),
+		Left:     r.uint32(),
+		Bottom:   r.uint32(),
+		Right:    r.uint32(),
+		Plane:    r.uint32(),
+		Planes:   r.uint32(),
+		RowPitch: r.uint32(),
+		ColPitch: r.uint32(),
+	}
+}
@@ -0,0 +1,140 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// opcodeList serializes opcodes the way DNG does, from (ID, parameters) pairs.
+type opcodeList struct {
+	buffer []byte
+	count  uint32
+}
+
+func (l *opcodeList) add(id OpcodeID, flags uint32, params ...any) *opcodeList {
+	var data []byte
+	for _, param := range params {
+		switch v := param.(type) {
+		case uint32:
+			data = binary.BigEndian.AppendUint32(data, v)
+		case float64:
+			data = binary.BigEndian.AppendUint64(data, math.Float64bits(v))
+		case float32:
+			data = binary.BigEndian.AppendUint32(data, math.Float32bits(v))
+		}
+	}
+	l.buffer = binary.BigEndian.AppendUint32(l.buffer, uint32(id))
+	l.buffer = binary.BigEndian.AppendUint32(l.buffer, 0x01030000)
+	l.buffer = binary.BigEndian.AppendUint32(l.buffer, flags)
+	l.buffer = binary.BigEndian.AppendUint32(l.buffer, uint32(len(data)))
+	l.buffer = append(l.buffer, data...)
+	l.count++
+
+	return l
+}
+
+func (l *opcodeList) bytes() []byte {
+	return append(binary.BigEndian.AppendUint32(nil, l.count), l.buffer...)
+}
+
+func TestParseOpcodeList(t *testing.T) {
+	list := (&opcodeList{}).
+		add(Opcode_GainMap, OpcodeFlag_Optional,
+			uint32(0), uint32(0), uint32(10), uint32(10), uint32(0), uint32(1), uint32(1), uint32(1),
+			uint32(2), uint32(2), 0.5, 0.5, 0.0, 0.0, uint32(1),
+			float32(1), float32(1.5), float32(2), float32(2.5)).
+		add(Opcode_FixBadPixelsConstant, 0, uint32(0), uint32(1)).
+		add(Opcode_WarpRectilinear, 0, uint32(1), 1.0, 0.1, 0.01, 0.001, 0.0, 0.0, 0.5, 0.5).
+		add(OpcodeID(99), OpcodeFlag_Optional, uint32(42))
+
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().WithUints32(uint16(NewSubfileType), 1).
+		WithSubIFD(uint16(SubIFDs)).WithBytes(uint16(OpcodeList2), test.TypeUndefined, list.bytes()...)
+
+	p, err := NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+	entries, err := p.Parse(OpcodeList2)
+	assert.NoError(t, err)
+
+	opcodes, err := ReadOpcodeList(entries, OpcodeList2)
+	assert.NoError(t, err)
+	assert.Len(t, opcodes, 4)
+
+	gainMap, ok := opcodes[0].(GainMap)
+	assert.True(t, ok)
+	assert.True(t, gainMap.Optional())
+	assert.EqualValues(t, 10, gainMap.Area.Bottom)
+	assert.EqualValues(t, 2, gainMap.PointsH)
+	assert.Equal(t, 0.5, gainMap.SpacingV)
+	assert.Equal(t, float32(2), gainMap.Gain(1, 0, 0))
+
+	fix, ok := opcodes[1].(FixBadPixelsConstant)
+	assert.True(t, ok)
+	assert.EqualValues(t, 1, fix.BayerPhase)
+
+	warp, ok := opcodes[2].(WarpRectilinear)
+	assert.True(t, ok)
+	assert.Len(t, warp.Planes, 1)
+	assert.Equal(t, [4]float64{1, 0.1, 0.01, 0.001}, warp.Planes[0].Kr)
+	assert.Equal(t, 0.5, warp.CenterY)
+
+	unknown, ok := opcodes[3].(UnknownOpcode)
+	assert.True(t, ok)
+	assert.EqualValues(t, 99, unknown.Header().ID)
+	assert.Equal(t, []byte{0, 0, 0, 42}, unknown.Params)
+}
+
+func TestParseOpcodeList_IFD0(t *testing.T) {
+	// DNG files holding a single image store its opcode lists in IFD#0
+	list := (&opcodeList{}).add(Opcode_FixBadPixelsConstant, 0, uint32(0), uint32(1))
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().WithUints32(uint16(NewSubfileType), 0).
+		WithBytes(uint16(OpcodeList1), test.TypeUndefined, list.bytes()...)
+
+	p, err := NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+	entries, err := p.Parse(OpcodeList1, OpcodeList3)
+	assert.NoError(t, err)
+	assert.Equal(t, Group_IFD0, entries[OpcodeList1].Group)
+	assert.NotContains(t, entries, OpcodeList3)
+
+	opcodes, err := ReadOpcodeList(entries, OpcodeList1)
+	assert.NoError(t, err)
+	assert.Len(t, opcodes, 1)
+	fix, ok := opcodes[0].(FixBadPixelsConstant)
+	assert.True(t, ok)
+	assert.EqualValues(t, 1, fix.BayerPhase)
+}
+
+func TestParseOpcodeList_Truncated(t *testing.T) {
+	list := (&opcodeList{}).add(Opcode_FixBadPixelsConstant, 0, uint32(0), uint32(1)).bytes()
+
+	_, err := ParseOpcodeList(list[:len(list)-2])
+	assert.Error(t, err)
+
+	// a gain map declaring more points than it holds
+	list = (&opcodeList{}).add(Opcode_GainMap, 0,
+		uint32(0), uint32(0), uint32(10), uint32(10), uint32(0), uint32(1), uint32(1), uint32(1),
+		uint32(1000), uint32(1000), 0.5, 0.5, 0.0, 0.0, uint32(1)).bytes()
+	_, err = ParseOpcodeList(list)
+	assert.Error(t, err)
+}
+
+func TestParseOpcodeList_MapPolynomial(t *testing.T) {
+	area := []any{uint32(0), uint32(0), uint32(10), uint32(10), uint32(0), uint32(1), uint32(1), uint32(1)}
+	list := (&opcodeList{}).add(Opcode_MapPolynomial, 0, append(area, uint32(1), 0.5, 2.0)...).bytes()
+	opcodes, err := ParseOpcodeList(list)
+	assert.NoError(t, err)
+	assert.Len(t, opcodes, 1)
+	assert.Equal(t, []float64{0.5, 2}, opcodes[0].(MapPolynomial).Coefficients)
+
+	// a degree whose number of coefficients overflows 32 bits
+	list = (&opcodeList{}).add(Opcode_MapPolynomial, 0, append(area, uint32(math.MaxUint32), 0.5)...).bytes()
+	_, err = ParseOpcodeList(list)
+	assert.Error(t, err)
+}
@@ -0,0 +1,186 @@
+package tiff
+
+import (
+	"fmt"
+	"io"
+)
+
+// Page represents one of the images (aka subfiles) stored in a TIFF file, each one described by an IFD of the main IFD
+// chain (IFD#0, IFD#1, ...).
+type Page struct {
+	Index   int               // position of the IFD in the chain
+	Offset  int64             // offset of the IFD
+	Entries map[EntryID]Entry // all entries of the IFD
+}
+
+// Pages reads all IFDs of the main IFD chain, returning one Page per IFD, up to the first the parser does not read (see
+// WithGroups). It returns an error if the chain loops or if any of the IFDs cannot be read.
+func (p *Parser) Pages() ([]Page, error) {
+	var pages []Page
+	seen := make(map[int64]bool)
+
+	for offset := p.firstIFDOffset; offset != 0; {
+		if seen[offset] {
+			return nil, fmt.Errorf("IFD chain loops back to offset %d", offset)
+		}
+		seen[offset] = true
+		if !p.follows(pageGroup(len(pages))) {
+			break
+		}
+
+		entries, next, err := p.readIFD(offset, pageGroup(len(pages)))
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, Page{Index: len(pages), Offset: offset, Entries: entries})
+		offset = next
+	}
+
+	return pages, nil
+}
+
+// Uint returns the value of a scalar unsigned integer entry of the page.
+func (pg Page) Uint(id EntryID) (uint32, bool) {
+	entry, ok := pg.Entries[id]
+	if !ok {
+		return 0, false
+	}
+	values, err := entry.Uints()
+	if err != nil || len(values) == 0 {
+		return 0, false
+	}
+
+	return values[0], true
+}
+
+// Width returns the width of the page's image, in pixels.
+func (pg Page) Width() (uint32, bool) {
+	return pg.Uint(ImageWidth)
+}
+
+// Height returns the height of the page's image, in pixels.
+func (pg Page) Height() (uint32, bool) {
+	return pg.Uint(ImageHeight)
+}
+
+// Description returns the ImageDescription of the page, if any.
+func (pg Page) Description() string {
+	if entry, ok := pg.Entries[ImageDescription]; ok && entry.Value.String != nil {
+		return *entry.Value.String
+	}
+
+	return ""
+}
+
+// Tiled tells whether the page's image is organized in tiles (rather than strips).
+func (pg Page) Tiled() bool {
+	_, ok := pg.Entries[TileOffsets]
+	return ok
+}
+
+// FirstPage reads IFD#0 only, returning it as a Page.
+func (p *Parser) FirstPage() (Page, error) {
+	entries, _, err := p.readIFD(p.firstIFDOffset, Group_IFD0)
+	if err != nil {
+		return Page{}, err
+	}
+
+	return Page{Index: 0, Offset: p.firstIFDOffset, Entries: entries}, nil
+}
+
+// Uints returns the value(s) of an unsigned integer entry of the page.
+func (pg Page) Uints(id EntryID) ([]uint32, bool) {
+	entry, ok := pg.Entries[id]
+	if !ok {
+		return nil, false
+	}
+	values, err := entry.Uints()
+	if err != nil {
+		return nil, false
+	}
+
+	return values, true
+}
+
+// ReadStrips reads the (still encoded) strips of the page's image, in order.
+func (p *Parser) ReadStrips(page Page) ([][]byte, error) {
+	return p.readChunks(page, StripOffsets, StripByteCounts)
+}
+
+// ReadTiles reads the (still encoded) tiles of the page's image, in order: left to right, then top to bottom.
+func (p *Parser) ReadTiles(page Page) ([][]byte, error) {
+	return p.readChunks(page, TileOffsets, TileByteCounts)
+}
+
+// readChunks reads the data areas (e.g. strips or tiles) pointed to by a pair of offsets, lengths entries.
+func (p *Parser) readChunks(page Page, offsetsID, lengthsID EntryID) ([][]byte, error) {
+	offsets, lengths, err := page.chunks(offsetsID, lengthsID)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make([][]byte, len(offsets))
+	for i := range offsets {
+		if chunks[i], err = p.readChunk(offsets[i], lengths[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return chunks, nil
+}
+
+// uncheckedChunkSize is the size up to which data areas are read without checking first that they lie within the file:
+// reading them fails anyway if they do not, and allocating their buffer costs little.
+const uncheckedChunkSize = 64 << 10
+
+// readChunk reads a data area. Larger areas are checked to lie within the file before their buffer is allocated, so that
+// a crafted length (e.g. the count of an entry) cannot make the parser allocate gigabytes.
+func (p *Parser) readChunk(offset, length uint32) ([]byte, error) {
+	if err := p.checkValueSize(int64(length)); err != nil {
+		return nil, err
+	}
+	if length > uncheckedChunkSize {
+		end, err := p.reader.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, err
+		}
+		if int64(offset)+int64(length) > end {
+			return nil, fmt.Errorf("%d bytes at offset %d extend past the end of the file: %w", length, offset, io.ErrUnexpectedEOF)
+		}
+	}
+	if _, err := p.reader.Seek(int64(offset), io.SeekStart); err != nil {
+		return nil, err
+	}
+	buffer := make([]byte, length)
+	if _, err := io.ReadFull(p.reader, buffer); err != nil {
+		return nil, err
+	}
+
+	return buffer, nil
+}
+
+// chunks returns the offsets and lengths of the data areas pointed to by a pair of offsets, lengths entries.
+func (pg Page) chunks(offsetsID, lengthsID EntryID) ([]uint32, []uint32, error) {
+	offsetsEntry, ok := pg.Entries[offsetsID]
+	if !ok {
+		return nil, nil, fmt.Errorf("entry 0x%X not found", offsetsID)
+	}
+	lengthsEntry, ok := pg.Entries[lengthsID]
+	if !ok {
+		return nil, nil, fmt.Errorf("entry 0x%X not found", lengthsID)
+	}
+
+	offsets, err := offsetsEntry.Uints()
+	if err != nil {
+		return nil, nil, err
+	}
+	lengths, err := lengthsEntry.Uints()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(offsets) != len(lengths) {
+		return nil, nil, fmt.Errorf("number of offsets (%d) and lengths (%d) differ", len(offsets), len(lengths))
+	}
+
+	return offsets, lengths, nil
+}
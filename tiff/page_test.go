@@ -0,0 +1,26 @@
+package tiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPages(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+
+	pages, err := p.Pages()
+	assert.NoError(t, err)
+	assert.Len(t, pages, 4)
+
+	width, ok := pages[0].Width()
+	assert.True(t, ok)
+	assert.EqualValues(t, 5184, width)
+
+	strips, err := p.ReadStrips(pages[0])
+	assert.NoError(t, err)
+	assert.NotEmpty(t, strips)
+	assert.Equal(t, []byte{0xFF, 0xD8}, strips[0][:2]) // IFD#0 of CR2 files holds a JPEG preview
+}
@@ -0,0 +1,154 @@
+package tiff
+
+import (
+	"cmp"
+	"io"
+	"slices"
+)
+
+// Limits of Prefetch
+const (
+	prefetchTableSize    = 2 + 64*EntryLength + 4 // bytes prefetched for an IFD table, enough for 64 entries
+	maxPrefetchValueSize = 64 << 10               // larger values (e.g. maker notes) are read on demand
+	maxPrefetchGap       = 4 << 10                // ranges closer than this are merged
+)
+
+// Range is a range of bytes of a file.
+type Range struct {
+	Offset int64
+	Length int64
+}
+
+// Prefetcher is implemented by readers that benefit from knowing in advance which ranges are about to be read, such as
+// ObjectReader: reading them with a few large requests is cheaper than with many small ones.
+type Prefetcher interface {
+	Prefetch(ranges []Range) error
+}
+
+// Prefetch tells the reader of the file which ranges reading the IFDs of the given groups will need: their tables and
+// the values of their entries (except large ones, such as maker notes), so that it can read them in advance, with a
+// few coalesced requests rather than one per seek. It only applies to readers implementing Prefetcher (or sections of
+// such readers, see NewSectionParser), and does nothing otherwise.
+//
+// Ranges are computed one level of IFDs at a time (e.g. IFD#0, then the Exif and GPS IFDs it points to), and
+// prefetched as such, as the tables of an IFD tell where the IFDs it points to are: reading the metadata of a remote
+// file typically takes one or two requests.
+func (p *Parser) Prefetch(groups ...Group) error {
+	prefetcher, base, ok := p.prefetcher()
+	if !ok {
+		return nil
+	}
+
+	// groups the parser does not read are neither prefetched nor traversed (see WithGroups)
+	wants := func(candidates ...Group) bool {
+		return slices.ContainsFunc(candidates, func(g Group) bool { return slices.Contains(groups, g) && p.follows(g) })
+	}
+
+	type ifd struct {
+		offset int64
+		group  Group
+	}
+	var values []Range
+	seen := map[int64]bool{p.firstIFDOffset: true}
+	for level := []ifd{{p.firstIFDOffset, Group_IFD0}}; len(level) > 0; {
+		tables := make([]Range, len(level))
+		for i, d := range level {
+			tables[i] = Range{Offset: d.offset, Length: prefetchTableSize}
+		}
+		if err := prefetcher.Prefetch(coalesce(tables, base)); err != nil {
+			return err
+		}
+
+		var next []ifd
+		for _, d := range level {
+			entries, nextOffset, err := p.ReadRawIFD(d.offset)
+			if err != nil {
+				return err
+			}
+			if wants(d.group) {
+				for _, e := range entries {
+					if size := e.Size(); !e.Inline() && size <= maxPrefetchValueSize && !p.excludes(d.group, e.ID) {
+						values = append(values, Range{Offset: int64(e.RawValue), Length: size})
+					}
+				}
+			}
+
+			// any IFD of the main chain may have SubIFDs
+			switch {
+			case nextOffset == 0:
+			case d.group == Group_IFD0 && p.follows(Group_IFD1) && wants(Group_IFD1, Group_Image, Group_SubIFD):
+				next = append(next, ifd{nextOffset, Group_IFD1})
+			case (d.group == Group_IFD1 || d.group == Group_Image) && p.follows(Group_Image) && wants(Group_Image, Group_SubIFD):
+				next = append(next, ifd{nextOffset, Group_Image})
+			}
+			for _, e := range entries {
+				for _, pointer := range subIFDPointers {
+					if e.ID != pointer.id || !wants(pointer.group) {
+						continue
+					}
+					value, err := p.readValue(e.DataType, e.Length, e.RawValue)
+					if err != nil {
+						return err
+					}
+					offsets, err := Entry{ID: e.ID, Value: value}.Uints()
+					if err != nil {
+						return err
+					}
+					for _, offset := range offsets {
+						next = append(next, ifd{int64(offset), pointer.group})
+					}
+				}
+			}
+		}
+
+		level = level[:0]
+		for _, d := range next {
+			if !seen[d.offset] && d.offset != 0 {
+				seen[d.offset] = true
+				level = append(level, d)
+			}
+		}
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	return prefetcher.Prefetch(coalesce(values, base))
+}
+
+// prefetcher returns the Prefetcher the parser reads from, if any, and the position in it of offsets of the file.
+func (p *Parser) prefetcher() (Prefetcher, int64, bool) {
+	r, base := io.Reader(p.reader), int64(0)
+	if shifted, ok := r.(*shiftedReader); ok {
+		r, base = shifted.ReadSeeker, shifted.base
+	}
+	if prefetcher, ok := r.(Prefetcher); ok {
+		return prefetcher, base, true
+	}
+	if section, ok := r.(*io.SectionReader); ok {
+		outer, offset, _ := section.Outer()
+		if prefetcher, ok := outer.(Prefetcher); ok {
+			return prefetcher, base + offset, true
+		}
+	}
+
+	return nil, 0, false
+}
+
+// coalesce sorts ranges, shifted by base, merging those that overlap or are closer than maxPrefetchGap.
+func coalesce(ranges []Range, base int64) []Range {
+	slices.SortFunc(ranges, func(a, b Range) int { return cmp.Compare(a.Offset, b.Offset) })
+
+	var res []Range
+	for _, r := range ranges {
+		r.Offset += base
+		if n := len(res); n > 0 && r.Offset <= res[n-1].Offset+res[n-1].Length+maxPrefetchGap {
+			res[n-1].Length = max(res[n-1].Length, r.Offset+r.Length-res[n-1].Offset)
+			continue
+		}
+		res = append(res, r)
+	}
+
+	return res
+}
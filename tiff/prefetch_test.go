@@ -0,0 +1,65 @@
+package tiff
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// prefetchRecorder records the ranges it is asked to prefetch.
+type prefetchRecorder struct {
+	*bytes.Reader
+	calls [][]Range
+}
+
+func (r *prefetchRecorder) Prefetch(ranges []Range) error {
+	r.calls = append(r.calls, ranges)
+	return nil
+}
+
+func TestParser_Prefetch(t *testing.T) {
+	r := &prefetchRecorder{Reader: bytes.NewReader(cr2Image)}
+	p, err := NewParser(r)
+	assert.NoError(t, err)
+
+	assert.NoError(t, p.Prefetch(Group_IFD0, Group_Exif))
+	assert.Equal(t, [][]Range{
+		{{Offset: 16, Length: prefetchTableSize}},  // IFD#0
+		{{Offset: 446, Length: prefetchTableSize}}, // Exif IFD
+		{{Offset: 238, Length: 57018}},             // values, coalesced
+	}, r.calls)
+
+	// IFD#1 is only reached through IFD#0
+	r.calls = nil
+	assert.NoError(t, p.Prefetch(Group_IFD1))
+	assert.Len(t, r.calls, 2) // the values of IFD#1 all fit in its entries
+	assert.Equal(t, []Range{{Offset: 48752, Length: prefetchTableSize}}, r.calls[1])
+}
+
+func TestCoalesce(t *testing.T) {
+	ranges := []Range{{Offset: 10000, Length: 10}, {Offset: 100, Length: 50}, {Offset: 120, Length: 10}, {Offset: 200, Length: 8}}
+	assert.Equal(t, []Range{{Offset: 1100, Length: 108}, {Offset: 11000, Length: 10}}, coalesce(ranges, 1000))
+}
+
+func TestParser_Prefetch_ObjectReader(t *testing.T) {
+	store := &objectStore{data: cr2Image}
+	obj := NewObjectReader(context.Background(), store, int64(len(cr2Image))).WithBlockSize(1<<10, 64)
+	p, err := NewSectionParser(obj, 0, obj.Size())
+	assert.NoError(t, err)
+
+	assert.NoError(t, p.Prefetch(Group_IFD0, Group_Exif))
+	requests := obj.Requests()
+
+	entries, err := p.Parse(Make, Model, ExposureTime, DateTimeOriginal, FNumber, ISO)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 6)
+	assert.Equal(t, requests, obj.Requests(), "everything was prefetched")
+}
+
+func TestParser_Prefetch_Unsupported(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	assert.NoError(t, p.Prefetch(Group_IFD0))
+}
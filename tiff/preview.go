@@ -0,0 +1,156 @@
+package tiff
+
+import (
+	"fmt"
+	"image/jpeg"
+	"io"
+	"sort"
+)
+
+// Compression schemes of JPEG-compressed images
+const (
+	compressionOldJPEG = 6
+	compressionJPEG    = 7
+)
+
+// Markers starting (Start Of Image) and ending (End Of Image) JPEG data
+var (
+	jpegSOI = []byte{0xff, 0xd8}
+	jpegEOI = []byte{0xff, 0xd9}
+)
+
+// Preview is a JPEG image embedded in a file to preview its main image, e.g. a thumbnail, a medium-sized preview or a
+// full-size JPEG.
+type Preview struct {
+	Group     Group // group of the IFD describing the preview
+	IFDOffset int64 // offset of the IFD describing the preview
+	Offset    int64 // offset of the JPEG data
+	Length    int64 // length of the JPEG data, in bytes
+	Width     int   // width, in pixels, as decoded from the JPEG data
+	Height    int   // height, in pixels, as decoded from the JPEG data
+}
+
+// Previews lists the JPEG previews found in all IFDs of the file (see Find): images referenced by ThumbnailOffset and
+// ThumbnailLength (aka JPEGInterchangeFormat and JPEGInterchangeFormatLength) and JPEG-compressed images stored in a
+// single strip (e.g. the full-size JPEG of IFD#0 of CR2 files), as well as those the vendor of the camera stores
+// elsewhere (e.g. in the maker note of ORF files, see Vendor). Their dimensions are decoded from the JPEG data itself,
+// as those written in IFDs are often missing or wrong: images that cannot be decoded (e.g. lossless JPEG raw images)
+// are skipped. Previews are sorted by increasing size.
+func (p *Parser) Previews() ([]Preview, error) {
+	candidates, err := p.previewCandidates()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Preview
+	seen := make(map[int64]bool)
+	for _, c := range candidates {
+		pv := c.Preview
+		if seen[pv.Offset] || pv.Length == 0 {
+			continue
+		}
+		seen[pv.Offset] = true
+
+		if _, err := p.reader.Seek(pv.Offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		config, err := jpeg.DecodeConfig(io.LimitReader(p.reader, pv.Length))
+		if err != nil {
+			continue
+		}
+		pv.Width, pv.Height = config.Width, config.Height
+		res = append(res, pv)
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Width*res[i].Height < res[j].Width*res[j].Height
+	})
+
+	return res, nil
+}
+
+// BestPreview returns the smallest preview (see Previews) that is at least minWidth pixels wide, or an error matching
+// ErrNoPreview if there is none.
+func (p *Parser) BestPreview(minWidth int) (Preview, error) {
+	previews, err := p.Previews()
+	if err != nil {
+		return Preview{}, err
+	}
+	for _, pv := range previews {
+		if pv.Width >= minWidth {
+			return pv, nil
+		}
+	}
+
+	return Preview{}, fmt.Errorf("%w at least %d pixels wide (out of %d previews)", ErrNoPreview, minWidth, len(previews))
+}
+
+// ReadPreview reads the JPEG data of a preview.
+func (p *Parser) ReadPreview(pv Preview) ([]byte, error) {
+	if err := p.checkValueSize(pv.Length); err != nil {
+		return nil, err
+	}
+	if _, err := p.reader.Seek(pv.Offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	buffer := make([]byte, pv.Length)
+	if _, err := io.ReadFull(p.reader, buffer); err != nil {
+		return nil, err
+	}
+
+	return buffer, nil
+}
+
+// previewCandidate is an image that may be a JPEG preview.
+type previewCandidate struct {
+	Preview
+	strip bool // stored in the strip of a JPEG-compressed image, rather than referenced by ThumbnailOffset
+}
+
+// previewCandidates lists the images of all IFDs that may be JPEG previews (see Previews), in the order their IFDs are
+// visited, followed by those the vendor of the camera stores elsewhere (e.g. in its maker note), without reading them.
+func (p *Parser) previewCandidates() ([]previewCandidate, error) {
+	var res []previewCandidate
+	err := p.walk(func(offset int64, group Group, entries map[EntryID]Entry) error {
+		pg := Page{Offset: offset, Entries: entries}
+		if start, ok := pg.Uint(ThumbnailOffset); ok {
+			if length, ok := pg.Uint(ThumbnailLength); ok {
+				res = append(res, previewCandidate{
+					Preview: Preview{Group: group, IFDOffset: offset, Offset: int64(start), Length: int64(length)},
+				})
+			}
+		}
+		if compression, ok := pg.Uint(Compression); ok && (compression == compressionOldJPEG || compression == compressionJPEG) {
+			offsets, _ := pg.Uints(StripOffsets)
+			lengths, _ := pg.Uints(StripByteCounts)
+			if len(offsets) == 1 && len(lengths) == 1 {
+				res = append(res, previewCandidate{
+					Preview: Preview{Group: group, IFDOffset: offset, Offset: int64(offsets[0]), Length: int64(lengths[0])},
+					strip:   true,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if previews := vendorProfiles[p.Vendor()].previews; previews != nil {
+		res = append(res, previews(p)...)
+	}
+
+	return res, nil
+}
+
+// thumbnailPriority ranks the candidates of ReadThumbnail, from 0 (preferred) to 3.
+func (c previewCandidate) thumbnailPriority() int {
+	switch {
+	case c.strip:
+		return 3
+	case c.Group == Group_IFD1:
+		return 0
+	case c.Group == Group_IFD0:
+		return 1
+	default:
+		return 2
+	}
+}
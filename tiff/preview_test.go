@@ -0,0 +1,150 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_Previews_CR2(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+
+	previews, err := p.Previews()
+	assert.NoError(t, err)
+	assert.Equal(t, []Preview{
+		{Group: Group_IFD1, IFDOffset: 48752, Offset: 57256, Length: 14557, Width: 160, Height: 120},
+		{Group: Group_IFD0, IFDOffset: 16, Offset: 71816, Length: 1658901, Width: 5184, Height: 3456},
+	}, previews)
+
+	best, err := p.BestPreview(0)
+	assert.NoError(t, err)
+	assert.Equal(t, 160, best.Width)
+
+	best, err = p.BestPreview(1200)
+	assert.NoError(t, err)
+	assert.Equal(t, 5184, best.Width)
+
+	data, err := p.ReadPreview(best)
+	assert.NoError(t, err)
+	assert.Len(t, data, 1658901)
+	assert.Equal(t, []byte{0xff, 0xd8}, data[:2])
+
+	_, err = p.BestPreview(6000)
+	assert.ErrorIs(t, err, ErrNoPreview)
+}
+
+func TestParser_Previews(t *testing.T) {
+	encode := func(width, height int) []byte {
+		var buf bytes.Buffer
+		assert.NoError(t, jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height)), nil))
+		return buf.Bytes()
+	}
+
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	ifd0 := b.AddIFD().
+		WithUints16(uint16(Compression), compressionJPEG).
+		WithData(uint16(StripOffsets), uint16(StripByteCounts), encode(64, 48))
+	ifd0.WithSubIFD(uint16(SubIFDs)).
+		WithData(uint16(ThumbnailOffset), uint16(ThumbnailLength), encode(16, 12))
+	ifd0.WithSubIFD(uint16(Exif)).
+		WithData(uint16(ThumbnailOffset), uint16(ThumbnailLength), []byte("not a JPEG"))
+
+	p, err := NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	previews, err := p.Previews()
+	assert.NoError(t, err)
+	if assert.Len(t, previews, 2) {
+		assert.Equal(t, Group_SubIFD, previews[0].Group)
+		assert.Equal(t, [2]int{16, 12}, [2]int{previews[0].Width, previews[0].Height})
+		assert.Equal(t, Group_IFD0, previews[1].Group)
+		assert.Equal(t, [2]int{64, 48}, [2]int{previews[1].Width, previews[1].Height})
+	}
+
+	best, err := p.BestPreview(20)
+	assert.NoError(t, err)
+	assert.Equal(t, 64, best.Width)
+}
+
+func TestParser_ReadThumbnail(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 8, 8)), nil))
+	jpegStrip := buf.Bytes()
+
+	tests := []struct {
+		name  string
+		build func(b *test.TIFFBuilder)
+		want  []byte
+	}{
+		{
+			name: "IFD#1",
+			build: func(b *test.TIFFBuilder) {
+				b.AddIFD().WithData(uint16(ThumbnailOffset), uint16(ThumbnailLength), []byte("preview"))
+				b.AddIFD().WithData(uint16(ThumbnailOffset), uint16(ThumbnailLength), []byte("thumbnail"))
+			},
+			want: []byte("thumbnail"),
+		},
+		{
+			name: "PreviewImageStart in IFD#0",
+			build: func(b *test.TIFFBuilder) {
+				b.AddIFD().WithData(uint16(ThumbnailOffset), uint16(ThumbnailLength), []byte("preview"))
+			},
+			want: []byte("preview"),
+		},
+		{
+			name: "JPEGInterchangeFormat in a SubIFD",
+			build: func(b *test.TIFFBuilder) {
+				ifd0 := b.AddIFD().
+					WithUints16(uint16(Compression), compressionJPEG).
+					WithData(uint16(StripOffsets), uint16(StripByteCounts), jpegStrip)
+				ifd0.WithSubIFD(uint16(SubIFDs)).
+					WithData(uint16(ThumbnailOffset), uint16(ThumbnailLength), []byte("jpeg"))
+			},
+			want: []byte("jpeg"),
+		},
+		{
+			name: "JPEG strip",
+			build: func(b *test.TIFFBuilder) {
+				b.AddIFD().
+					WithUints16(uint16(Compression), compressionOldJPEG).
+					WithData(uint16(StripOffsets), uint16(StripByteCounts), []byte("raw data"))
+				b.AddIFD().
+					WithUints16(uint16(Compression), compressionJPEG).
+					WithData(uint16(StripOffsets), uint16(StripByteCounts), jpegStrip)
+			},
+			want: jpegStrip,
+		},
+		{
+			name: "none",
+			build: func(b *test.TIFFBuilder) {
+				b.AddIFD().
+					WithUints16(uint16(Compression), 1).
+					WithData(uint16(StripOffsets), uint16(StripByteCounts), []byte("pixels"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := test.NewTIFFBuilder(binary.LittleEndian)
+			tt.build(b)
+
+			p, err := NewParser(bytes.NewReader(b.Bytes()))
+			assert.NoError(t, err)
+
+			thumbnail, err := p.ReadThumbnail()
+			if tt.want == nil {
+				assert.ErrorIs(t, err, ErrNoPreview)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, thumbnail)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package tiff
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+const (
+	// subfileType_ReducedResolution is the NewSubfileType bit set for reduced-resolution versions of another image
+	subfileType_ReducedResolution = 0x1
+	// subfileType_Mask is the NewSubfileType bit set for transparency masks
+	subfileType_Mask = 0x4
+)
+
+// Level represents a resolution level of a tiled, multi-resolution (aka pyramid) TIFF.
+type Level struct {
+	Page        Page
+	Width       uint32
+	Height      uint32
+	TileWidth   uint32
+	TileLength  uint32
+	TilesAcross uint32
+	TilesDown   uint32
+}
+
+// Levels returns the resolution levels of a pyramid TIFF, from the largest to the smallest. Reduced-resolution levels
+// are looked up in the SubIFDs of IFD#0 when present (e.g. OME-TIFF, DNG), otherwise in the main IFD chain (one page per
+// level, e.g. COG, SVS). Pages that are not tiled or that are transparency masks are ignored.
+func (p *Parser) Levels() ([]Level, error) {
+	pages, err := p.Pages()
+	if err != nil {
+		return nil, err
+	}
+	if len(pages) == 0 {
+		return nil, errors.New("no IFD found")
+	}
+
+	candidates := pages
+	if _, ok := pages[0].Entries[SubIFDs]; ok {
+		subIFDs, err := p.SubIFDs(pages[0])
+		if err != nil {
+			return nil, err
+		}
+		candidates = append([]Page{pages[0]}, subIFDs...)
+	}
+
+	var levels []Level
+	for _, page := range candidates {
+		if subfileType, ok := page.Uint(NewSubfileType); ok && subfileType&subfileType_Mask != 0 {
+			continue
+		}
+		level, err := newLevel(page)
+		if err != nil {
+			continue
+		}
+		levels = append(levels, level)
+	}
+
+	if len(levels) == 0 {
+		return nil, errors.New("no tiled image found")
+	}
+
+	sort.SliceStable(levels, func(i, j int) bool { return levels[i].Width > levels[j].Width })
+
+	return levels, nil
+}
+
+// SubIFDs reads the IFDs pointed to by the SubIFDs entry of a page, returning them as pages whose Index is -1 (as they
+// are not part of the main IFD chain). It returns none if the parser does not read Group_SubIFD (see WithGroups).
+func (p *Parser) SubIFDs(page Page) ([]Page, error) {
+	entry, ok := page.Entries[SubIFDs]
+	if !ok || !p.follows(Group_SubIFD) {
+		return nil, nil
+	}
+	offsets, err := entry.Uints()
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make([]Page, 0, len(offsets))
+	for _, offset := range offsets {
+		entries, _, err := p.readIFD(int64(offset), Group_SubIFD)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, Page{Index: -1, Offset: int64(offset), Entries: entries})
+	}
+
+	return pages, nil
+}
+
+// ReadTile reads the (still encoded) tile at the given column and row of a level.
+func (p *Parser) ReadTile(level Level, col, row uint32) ([]byte, error) {
+	if col >= level.TilesAcross || row >= level.TilesDown {
+		return nil, fmt.Errorf("tile (%d, %d) out of bounds (%d x %d tiles)", col, row, level.TilesAcross, level.TilesDown)
+	}
+
+	offsets, lengths, err := level.Page.chunks(TileOffsets, TileByteCounts)
+	if err != nil {
+		return nil, err
+	}
+	index := row*level.TilesAcross + col
+	if int(index) >= len(offsets) {
+		return nil, fmt.Errorf("tile %d not found", index)
+	}
+
+	return p.readChunk(offsets[index], lengths[index])
+}
+
+// newLevel returns the Level described by a tiled page, or an error if the page is not tiled.
+func newLevel(page Page) (Level, error) {
+	level := Level{Page: page}
+	var ok bool
+	if level.Width, ok = page.Width(); !ok {
+		return Level{}, errors.New("image width not found")
+	}
+	if level.Height, ok = page.Height(); !ok {
+		return Level{}, errors.New("image height not found")
+	}
+	if level.TileWidth, ok = page.Uint(TileWidth); !ok || level.TileWidth == 0 {
+		return Level{}, errors.New("tile width not found")
+	}
+	if level.TileLength, ok = page.Uint(TileLength); !ok || level.TileLength == 0 {
+		return Level{}, errors.New("tile length not found")
+	}
+	level.TilesAcross = (level.Width + level.TileWidth - 1) / level.TileWidth
+	level.TilesDown = (level.Height + level.TileLength - 1) / level.TileLength
+
+	return level, nil
+}
@@ -0,0 +1,76 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevels_PagePerLevel(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(svsImage()))
+	assert.NoError(t, err)
+
+	levels, err := p.Levels()
+	assert.NoError(t, err)
+	assert.Len(t, levels, 2)
+
+	assert.EqualValues(t, 2048, levels[0].Width)
+	assert.EqualValues(t, 8, levels[0].TilesAcross)
+	assert.EqualValues(t, 4, levels[0].TilesDown)
+	assert.EqualValues(t, 512, levels[1].Width)
+	assert.EqualValues(t, 1, levels[1].TilesDown)
+
+	tile, err := p.ReadTile(levels[0], 1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{2}, tile)
+
+	tile, err = p.ReadTile(levels[1], 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{4}, tile)
+
+	_, err = p.ReadTile(levels[1], 2, 0)
+	assert.Error(t, err)
+}
+
+func TestLevels_SubIFDs(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.BigEndian)
+	ifd0 := b.AddIFD().
+		WithUints16(uint16(ImageWidth), 100).
+		WithUints16(uint16(ImageHeight), 100).
+		WithUints16(uint16(TileWidth), 64).
+		WithUints16(uint16(TileLength), 64).
+		WithData(uint16(TileOffsets), uint16(TileByteCounts), []byte("a"), []byte("b"), []byte("c"), []byte("d"))
+	ifd0.WithSubIFD(uint16(SubIFDs)).
+		WithUints32(uint16(NewSubfileType), 1).
+		WithUints16(uint16(ImageWidth), 50).
+		WithUints16(uint16(ImageHeight), 50).
+		WithUints16(uint16(TileWidth), 64).
+		WithUints16(uint16(TileLength), 64).
+		WithData(uint16(TileOffsets), uint16(TileByteCounts), []byte("e"))
+	// a thumbnail, which is not part of the pyramid
+	b.AddIFD().
+		WithUints16(uint16(ImageWidth), 10).
+		WithUints16(uint16(ImageHeight), 10).
+		WithData(uint16(StripOffsets), uint16(StripByteCounts), []byte("f"))
+
+	p, err := NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	levels, err := p.Levels()
+	assert.NoError(t, err)
+	assert.Len(t, levels, 2)
+	assert.EqualValues(t, 2, levels[0].TilesAcross)
+	assert.EqualValues(t, -1, levels[1].Page.Index)
+	assert.Equal(t, Group_SubIFD, levels[1].Page.Entries[ImageWidth].Group)
+
+	tile, err := p.ReadTile(levels[0], 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("d"), tile)
+
+	tile, err = p.ReadTile(levels[1], 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("e"), tile)
+}
@@ -0,0 +1,109 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Read budget of QuickThumbnail
+const (
+	maxQuickEntries        = 64      // entries of IFD#1 read at most
+	maxQuickThumbnailSize  = 1 << 20 // largest thumbnail read
+	maxQuickThumbnailReads = 6       // header, count and next pointer of IFD#0, count and table of IFD#1, thumbnail
+	maxQuickMetadataBytes  = 8 + 2 + 4 + 2 + maxQuickEntries*EntryLength
+)
+
+// QuickThumbnail reads the thumbnail that IFD#1 references (ThumbnailOffset and ThumbnailLength), touching as few bytes
+// of the file as possible: the header, the count and the pointer to the next IFD of IFD#0 (skipping its entries), the
+// count and the first 64 entries of IFD#1, then the thumbnail itself. It reads the file at most 6 times, for at most
+// 784 bytes of metadata and 1 MiB of thumbnail, whatever the file, so that thumbnailing services get predictable
+// latencies, especially when reading remote files. Larger thumbnails fail with an error matching ErrLimitExceeded.
+//
+// Files without a thumbnail in IFD#1 (e.g. ORF files, which store it in their maker note) fail with an error matching
+// ErrNoPreview: Parser.ReadThumbnail looks for it everywhere else.
+func QuickThumbnail(r io.ReadSeeker) ([]byte, error) {
+	read := func(offset int64, buffer []byte, group Group) error {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(r, buffer); err != nil {
+			return truncated(err, group, offset)
+		}
+
+		return nil
+	}
+
+	var buffer [8]byte
+	if err := read(0, buffer[:], Group_IFD0); err != nil {
+		return nil, err
+	}
+	byteOrder, err := readEndianness(buffer[0:2])
+	if err != nil {
+		return nil, err
+	}
+	if err := validateMagicNumber(byteOrder, buffer[2:4]); err != nil {
+		return nil, err
+	}
+
+	ifd0 := int64(byteOrder.Uint32(buffer[4:8]))
+	if err := read(ifd0, buffer[:2], Group_IFD0); err != nil {
+		return nil, err
+	}
+	if err := read(ifd0+2+int64(byteOrder.Uint16(buffer[:2]))*EntryLength, buffer[:4], Group_IFD0); err != nil {
+		return nil, err
+	}
+	ifd1 := int64(byteOrder.Uint32(buffer[:4]))
+	if ifd1 == 0 {
+		return nil, fmt.Errorf("%w: no IFD#1", ErrNoPreview)
+	}
+
+	if err := read(ifd1, buffer[:2], Group_IFD1); err != nil {
+		return nil, err
+	}
+	table := make([]byte, min(int(byteOrder.Uint16(buffer[:2])), maxQuickEntries)*EntryLength)
+	if err := read(ifd1+2, table, Group_IFD1); err != nil {
+		return nil, err
+	}
+	var offset, length int64
+	for entry := table; len(entry) >= EntryLength; entry = entry[EntryLength:] {
+		switch EntryID(byteOrder.Uint16(entry)) {
+		case ThumbnailOffset:
+			offset = quickUint(byteOrder, entry)
+		case ThumbnailLength:
+			length = quickUint(byteOrder, entry)
+		}
+	}
+	if offset == 0 || length == 0 {
+		return nil, fmt.Errorf("%w: no thumbnail in IFD#1", ErrNoPreview)
+	}
+	if length > maxQuickThumbnailSize {
+		return nil, fmt.Errorf("%w: thumbnail of %d bytes, at most %d allowed", ErrLimitExceeded, length, maxQuickThumbnailSize)
+	}
+
+	thumbnail := make([]byte, length)
+	if err := read(offset, thumbnail, Group_IFD1); err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(thumbnail, jpegSOI) {
+		return nil, fmt.Errorf("%w: thumbnail of IFD#1 is not a JPEG image", ErrNoPreview)
+	}
+
+	return thumbnail, nil
+}
+
+// quickUint returns the value of a raw entry holding a single SHORT or LONG, or 0 if it holds anything else.
+func quickUint(byteOrder binary.ByteOrder, entry []byte) int64 {
+	if byteOrder.Uint32(entry[4:8]) != 1 {
+		return 0
+	}
+	switch DataType(byteOrder.Uint16(entry[2:4])) {
+	case DataType_UShort:
+		return int64(byteOrder.Uint16(entry[8:10]))
+	case DataType_ULong:
+		return int64(byteOrder.Uint32(entry[8:12]))
+	default:
+		return 0
+	}
+}
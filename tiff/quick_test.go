@@ -0,0 +1,76 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingReader counts the reads of a file and the bytes read.
+type countingReader struct {
+	io.ReadSeeker
+	reads int
+	bytes int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadSeeker.Read(p)
+	c.reads++
+	c.bytes += n
+
+	return n, err
+}
+
+func TestQuickThumbnail(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	want, err := p.ReadThumbnail()
+	assert.NoError(t, err)
+
+	r := &countingReader{ReadSeeker: bytes.NewReader(cr2Image)}
+	thumbnail, err := QuickThumbnail(r)
+	assert.NoError(t, err)
+	assert.Equal(t, want, thumbnail)
+	assert.LessOrEqual(t, r.reads, maxQuickThumbnailReads)
+	assert.LessOrEqual(t, r.bytes, maxQuickMetadataBytes+len(thumbnail))
+}
+
+func TestQuickThumbnail_Errors(t *testing.T) {
+	noIFD1 := test.NewTIFFBuilder(binary.LittleEndian)
+	noIFD1.AddIFD().WithUints16(uint16(ImageWidth), 100)
+
+	noThumbnail := test.NewTIFFBuilder(binary.LittleEndian)
+	noThumbnail.AddIFD().WithUints16(uint16(ImageWidth), 100)
+	noThumbnail.AddIFD().WithUints16(uint16(ImageWidth), 10)
+
+	notJPEG := test.NewTIFFBuilder(binary.LittleEndian)
+	notJPEG.AddIFD().WithUints16(uint16(ImageWidth), 100)
+	notJPEG.AddIFD().WithData(uint16(ThumbnailOffset), uint16(ThumbnailLength), []byte("not a JPEG"))
+
+	tooLarge := test.NewTIFFBuilder(binary.LittleEndian)
+	tooLarge.AddIFD().WithUints16(uint16(ImageWidth), 100)
+	tooLarge.AddIFD().WithUints32(uint16(ThumbnailOffset), 8).WithUints32(uint16(ThumbnailLength), maxQuickThumbnailSize+1)
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"no IFD#1", noIFD1.Bytes(), ErrNoPreview},
+		{"no thumbnail", noThumbnail.Bytes(), ErrNoPreview},
+		{"not a JPEG", notJPEG.Bytes(), ErrNoPreview},
+		{"too large", tooLarge.Bytes(), ErrLimitExceeded},
+		{"ORF", orfImage, ErrNoPreview},
+		{"truncated", cr2Image[:48752], ErrTruncated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := QuickThumbnail(bytes.NewReader(tt.data))
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
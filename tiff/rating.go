@@ -0,0 +1,52 @@
+package tiff
+
+import "fmt"
+
+// MaxRating is the highest rating, in stars.
+const MaxRating = 5
+
+// ratingPercents maps ratings, in stars, to the percents written by Windows.
+var ratingPercents = [MaxRating + 1]int{0, 1, 25, 50, 75, 99}
+
+// RatingToPercent converts a rating, from 0 (unrated) to 5 stars, to a percent, as written by Windows in RatingPercent.
+func RatingToPercent(stars int) int {
+	return ratingPercents[max(0, min(stars, MaxRating))]
+}
+
+// PercentToRating converts a percent, as found in RatingPercent, to a rating from 0 (unrated) to 5 stars, rounding it
+// the way Windows does (e.g. 1 to 12% is 1 star, 13 to 37% is 2 stars).
+func PercentToRating(percent int) int {
+	switch {
+	case percent <= 0:
+		return 0
+	case percent < 13:
+		return 1
+	default:
+		return min((percent+12)/25+1, MaxRating)
+	}
+}
+
+// ReadRating reads the rating of the image, from 0 (unrated) to 5 stars, from Rating or, if it is missing, from
+// RatingPercent.
+func ReadRating(entries map[EntryID]Entry) (int, error) {
+	if _, ok := entries[Rating]; ok {
+		stars, err := readUint(entries, Rating)
+		if err != nil {
+			return 0, err
+		}
+		if stars > MaxRating {
+			return 0, fmt.Errorf("invalid Rating %d", stars)
+		}
+		return int(stars), nil
+	}
+
+	percent, err := readUint(entries, RatingPercent)
+	if err != nil {
+		return 0, err
+	}
+	if percent > 100 {
+		return 0, fmt.Errorf("invalid RatingPercent %d", percent)
+	}
+
+	return PercentToRating(int(percent)), nil
+}
@@ -0,0 +1,48 @@
+package tiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRatingPercent(t *testing.T) {
+	for stars := 0; stars <= MaxRating; stars++ {
+		assert.Equal(t, stars, PercentToRating(RatingToPercent(stars)))
+	}
+	assert.Equal(t, 1, PercentToRating(12))
+	assert.Equal(t, 2, PercentToRating(13))
+	assert.Equal(t, 4, PercentToRating(87))
+	assert.Equal(t, 5, PercentToRating(88))
+	assert.Equal(t, 5, PercentToRating(100))
+}
+
+func TestReadRating(t *testing.T) {
+	value := func(v uint16) Entry { return Entry{Value: EntryValue{Uint16: &v}} }
+
+	tests := []struct {
+		name    string
+		entries map[EntryID]Entry
+		want    int
+		wantErr bool
+	}{
+		{"rating", map[EntryID]Entry{Rating: value(4), RatingPercent: value(1)}, 4, false},
+		{"percent", map[EntryID]Entry{RatingPercent: value(50)}, 3, false},
+		{"unrated", map[EntryID]Entry{Rating: value(0)}, 0, false},
+		{"invalid rating", map[EntryID]Entry{Rating: value(6)}, 0, true},
+		{"invalid percent", map[EntryID]Entry{RatingPercent: value(101)}, 0, true},
+		{"missing", map[EntryID]Entry{}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadRating(tt.entries)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
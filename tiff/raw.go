@@ -0,0 +1,101 @@
+package tiff
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// RawEntry is an IFD entry as it is written in the file: its value is not decoded, nor read when it does not fit in the
+// entry itself.
+type RawEntry struct {
+	Offset   int64 // offset of the entry itself, within its IFD table
+	ID       EntryID
+	DataType DataType
+	Length   uint32
+	RawValue uint32 // value of the entry or offset to read the value from, depending on DataType and Length (see Inline)
+}
+
+// Size returns the size of the value of the entry, in bytes, or 0 if its data type is unknown.
+func (e RawEntry) Size() int64 {
+	return int64(e.DataType.Size()) * int64(e.Length)
+}
+
+// Inline tells whether the value of the entry is stored in RawValue (true) or at the offset held by RawValue (false).
+func (e RawEntry) Inline() bool {
+	return e.Size() <= 4
+}
+
+// IFDOffsets follows the main IFD chain from the header, returning the offset of each IFD without reading it beyond
+// its number of entries and the offset of the next IFD. It returns an error if the chain loops.
+func (p *Parser) IFDOffsets() ([]int64, error) {
+	var offsets []int64
+	seen := make(map[int64]bool)
+
+	for offset := p.firstIFDOffset; offset != 0; {
+		if seen[offset] {
+			return nil, fmt.Errorf("IFD chain loops back to offset %d", offset)
+		}
+		seen[offset] = true
+		offsets = append(offsets, offset)
+
+		_, next, err := p.ReadRawIFD(offset)
+		if err != nil {
+			return nil, err
+		}
+		offset = next
+	}
+
+	return offsets, nil
+}
+
+// ReadRawIFD reads the table of the IFD starting at the given offset (which does not need to be part of the main
+// chain), returning its entries in the order they are written, together with the offset of the next IFD (0 if there is
+// none).
+func (p *Parser) ReadRawIFD(offset int64) ([]RawEntry, int64, error) {
+	table, next, err := p.readTable(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries := make([]RawEntry, len(table)/EntryLength)
+	for i := range entries {
+		buffer := table[i*EntryLength : (i+1)*EntryLength]
+		entries[i] = RawEntry{
+			Offset:   offset + 2 + int64(i)*EntryLength,
+			ID:       EntryID(p.byteOrder.Uint16(buffer[:2])),
+			DataType: DataType(p.byteOrder.Uint16(buffer[2:4])),
+			Length:   p.byteOrder.Uint32(buffer[4:8]),
+			RawValue: p.byteOrder.Uint32(buffer[8:12]),
+		}
+	}
+
+	return entries, next, nil
+}
+
+// ReadRawValue reads the value of an entry as it is written in the file, whether it is stored in the entry itself or at
+// an offset, without decoding nor trimming it, e.g. to compare or copy values byte for byte.
+func (p *Parser) ReadRawValue(e Entry) ([]byte, error) {
+	return p.readArray(e.DataType, e.Length, e.RawValue)
+}
+
+// ValueReader returns a reader of the value of an entry as it is written in the file (see ReadRawValue), e.g. to stream
+// a value skipped with WithSkipValuesLargerThan rather than hold it in memory. It reads from the file of the parser: it
+// must be consumed before the parser is used again.
+func (p *Parser) ValueReader(e Entry) (io.Reader, error) {
+	if e.DataType.Size() == 0 {
+		return nil, fmt.Errorf("unknown data type %d", e.DataType)
+	}
+	size, err := byteSize(uint64(e.Length), e.DataType.Size())
+	if err != nil {
+		return nil, err
+	}
+	if size <= 4 {
+		return bytes.NewReader(p.inline(e.RawValue)[:size]), nil
+	}
+	if _, err := p.reader.Seek(int64(e.RawValue), io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return io.LimitReader(p.reader, int64(size)), nil
+}
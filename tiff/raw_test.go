@@ -0,0 +1,77 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIFDOffsets(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+
+	offsets, err := p.IFDOffsets()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{16, 48752, 48782, 48966}, offsets)
+}
+
+func TestIFDOffsets_Loop(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().WithUints32(uint16(ImageWidth), 256)
+	data := b.Bytes()
+	binary.LittleEndian.PutUint32(data[8+2+EntryLength:], 8) // next IFD is itself
+
+	p, err := NewParser(bytes.NewReader(data))
+	assert.NoError(t, err)
+
+	_, err = p.IFDOffsets()
+	assert.ErrorContains(t, err, "loops back")
+}
+
+func TestReadRawIFD(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+
+	entries, next, err := p.ReadRawIFD(16)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(48752), next)
+
+	assert.Equal(t, RawEntry{Offset: 18, ID: ImageWidth, DataType: DataType_UShort, Length: 1, RawValue: 5184}, entries[0])
+	assert.True(t, entries[0].Inline())
+
+	var makeEntry RawEntry
+	for _, entry := range entries {
+		if entry.ID == Make {
+			makeEntry = entry
+		}
+	}
+	assert.Equal(t, DataType_String, makeEntry.DataType)
+	assert.Equal(t, int64(6), makeEntry.Size())
+	assert.False(t, makeEntry.Inline())
+	assert.Equal(t, "Canon\x00", string(cr2Image[makeEntry.RawValue:makeEntry.RawValue+6]))
+}
+
+func TestParser_ReadRawValue(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	entries, err := p.Parse(ImageWidth, Make, BitsPerSample)
+	assert.NoError(t, err)
+
+	raw, err := p.ReadRawValue(entries[Make])
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("Canon\x00"), raw)
+
+	raw, err = p.ReadRawValue(entries[ImageWidth])
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x40, 0x14}, raw)
+
+	raw, err = p.ReadRawValue(entries[BitsPerSample])
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{8, 0, 8, 0, 8, 0}, raw)
+
+	_, err = p.ReadRawValue(Entry{ID: Make, DataType: 0, Length: 1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package tiff
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"maps"
+)
+
+// Redaction tells what a Redactor does with an entry.
+type Redaction int
+
+const (
+	Redaction_Keep   Redaction = iota // the entry is kept as it is, e.g. to keep one entry of a redacted group
+	Redaction_Remove                  // the entry is left out
+	Redaction_Hash                    // the value is replaced with a keyed hash of it, so that equal values still match
+)
+
+// Redactor redacts the sensitive entries of files (e.g. serial numbers, the name of their owner or their position)
+// while keeping the rest, to export shareable metadata reports from sensitive collections: its output can be passed
+// to any exporter, such as Parser.ExifTool (JSON), WriteCSV or tiffpb.ToProto.
+//
+// Hashes are the hex-encoded HMAC-SHA256 of the value, keyed with Key: the same value always gives the same hash (e.g.
+// to group images by camera), but it cannot be recovered from it. The key must be kept secret, as values such as
+// serial numbers are short enough to be recovered by brute force otherwise. Hashed entries are strings.
+type Redactor struct {
+	Entries map[EntryID]Redaction // redaction of entries, taking precedence over that of their group
+	Groups  map[Group]Redaction   // redaction of all the entries of a group, e.g. Group_GPSInfo
+	Key     []byte                // key of the hashes
+}
+
+// DefaultRedactor returns a Redactor removing GPS data, the names of the owner and of the creator, maker notes (which
+// hold serial numbers, among others) and embedded XMP and IPTC data (which may hold any of the above), and hashing
+// serial numbers and ImageUniqueID with key.
+func DefaultRedactor(key []byte) Redactor {
+	return Redactor{
+		Entries: map[EntryID]Redaction{
+			GPSInfo:            Redaction_Remove,
+			CameraOwnerName:    Redaction_Remove,
+			Artist:             Redaction_Remove,
+			MakerNotes:         Redaction_Remove,
+			DNGPrivateData:     Redaction_Remove,
+			XMLPacket:          Redaction_Remove,
+			IPTC:               Redaction_Remove,
+			BodySerialNumber:   Redaction_Hash,
+			CameraSerialNumber: Redaction_Hash,
+			LensSerialNumber:   Redaction_Hash,
+			ImageUniqueID:      Redaction_Hash,
+		},
+		Groups: map[Group]Redaction{Group_GPSInfo: Redaction_Remove},
+		Key:    key,
+	}
+}
+
+// redaction returns the redaction of an entry: that of its ID if any, else that of its group.
+func (r Redactor) redaction(e Entry) Redaction {
+	if redaction, ok := r.Entries[e.ID]; ok {
+		return redaction
+	}
+
+	return r.Groups[e.Group]
+}
+
+// RedactEntry returns the entry once redacted, or false if it is removed.
+func (r Redactor) RedactEntry(e Entry) (Entry, bool) {
+	switch r.redaction(e) {
+	case Redaction_Remove:
+		return Entry{}, false
+	case Redaction_Hash:
+		var b bytes.Buffer
+		appendCanonicalValue(&b, e.Value)
+		mac := hmac.New(sha256.New, r.Key)
+		mac.Write(b.Bytes())
+		hash := hex.EncodeToString(mac.Sum(nil))
+		return Entry{
+			ID:        e.ID,
+			DataType:  DataType_String,
+			Length:    uint32(len(hash) + 1),
+			Value:     EntryValue{String: &hash},
+			Group:     e.Group,
+			IFDOffset: e.IFDOffset,
+		}, true
+	default:
+		return e, true
+	}
+}
+
+// Redact returns a copy of entries (e.g. as returned by Parser.Parse) once redacted (see RedactEntry).
+func (r Redactor) Redact(entries map[EntryID]Entry) map[EntryID]Entry {
+	res := maps.Clone(entries)
+	for id, e := range entries {
+		if redacted, ok := r.RedactEntry(e); ok {
+			res[id] = redacted
+		} else {
+			delete(res, id)
+		}
+	}
+
+	return res
+}
+
+// RedactEntries returns entries (e.g. as returned by Parser.Find) once redacted (see RedactEntry), in the same order.
+func (r Redactor) RedactEntries(entries []Entry) []Entry {
+	res := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		if redacted, ok := r.RedactEntry(e); ok {
+			res = append(res, redacted)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,70 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactor(t *testing.T) {
+	newParser := func(t *testing.T, serial string) *Parser {
+		b := test.NewTIFFBuilder(binary.LittleEndian)
+		ifd := b.AddIFD().WithString(uint16(Make), "Canon").WithString(uint16(Artist), "Jane Doe")
+		ifd.WithSubIFD(uint16(Exif)).
+			WithString(uint16(CameraOwnerName), "Jane Doe").
+			WithString(uint16(BodySerialNumber), serial).
+			WithURationals(uint16(ExposureTime), 1, 200)
+		ifd.WithSubIFD(uint16(GPSInfo)).
+			WithString(uint16(GPSLatitudeRef), "N").
+			WithURationals(uint16(GPSLatitude), 52, 1, 22, 1, 1234, 100)
+		p, err := NewParser(bytes.NewReader(b.Bytes()))
+		assert.NoError(t, err)
+		return p
+	}
+	ids := []EntryID{Make, Artist, CameraOwnerName, BodySerialNumber, ExposureTime, GPSLatitudeRef, GPSLatitude}
+
+	p := newParser(t, "012345678")
+	entries, err := p.Parse(ids...)
+	assert.NoError(t, err)
+	assert.Len(t, entries, len(ids))
+
+	r := DefaultRedactor([]byte("secret"))
+	redacted := r.Redact(entries)
+	assert.Len(t, entries, len(ids), "entries must not be modified")
+	assert.ElementsMatch(t, []EntryID{Make, BodySerialNumber, ExposureTime}, slices.Collect(maps.Keys(redacted)))
+	assert.Equal(t, entries[Make], redacted[Make])
+	serial := redacted[BodySerialNumber]
+	assert.Equal(t, DataType_String, serial.DataType)
+	assert.Len(t, serial.Text(), 64)
+	assert.NotContains(t, serial.Text(), "012345678")
+	assert.Equal(t, "1/200", p.ExifTool(redacted)["EXIF:ExposureTime"])
+
+	t.Run("equal values give equal hashes", func(t *testing.T) {
+		same, err := newParser(t, "012345678").Parse(BodySerialNumber)
+		assert.NoError(t, err)
+		other, err := newParser(t, "876543210").Parse(BodySerialNumber)
+		assert.NoError(t, err)
+
+		assert.Equal(t, serial, r.Redact(same)[BodySerialNumber])
+		assert.NotEqual(t, serial, r.Redact(other)[BodySerialNumber])
+		assert.NotEqual(t, serial, DefaultRedactor([]byte("other")).Redact(same)[BodySerialNumber])
+	})
+
+	t.Run("entries override groups", func(t *testing.T) {
+		r := Redactor{
+			Entries: map[EntryID]Redaction{GPSLatitudeRef: Redaction_Keep, Make: Redaction_Hash},
+			Groups:  map[Group]Redaction{Group_GPSInfo: Redaction_Remove},
+		}
+		redacted := r.RedactEntries([]Entry{entries[Make], entries[GPSLatitudeRef], entries[GPSLatitude], entries[Artist]})
+		assert.Len(t, redacted, 3)
+		assert.Equal(t, Make, redacted[0].ID)
+		assert.NotEqual(t, "Canon", redacted[0].Text())
+		assert.Equal(t, entries[GPSLatitudeRef], redacted[1])
+		assert.Equal(t, entries[Artist], redacted[2])
+	})
+}
@@ -0,0 +1,91 @@
+package tiff
+
+import (
+	"fmt"
+	"image"
+)
+
+// Tile is a tile of a level, as returned by TilesIn and ReadRegion.
+type Tile struct {
+	Col, Row uint32
+	Plane    uint32          // component of the samples it holds if they are stored in separate planes, 0 otherwise
+	Bounds   image.Rectangle // pixels of the level it covers, clipped to the bounds of the level
+	Data     []byte          // still encoded, only set by ReadRegion
+}
+
+// Level returns the resolution level described by a tiled page, or an error if the page is not tiled.
+func (pg Page) Level() (Level, error) {
+	return newLevel(pg)
+}
+
+// TilesIn returns the tiles of a level intersecting a rectangle of its pixels, left to right then top to bottom. When
+// samples are stored in separate planes (PlanarConfiguration 2), the tiles of each plane are returned, plane by plane.
+func (l Level) TilesIn(rect image.Rectangle) []Tile {
+	rect = rect.Intersect(image.Rect(0, 0, int(l.Width), int(l.Height)))
+	if rect.Empty() {
+		return nil
+	}
+
+	planes := uint32(1)
+	if config, ok := l.Page.Uint(PlanarConfiguration); ok && config == 2 {
+		if spp, ok := l.Page.Uint(SamplesPerPixel); ok && spp > 1 {
+			planes = spp
+		}
+	}
+
+	tw, tl := int(l.TileWidth), int(l.TileLength)
+	var tiles []Tile
+	for plane := uint32(0); plane < planes; plane++ {
+		for row := rect.Min.Y / tl; row*tl < rect.Max.Y; row++ {
+			for col := rect.Min.X / tw; col*tw < rect.Max.X; col++ {
+				bounds := image.Rect(col*tw, row*tl, (col+1)*tw, (row+1)*tl)
+				tiles = append(tiles, Tile{
+					Col:    uint32(col),
+					Row:    uint32(row),
+					Plane:  plane,
+					Bounds: bounds.Intersect(image.Rect(0, 0, int(l.Width), int(l.Height))),
+				})
+			}
+		}
+	}
+
+	return tiles
+}
+
+// ReadRegion reads the (still encoded) tiles of a level intersecting a rectangle of its pixels, in the order of
+// TilesIn, without reading the others: together with NewObjectReader, it reads a region of a cloud-optimized GeoTIFF
+// (COG) with a few range requests, as the tiles are prefetched with coalesced requests first when the file is read
+// from a Prefetcher. It returns an error if the rectangle lies outside of the level.
+func (p *Parser) ReadRegion(level Level, rect image.Rectangle) ([]Tile, error) {
+	tiles := level.TilesIn(rect)
+	if len(tiles) == 0 {
+		return nil, fmt.Errorf("region %v lies outside of the %dx%d level", rect, level.Width, level.Height)
+	}
+
+	offsets, lengths, err := level.Page.chunks(TileOffsets, TileByteCounts)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]int, len(tiles))
+	ranges := make([]Range, len(tiles))
+	for i, tile := range tiles {
+		indexes[i] = int((tile.Plane*level.TilesDown+tile.Row)*level.TilesAcross + tile.Col)
+		if indexes[i] >= len(offsets) {
+			return nil, fmt.Errorf("tile %d not found", indexes[i])
+		}
+		ranges[i] = Range{Offset: int64(offsets[indexes[i]]), Length: int64(lengths[indexes[i]])}
+	}
+
+	if prefetcher, base, ok := p.prefetcher(); ok {
+		if err := prefetcher.Prefetch(coalesce(ranges, base)); err != nil {
+			return nil, err
+		}
+	}
+	for i, index := range indexes {
+		if tiles[i].Data, err = p.readChunk(offsets[index], lengths[index]); err != nil {
+			return nil, err
+		}
+	}
+
+	return tiles, nil
+}
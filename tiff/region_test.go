@@ -0,0 +1,94 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// tiledImage builds a synthetic 100x70 image made of 4 tiles of 64x64 pixels, stored in 2 planes if planar is true.
+func tiledImage(planar bool) []byte {
+	tiles := [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d")}
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	ifd := b.AddIFD().
+		WithUints16(uint16(ImageWidth), 100).
+		WithUints16(uint16(ImageHeight), 70).
+		WithUints16(uint16(TileWidth), 64).
+		WithUints16(uint16(TileLength), 64)
+	if planar {
+		ifd.WithUints16(uint16(SamplesPerPixel), 2).WithUints16(uint16(PlanarConfiguration), 2)
+		tiles = append(tiles, []byte("e"), []byte("f"), []byte("g"), []byte("h"))
+	}
+	ifd.WithData(uint16(TileOffsets), uint16(TileByteCounts), tiles...)
+
+	return b.Bytes()
+}
+
+func TestLevel_TilesIn(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(tiledImage(false)))
+	assert.NoError(t, err)
+	page, err := p.FirstPage()
+	assert.NoError(t, err)
+	level, err := page.Level()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []Tile{
+		{Col: 0, Row: 0, Bounds: image.Rect(0, 0, 64, 64)},
+		{Col: 1, Row: 0, Bounds: image.Rect(64, 0, 100, 64)},
+	}, level.TilesIn(image.Rect(60, 10, 70, 20)))
+	assert.Equal(t, []Tile{
+		{Col: 1, Row: 1, Bounds: image.Rect(64, 64, 100, 70)},
+	}, level.TilesIn(image.Rect(90, 65, 200, 200)))
+	assert.Len(t, level.TilesIn(image.Rect(-10, -10, 1000, 1000)), 4)
+	assert.Empty(t, level.TilesIn(image.Rect(100, 0, 110, 10)))
+}
+
+func TestParser_ReadRegion(t *testing.T) {
+	tests := []struct {
+		name   string
+		planar bool
+		rect   image.Rectangle
+		want   []string
+	}{
+		{"one tile", false, image.Rect(0, 64, 10, 70), []string{"c"}},
+		{"two tiles", false, image.Rect(60, 60, 70, 70), []string{"a", "b", "c", "d"}},
+		{"planes", true, image.Rect(70, 0, 80, 10), []string{"b", "f"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &prefetchRecorder{Reader: bytes.NewReader(tiledImage(tt.planar))}
+			p, err := NewParser(r)
+			assert.NoError(t, err)
+			page, err := p.FirstPage()
+			assert.NoError(t, err)
+			level, err := page.Level()
+			assert.NoError(t, err)
+
+			tiles, err := p.ReadRegion(level, tt.rect)
+			assert.NoError(t, err)
+			var got []string
+			for _, tile := range tiles {
+				got = append(got, string(tile.Data))
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Len(t, r.calls, 1) // tiles are prefetched first
+		})
+	}
+}
+
+func TestParser_ReadRegion_OutOfBounds(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(tiledImage(false)))
+	assert.NoError(t, err)
+	page, err := p.FirstPage()
+	assert.NoError(t, err)
+	level, err := page.Level()
+	assert.NoError(t, err)
+
+	_, err = p.ReadRegion(level, image.Rect(100, 70, 200, 200))
+	assert.Error(t, err)
+}
@@ -0,0 +1,204 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// RepairOptions tells how Repair salvages broken files.
+type RepairOptions struct {
+	// ClampCounts shrinks the count of entries whose value ends after the end of the file to the number of values that
+	// fit in it, instead of dropping them (e.g. to keep the beginning of a truncated string).
+	ClampCounts bool
+	// KeepUnknownTypes keeps entries with unknown data types: by default they are dropped, as the size of their value
+	// cannot be known.
+	KeepUnknownTypes bool
+}
+
+// Repair copies a TIFF file from r to w, salvaging all entries that can still be read: the IFD chain is cut before the
+// first IFD that cannot be read (e.g. because of a broken next-IFD pointer), tables whose number of entries exceeds the
+// size of the file are shortened, and entries whose value cannot be read (or pointing to sub-IFDs that cannot be read)
+// are dropped. Everything else is copied as it is: offsets stay valid, and image data is not touched.
+//
+// The returned report lists what was dropped (as errors) or shortened (as warnings): it is empty if the file did not
+// need any repair.
+func Repair(r io.Reader, w io.Writer, opts RepairOptions) (Report, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Report{}, err
+	}
+
+	p, err := NewParser(bytes.NewReader(data))
+	if err != nil {
+		return Report{}, err
+	}
+
+	rp := &repairer{data: data, byteOrder: p.byteOrder, opts: opts, seen: make(map[int64]bool)}
+	if !rp.readable(p.firstIFDOffset) {
+		return Report{}, errors.New("IFD#0 cannot be read")
+	}
+
+	pointer := int64(4) // position of the offset of the next IFD: in the header, then in the previous IFD
+	for offset := p.firstIFDOffset; offset != 0; {
+		if !rp.readable(offset) {
+			rp.errorf(offset, 0, "dropped the IFDs from offset %d: the chain is broken", offset)
+			rp.byteOrder.PutUint32(rp.data[pointer:], 0)
+			break
+		}
+		offset, pointer = rp.repairIFD(offset, "IFD")
+	}
+
+	if _, err := w.Write(rp.data); err != nil {
+		return Report{}, err
+	}
+
+	return rp.report, nil
+}
+
+// repairer repairs a file held in memory.
+type repairer struct {
+	data      []byte
+	byteOrder binary.ByteOrder
+	opts      RepairOptions
+	report    Report
+	seen      map[int64]bool // offsets of the IFDs already repaired
+}
+
+func (rp *repairer) warnf(offset int64, id EntryID, format string, args ...any) {
+	rp.report.Issues = append(rp.report.Issues, Issue{Severity: Severity_Warning, Offset: offset, Entry: id, Message: fmt.Sprintf(format, args...)})
+}
+
+func (rp *repairer) errorf(offset int64, id EntryID, format string, args ...any) {
+	rp.report.Issues = append(rp.report.Issues, Issue{Severity: Severity_Error, Offset: offset, Entry: id, Message: fmt.Sprintf(format, args...)})
+}
+
+// size returns the size of the file.
+func (rp *repairer) size() int64 {
+	return int64(len(rp.data))
+}
+
+// readable tells whether an IFD can start at the given offset, and has not been repaired already.
+func (rp *repairer) readable(offset int64) bool {
+	return offset >= 8 && offset+2+4 <= rp.size() && !rp.seen[offset]
+}
+
+// repairIFD repairs the IFD (named after its kind, e.g. "IFD" or "Exif") starting at the given offset and its sub-IFDs,
+// returning the offset of the next IFD together with the position of that offset in the file.
+func (rp *repairer) repairIFD(offset int64, name string) (int64, int64) {
+	rp.seen[offset] = true
+
+	count := int64(rp.byteOrder.Uint16(rp.data[offset:]))
+	truncated := false
+	if fit := (rp.size() - offset - 2 - 4) / EntryLength; count > fit {
+		rp.warnf(offset, 0, "%s at offset %d is truncated: kept %d of its %d entries", name, offset, fit, count)
+		count, truncated = fit, true
+	}
+
+	table := rp.data[offset+2:]
+	next := int64(rp.byteOrder.Uint32(table[count*EntryLength:]))
+	kept := int64(0)
+	for i := int64(0); i < count; i++ {
+		entry := table[i*EntryLength : (i+1)*EntryLength]
+		if rp.repairEntry(offset+2+i*EntryLength, entry) {
+			copy(table[kept*EntryLength:], entry)
+			kept++
+		}
+	}
+
+	if truncated {
+		next = 0 // what was read as the offset of the next IFD is part of an entry
+	}
+	if kept != int64(rp.byteOrder.Uint16(rp.data[offset:])) {
+		rp.byteOrder.PutUint16(rp.data[offset:], uint16(kept))
+		rp.byteOrder.PutUint32(table[kept*EntryLength:], uint32(next))
+		clear(table[kept*EntryLength+4 : count*EntryLength+4])
+	}
+
+	return next, offset + 2 + kept*EntryLength
+}
+
+// repairEntry repairs the entry found at the given offset (and the sub-IFDs it points to), telling whether it must be
+// kept.
+func (rp *repairer) repairEntry(offset int64, entry []byte) bool {
+	id := EntryID(rp.byteOrder.Uint16(entry))
+	dt := DataType(rp.byteOrder.Uint16(entry[2:]))
+	length := int64(rp.byteOrder.Uint32(entry[4:]))
+	rawValue := int64(rp.byteOrder.Uint32(entry[8:]))
+
+	if dt.Size() == 0 {
+		if rp.opts.KeepUnknownTypes {
+			return true
+		}
+		rp.errorf(offset, id, "dropped entry 0x%04x: unknown data type %d", uint16(id), dt)
+		return false
+	}
+
+	if size := int64(dt.Size()) * length; size > 4 && rawValue+size > rp.size() {
+		fit := max(rp.size()-rawValue, 0) / int64(dt.Size())
+		if !rp.opts.ClampCounts || fit == 0 {
+			rp.errorf(offset, id, "dropped entry 0x%04x: its value ends after the end of the file", uint16(id))
+			return false
+		}
+		rp.warnf(offset, id, "shortened entry 0x%04x: kept %d of its %d values", uint16(id), fit, length)
+		length = fit
+		rp.byteOrder.PutUint32(entry[4:], uint32(length))
+	}
+
+	for _, pointer := range subIFDPointers {
+		if id == pointer.id {
+			return rp.repairSubIFDs(offset, entry, pointer.name)
+		}
+	}
+
+	return true
+}
+
+// repairSubIFDs repairs the sub-IFDs pointed to by an entry, dropping those that cannot be read. It tells whether the
+// entry must be kept, i.e. whether it still points to at least one sub-IFD.
+func (rp *repairer) repairSubIFDs(offset int64, entry []byte, name string) bool {
+	id := EntryID(rp.byteOrder.Uint16(entry))
+	dt := DataType(rp.byteOrder.Uint16(entry[2:]))
+	length := int64(rp.byteOrder.Uint32(entry[4:]))
+	if dt.Size() != 4 || length == 0 {
+		rp.errorf(offset, id, "dropped entry 0x%04x: it does not point to %s IFDs", uint16(id), name)
+		return false
+	}
+
+	values := entry[8:12]
+	if length > 1 {
+		start := int64(rp.byteOrder.Uint32(entry[8:]))
+		values = rp.data[start : start+4*length]
+	}
+
+	kept := int64(0)
+	for i := int64(0); i < length; i++ {
+		subOffset := int64(rp.byteOrder.Uint32(values[4*i:]))
+		switch {
+		case rp.seen[subOffset]:
+		case !rp.readable(subOffset):
+			rp.errorf(offset, id, "dropped %s IFD at offset %d: it cannot be read", name, subOffset)
+			continue
+		default:
+			rp.repairIFD(subOffset, name)
+		}
+		rp.byteOrder.PutUint32(values[4*kept:], uint32(subOffset))
+		kept++
+	}
+
+	if kept == 0 {
+		rp.errorf(offset, id, "dropped entry 0x%04x: it does not point to any readable %s IFD", uint16(id), name)
+		return false
+	}
+	if kept != length {
+		clear(values[4*kept:])
+		rp.byteOrder.PutUint32(entry[4:], uint32(kept))
+		if kept == 1 && length > 1 {
+			copy(entry[8:12], values[:4]) // a single offset is stored in the entry itself
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,151 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepair_Images(t *testing.T) {
+	for _, image := range [][]byte{cr2Image, orfImage} {
+		var out bytes.Buffer
+		report, err := Repair(bytes.NewReader(image), &out, RepairOptions{})
+		assert.NoError(t, err)
+		assert.Empty(t, report.Issues)
+		assert.Equal(t, image, out.Bytes())
+	}
+}
+
+func TestRepair(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  func() []byte
+		opts  RepairOptions
+		want  []string // messages of the expected issues
+		check func(t *testing.T, p *Parser)
+	}{
+		{
+			name: "broken next-IFD pointer",
+			data: func() []byte {
+				b := test.NewTIFFBuilder(binary.LittleEndian)
+				b.AddIFD().WithUints32(uint16(ImageWidth), 256)
+				b.AddIFD().WithUints32(uint16(ImageWidth), 128)
+				data := b.Bytes()
+				binary.LittleEndian.PutUint32(data[8+2+EntryLength:], 0xffffff) // next IFD of IFD#0
+				return data
+			},
+			want: []string{"dropped the IFDs from offset 16777215: the chain is broken"},
+			check: func(t *testing.T, p *Parser) {
+				pages, err := p.Pages()
+				assert.NoError(t, err)
+				assert.Len(t, pages, 1)
+			},
+		},
+		{
+			name: "truncated value",
+			data: func() []byte {
+				b := test.NewTIFFBuilder(binary.LittleEndian)
+				b.AddIFD().
+					WithUints32(uint16(ImageWidth), 256).
+					WithString(uint16(Make), "Canon EOS")
+				data := b.Bytes()
+				return data[:len(data)-4]
+			},
+			want: []string{"dropped entry 0x010f: its value ends after the end of the file"},
+			check: func(t *testing.T, p *Parser) {
+				entries, err := p.Parse(ImageWidth, Make)
+				assert.NoError(t, err)
+				assert.Contains(t, entries, ImageWidth)
+				assert.NotContains(t, entries, Make)
+			},
+		},
+		{
+			name: "truncated value, clamped",
+			data: func() []byte {
+				b := test.NewTIFFBuilder(binary.LittleEndian)
+				b.AddIFD().
+					WithUints32(uint16(ImageWidth), 256).
+					WithString(uint16(Make), "Canon EOS")
+				data := b.Bytes()
+				return data[:len(data)-4]
+			},
+			opts: RepairOptions{ClampCounts: true},
+			want: []string{"shortened entry 0x010f: kept 6 of its 10 values"},
+			check: func(t *testing.T, p *Parser) {
+				entries, err := p.Parse(Make)
+				assert.NoError(t, err)
+				assert.Equal(t, "Canon ", *entries[Make].Value.String)
+			},
+		},
+		{
+			name: "wrong number of entries",
+			data: func() []byte {
+				b := test.NewTIFFBuilder(binary.LittleEndian)
+				b.AddIFD().
+					WithUints32(uint16(ImageWidth), 256).
+					WithUints32(uint16(ImageHeight), 128)
+				data := b.Bytes()
+				binary.LittleEndian.PutUint16(data[8:], 50)
+				return data
+			},
+			want: []string{"IFD at offset 8 is truncated: kept 2 of its 50 entries"},
+			check: func(t *testing.T, p *Parser) {
+				entries, err := p.Parse(ImageWidth, ImageHeight)
+				assert.NoError(t, err)
+				assert.Len(t, entries, 2)
+			},
+		},
+		{
+			name: "unknown data type and broken sub-IFD pointer",
+			data: func() []byte {
+				b := test.NewTIFFBuilder(binary.LittleEndian)
+				b.AddIFD().
+					WithUints32(uint16(ImageWidth), 256).
+					WithUints16(uint16(BitsPerSample), 8).
+					WithUints32(uint16(Exif), 0xffff)
+				data := b.Bytes()
+				binary.LittleEndian.PutUint16(data[8+2+EntryLength+2:], 99) // data type of BitsPerSample
+				return data
+			},
+			want: []string{
+				"dropped entry 0x0102: unknown data type 99",
+				"dropped Exif IFD at offset 65535: it cannot be read",
+				"dropped entry 0x8769: it does not point to any readable Exif IFD",
+			},
+			check: func(t *testing.T, p *Parser) {
+				infos, err := p.ListIDs()
+				assert.NoError(t, err)
+				assert.Len(t, infos, 1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			report, err := Repair(bytes.NewReader(tt.data()), &out, tt.opts)
+			assert.NoError(t, err)
+
+			var messages []string
+			for _, issue := range report.Issues {
+				messages = append(messages, issue.Message)
+			}
+			assert.Equal(t, tt.want, messages)
+
+			p, err := NewParser(bytes.NewReader(out.Bytes()))
+			assert.NoError(t, err)
+			assert.True(t, p.Validate().Valid(), p.Validate().Issues)
+			tt.check(t, p)
+		})
+	}
+}
+
+func TestRepair_Unreadable(t *testing.T) {
+	data := []byte{'I', 'I', 0x2a, 0x00, 0xff, 0xff, 0x00, 0x00}
+
+	_, err := Repair(bytes.NewReader(data), &bytes.Buffer{}, RepairOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package tiff
+
+import "errors"
+
+// Values of ResolutionUnit
+const (
+	resolutionUnitInch       = 2
+	resolutionUnitCentimeter = 3
+)
+
+// DPI returns the horizontal and vertical resolution of the image, in dots per inch, converted from XResolution and
+// YResolution in the unit given by ResolutionUnit (inches if it is missing, as per the TIFF specification).
+// YResolution defaults to XResolution. It returns 0 for both when the resolution is missing or invalid, or when
+// ResolutionUnit is 1 (no absolute unit, only the aspect ratio is known).
+func DPI(entries map[EntryID]Entry) (x, y float64) {
+	unit := uint64(resolutionUnitInch)
+	if _, ok := entries[ResolutionUnit]; ok {
+		var err error
+		if unit, err = readUint(entries, ResolutionUnit); err != nil {
+			return 0, 0
+		}
+	}
+
+	var factor float64
+	switch unit {
+	case resolutionUnitInch:
+		factor = 1
+	case resolutionUnitCentimeter:
+		factor = 2.54
+	default:
+		return 0, 0
+	}
+
+	x, err := readPositiveFloat(entries, XResolution)
+	if err != nil {
+		return 0, 0
+	}
+	y = x
+	if _, ok := entries[YResolution]; ok {
+		if y, err = readPositiveFloat(entries, YResolution); err != nil {
+			return 0, 0
+		}
+	}
+
+	return x * factor, y * factor
+}
+
+// ReadImageSize reads the dimensions of the image, in pixels, from ImageWidth and ImageHeight or, if IFD#0 lacks them
+// (as in the Exif metadata of JPEG files), from ExifImageWidth and ExifImageHeight.
+func ReadImageSize(entries map[EntryID]Entry) (width, height uint64, err error) {
+	for _, ids := range [][2]EntryID{{ImageWidth, ImageHeight}, {ExifImageWidth, ExifImageHeight}} {
+		_, hasWidth := entries[ids[0]]
+		_, hasHeight := entries[ids[1]]
+		if !hasWidth || !hasHeight {
+			continue
+		}
+		if width, err = readUint(entries, ids[0]); err != nil {
+			return 0, 0, err
+		}
+		if height, err = readUint(entries, ids[1]); err != nil {
+			return 0, 0, err
+		}
+		if width > 0 && height > 0 {
+			return width, height, nil
+		}
+	}
+
+	return 0, 0, errors.New("missing ImageWidth and ImageHeight, or ExifImageWidth and ExifImageHeight")
+}
@@ -0,0 +1,87 @@
+package tiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDPI(t *testing.T) {
+	inches, centimeters, none := uint16(2), uint16(3), uint16(1)
+	resolution := func(n uint32) Entry {
+		return Entry{Value: EntryValue{URational: &URational{Numerator: n, Denominator: 1}}}
+	}
+	unit := func(u *uint16) Entry { return Entry{Value: EntryValue{Uint16: u}} }
+
+	tests := []struct {
+		name    string
+		entries map[EntryID]Entry
+		x, y    float64
+	}{
+		{"inches", map[EntryID]Entry{XResolution: resolution(300), YResolution: resolution(150), ResolutionUnit: unit(&inches)}, 300, 150},
+		{"centimeters", map[EntryID]Entry{XResolution: resolution(100), YResolution: resolution(100), ResolutionUnit: unit(&centimeters)}, 254, 254},
+		{"default unit", map[EntryID]Entry{XResolution: resolution(72), YResolution: resolution(72)}, 72, 72},
+		{"default YResolution", map[EntryID]Entry{XResolution: resolution(72)}, 72, 72},
+		{"no absolute unit", map[EntryID]Entry{XResolution: resolution(1), YResolution: resolution(2), ResolutionUnit: unit(&none)}, 0, 0},
+		{"missing", map[EntryID]Entry{}, 0, 0},
+		{"invalid", map[EntryID]Entry{XResolution: resolution(0)}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y := DPI(tt.entries)
+			assert.InDelta(t, tt.x, x, 1e-9)
+			assert.InDelta(t, tt.y, y, 1e-9)
+		})
+	}
+}
+
+func TestDPI_CR2(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	entries, err := p.Parse(XResolution, YResolution, ResolutionUnit)
+	assert.NoError(t, err)
+
+	x, y := DPI(entries)
+	assert.Equal(t, 72.0, x)
+	assert.Equal(t, 72.0, y)
+}
+
+func TestReadImageSize(t *testing.T) {
+	size := func(n uint32) Entry { return Entry{Value: EntryValue{Uint32: &n}} }
+
+	tests := []struct {
+		name          string
+		entries       map[EntryID]Entry
+		width, height uint64
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{"IFD#0", map[EntryID]Entry{ImageWidth: size(6000), ImageHeight: size(4000), ExifImageWidth: size(1), ExifImageHeight: size(1)}, 6000, 4000, assert.NoError},
+		{"Exif", map[EntryID]Entry{ExifImageWidth: size(4032), ExifImageHeight: size(3024)}, 4032, 3024, assert.NoError},
+		{"incomplete IFD#0", map[EntryID]Entry{ImageWidth: size(6000), ExifImageWidth: size(4032), ExifImageHeight: size(3024)}, 4032, 3024, assert.NoError},
+		{"zero in IFD#0", map[EntryID]Entry{ImageWidth: size(0), ImageHeight: size(0), ExifImageWidth: size(4032), ExifImageHeight: size(3024)}, 4032, 3024, assert.NoError},
+		{"missing", map[EntryID]Entry{}, 0, 0, assert.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height, err := ReadImageSize(tt.entries)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.width, width)
+			assert.Equal(t, tt.height, height)
+		})
+	}
+}
+
+func TestReadImageSize_CR2(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	entries, err := p.Parse(ExifImageWidth, ExifImageHeight)
+	assert.NoError(t, err)
+
+	width, height, err := ReadImageSize(entries)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5184), width)
+	assert.Equal(t, uint64(3456), height)
+}
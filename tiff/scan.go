@@ -0,0 +1,57 @@
+package tiff
+
+import (
+	"errors"
+	"io"
+)
+
+// scanBufferSize is the size of the chunks read by FindHeaders.
+const scanBufferSize = 64 * 1024
+
+// FindHeaders scans a stream for TIFF headers (including those of ORF files), returning the offset of each one, in
+// order. A candidate header must start with a known byte order and magic number, followed by a plausible offset of
+// IFD#0: it can be read with NewParser, e.g. through an io.SectionReader starting at that offset. This allows carving
+// TIFF structures (e.g. Exif metadata) out of arbitrary containers, such as PDF files, firmware dumps or disk images.
+func FindHeaders(r io.Reader) ([]int64, error) {
+	var res []int64
+	buffer := make([]byte, scanBufferSize)
+	var base int64 // offset of buffer[0] in the stream
+	carried := 0   // bytes carried over from the previous chunk, which may hold the beginning of a header
+
+	for {
+		n, err := io.ReadFull(r, buffer[carried:])
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, err
+		}
+		end := carried + n
+
+		for i := 0; i+8 <= end; i++ {
+			if isHeader(buffer[i : i+8]) {
+				res = append(res, base+int64(i))
+			}
+		}
+		if err != nil {
+			return res, nil
+		}
+
+		carried = min(7, end)
+		copy(buffer, buffer[end-carried:end])
+		base += int64(end - carried)
+	}
+}
+
+// isHeader tells whether the 8 bytes of a buffer can be a TIFF header.
+func isHeader(buffer []byte) bool {
+	if buffer[0] != buffer[1] || (buffer[0] != 'I' && buffer[0] != 'M') {
+		return false
+	}
+	byteOrder, err := readEndianness(buffer[0:2])
+	if err != nil {
+		return false
+	}
+	if err := validateMagicNumber(byteOrder, buffer[2:4]); err != nil {
+		return false
+	}
+
+	return byteOrder.Uint32(buffer[4:8]) >= 8
+}
@@ -0,0 +1,44 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindHeaders(t *testing.T) {
+	little := test.NewTIFFBuilder(binary.LittleEndian)
+	little.AddIFD().WithString(uint16(Make), "Canon")
+	big := test.NewTIFFBuilder(binary.BigEndian)
+	big.AddIFD().WithString(uint16(Make), "Nikon")
+
+	var data []byte
+	data = append(data, bytes.Repeat([]byte("II*"), 10)...) // partial headers
+	data = append(data, little.Bytes()...)                  // at offset 30
+	data = append(data, make([]byte, scanBufferSize-len(data)-3)...)
+	data = append(data, big.Bytes()...)               // across two chunks
+	data = append(data, 'M', 'M', 0, '*', 0, 0, 0, 0) // invalid offset of IFD#0
+	data = append(data, orfImage[:8]...)
+
+	offsets, err := FindHeaders(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{30, scanBufferSize - 3, int64(len(data) - 8)}, offsets)
+
+	for _, offset := range offsets[:2] {
+		p, err := NewParser(io.NewSectionReader(bytes.NewReader(data), offset, int64(len(data))-offset))
+		assert.NoError(t, err)
+		entries, err := p.Parse(Make)
+		assert.NoError(t, err)
+		assert.Contains(t, entries, Make)
+	}
+}
+
+func TestFindHeaders_Empty(t *testing.T) {
+	offsets, err := FindHeaders(bytes.NewReader(nil))
+	assert.NoError(t, err)
+	assert.Empty(t, offsets)
+}
@@ -0,0 +1,48 @@
+package tiff
+
+import (
+	"errors"
+	"io"
+)
+
+// NewSectionParser returns a new parser for a TIFF structure embedded in a larger file (e.g. the APP1 segment of a JPEG
+// file, or a firmware blob), whose header starts at the given offset of r and which spans the given number of bytes.
+// Internal offsets of the structure are resolved relatively to its header, without copying it to memory first.
+func NewSectionParser(r io.ReaderAt, offset, length int64) (*Parser, error) {
+	return NewParser(io.NewSectionReader(r, offset, length))
+}
+
+// WithOffsetBase tells the parser that internal offsets of the file are relative to the given position (relative to the
+// header) rather than to the header itself, as some embedded structures do.
+func (p *Parser) WithOffsetBase(base int64) *Parser {
+	if shifted, ok := p.reader.(*shiftedReader); ok {
+		p.reader = shifted.ReadSeeker
+	}
+	if base != 0 {
+		p.reader = &shiftedReader{ReadSeeker: p.reader, base: base}
+	}
+
+	return p
+}
+
+// shiftedReader shifts absolute positions by base: position 0 of the shiftedReader is position base of the underlying
+// reader.
+type shiftedReader struct {
+	io.ReadSeeker
+	base int64
+}
+
+func (s *shiftedReader) Seek(offset int64, whence int) (int64, error) {
+	if whence == io.SeekStart {
+		offset += s.base
+	}
+	position, err := s.ReadSeeker.Seek(offset, whence)
+	if err != nil {
+		return 0, err
+	}
+	if position < s.base {
+		return 0, errors.New("seek before the offset base")
+	}
+
+	return position - s.base, nil
+}
@@ -0,0 +1,52 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSectionParser(t *testing.T) {
+	data := append(bytes.Repeat([]byte{0xff}, 100), cr2Image...)
+
+	p, err := NewSectionParser(bytes.NewReader(data), 100, int64(len(cr2Image)))
+	assert.NoError(t, err)
+
+	entries, err := p.Parse(Model, ExposureTime)
+	assert.NoError(t, err)
+	assert.Equal(t, "Canon EOS 7D", *entries[Model].Value.String)
+	assert.Contains(t, entries, ExposureTime)
+	assert.True(t, p.Validate().Valid())
+}
+
+func TestWithOffsetBase(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.BigEndian)
+	b.AddIFD().WithString(uint16(Make), "Olympus")
+	original := b.Bytes()
+
+	// 16 bytes inserted after the header: offsets are relative to position 16, not to the header
+	var data []byte
+	data = append(data, original[:8]...)
+	data = append(data, make([]byte, 16)...)
+	data = append(data, original[8:]...)
+
+	p, err := NewParser(bytes.NewReader(data))
+	assert.NoError(t, err)
+	entries, err := p.Parse(Make)
+	assert.NoError(t, err)
+	assert.NotContains(t, entries, Make) // IFD#0 is read from the inserted bytes
+
+	p, err = NewParser(bytes.NewReader(data))
+	assert.NoError(t, err)
+	entries, err = p.WithOffsetBase(16).Parse(Make)
+	assert.NoError(t, err)
+	assert.Equal(t, "Olympus", *entries[Make].Value.String)
+
+	// resetting the base
+	entries, err = p.WithOffsetBase(0).Parse(Make)
+	assert.NoError(t, err)
+	assert.NotContains(t, entries, Make)
+}
@@ -0,0 +1,67 @@
+package tiff
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var sortedIDs = []EntryID{ExposureTime, Make, Model, ImageWidth, ImageHeight, DateTimeOriginal, FNumber, Orientation}
+
+func TestParser_ParseSorted(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+
+	sorted, err := p.ParseSorted(sortedIDs...)
+	assert.NoError(t, err)
+	entries, err := p.Parse(sortedIDs...)
+	assert.NoError(t, err)
+	assert.Equal(t, entries, sorted.Map())
+
+	assert.True(t, slices.IsSortedFunc(sorted, func(a, b Entry) int { return int(a.ID) - int(b.ID) }))
+	for _, id := range sortedIDs {
+		entry, ok := sorted.Get(id)
+		assert.True(t, ok)
+		assert.Equal(t, entries[id], entry)
+	}
+	_, ok := sorted.Get(GPSLatitude)
+	assert.False(t, ok)
+	_, ok = SortedEntries(nil).Get(Make)
+	assert.False(t, ok)
+}
+
+func TestParser_ParseSorted_Truncated(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image[:60]))
+	assert.NoError(t, err)
+
+	sorted, err := p.ParseSorted(Make, ImageHeight, ImageWidth)
+	assert.ErrorIs(t, err, ErrTruncated)
+	assert.Len(t, sorted, 2)
+	assert.Equal(t, ImageWidth, sorted[0].ID)
+}
+
+func BenchmarkParser_Parse(b *testing.B) {
+	r := bytes.NewReader(cr2Image)
+	p, _ := NewParser(r)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		entries, _ := p.Parse(sortedIDs...)
+		for _, id := range sortedIDs {
+			_ = entries[id]
+		}
+	}
+}
+
+func BenchmarkParser_ParseSorted(b *testing.B) {
+	r := bytes.NewReader(cr2Image)
+	p, _ := NewParser(r)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		entries, _ := p.ParseSorted(sortedIDs...)
+		for _, id := range sortedIDs {
+			_, _ = entries.Get(id)
+		}
+	}
+}
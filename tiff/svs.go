@@ -0,0 +1,144 @@
+package tiff
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+type (
+	// SlideImageKind enumerates the kinds of images stored in an Aperio SVS whole-slide image.
+	SlideImageKind uint8
+)
+
+const (
+	SlideImage_Level SlideImageKind = iota
+	SlideImage_Thumbnail
+	SlideImage_Label
+	SlideImage_Macro
+
+	// aperioSignature is how ImageDescription starts in all pages written by Aperio scanners
+	aperioSignature = "Aperio"
+)
+
+// SlideImage represents one of the images of an Aperio SVS file.
+type SlideImage struct {
+	Kind       SlideImageKind
+	Page       Page
+	Width      uint32
+	Height     uint32
+	Downsample float64 // how smaller this image is compared to the full-resolution level (levels only)
+	Objective  float64 // magnification of this level, e.g. 20 for a 20x scan at full resolution (levels only)
+	MPP        float64 // microns per pixel at this level, 0 if unknown (levels only)
+}
+
+// Slide represents the structure of an Aperio SVS file: pyramid levels (full resolution first) and associated images.
+type Slide struct {
+	Levels     []SlideImage
+	Thumbnail  *SlideImage
+	Label      *SlideImage
+	Macro      *SlideImage
+	Properties map[string]string // key/value pairs of the full-resolution level's ImageDescription (e.g. AppMag, MPP)
+}
+
+// ReadSlide reads the structure of an Aperio SVS whole-slide image: SVS files are multi-page TIFFs where tiled pages are
+// pyramid levels, while stripped pages are the associated thumbnail, label and macro images, which are identified by
+// their ImageDescription. It returns an error if the file is not an SVS.
+func (p *Parser) ReadSlide() (*Slide, error) {
+	pages, err := p.Pages()
+	if err != nil {
+		return nil, err
+	}
+	if len(pages) == 0 || !strings.HasPrefix(pages[0].Description(), aperioSignature) {
+		return nil, errors.New("not an Aperio SVS file")
+	}
+
+	slide := &Slide{Properties: parseAperioProperties(pages[0].Description())}
+	for _, page := range pages {
+		width, _ := page.Width()
+		height, _ := page.Height()
+		image := SlideImage{Page: page, Width: width, Height: height}
+
+		if page.Tiled() {
+			image.Kind = SlideImage_Level
+			slide.Levels = append(slide.Levels, image)
+			continue
+		}
+
+		switch descriptionLine(page.Description(), 1) {
+		case "label":
+			image.Kind = SlideImage_Label
+			slide.Label = &image
+		case "macro":
+			image.Kind = SlideImage_Macro
+			slide.Macro = &image
+		default:
+			image.Kind = SlideImage_Thumbnail
+			slide.Thumbnail = &image
+		}
+	}
+
+	if len(slide.Levels) == 0 {
+		return nil, errors.New("no pyramid level found")
+	}
+
+	objective, _ := strconv.ParseFloat(slide.Properties["AppMag"], 64)
+	mpp, _ := strconv.ParseFloat(slide.Properties["MPP"], 64)
+	for i := range slide.Levels {
+		level := &slide.Levels[i]
+		level.Downsample = 1
+		if level.Width > 0 {
+			level.Downsample = float64(slide.Levels[0].Width) / float64(level.Width)
+		}
+		level.Objective = objective / level.Downsample
+		level.MPP = mpp * level.Downsample
+	}
+
+	return slide, nil
+}
+
+// ReadSlideImage reads the (still encoded) image data of an associated image: it is made of one or more strips, whose
+// encoding depends on the Compression of the image's page (macro images are usually JPEG, labels LZW).
+func (p *Parser) ReadSlideImage(image SlideImage) ([]byte, error) {
+	if image.Page.Tiled() {
+		return nil, errors.New("tiled images cannot be read as a whole")
+	}
+
+	strips, err := p.ReadStrips(image.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.Join(strips, nil), nil
+}
+
+// parseAperioProperties parses the `key = value` pairs of an Aperio ImageDescription, which are separated by pipes and
+// follow the (free text) header and dimensions.
+func parseAperioProperties(description string) map[string]string {
+	properties := make(map[string]string)
+	for i, field := range strings.Split(description, "|") {
+		if i == 0 {
+			continue
+		}
+		key, value, ok := strings.Cut(field, "=")
+		if ok {
+			properties[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	return properties
+}
+
+// descriptionLine returns the first word of the n-th line of an ImageDescription (e.g. "label" from "label 387x463").
+func descriptionLine(description string, n int) string {
+	lines := strings.FieldsFunc(description, func(r rune) bool { return r == '\r' || r == '\n' })
+	if n >= len(lines) {
+		return ""
+	}
+	if fields := strings.Fields(lines[n]); len(fields) > 0 {
+		return fields[0]
+	}
+
+	return ""
+}
@@ -0,0 +1,94 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// svsImage builds a synthetic SVS file: 2 pyramid levels, a thumbnail, a label and a macro image.
+func svsImage() []byte {
+	const header = "Aperio Image Library v12.0.15\r\n"
+
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().
+		WithUints32(uint16(ImageWidth), 2048).
+		WithUints32(uint16(ImageHeight), 1024).
+		WithUints16(uint16(TileWidth), 256).
+		WithUints16(uint16(TileLength), 256).
+		WithData(uint16(TileOffsets), uint16(TileByteCounts), []byte{1}, []byte{2}).
+		WithString(uint16(ImageDescription), header+"2048x1024 [0,0 2048x1024] (256x256) JPEG/RGB Q=70|AppMag = 40|MPP = 0.2500")
+	b.AddIFD().
+		WithUints32(uint16(ImageWidth), 512).
+		WithUints32(uint16(ImageHeight), 256).
+		WithData(uint16(StripOffsets), uint16(StripByteCounts), []byte{3}).
+		WithString(uint16(ImageDescription), header+"2048x1024 -> 512x256 - |AppMag = 40|MPP = 0.2500")
+	b.AddIFD().
+		WithUints32(uint16(ImageWidth), 512).
+		WithUints32(uint16(ImageHeight), 256).
+		WithUints16(uint16(TileWidth), 256).
+		WithUints16(uint16(TileLength), 256).
+		WithData(uint16(TileOffsets), uint16(TileByteCounts), []byte{4}).
+		WithString(uint16(ImageDescription), header+"2048x1024 [0,0 2048x1024] (256x256) -> 512x256 JPEG/RGB Q=70")
+	b.AddIFD().
+		WithUints32(uint16(ImageWidth), 100).
+		WithUints32(uint16(ImageHeight), 80).
+		WithUints32(uint16(NewSubfileType), 1).
+		WithData(uint16(StripOffsets), uint16(StripByteCounts), []byte("lab"), []byte("el")).
+		WithString(uint16(ImageDescription), header+"label 100x80")
+	b.AddIFD().
+		WithUints32(uint16(ImageWidth), 300).
+		WithUints32(uint16(ImageHeight), 100).
+		WithUints32(uint16(NewSubfileType), 9).
+		WithData(uint16(StripOffsets), uint16(StripByteCounts), []byte("macro")).
+		WithString(uint16(ImageDescription), header+"macro 300x100")
+
+	return b.Bytes()
+}
+
+func TestReadSlide(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(svsImage()))
+	assert.NoError(t, err)
+
+	slide, err := p.ReadSlide()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "40", slide.Properties["AppMag"])
+	assert.Len(t, slide.Levels, 2)
+	assert.EqualValues(t, 2048, slide.Levels[0].Width)
+	assert.Equal(t, 40.0, slide.Levels[0].Objective)
+	assert.Equal(t, 0.25, slide.Levels[0].MPP)
+	assert.EqualValues(t, 512, slide.Levels[1].Width)
+	assert.Equal(t, 4.0, slide.Levels[1].Downsample)
+	assert.Equal(t, 10.0, slide.Levels[1].Objective)
+	assert.Equal(t, 1.0, slide.Levels[1].MPP)
+
+	assert.NotNil(t, slide.Thumbnail)
+	assert.EqualValues(t, 1, slide.Thumbnail.Page.Index)
+	assert.NotNil(t, slide.Label)
+	assert.EqualValues(t, 100, slide.Label.Width)
+	assert.NotNil(t, slide.Macro)
+	assert.EqualValues(t, 300, slide.Macro.Width)
+
+	label, err := p.ReadSlideImage(*slide.Label)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("label"), label)
+
+	macro, err := p.ReadSlideImage(*slide.Macro)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("macro"), macro)
+
+	_, err = p.ReadSlideImage(slide.Levels[0])
+	assert.Error(t, err)
+}
+
+func TestReadSlide_NotSVS(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+
+	_, err = p.ReadSlide()
+	assert.Error(t, err)
+}
@@ -0,0 +1,18 @@
+package tiff
+
+// WithTextDecoder makes the parser decode the strings of ASCII values with the given function, rather than as UTF-8,
+// e.g. to read files written by legacy software in Latin-1 or Shift-JIS without getting mojibake. Decoders of
+// golang.org/x/text can be plugged in:
+//
+//	p.WithTextDecoder(func(b []byte) string {
+//		s, _ := charmap.ISO8859_1.NewDecoder().Bytes(b)
+//		return string(s)
+//	})
+//
+// The decoder is called on each NUL-separated string of a value, without its terminating NUL. Values of the UTF-8 data
+// type are always decoded as UTF-8. A nil decoder restores the default.
+func (p *Parser) WithTextDecoder(decode func([]byte) string) *Parser {
+	p.textDecoder = decode
+
+	return p
+}
@@ -0,0 +1,50 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_WithTextDecoder(t *testing.T) {
+	latin1 := func(b []byte) string {
+		runes := make([]rune, len(b))
+		for i, c := range b {
+			runes[i] = rune(c)
+		}
+		return string(runes)
+	}
+
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().
+		WithStrings(uint16(Artist), "Ren\xe9", "Fran\xe7ois").
+		WithField(uint16(Copyright), uint16(DataType_UTF8), 7, []byte("Ren\xc3\xa9 \x00")).
+		WithString(uint16(Make), "Ab\xe9")
+
+	tests := []struct {
+		name      string
+		decoder   func([]byte) string
+		artist    []string
+		copyright string
+		make      string
+	}{
+		{"default", nil, []string{"Ren\xe9", "Fran\xe7ois"}, "René ", "Ab\xe9"},
+		{"latin-1", latin1, []string{"René", "François"}, "René ", "Abé"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(bytes.NewReader(b.Bytes()))
+			assert.NoError(t, err)
+			entries, err := p.WithTextDecoder(tt.decoder).Parse(Artist, Copyright, Make)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.artist, entries[Artist].Value.Strings)
+			assert.Equal(t, tt.copyright, *entries[Copyright].Value.String)
+			assert.Equal(t, tt.make, *entries[Make].Value.String)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package tiff
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+)
+
+// ThumbnailOptions tells Thumbnail how to transform the thumbnail of a file.
+type ThumbnailOptions struct {
+	AutoOrient bool // rotate and/or flip the thumbnail according to Orientation, so that it is displayed upright
+	Quality    int  // quality of the re-encoded JPEG, from 1 to 100 (jpeg.DefaultQuality if 0)
+}
+
+// Thumbnail reads the thumbnail of the file (see ReadThumbnail). With AutoOrient, it decodes the thumbnail, applies the
+// transform given by the Orientation entry of IFD#0 (see Orient) and re-encodes it, so that it can be shipped to
+// clients ignoring Exif orientation (e.g. browsers displaying a bare JPEG). Thumbnails that need no transform are
+// returned as they are.
+func (p *Parser) Thumbnail(opts ThumbnailOptions) ([]byte, error) {
+	thumbnail, err := p.ReadThumbnail()
+	if err != nil || !opts.AutoOrient {
+		return thumbnail, err
+	}
+
+	entries, err := p.Parse(Orientation)
+	if err != nil {
+		return nil, err
+	}
+	orientation := 1
+	if entry, ok := entries[Orientation]; ok && entry.Value.Uint16 != nil {
+		orientation = int(*entry.Value.Uint16)
+	}
+	if orientation <= 1 || orientation > 8 {
+		return thumbnail, nil
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(thumbnail))
+	if err != nil {
+		return nil, err
+	}
+	quality := opts.Quality
+	if quality == 0 {
+		quality = jpeg.DefaultQuality
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, Orient(img, orientation), &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Orient applies to img the transform given by an Exif orientation (the value of the Orientation entry), returning an
+// image that is displayed upright:
+//   - 1: none (img is returned as is);
+//   - 2: flip horizontally;
+//   - 3: rotate by 180°;
+//   - 4: flip vertically;
+//   - 5: transpose (flip along the top-left to bottom-right diagonal);
+//   - 6: rotate by 90° clockwise;
+//   - 7: transverse (flip along the top-right to bottom-left diagonal);
+//   - 8: rotate by 90° counterclockwise.
+//
+// Unknown orientations are handled like 1.
+func Orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			dst.Set(x, y, img.At(b.Min.X+sx, b.Min.Y+sy))
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,78 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrient(t *testing.T) {
+	// 1 2 3
+	// 4 5 6
+	src := &image.Gray{Pix: []byte{1, 2, 3, 4, 5, 6}, Stride: 3, Rect: image.Rect(0, 0, 3, 2)}
+
+	tests := []struct {
+		orientation int
+		want        [][]byte // rows
+	}{
+		{1, [][]byte{{1, 2, 3}, {4, 5, 6}}},
+		{2, [][]byte{{3, 2, 1}, {6, 5, 4}}},
+		{3, [][]byte{{6, 5, 4}, {3, 2, 1}}},
+		{4, [][]byte{{4, 5, 6}, {1, 2, 3}}},
+		{5, [][]byte{{1, 4}, {2, 5}, {3, 6}}},
+		{6, [][]byte{{4, 1}, {5, 2}, {6, 3}}},
+		{7, [][]byte{{6, 3}, {5, 2}, {4, 1}}},
+		{8, [][]byte{{3, 6}, {2, 5}, {1, 4}}},
+		{9, [][]byte{{1, 2, 3}, {4, 5, 6}}},
+	}
+
+	for _, tt := range tests {
+		dst := Orient(src, tt.orientation)
+		b := dst.Bounds()
+		got := make([][]byte, b.Dy())
+		for y := range got {
+			got[y] = make([]byte, b.Dx())
+			for x := range got[y] {
+				got[y][x] = color.GrayModel.Convert(dst.At(b.Min.X+x, b.Min.Y+y)).(color.Gray).Y
+			}
+		}
+		assert.Equal(t, tt.want, got, "orientation %d", tt.orientation)
+	}
+}
+
+func TestParser_Thumbnail(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 16, 8)), nil))
+	thumbnail := buf.Bytes()
+
+	build := func(orientation uint16) *Parser {
+		b := test.NewTIFFBuilder(binary.LittleEndian)
+		b.AddIFD().WithUints16(uint16(Orientation), orientation)
+		b.AddIFD().WithData(uint16(ThumbnailOffset), uint16(ThumbnailLength), thumbnail)
+		p, err := NewParser(bytes.NewReader(b.Bytes()))
+		assert.NoError(t, err)
+		return p
+	}
+
+	// rotated by 90°
+	got, err := build(6).Thumbnail(ThumbnailOptions{AutoOrient: true, Quality: 90})
+	assert.NoError(t, err)
+	config, err := jpeg.DecodeConfig(bytes.NewReader(got))
+	assert.NoError(t, err)
+	assert.Equal(t, [2]int{8, 16}, [2]int{config.Width, config.Height})
+
+	// as is
+	got, err = build(6).Thumbnail(ThumbnailOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, thumbnail, got)
+
+	got, err = build(1).Thumbnail(ThumbnailOptions{AutoOrient: true})
+	assert.NoError(t, err)
+	assert.Equal(t, thumbnail, got)
+}
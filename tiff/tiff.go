@@ -5,7 +5,11 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"image/jpeg"
 	"io"
+	"maps"
+	"math"
+	"sort"
 )
 
 // Parser represents a TIFF parser
@@ -14,34 +18,86 @@ type Parser struct {
 	byteOrder      binary.ByteOrder
 	firstIFDOffset int64
 	mapping        map[EntryID]Group
+
+	duplicatePolicy DuplicatePolicy // see ParseAll
+	groupOrder      []Group         // order of preference of groups, with DuplicatePolicy_PreferGroupOrder
+
+	maxValueSize     int64 // see WithMaxValueSize
+	maxEntriesPerIFD int   // see WithMaxEntriesPerIFD
+	strictTypes      bool  // see WithStrictTypes
+	skipValueSize    int64 // see WithSkipValuesLargerThan
+
+	format     Format // see Format
+	vendor     Vendor // see Vendor
+	vendorRead bool   // whether vendor has been read
+	normalize  bool   // see WithNormalization
+	trim       bool   // see WithTrimming
+
+	textDecoder func([]byte) string // see WithTextDecoder
+	vendorIFDs  map[Group]vendorIFD // see WithVendorIFD
+	composites  *CompositeRegistry  // see WithComposites
+	groups      []Group             // groups whose IFDs are read, if restricted (see WithGroups)
+
+	scratch [EntryLength]byte // reused to read headers and entries
 }
 
 // NewParser returns a new parser or an error if the content is not a valid TIFF.
 func NewParser(r io.ReadSeeker) (*Parser, error) {
-	header := make([]byte, 8) // only read the TIFF header
-	if _, err := io.ReadFull(r, header); err != nil {
+	p := &Parser{mapping: Defaults}
+	if err := p.Reset(r); err != nil {
 		return nil, err
 	}
 
+	return p, nil
+}
+
+// Reset makes the parser read another file, as NewParser would, keeping its options (e.g. WithMaxValueSize or
+// WithNormalization) but not its offset base (see WithOffsetBase). It returns an error, leaving the parser unchanged, if
+// the content is not a valid TIFF. Reusing parsers, e.g. through a sync.Pool, spares a server the allocation of a
+// parser and its scratch buffers for each file it reads: the zero Parser is ready to use once Reset.
+func (p *Parser) Reset(r io.ReadSeeker) error {
+	header := p.scratch[:8] // only read the TIFF header
+	if _, err := io.ReadFull(r, header); err != nil {
+		return err
+	}
+
 	byteOrder, err := readEndianness(header[0:2])
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if err := validateMagicNumber(byteOrder, header[2:4]); err != nil {
-		return nil, err
+		return err
+	}
+
+	p.reader = r
+	p.byteOrder = byteOrder
+	p.firstIFDOffset = int64(byteOrder.Uint32(header[4:8]))
+	p.format = formatOf(byteOrder.Uint16(header[2:4]))
+	p.vendor, p.vendorRead = Vendor_Unknown, false
+	if p.mapping == nil {
+		p.mapping = Defaults
 	}
 
+	return nil
+}
+
+// NewParserRaw returns a new parser reading IFD data that lacks a TIFF header (e.g. a fragment recovered from a damaged
+// file), with the given byte order and offset of the first IFD. Offsets are relative to the start of r, as they would
+// be to the start of the header.
+func NewParserRaw(r io.ReadSeeker, byteOrder binary.ByteOrder, firstIFDOffset int64) *Parser {
 	return &Parser{
 		reader:         r,
 		byteOrder:      byteOrder,
-		firstIFDOffset: int64(byteOrder.Uint32(header[4:8])),
+		firstIFDOffset: firstIFDOffset,
 		mapping:        Defaults,
-	}, nil
+	}
 }
 
 // WithMapping adds entry mapping(s) to the parser, so that it will know where those entries appear in the file.
 func (p *Parser) WithMapping(m map[EntryID]Group) *Parser {
+	// copied rather than changed in place, as it may be shared with other parsers (e.g. Defaults)
+	p.mapping = maps.Clone(p.mapping)
 	for k, v := range m {
 		p.mapping[k] = v
 	}
@@ -49,75 +105,162 @@ func (p *Parser) WithMapping(m map[EntryID]Group) *Parser {
 	return p
 }
 
+// ByteOrder returns the byte order of the TIFF file.
+func (p *Parser) ByteOrder() binary.ByteOrder {
+	return p.byteOrder
+}
+
 // Parse parses the TIFF file, returning any entry found in it that matches the given IDs or an error if the read fails. It does not return an error if one or more of the entries are not found.
+// If the end of the file is reached before all entries could be read, it returns the entries read so far together with
+// a *TruncatedError (matching ErrTruncated).
 func (p *Parser) Parse(ids ...EntryID) (map[EntryID]Entry, error) {
-	entries := make(map[EntryID]Entry)
-	ifd0Wanted := newWanted()
-	exifWanted := newWanted()
-	gpsInfoWanted := newWanted()
+	return p.parse(p.groupsOf(ids))
+}
+
+// ParseEach parses the TIFF file like Parse does, but calls fn with each entry matching the given IDs as soon as it is
+// decoded, rather than collecting them in a map, which saves allocations when scanning many files. Entries are visited
+// in the order they are found in the file. It stops at the first error returned by fn, returning it as is; if the end
+// of the file is reached before all entries could be read, fn has been called with the entries read so far and it
+// returns a *TruncatedError (matching ErrTruncated).
+func (p *Parser) ParseEach(ids []EntryID, fn func(Entry) error) error {
+	return p.parseEach(p.groupsOf(ids), fn)
+}
 
+// groupsOf returns the group each of the given IDs is looked up in, according to the mapping of the parser. IDs that
+// are not mapped are left out.
+func (p *Parser) groupsOf(ids []EntryID) map[EntryID]Group {
+	groups := make(map[EntryID]Group, len(ids))
 	for _, id := range ids {
-		group, ok := p.mapping[id]
-
-		if ok {
-			switch group {
-			case Group_IFD0:
-				ifd0Wanted.Put(id)
-			case Group_Exif:
-				ifd0Wanted.Put(Exif)
-				exifWanted.Put(id)
-			case Group_GPSInfo:
-				ifd0Wanted.Put(GPSInfo)
-				gpsInfoWanted.Put(id)
-			}
+		if group, ok := p.mapping[id]; ok {
+			groups[id] = group
 		}
 	}
 
-	ifd0Offset := p.firstIFDOffset
-	ifd0Entries, err := p.collect(ifd0Offset, ifd0Wanted)
-	if err != nil {
+	return groups
+}
+
+// parse parses the TIFF file, returning any entry found in it that matches the given IDs, each one being looked up in
+// the given group.
+func (p *Parser) parse(groups map[EntryID]Group) (map[EntryID]Entry, error) {
+	entries := make(map[EntryID]Entry)
+	err := p.parseEach(groups, func(entry Entry) error {
+		entries[entry.ID] = entry
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrTruncated) {
 		return nil, err
 	}
 
-	for key, value := range ifd0Entries {
-		if key != Exif && key != GPSInfo {
-			entries[key] = value
+	return entries, err
+}
+
+// parseEach parses the TIFF file, calling fn with any entry found in it that matches the given IDs, each one being
+// looked up in the given group (see ParseEach).
+func (p *Parser) parseEach(groups map[EntryID]Group, fn func(Entry) error) error {
+	ifd0Wanted := newWanted()
+	exifWanted := newWanted()
+	gpsInfoWanted := newWanted()
+	subIFDWanted := newWanted()
+	vendorWanted := make(map[Group]*wanted)
+
+	for id, group := range groups {
+		if !p.follows(group) || p.excludes(group, id) {
+			continue // as if the file did not have them
+		}
+		switch group {
+		case Group_IFD0:
+			ifd0Wanted.Put(id)
+		case Group_Exif:
+			ifd0Wanted.Put(Exif)
+			exifWanted.Put(id)
+		case Group_GPSInfo:
+			ifd0Wanted.Put(GPSInfo)
+			gpsInfoWanted.Put(id)
+		case Group_SubIFD:
+			subIFDWanted.Put(id)
+		default:
+			if _, ok := p.vendorIFDs[group]; (ok && p.follows(Group_MakerNote)) || group == Group_MakerNote {
+				if vendorWanted[group] == nil {
+					vendorWanted[group] = newWanted()
+				}
+				vendorWanted[group].Put(id)
+			}
 		}
 	}
 
-	if !exifWanted.Empty() {
-		exifEntry, ok := ifd0Entries[Exif]
-		if !ok {
-			return nil, errors.New("exif IFD not found")
+	var exifOffset, gpsInfoOffset int64
+	var hasExif, hasGPSInfo bool
+	err := p.each(p.firstIFDOffset, Group_IFD0, ifd0Wanted, func(entry Entry) error {
+		switch entry.ID {
+		case Exif:
+			exifOffset, hasExif = int64(entry.RawValue), true
+		case GPSInfo:
+			gpsInfoOffset, hasGPSInfo = int64(entry.RawValue), true
+		default:
+			return fn(entry)
 		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
-		exifEntries, err := p.collect(int64(exifEntry.RawValue), exifWanted)
-		if err != nil {
-			return nil, err
+	if !exifWanted.Empty() {
+		if !hasExif {
+			return errors.New("exif IFD not found")
 		}
-
-		for key, value := range exifEntries {
-			entries[key] = value
+		if err := p.each(exifOffset, Group_Exif, exifWanted, fn); err != nil {
+			return err
 		}
 	}
 
 	if !gpsInfoWanted.Empty() {
-		gpsInfoEntry, ok := ifd0Entries[GPSInfo]
-		if !ok {
-			return nil, errors.New("exif IFD not found")
+		if !hasGPSInfo {
+			return errors.New("GPSInfo IFD not found")
 		}
+		if err := p.each(gpsInfoOffset, Group_GPSInfo, gpsInfoWanted, fn); err != nil {
+			return err
+		}
+	}
 
-		gpsInfoEntries, err := p.collect(int64(gpsInfoEntry.RawValue), gpsInfoWanted)
+	if !subIFDWanted.Empty() {
+		// entries are taken from the first SubIFD holding them, usually that of the raw image, else from IFD#0, where
+		// files without SubIFDs (e.g. DNG files holding a single image) store them
+		offsets, err := p.groupOffsets(Group_SubIFD)
 		if err != nil {
-			return nil, err
+			return err
 		}
-
-		for key, value := range gpsInfoEntries {
-			entries[key] = value
+		found := make(map[EntryID]bool)
+		for _, offset := range offsets {
+			err := p.each(offset, Group_SubIFD, subIFDWanted, func(entry Entry) error {
+				if found[entry.ID] {
+					return nil
+				}
+				found[entry.ID] = true
+				return fn(entry)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		notFound := newWanted()
+		for id := range subIFDWanted.ids {
+			if !found[id] {
+				notFound.Put(id)
+			}
+		}
+		if !notFound.Empty() {
+			if err := p.each(p.firstIFDOffset, Group_IFD0, notFound, fn); err != nil {
+				return err
+			}
 		}
 	}
 
-	return entries, nil
+	if len(vendorWanted) > 0 {
+		return p.eachVendorEntry(vendorWanted, fn)
+	}
+
+	return nil
 }
 
 // readEndianness reads and returns the endianness of the metadata.
@@ -146,244 +289,311 @@ func validateMagicNumber(byteOrder binary.ByteOrder, buffer []byte) error {
 	return nil
 }
 
-// collect collects a set of IFD entries from an IFD.
+// collect collects a set of IFD entries from an IFD, whose entries belong to the given group (see each). If it fails,
+// it returns the entries collected so far together with the error.
+func (p *Parser) collect(startingOffset int64, group Group, wanted *wanted) (map[EntryID]Entry, error) {
+	entries := make(map[EntryID]Entry)
+	err := p.each(startingOffset, group, wanted, func(entry Entry) error {
+		entries[entry.ID] = entry
+		return nil
+	})
+
+	return entries, err
+}
+
+// each calls fn with each wanted entry of an IFD, whose entries belong to the given group, as soon as it is decoded. It
+// stops at the first error, returning it.
 // To save memory and time (an IFD may contain tens of thousands of entries), it returns as soon as:
 // - all entries have been collected, or
 // - it has scanned the maximum ID among the desired ones (entries are written according to the natural ordering of their
 // ID value: no point in looking further).
-func (p *Parser) collect(startingOffset int64, wanted *wanted) (map[EntryID]Entry, error) {
+func (p *Parser) each(startingOffset int64, group Group, wanted *wanted, fn func(Entry) error) error {
+	if !p.follows(group) {
+		return errNotFollowed(group)
+	}
+
 	offset := startingOffset
 	if _, err := p.reader.Seek(offset, io.SeekStart); err != nil {
-		return nil, err
+		return err
 	}
 
-	var entries = make(map[EntryID]Entry)
-	buffer := make([]byte, 2)
-	if _, err := io.ReadFull(p.reader, buffer); err != nil {
-		return nil, err
+	buffer := p.scratch[:]
+	if _, err := io.ReadFull(p.reader, buffer[:2]); err != nil {
+		return truncated(err, group, offset)
 	}
 	numEntries := int64(p.byteOrder.Uint16(buffer))
+	if err := p.checkEntriesCount(int(numEntries)); err != nil {
+		return err
+	}
 	offset += 2
 
 	for i := int64(0); i < numEntries; i++ {
-		buffer := make([]byte, EntryLength)
 		if _, err := p.reader.Seek(offset, io.SeekStart); err != nil {
-			return nil, err
+			return err
 		}
 		if _, err := io.ReadFull(p.reader, buffer); err != nil {
-			return nil, err
+			return truncated(err, group, offset)
 		}
-		offset += EntryLength
 
 		id := EntryID(p.byteOrder.Uint16(buffer[:2]))
-		if wanted.Contains(id) {
-			dt := DataType(p.byteOrder.Uint16(buffer[2:4]))
-			length := p.byteOrder.Uint32(buffer[4:8])
-			rawValue := p.byteOrder.Uint32(buffer[8:12])
-			value, err := p.readValue(dt, length, rawValue)
-			if err != nil {
-				return nil, err
+		if wanted.Contains(id) && !p.excludes(group, id) {
+			var entry Entry
+			var err error
+			if p.skipsValue(buffer) {
+				entry = p.skippedEntry(buffer)
+			} else if entry, err = p.readEntry(buffer); err != nil {
+				return truncated(err, group, offset)
 			}
-
-			entries[id] = Entry{
-				ID:       id,
-				DataType: dt,
-				Length:   length,
-				RawValue: rawValue,
-				Value:    value,
+			entry.Group, entry.IFDOffset = group, startingOffset
+			if err := fn(entry); err != nil {
+				return err
 			}
 		}
+		offset += EntryLength
 
 		if id >= wanted.Max() {
 			break
 		}
 	}
 
-	return entries, nil
+	return nil
+}
+
+// readEntry decodes a 12-bytes IFD entry, reading its value from wherever it is stored.
+func (p *Parser) readEntry(buffer []byte) (Entry, error) {
+	id := EntryID(p.byteOrder.Uint16(buffer[:2]))
+	dt := DataType(p.byteOrder.Uint16(buffer[2:4]))
+	length := p.byteOrder.Uint32(buffer[4:8])
+	rawValue := p.byteOrder.Uint32(buffer[8:12])
+	if p.strictTypes {
+		if err := checkDataType(id, dt, length); err != nil {
+			return Entry{}, err
+		}
+	}
+	value, err := p.readValue(dt, length, rawValue)
+	if err != nil {
+		return Entry{}, err
+	}
+	if p.normalize {
+		value = normalizeValue(id, value)
+	}
+
+	return Entry{
+		ID:       id,
+		DataType: dt,
+		Length:   length,
+		RawValue: rawValue,
+		Value:    value,
+	}, nil
+}
+
+// readIFD reads all entries of the IFD starting at the given offset, whose entries belong to the given group, returning
+// them together with the offset of the next IFD (0 if there is none).
+func (p *Parser) readIFD(offset int64, group Group) (map[EntryID]Entry, int64, error) {
+	if !p.follows(group) {
+		return nil, 0, errNotFollowed(group)
+	}
+
+	table, next, err := p.readTable(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	numEntries := len(table) / EntryLength
+	entries := make(map[EntryID]Entry, numEntries)
+	for i := 0; i < numEntries; i++ {
+		buffer := table[i*EntryLength : (i+1)*EntryLength]
+		if p.excludes(group, EntryID(p.byteOrder.Uint16(buffer[:2]))) {
+			continue
+		}
+		entry, err := p.readEntry(buffer)
+		if err != nil {
+			return nil, 0, err
+		}
+		entry.Group, entry.IFDOffset = group, offset
+		entries[entry.ID] = entry
+	}
+
+	return entries, next, nil
+}
+
+// readTable reads the table of the IFD starting at the given offset, returning its (still encoded) entries together with
+// the offset of the next IFD (0 if there is none). The whole table is read at once, as reading values moves the reader
+// around.
+func (p *Parser) readTable(offset int64) ([]byte, int64, error) {
+	numEntries, err := p.readEntriesCount(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	table := make([]byte, int(numEntries)*EntryLength+4)
+	if _, err := io.ReadFull(p.reader, table); err != nil {
+		return nil, 0, err
+	}
+
+	return table[:len(table)-4], int64(p.byteOrder.Uint32(table[len(table)-4:])), nil
 }
 
+// readValue reads and decodes the value of an entry holding length values of the given data type (see readArray).
+// Values of unknown data types are not decoded.
 func (p *Parser) readValue(dt DataType, length uint32, rawValue uint32) (EntryValue, error) {
+	if dt.Size() == 0 {
+		return EntryValue{}, nil
+	}
+	raw, err := p.readArray(dt, length, rawValue)
+	if err != nil {
+		return EntryValue{}, err
+	}
+	bo := p.byteOrder
+
 	switch dt {
 	case DataType_UByte:
-		value := byte(rawValue)
-		return EntryValue{UByte: &value}, nil
-	case DataType_String:
-		value, err := p.readString(length, rawValue)
-		if err != nil {
-			return EntryValue{}, err
+		if length == 1 {
+			return EntryValue{UByte: &raw[0]}, nil
 		}
-		return EntryValue{String: &value}, nil
+		return EntryValue{Bytes: raw}, nil
+	case DataType_String, DataType_UTF8:
+		return p.stringsValue(p.splitStrings(dt, raw)), nil
 	case DataType_UShort:
+		values := decodeArray(raw, 2, bo.Uint16)
 		if length == 1 {
-			value := uint16(rawValue)
-			return EntryValue{Uint16: &value}, nil
-		} else {
-			values, err := p.readUints16(length, rawValue)
-			if err != nil {
-				return EntryValue{}, err
-			}
-			return EntryValue{Uints16: values}, nil
+			return EntryValue{Uint16: &values[0]}, nil
 		}
-	case DataType_ULong:
+		return EntryValue{Uints16: values}, nil
+	case DataType_ULong, DataType_IFD:
+		values := decodeArray(raw, 4, bo.Uint32)
 		if length == 1 {
-			value := rawValue
-			return EntryValue{Uint32: &value}, nil
-		} else {
-			values, err := p.readUints32(length, rawValue)
-			if err != nil {
-				return EntryValue{}, err
-			}
-			return EntryValue{Uints32: values}, nil
+			return EntryValue{Uint32: &values[0]}, nil
 		}
+		return EntryValue{Uints32: values}, nil
 	case DataType_URational:
-		value, err := p.readURational(rawValue)
-		if err != nil {
-			return EntryValue{}, err
+		values := decodeArray(raw, 8, func(b []byte) URational { return URational{bo.Uint32(b), bo.Uint32(b[4:])} })
+		if length == 1 {
+			return EntryValue{URational: &values[0]}, nil
 		}
-		return EntryValue{URational: &value}, nil
+		return EntryValue{URationals: values}, nil
 	case DataType_Byte:
-		value := byte(rawValue)
-		return EntryValue{Byte: &value}, nil
+		if length == 1 {
+			return EntryValue{Byte: &raw[0]}, nil
+		}
+		return EntryValue{SBytes: decodeArray(raw, 1, func(b []byte) int8 { return int8(b[0]) })}, nil
+	case DataType_UByte_Sequence:
+		return EntryValue{Bytes: raw}, nil
 	case DataType_Short:
+		values := decodeArray(raw, 2, func(b []byte) int16 { return int16(bo.Uint16(b)) })
 		if length == 1 {
-			value := int16(rawValue)
-			return EntryValue{Int16: &value}, nil
-		} else {
-			values, err := p.readInts16(length, rawValue)
-			if err != nil {
-				return EntryValue{}, err
-			}
-			return EntryValue{Ints16: values}, nil
+			return EntryValue{Int16: &values[0]}, nil
 		}
+		return EntryValue{Ints16: values}, nil
 	case DataType_Long:
+		values := decodeArray(raw, 4, func(b []byte) int32 { return int32(bo.Uint32(b)) })
 		if length == 1 {
-			value := int32(rawValue)
-			return EntryValue{Int32: &value}, nil
-		} else {
-			values, err := p.readInts32(length, rawValue)
-			if err != nil {
-				return EntryValue{}, err
-			}
-			return EntryValue{Ints32: values}, nil
+			return EntryValue{Int32: &values[0]}, nil
 		}
+		return EntryValue{Ints32: values}, nil
 	case DataType_Rational:
-		value, err := p.readRational(rawValue)
-		if err != nil {
-			return EntryValue{}, err
+		values := decodeArray(raw, 8, func(b []byte) Rational { return Rational{int32(bo.Uint32(b)), int32(bo.Uint32(b[4:]))} })
+		if length == 1 {
+			return EntryValue{Rational: &values[0]}, nil
+		}
+		return EntryValue{Rationals: values}, nil
+	case DataType_Float:
+		values := decodeArray(raw, 4, func(b []byte) float32 { return math.Float32frombits(bo.Uint32(b)) })
+		if length == 1 {
+			return EntryValue{Float32: &values[0]}, nil
 		}
-		return EntryValue{Rational: &value}, nil
+		return EntryValue{Floats32: values}, nil
+	case DataType_Double:
+		values := decodeArray(raw, 8, func(b []byte) float64 { return math.Float64frombits(bo.Uint64(b)) })
+		if length == 1 {
+			return EntryValue{Float64: &values[0]}, nil
+		}
+		return EntryValue{Floats64: values}, nil
+	case DataType_ULong8, DataType_IFD8:
+		values := decodeArray(raw, 8, bo.Uint64)
+		if length == 1 {
+			return EntryValue{Uint64: &values[0]}, nil
+		}
+		return EntryValue{Uints64: values}, nil
+	case DataType_Long8:
+		values := decodeArray(raw, 8, func(b []byte) int64 { return int64(bo.Uint64(b)) })
+		if length == 1 {
+			return EntryValue{Int64: &values[0]}, nil
+		}
+		return EntryValue{Ints64: values}, nil
 	}
+
 	return EntryValue{}, nil
 }
 
-// readString reads and returns a string from an IFD entry, trimming its NUL-byte terminator. It returns an error if it cannot read the string.
-func (p *Parser) readString(length uint32, offset uint32) (string, error) {
-	if _, err := p.reader.Seek(int64(offset), io.SeekStart); err != nil {
-		return "", err
-	}
-
-	buffer := make([]byte, length)
-	if _, err := io.ReadFull(p.reader, buffer); err != nil {
-		return "", err
+// splitStrings splits the value of an ASCII (or UTF-8) entry, which may hold several NUL-terminated strings, ignoring
+// trailing NULs (padding), and decodes them (see WithTextDecoder). It always returns at least one string.
+func (p *Parser) splitStrings(dt DataType, buffer []byte) []string {
+	parts := bytes.Split(bytes.TrimRight(buffer, "\x00"), []byte{0})
+	res := make([]string, len(parts))
+	for i, part := range parts {
+		if dt == DataType_String && p.textDecoder != nil {
+			res[i] = p.textDecoder(part)
+		} else {
+			res[i] = string(part)
+		}
 	}
 
-	return string(bytes.TrimSuffix(buffer, []byte{0x0})), nil
+	return res
 }
 
-// readUints16 reads and returns a slice of uint16 from an IFD entry. It returns an error if it cannot read the slice.
-func (p *Parser) readUints16(length uint32, offset uint32) ([]uint16, error) {
-	res := make([]uint16, length)
-	if _, err := p.reader.Seek(int64(offset), io.SeekStart); err != nil {
-		return nil, err
+// stringsValue returns the value of an ASCII entry holding the given strings: String holds the first one, and Strings
+// all of them if there are several. Strings are trimmed first if the parser is configured so (see WithTrimming).
+func (p *Parser) stringsValue(values []string) EntryValue {
+	if p.trim {
+		values = trimStrings(values)
 	}
-
-	size := 2
-	buffer := make([]byte, size*int(length))
-	if _, err := io.ReadFull(p.reader, buffer); err != nil {
-		return nil, err
-	}
-
-	for i := 0; i < int(length); i++ {
-		res[i] = p.byteOrder.Uint16(buffer[i*size : i*size+size])
+	value := EntryValue{String: &values[0]}
+	if len(values) > 1 {
+		value.Strings = values
 	}
 
-	return res, nil
+	return value
 }
 
-// readInts16 reads and returns a slice of int16 from an IFD entry. It returns an error if it cannot read the slice.
-func (p *Parser) readInts16(length uint32, offset uint32) ([]int16, error) {
-	uints16, err := p.readUints16(length, offset)
-	if err != nil {
-		return nil, err
-	}
-	ints16 := make([]int16, len(uints16))
-	for i, u := range uints16 {
-		ints16[i] = int16(u)
-	}
+// inline returns the 4 bytes of an IFD entry's value field, as they are written in the file: values that fit in 4 bytes
+// are stored there, left-justified, rather than at an offset.
+func (p *Parser) inline(rawValue uint32) []byte {
+	buffer := make([]byte, 4)
+	p.byteOrder.PutUint32(buffer, rawValue)
 
-	return ints16, nil
+	return buffer
 }
 
-// readUints32 reads and returns a slice of uint32 from an IFD entry. It returns an error if it cannot read the slice.
-func (p *Parser) readUints32(length uint32, offset uint32) ([]uint32, error) {
-	res := make([]uint32, length)
-	if _, err := p.reader.Seek(int64(offset), io.SeekStart); err != nil {
-		return nil, err
-	}
-
-	size := 4
-	buffer := make([]byte, size*int(length))
-	if _, err := io.ReadFull(p.reader, buffer); err != nil {
-		return nil, err
-	}
-
-	for i := 0; i < int(length); i++ {
-		res[i] = p.byteOrder.Uint32(buffer[i*size : i*size+size])
+// readArray reads the value of an entry holding count values of the given data type, as it is written in the file: from
+// the entry itself (rawValue) when it fits in 4 bytes, from the offset held by rawValue otherwise. It returns an error
+// if the data type is unknown, or if the value cannot be read.
+func (p *Parser) readArray(dt DataType, count uint32, rawValue uint32) ([]byte, error) {
+	if dt.Size() == 0 {
+		return nil, fmt.Errorf("unknown data type %d", dt)
 	}
-
-	return res, nil
-}
-
-// readInts32 reads and returns a slice of int32 from an IFD entry. It returns an error if it cannot read the slice.
-func (p *Parser) readInts32(length uint32, offset uint32) ([]int32, error) {
-	uints32, err := p.readUints32(length, offset)
+	size, err := byteSize(uint64(count), dt.Size())
 	if err != nil {
 		return nil, err
 	}
-	ints32 := make([]int32, len(uints32))
-	for i, u := range uints32 {
-		ints32[i] = int32(u)
-	}
-
-	return ints32, nil
-}
-
-// readURational reads and returns an unsigned rational from an IFD entry, representing its numerator and denominator as uint32. It returns an error if it cannot read from the underlying reader.
-func (p *Parser) readURational(offset uint32) (URational, error) {
-	if _, err := p.reader.Seek(int64(offset), io.SeekStart); err != nil {
-		return URational{}, err
+	if size <= 4 {
+		return p.inline(rawValue)[:size], nil
 	}
-
-	buffer := make([]byte, 8)
-	if _, err := io.ReadFull(p.reader, buffer); err != nil {
-		return URational{}, err
+	if int64(size) > math.MaxUint32 {
+		return nil, fmt.Errorf("%w: value of %d bytes", ErrOutOfRange, size)
 	}
 
-	return URational{p.byteOrder.Uint32(buffer[0:4]), p.byteOrder.Uint32(buffer[4:8])}, nil
+	return p.readChunk(rawValue, uint32(size))
 }
 
-// readRational reads and returns an signed rational from an IFD entry, representing its numerator and denominator as int32. It returns an error if it cannot read from the underlying reader.
-func (p *Parser) readRational(offset uint32) (Rational, error) {
-	if _, err := p.reader.Seek(int64(offset), io.SeekStart); err != nil {
-		return Rational{}, err
-	}
-
-	buffer := make([]byte, 8)
-	if _, err := io.ReadFull(p.reader, buffer); err != nil {
-		return Rational{}, err
+// decodeArray decodes the values of an array read by readArray, each of them being size bytes long.
+func decodeArray[T any](raw []byte, size int, decode func([]byte) T) []T {
+	res := make([]T, len(raw)/size)
+	for i := range res {
+		res[i] = decode(raw[i*size : (i+1)*size])
 	}
 
-	return Rational{int32(p.byteOrder.Uint32(buffer[0:4])), int32(p.byteOrder.Uint32(buffer[4:8]))}, nil
+	return res
 }
 
 func printEntries(p *Parser, offsets []int64) error {
@@ -413,27 +623,15 @@ func printEntries(p *Parser, offsets []int64) error {
 			return err
 		}
 
-		id := EntryID(p.byteOrder.Uint16(buffer[:2]))
-		dt := DataType(p.byteOrder.Uint16(buffer[2:4]))
-		length := p.byteOrder.Uint32(buffer[4:8])
-		rawValue := p.byteOrder.Uint32(buffer[8:12])
-		value, err := p.readValue(dt, length, rawValue)
+		entry, err := p.readEntry(buffer)
 		if err != nil {
 			return err
 		}
 
-		entry := Entry{
-			ID:       id,
-			DataType: dt,
-			Length:   length,
-			RawValue: rawValue,
-			Value:    value,
-		}
-
-		if entry.ID == Exif {
+		if entry.ID == Exif && p.follows(Group_Exif) {
 			fmt.Println("exif offset", entry.RawValue)
 			offsets = append(offsets, int64(entry.RawValue))
-		} else if entry.ID == GPSInfo {
+		} else if entry.ID == GPSInfo && p.follows(Group_GPSInfo) {
 			fmt.Println("gps offset", entry.RawValue)
 			offsets = append(offsets, int64(entry.RawValue))
 		}
@@ -465,64 +663,39 @@ func (p *Parser) PrintEntries() error {
 	return printEntries(p, []int64{p.firstIFDOffset})
 }
 
-// ReadThumbnail reads the thumbnail stored in Image Data #1. The offset and length of Image Data #1 are written in IFD #1.
+// ReadThumbnail reads the thumbnail of the file, looking for it where it is actually stored, in order:
+//   - Image Data #1, whose offset and length are written in IFD#1 (ThumbnailOffset and ThumbnailLength);
+//   - the preview referenced by IFD#0 (PreviewImageStart and PreviewImageLength, which share the IDs of ThumbnailOffset
+//     and ThumbnailLength), as in ORF files;
+//   - the image referenced by JPEGInterchangeFormat and JPEGInterchangeFormatLength (same IDs again) in any other IFD,
+//     or the preview the vendor of the camera stores elsewhere (e.g. in the maker note of ORF files, see Vendor);
+//   - the first JPEG-compressed image stored in a single strip.
+//
+// It returns an error matching ErrNoPreview if there is none.
 func (p *Parser) ReadThumbnail() ([]byte, error) {
-	offset := p.firstIFDOffset
-	if _, err := p.reader.Seek(offset, io.SeekStart); err != nil {
-		return nil, err
-	}
-
-	buffer := make([]byte, 2)
-	if _, err := io.ReadFull(p.reader, buffer); err != nil {
-		return nil, err
-	}
-
-	// skip all entries in this IFD
-	numEntries := int64(p.byteOrder.Uint16(buffer))
-	offset += 2 + numEntries*12
-	if _, err := p.reader.Seek(offset, io.SeekStart); err != nil {
-		return nil, err
-	}
-
-	buffer = make([]byte, 4) // offset to IFD#1 is a ulong
-	if _, err := io.ReadFull(p.reader, buffer); err != nil {
-		return nil, err
-	}
-	offsetToIdf1 := int64(p.byteOrder.Uint32(buffer))
-
-	entries, err := p.collect(
-		offsetToIdf1,
-		newWanted(ThumbnailOffset, ThumbnailLength),
-	)
+	candidates, err := p.previewCandidates()
 	if err != nil {
 		return nil, err
 	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].thumbnailPriority() < candidates[j].thumbnailPriority()
+	})
 
-	var thumbnailOffset uint32
-	if elem, ok := entries[ThumbnailOffset]; ok {
-		if offset := elem.Value.Uint32; offset != nil {
-			thumbnailOffset = *offset
-		} else {
-			return nil, errors.New("thumbnail offset not found")
+	for _, c := range candidates {
+		if c.Length == 0 {
+			continue
 		}
-	}
-
-	var thumbnailLength uint32
-	if elem, ok := entries[ThumbnailLength]; ok {
-		if length := elem.Value.Uint32; length != nil {
-			thumbnailLength = *length
-		} else {
-			return nil, errors.New("thumbnail length not found")
+		if c.strip {
+			// strips may hold anything JPEG-compressed, including raw images
+			if _, err := p.reader.Seek(c.Offset, io.SeekStart); err != nil {
+				return nil, err
+			}
+			if _, err := jpeg.DecodeConfig(io.LimitReader(p.reader, c.Length)); err != nil {
+				continue
+			}
 		}
+		return p.ReadPreview(c.Preview)
 	}
 
-	if _, err := p.reader.Seek(int64(thumbnailOffset), io.SeekStart); err != nil {
-		return nil, err
-	}
-	buffer = make([]byte, thumbnailLength)
-	if _, err := io.ReadFull(p.reader, buffer); err != nil {
-		return nil, err
-	}
-
-	return buffer, nil
+	return nil, fmt.Errorf("%w: no thumbnail found", ErrNoPreview)
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	_ "embed"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -114,247 +115,215 @@ func Test_ParseMagicNumber(t *testing.T) {
 	}
 }
 
-func TestParser_readUints16(t *testing.T) {
-	type fields struct {
-		reader io.ReadSeeker
-	}
+func TestParser_readArray(t *testing.T) {
 	type args struct {
-		length uint32
-		offset uint32
+		dt       DataType
+		count    uint32
+		rawValue uint32
 	}
 	tests := []struct {
 		name    string
-		fields  fields
+		reader  io.ReadSeeker
 		args    args
-		want    []uint16
+		want    []byte
 		wantErr assert.ErrorAssertionFunc
 	}{
 		{
-			"returns an error when seek fails",
-			fields{
-				test.NewBytesReadSeeker(),
-			},
-			args{
-				length: 1,
-				offset: 0,
-			},
+			"returns values fitting in 4 bytes from the entry itself",
+			test.NewBytesReadSeeker(),
+			args{DataType_UShort, 2, 0x00de006f},
+			[]byte{0x6f, 0x00, 0xde, 0x00},
+			assert.NoError,
+		},
+		{
+			"reads larger values at the given offset",
+			test.NewBytesReadSeeker().WithUints32(111, 222, 333),
+			args{DataType_ULong, 2, 4},
+			[]byte{222, 0, 0, 0, 0x4d, 0x01, 0, 0},
+			assert.NoError,
+		},
+		{
+			"returns an error when the value is out of bounds",
+			test.NewBytesReadSeeker(),
+			args{DataType_URational, 1, 0},
 			nil,
 			assert.Error,
 		},
 		{
-			"returns the entry value when length == 1",
-			fields{
-				test.NewBytesReadSeeker().WithUints16(111),
-			},
-			args{
-				length: 1,
-				offset: 0,
-			},
-			[]uint16{111},
-			assert.NoError,
+			"returns an error when the values are truncated",
+			test.NewBytesReadSeeker().WithUints16(111, 222, 333),
+			args{DataType_UShort, 3, 2},
+			nil,
+			assert.Error,
 		},
 		{
-			"returns all values when length > 1",
-			fields{
-				test.NewBytesReadSeeker().WithUints16(111, 222),
-			},
-			args{
-				length: 2,
-				offset: 0,
-			},
-			[]uint16{111, 222},
-			assert.NoError,
+			"returns an error when the data type is unknown",
+			test.NewBytesReadSeeker().WithUints16(111, 222, 333),
+			args{DataType(99), 1, 0},
+			nil,
+			assert.Error,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p := &Parser{
-				reader:    tt.fields.reader,
-				byteOrder: binary.LittleEndian,
-			}
-			got, err := p.readUints16(tt.args.length, tt.args.offset)
-			if !tt.wantErr(t, err, fmt.Sprintf("readUints16(%v, %v)", tt.args.length, tt.args.offset)) {
+			p := &Parser{reader: tt.reader, byteOrder: binary.LittleEndian}
+			got, err := p.readArray(tt.args.dt, tt.args.count, tt.args.rawValue)
+			if !tt.wantErr(t, err) {
 				return
 			}
-			assert.Equalf(t, tt.want, got, "readUints16(%v, %v)", tt.args.length, tt.args.offset)
+			assert.Equal(t, tt.want, got)
 		})
 	}
 }
 
-func TestParser_readUints32(t *testing.T) {
-	type fields struct {
-		reader io.ReadSeeker
-	}
+func TestParser_readValue(t *testing.T) {
+	uint16Value, uint32Value := uint16(111), uint32(111)
+	urational, rational := URational{111, 222}, Rational{-1, 3}
+	str, abcde, defg := "abc", "abcde", "defg"
+	uint64Value := uint64(1<<32 + 2)
+
 	type args struct {
-		length uint32
-		offset uint32
+		dt       DataType
+		length   uint32
+		rawValue uint32
 	}
 	tests := []struct {
 		name    string
-		fields  fields
+		reader  io.ReadSeeker
 		args    args
-		want    []uint32
+		want    EntryValue
 		wantErr assert.ErrorAssertionFunc
 	}{
 		{
-			"returns an error when seek fails",
-			fields{
-				test.NewBytesReadSeeker(),
-			},
-			args{
-				length: 1,
-				offset: 0,
-			},
-			nil,
-			assert.Error,
+			"returns a single short from the entry itself",
+			test.NewBytesReadSeeker(),
+			args{DataType_UShort, 1, 111},
+			EntryValue{Uint16: &uint16Value},
+			assert.NoError,
 		},
 		{
-			"returns the entry value when length == 1",
-			fields{
-				test.NewBytesReadSeeker().WithUints32(111),
-			},
-			args{
-				length: 1,
-				offset: 0,
-			},
-			[]uint32{111},
+			"returns all shorts",
+			test.NewBytesReadSeeker().WithUints16(111, 222, 333),
+			args{DataType_UShort, 3, 0},
+			EntryValue{Uints16: []uint16{111, 222, 333}},
 			assert.NoError,
 		},
 		{
-			"returns all values when length > 1",
-			fields{
-				test.NewBytesReadSeeker().WithUints32(111, 222),
-			},
-			args{
-				length: 2,
-				offset: 0,
-			},
-			[]uint32{111, 222},
+			"returns signed shorts at the given offset",
+			test.NewBytesReadSeeker().WithInts16(-1, 2, -32768, 4),
+			args{DataType_Short, 3, 2},
+			EntryValue{Ints16: []int16{2, -32768, 4}},
+			assert.NoError,
+		},
+		{
+			"returns a single long from the entry itself",
+			test.NewBytesReadSeeker(),
+			args{DataType_ULong, 1, 111},
+			EntryValue{Uint32: &uint32Value},
+			assert.NoError,
+		},
+		{
+			"returns signed longs",
+			test.NewBytesReadSeeker().WithInts32(-1, 2),
+			args{DataType_Long, 2, 0},
+			EntryValue{Ints32: []int32{-1, 2}},
+			assert.NoError,
+		},
+		{
+			"returns a single unsigned rational",
+			test.NewBytesReadSeeker().WithURationals(1, 2, 111, 222),
+			args{DataType_URational, 1, 8},
+			EntryValue{URational: &urational},
+			assert.NoError,
+		},
+		{
+			"returns a single signed rational",
+			test.NewBytesReadSeeker().WithRationals(1, 2, -1, 3),
+			args{DataType_Rational, 1, 8},
+			EntryValue{Rational: &rational},
 			assert.NoError,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			p := &Parser{
-				reader:    tt.fields.reader,
-				byteOrder: binary.LittleEndian,
-			}
-			got, err := p.readUints32(tt.args.length, tt.args.offset)
-			if !tt.wantErr(t, err, fmt.Sprintf("readUints32(%v, %v)", tt.args.length, tt.args.offset)) {
-				return
-			}
-			assert.Equalf(t, tt.want, got, "readUints32(%v, %v)", tt.args.length, tt.args.offset)
-		})
-	}
-}
-
-func TestParser_readURational(t *testing.T) {
-	type fields struct {
-		reader io.ReadSeeker
-	}
-	type args struct {
-		offset uint32
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    URational
-		wantErr assert.ErrorAssertionFunc
-	}{
 		{
-			"returns an error when seek fails",
-			fields{
-				test.NewBytesReadSeeker(),
-			},
-			args{
-				offset: 0,
-			},
-			URational{},
+			"returns signed rationals",
+			test.NewBytesReadSeeker().WithRationals(1, 2, -1, 3),
+			args{DataType_Rational, 2, 0},
+			EntryValue{Rationals: []Rational{{1, 2}, {-1, 3}}},
+			assert.NoError,
+		},
+		{
+			"returns an error when the rationals are truncated",
+			test.NewBytesReadSeeker().WithRationals(1, 2, -1, 3),
+			args{DataType_Rational, 3, 0},
+			EntryValue{},
 			assert.Error,
 		},
 		{
-			"returns numerator and denominator",
-			fields{
-				test.NewBytesReadSeeker().WithUints32(111, 222),
-			},
-			args{
-				offset: 0,
-			},
-			URational{111, 222},
+			"returns a string",
+			test.NewBytesReadSeeker().WithString("abcde\000"),
+			args{DataType_String, 6, 0},
+			EntryValue{String: &abcde},
+			assert.NoError,
+		},
+		{
+			"returns the string at the given offset",
+			test.NewBytesReadSeeker().WithStrings("abc", "defg"),
+			args{DataType_String, 5, 4},
+			EntryValue{String: &defg},
+			assert.NoError,
+		},
+		{
+			"returns all strings, ignoring trailing padding",
+			test.NewBytesReadSeeker().WithStrings("abc", "", "defg", "", ""),
+			args{DataType_String, 11, 0},
+			EntryValue{String: &str, Strings: []string{"abc", "", "defg"}},
 			assert.NoError,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			p := &Parser{
-				reader:    tt.fields.reader,
-				byteOrder: binary.LittleEndian,
-			}
-			got, err := p.readURational(tt.args.offset)
-			if !tt.wantErr(t, err, fmt.Sprintf("readURational(%v)", tt.args.offset)) {
-				return
-			}
-			assert.Equalf(t, tt.want, got, "readURational(%v)", tt.args.offset)
-		})
-	}
-}
-
-func TestParser_readString(t *testing.T) {
-	type fields struct {
-		reader io.ReadSeeker
-	}
-	type args struct {
-		length uint32
-		offset uint32
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    string
-		wantErr assert.ErrorAssertionFunc
-	}{
 		{
-			"returns an error when seek fails",
-			fields{
-				test.NewBytesReadSeeker(),
-			},
-			args{
-				length: 1,
-				offset: 0,
-			},
-			"",
+			"returns an error when the string is out of bounds",
+			test.NewBytesReadSeeker(),
+			args{DataType_String, 5, 0},
+			EntryValue{},
 			assert.Error,
 		},
 		{
-			"returns string",
-			fields{
-				test.NewBytesReadSeeker().WithString("abc\000"),
-			},
-			args{
-				length: 4,
-				offset: 0,
-			},
-			"abc",
+			"returns a single LONG8",
+			test.NewBytesReadSeeker().WithUints32(2, 1),
+			args{DataType_ULong8, 1, 0},
+			EntryValue{Uint64: &uint64Value},
+			assert.NoError,
+		},
+		{
+			"returns IFD8 offsets",
+			test.NewBytesReadSeeker().WithUints32(2, 1, 8, 0),
+			args{DataType_IFD8, 2, 0},
+			EntryValue{Uints64: []uint64{uint64Value, 8}},
+			assert.NoError,
+		},
+		{
+			"returns SLONG8s",
+			test.NewBytesReadSeeker().WithInts32(-1, -1, 5, 0),
+			args{DataType_Long8, 2, 0},
+			EntryValue{Ints64: []int64{-1, 5}},
+			assert.NoError,
+		},
+		{
+			"does not decode unknown data types",
+			test.NewBytesReadSeeker(),
+			args{DataType(99), 10, 0},
+			EntryValue{},
 			assert.NoError,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p := &Parser{
-				reader:    tt.fields.reader,
-				byteOrder: binary.LittleEndian,
-			}
-			got, err := p.readString(tt.args.length, tt.args.offset)
-			if !tt.wantErr(t, err, fmt.Sprintf("readString(%v, %v)", tt.args.length, tt.args.offset)) {
+			p := &Parser{reader: tt.reader, byteOrder: binary.LittleEndian}
+			got, err := p.readValue(tt.args.dt, tt.args.length, tt.args.rawValue)
+			if !tt.wantErr(t, err) {
 				return
 			}
-			assert.Equalf(t, tt.want, got, "readString(%v, %v)", tt.args.length, tt.args.offset)
+			assert.Equal(t, tt.want, got)
 		})
 	}
 }
@@ -440,3 +409,226 @@ func TestParse_ORF(t *testing.T) {
 	assert.NotNil(t, dateTime)
 	assert.EqualValues(t, "2016:08:12 13:32:54", *dateTime)
 }
+
+func TestParse_Provenance(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+
+	entries, err := p.Parse(Make, DateTimeOriginal)
+	assert.NoError(t, err)
+	assert.Equal(t, Group_IFD0, entries[Make].Group)
+	assert.EqualValues(t, 16, entries[Make].IFDOffset)
+	assert.Equal(t, Group_Exif, entries[DateTimeOriginal].Group)
+	assert.EqualValues(t, 446, entries[DateTimeOriginal].IFDOffset)
+
+	pages, err := p.Pages()
+	assert.NoError(t, err)
+	for i, group := range []Group{Group_IFD0, Group_IFD1, Group_Image, Group_Image} {
+		for _, entry := range pages[i].Entries {
+			assert.Equal(t, group, entry.Group)
+			assert.Equal(t, pages[i].Offset, entry.IFDOffset)
+		}
+	}
+
+	assert.Equal(t, "GPSInfo", Group_GPSInfo.String())
+	assert.Equal(t, "MakerNote", Group_MakerNote.String())
+	assert.Equal(t, "Group(42)", Group(42).String())
+}
+
+func TestParse_Truncated(t *testing.T) {
+	// IFD#0 starts at offset 16: the file ends in the middle of its 4th entry (Compression, at offset 54)
+	p, err := NewParser(bytes.NewReader(cr2Image[:60]))
+	assert.NoError(t, err)
+
+	entries, err := p.Parse(ImageWidth, ImageHeight, Compression, Make)
+	assert.ErrorIs(t, err, ErrTruncated)
+
+	var truncatedErr *TruncatedError
+	assert.ErrorAs(t, err, &truncatedErr)
+	assert.Equal(t, Group_IFD0, truncatedErr.Group)
+	assert.Equal(t, int64(54), truncatedErr.Offset)
+
+	assert.Len(t, entries, 2)
+	assert.Equal(t, uint16(5184), *entries[ImageWidth].Value.Uint16)
+	assert.Contains(t, entries, ImageHeight)
+}
+
+func TestParse_TruncatedExif(t *testing.T) {
+	// the Exif IFD starts at offset 446: the file ends in the middle of its value area
+	p, err := NewParser(bytes.NewReader(cr2Image[:800]))
+	assert.NoError(t, err)
+
+	entries, err := p.Parse(Make, ExposureTime, DateTimeOriginal)
+	assert.ErrorIs(t, err, ErrTruncated)
+
+	var truncatedErr *TruncatedError
+	assert.ErrorAs(t, err, &truncatedErr)
+	assert.Equal(t, Group_Exif, truncatedErr.Group)
+	assert.Equal(t, "Canon", *entries[Make].Value.String)
+}
+
+func TestParser_ParseEach(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+
+	var ids []EntryID
+	err = p.ParseEach([]EntryID{ExposureTime, Make, ImageWidth}, func(entry Entry) error {
+		ids = append(ids, entry.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []EntryID{ImageWidth, Make, ExposureTime}, ids)
+
+	stop := errors.New("stop")
+	ids = nil
+	err = p.ParseEach([]EntryID{ExposureTime, Make, ImageWidth}, func(entry Entry) error {
+		ids = append(ids, entry.ID)
+		if entry.ID == Make {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, []EntryID{ImageWidth, Make}, ids)
+}
+
+func TestParser_ParseEach_Truncated(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image[:60]))
+	assert.NoError(t, err)
+
+	var ids []EntryID
+	err = p.ParseEach([]EntryID{ImageWidth, ImageHeight, Compression, Make}, func(entry Entry) error {
+		ids = append(ids, entry.ID)
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrTruncated)
+	assert.Equal(t, []EntryID{ImageWidth, ImageHeight}, ids)
+}
+
+func TestParser_Reset(t *testing.T) {
+	var p Parser
+	assert.Error(t, p.Reset(bytes.NewReader([]byte("not a TIFF"))))
+
+	assert.NoError(t, p.Reset(bytes.NewReader(cr2Image)))
+	p.WithNormalization(true)
+	entries, err := p.Parse(ImageWidth, Make)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(5184), *entries[ImageWidth].Value.Uint32)
+	assert.Equal(t, Vendor_Canon, p.Vendor())
+
+	assert.NoError(t, p.Reset(bytes.NewReader(orfImage)))
+	assert.Equal(t, Format_ORF, p.Format())
+	assert.Equal(t, Vendor_Olympus, p.Vendor())
+	entries, err = p.Parse(ImageWidth, Make)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(4640), *entries[ImageWidth].Value.Uint32)
+	assert.Equal(t, "OLYMPUS CORPORATION", *entries[Make].Value.String, "options are kept")
+
+	// the parser is left unchanged
+	assert.Error(t, p.Reset(bytes.NewReader(cr2Image[:4])))
+	assert.Equal(t, Format_ORF, p.Format())
+	entries, err = p.Parse(Make)
+	assert.NoError(t, err)
+	assert.Equal(t, "OLYMPUS CORPORATION", *entries[Make].Value.String)
+}
+
+func TestNewParserRaw(t *testing.T) {
+	// the header is lost: IFD#0 is still at offset 16, relative to the start of the file
+	data := bytes.Clone(cr2Image)
+	clear(data[:8])
+
+	_, err := NewParser(bytes.NewReader(data))
+	assert.Error(t, err)
+
+	p := NewParserRaw(bytes.NewReader(data), binary.LittleEndian, 16)
+	entries, err := p.Parse(ImageWidth, Model, ExposureTime)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(5184), *entries[ImageWidth].Value.Uint16)
+	assert.Equal(t, "Canon EOS 7D", *entries[Model].Value.String)
+	assert.Contains(t, entries, ExposureTime)
+
+	// an IFD other than IFD#0, e.g. the Exif IFD
+	p = NewParserRaw(bytes.NewReader(data), binary.LittleEndian, 446)
+	page, err := p.FirstPage()
+	assert.NoError(t, err)
+	assert.Contains(t, page.Entries, ExposureTime)
+}
+
+func TestParse_Strings(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().
+		WithStrings(uint16(ImageDescription), "first", "second").
+		WithStrings(uint16(Make), "a", "b").
+		WithField(uint16(Model), test.TypeASCII, 8, []byte("Pen F\x00\x00\x00"))
+
+	p, err := NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	entries, err := p.Parse(ImageDescription, Make, Model)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "first", *entries[ImageDescription].Value.String)
+	assert.Equal(t, []string{"first", "second"}, entries[ImageDescription].Value.Strings)
+	assert.Equal(t, ValueKind_Strings, entries[ImageDescription].Value.Kind())
+
+	// inline value
+	assert.Equal(t, []string{"a", "b"}, entries[Make].Value.Strings)
+
+	// a single string, padded with NULs
+	assert.Equal(t, "Pen F", *entries[Model].Value.String)
+	assert.Nil(t, entries[Model].Value.Strings)
+	assert.Equal(t, ValueKind_String, entries[Model].Value.Kind())
+}
+
+func TestParse_UTF8(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().
+		WithField(uint16(ImageDescription), test.TypeUTF8, 16, []byte("Zoë Ångström\x00")).
+		WithField(uint16(Make), test.TypeUTF8, 4, []byte("äb\x00")).
+		WithField(uint16(Model), test.TypeUTF8, 14, []byte("東京\x00大阪\x00"))
+
+	p, err := NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	entries, err := p.Parse(ImageDescription, Make, Model)
+	assert.NoError(t, err)
+
+	assert.Equal(t, DataType_UTF8, entries[ImageDescription].DataType)
+	assert.Equal(t, "Zoë Ångström", *entries[ImageDescription].Value.String)
+	assert.Contains(t, entries[ImageDescription].String(), "DataType: UTF-8 string\nLength: 16\nValue: Zoë Ångström\n")
+	// inline value
+	assert.Equal(t, "äb", *entries[Make].Value.String)
+	assert.Equal(t, []string{"東京", "大阪"}, entries[Model].Value.Strings)
+
+	assert.Empty(t, p.Validate().Issues)
+}
+
+func TestParse_ByteArrays(t *testing.T) {
+	for _, byteOrder := range []test.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		t.Run(byteOrder.String(), func(t *testing.T) {
+			b := test.NewTIFFBuilder(byteOrder)
+			ifd0 := b.AddIFD().
+				WithBytes(uint16(ImageWidth), test.TypeByte, 42).
+				WithBytes(uint16(ImageHeight), test.TypeSByte, 0xfe).
+				WithBytes(uint16(BitsPerSample), test.TypeSByte, 0xff, 0x80, 0x7f, 0x00, 0x01)
+			ifd0.WithSubIFD(uint16(GPSInfo)).
+				WithBytes(uint16(GPSVersionID), test.TypeByte, 2, 3, 0, 0)
+
+			p, err := NewParser(bytes.NewReader(b.Bytes()))
+			assert.NoError(t, err)
+
+			entries, err := p.Parse(ImageWidth, ImageHeight, BitsPerSample, GPSVersionID)
+			assert.NoError(t, err)
+
+			// single values
+			assert.Equal(t, byte(42), *entries[ImageWidth].Value.UByte)
+			assert.Equal(t, int8(-2), entries[ImageHeight].Value.Interface())
+			// inline array
+			assert.Equal(t, []byte{2, 3, 0, 0}, entries[GPSVersionID].Value.Bytes)
+			assert.Equal(t, ValueKind_Bytes, entries[GPSVersionID].Value.Kind())
+			// array stored at an offset
+			assert.Equal(t, []int8{-1, -128, 127, 0, 1}, entries[BitsPerSample].Value.SBytes)
+			assert.Equal(t, ValueKind_SBytes, entries[BitsPerSample].Value.Kind())
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package tiff
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeZoneResolver returns the time zone of a position (in decimal degrees, see ReadGPSPosition), e.g. by looking it up
+// in a database of time zone boundaries. This package does not provide one, so that programs that do not need it do
+// not depend on such a database.
+type TimeZoneResolver interface {
+	TimeZone(latitude, longitude float64) (*time.Location, error)
+}
+
+// TimeZoneResolverFunc is a function implementing TimeZoneResolver.
+type TimeZoneResolverFunc func(latitude, longitude float64) (*time.Location, error)
+
+func (f TimeZoneResolverFunc) TimeZone(latitude, longitude float64) (*time.Location, error) {
+	return f(latitude, longitude)
+}
+
+// CaptureTimeEntries lists the entries read by CaptureTimeWithResolver.
+var CaptureTimeEntries = []EntryID{
+	DateTimeOriginal, OffsetTimeOriginal, OffsetTime, GPSLatitude, GPSLatitudeRef, GPSLongitude, GPSLongitudeRef,
+}
+
+// CaptureTimeWithResolver returns the time at which the image was taken, like CaptureTime, except that when both
+// OffsetTimeOriginal and OffsetTime are missing, the time zone is the one resolver returns for the position of the GPS
+// receiver (see ReadGPSPosition), so that the time is correct even though the camera did not record its time zone.
+// Without position, the time is returned in UTC, as CaptureTime does. Entries should hold CaptureTimeEntries.
+func CaptureTimeWithResolver(entries map[EntryID]Entry, resolver TimeZoneResolver) (time.Time, error) {
+	for _, id := range []EntryID{OffsetTimeOriginal, OffsetTime} {
+		if offset, ok := entries[id]; ok && offset.Value.String != nil && strings.TrimSpace(*offset.Value.String) != "" {
+			return CaptureTime(entries)
+		}
+	}
+	if _, ok := entries[GPSLatitude]; !ok {
+		return CaptureTime(entries)
+	}
+
+	latitude, longitude, err := ReadGPSPosition(entries)
+	if err != nil {
+		return time.Time{}, err
+	}
+	location, err := resolver.TimeZone(latitude, longitude)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cannot resolve the time zone at %f, %f: %w", latitude, longitude, err)
+	}
+	t, err := CaptureTime(entries)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// the time read in UTC is the local time of the location
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), location), nil
+}
@@ -0,0 +1,83 @@
+package tiff
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptureTimeWithResolver(t *testing.T) {
+	str := func(id EntryID, value string) Entry {
+		return Entry{ID: id, Value: EntryValue{String: &value}}
+	}
+	dms := func(id EntryID, degrees uint32) Entry {
+		return Entry{ID: id, Value: EntryValue{URationals: []URational{{degrees, 1}, {0, 1}, {0, 1}}}}
+	}
+	tokyo := time.FixedZone("JST", 9*3600)
+	resolver := TimeZoneResolverFunc(func(latitude, longitude float64) (*time.Location, error) {
+		if latitude == 35 && longitude == 139 {
+			return tokyo, nil
+		}
+		return nil, errors.New("unknown position")
+	})
+
+	tests := []struct {
+		name    string
+		entries map[EntryID]Entry
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "resolved",
+			entries: map[EntryID]Entry{
+				DateTimeOriginal: str(DateTimeOriginal, "2021:11:19 12:21:10"),
+				GPSLatitude:      dms(GPSLatitude, 35),
+				GPSLongitude:     dms(GPSLongitude, 139),
+			},
+			want: "2021-11-19T03:21:10Z",
+		},
+		{
+			name: "OffsetTimeOriginal",
+			entries: map[EntryID]Entry{
+				DateTimeOriginal:   str(DateTimeOriginal, "2021:11:19 12:21:10"),
+				OffsetTimeOriginal: str(OffsetTimeOriginal, "+01:00"),
+				GPSLatitude:        dms(GPSLatitude, 35),
+				GPSLongitude:       dms(GPSLongitude, 139),
+			},
+			want: "2021-11-19T11:21:10Z",
+		},
+		{
+			name:    "no position",
+			entries: map[EntryID]Entry{DateTimeOriginal: str(DateTimeOriginal, "2021:11:19 12:21:10")},
+			want:    "2021-11-19T12:21:10Z",
+		},
+		{
+			name: "unresolved",
+			entries: map[EntryID]Entry{
+				DateTimeOriginal: str(DateTimeOriginal, "2021:11:19 12:21:10"),
+				GPSLatitude:      dms(GPSLatitude, 0),
+				GPSLongitude:     dms(GPSLongitude, 0),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "missing time",
+			entries: map[EntryID]Entry{GPSLatitude: dms(GPSLatitude, 35), GPSLongitude: dms(GPSLongitude, 139)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CaptureTimeWithResolver(tt.entries, resolver)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.UTC().Format(time.RFC3339))
+		})
+	}
+}
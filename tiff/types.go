@@ -0,0 +1,132 @@
+package tiff
+
+import (
+	"fmt"
+	"slices"
+)
+
+// typeSpec is the data type(s) and count an entry must have according to the TIFF, Exif or DNG specification.
+type typeSpec struct {
+	types []DataType
+	count uint32 // 0 if any count is allowed
+}
+
+var (
+	shortOrLong = []DataType{DataType_UShort, DataType_ULong}
+	text        = []DataType{DataType_String, DataType_UTF8} // Exif 3.0 allows UTF-8 wherever ASCII is expected
+	longOrIFD   = []DataType{DataType_ULong, DataType_IFD}
+)
+
+// typeSpecs lists the type specifications of known entries. GPSInfo entries are listed too: their IDs do not collide
+// with those of other IFDs the parser reads.
+var typeSpecs = map[EntryID]typeSpec{
+	NewSubfileType:            {[]DataType{DataType_ULong}, 1},
+	ImageWidth:                {shortOrLong, 1},
+	ImageHeight:               {shortOrLong, 1},
+	BitsPerSample:             {[]DataType{DataType_UShort}, 0},
+	Compression:               {[]DataType{DataType_UShort}, 1},
+	PhotometricInterpretation: {[]DataType{DataType_UShort}, 1},
+	DocumentName:              {text, 0},
+	ImageDescription:          {text, 0},
+	Make:                      {text, 0},
+	Model:                     {text, 0},
+	StripOffsets:              {shortOrLong, 0},
+	Orientation:               {[]DataType{DataType_UShort}, 1},
+	SamplesPerPixel:           {[]DataType{DataType_UShort}, 1},
+	RowsPerStrip:              {shortOrLong, 1},
+	StripByteCounts:           {shortOrLong, 0},
+	XResolution:               {[]DataType{DataType_URational}, 1},
+	YResolution:               {[]DataType{DataType_URational}, 1},
+	PlanarConfiguration:       {[]DataType{DataType_UShort}, 1},
+	ResolutionUnit:            {[]DataType{DataType_UShort}, 1},
+	Software:                  {text, 0},
+	DateTime:                  {text, 20},
+	Artist:                    {text, 0},
+	HostComputer:              {text, 0},
+	Predictor:                 {[]DataType{DataType_UShort}, 1},
+	ColorMap:                  {[]DataType{DataType_UShort}, 0},
+	TileWidth:                 {shortOrLong, 1},
+	TileLength:                {shortOrLong, 1},
+	TileOffsets:               {[]DataType{DataType_ULong}, 0},
+	TileByteCounts:            {shortOrLong, 0},
+	SubIFDs:                   {longOrIFD, 0},
+	ExtraSamples:              {[]DataType{DataType_UShort}, 0},
+	SampleFormat:              {[]DataType{DataType_UShort}, 0},
+	JPEGTables:                {[]DataType{DataType_UByte_Sequence}, 0},
+	ThumbnailOffset:           {[]DataType{DataType_ULong}, 1},
+	ThumbnailLength:           {[]DataType{DataType_ULong}, 1},
+	YCbCrCoefficients:         {[]DataType{DataType_URational}, 3},
+	YCbCrSubSampling:          {[]DataType{DataType_UShort}, 2},
+	YCbCrPositioning:          {[]DataType{DataType_UShort}, 1},
+	ReferenceBlackWhite:       {[]DataType{DataType_URational}, 6},
+	Rating:                    {[]DataType{DataType_UShort}, 1},
+	RatingPercent:             {[]DataType{DataType_UShort}, 1},
+	Copyright:                 {text, 0},
+	GDALMetadata:              {[]DataType{DataType_String}, 0},
+	GDALNoData:                {[]DataType{DataType_String}, 0},
+	Exif:                      {longOrIFD, 1},
+	GPSInfo:                   {longOrIFD, 1},
+	XPKeywords:                {[]DataType{DataType_UByte}, 0},
+	ExposureTime:              {[]DataType{DataType_URational}, 1},
+	FNumber:                   {[]DataType{DataType_URational}, 1},
+	ISO:                       {[]DataType{DataType_UShort}, 0},
+	ExifVersion:               {[]DataType{DataType_UByte_Sequence}, 4},
+	DateTimeOriginal:          {text, 20},
+	OffsetTime:                {text, 7},
+	OffsetTimeOriginal:        {text, 7},
+	ComponentsConfiguration:   {[]DataType{DataType_UByte_Sequence}, 4},
+	ShutterSpeedValue:         {[]DataType{DataType_Rational}, 1},
+	ApertureValue:             {[]DataType{DataType_URational}, 1},
+	BrightnessValue:           {[]DataType{DataType_Rational}, 1},
+	ExposureBiasValue:         {[]DataType{DataType_Rational}, 1},
+	FocalLength:               {[]DataType{DataType_URational}, 1},
+	MakerNotes:                {[]DataType{DataType_UByte_Sequence}, 0},
+	FlashpixVersion:           {[]DataType{DataType_UByte_Sequence}, 4},
+	ExifImageWidth:            {shortOrLong, 1},
+	ExifImageHeight:           {shortOrLong, 1},
+	FocalPlaneXResolution:     {[]DataType{DataType_URational}, 1},
+	FocalPlaneYResolution:     {[]DataType{DataType_URational}, 1},
+	FocalPlaneResolutionUnit:  {[]DataType{DataType_UShort}, 1},
+	FocalLengthIn35mmFilm:     {[]DataType{DataType_UShort}, 1},
+	ImageUniqueID:             {text, 33},
+	CameraOwnerName:           {text, 0},
+	BodySerialNumber:          {text, 0},
+	LensSerialNumber:          {text, 0},
+	GPSVersionID:              {[]DataType{DataType_UByte}, 4},
+	GPSLatitudeRef:            {text, 2},
+	GPSLatitude:               {[]DataType{DataType_URational}, 3},
+	GPSLongitudeRef:           {text, 2},
+	GPSLongitude:              {[]DataType{DataType_URational}, 3},
+	GPSSpeedRef:               {text, 2},
+	GPSSpeed:                  {[]DataType{DataType_URational}, 1},
+	GPSTrackRef:               {text, 2},
+	GPSTrack:                  {[]DataType{DataType_URational}, 1},
+	GPSImgDirectionRef:        {text, 2},
+	GPSImgDirection:           {[]DataType{DataType_URational}, 1},
+}
+
+// WithStrictTypes makes the parser fail with an error matching ErrBadDataType when the data type or count of a known
+// entry does not match its specification (e.g. ExposureTime must be a single unsigned rational), instead of
+// interpreting its bytes anyway. In lenient mode (the default), such mismatches are reported as warnings by Validate.
+func (p *Parser) WithStrictTypes(strict bool) *Parser {
+	p.strictTypes = strict
+
+	return p
+}
+
+// checkDataType returns an error matching ErrBadDataType if the data type or count of a known entry does not match its
+// specification.
+func checkDataType(id EntryID, dt DataType, length uint32) error {
+	spec, ok := typeSpecs[id]
+	if !ok {
+		return nil
+	}
+	if !slices.Contains(spec.types, dt) {
+		return fmt.Errorf("%w: entry 0x%04x (%s) has data type %d, expected %v", ErrBadDataType, uint16(id), id.Name(), dt, spec.types)
+	}
+	if spec.count != 0 && length != spec.count {
+		return fmt.Errorf("%w: entry 0x%04x (%s) holds %d values, expected %d", ErrBadDataType, uint16(id), id.Name(), length, spec.count)
+	}
+
+	return nil
+}
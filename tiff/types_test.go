@@ -0,0 +1,60 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithStrictTypes(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().
+		WithUints16(uint16(ImageWidth), 256).
+		WithSubIFD(uint16(Exif)).
+		WithUints32(uint16(ExposureTime), 1, 40) // two longs rather than an unsigned rational
+	data := b.Bytes()
+
+	// lenient mode: the value is read as it is, and Validate reports a warning
+	p, err := NewParser(bytes.NewReader(data))
+	assert.NoError(t, err)
+	entries, err := p.Parse(ImageWidth, ExposureTime)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{1, 40}, entries[ExposureTime].Value.Uints32)
+
+	report := p.Validate()
+	assert.True(t, report.Valid())
+	assert.Len(t, report.Issues, 1)
+	assert.Equal(t, Severity_Warning, report.Issues[0].Severity)
+	assert.Equal(t, ExposureTime, report.Issues[0].Entry)
+
+	// strict mode
+	_, err = p.WithStrictTypes(true).Parse(ImageWidth, ExposureTime)
+	assert.ErrorIs(t, err, ErrBadDataType)
+	assert.False(t, p.Validate().Valid())
+
+	entries, err = p.Parse(ImageWidth)
+	assert.NoError(t, err)
+	assert.Contains(t, entries, ImageWidth)
+}
+
+func TestWithStrictTypes_Images(t *testing.T) {
+	for _, image := range [][]byte{cr2Image, orfImage} {
+		p, err := NewParser(bytes.NewReader(image))
+		assert.NoError(t, err)
+
+		_, err = p.WithStrictTypes(true).Find(func(Entry) bool { return true })
+		assert.NoError(t, err)
+	}
+}
+
+func TestCheckDataType(t *testing.T) {
+	assert.NoError(t, checkDataType(ImageWidth, DataType_UShort, 1))
+	assert.NoError(t, checkDataType(ImageWidth, DataType_ULong, 1))
+	assert.ErrorIs(t, checkDataType(ImageWidth, DataType_ULong, 2), ErrBadDataType)
+	assert.ErrorIs(t, checkDataType(GPSLatitude, DataType_Rational, 3), ErrBadDataType)
+	assert.NoError(t, checkDataType(Make, DataType_String, 42))
+	assert.NoError(t, checkDataType(0xbeef, DataType_Double, 7)) // unknown entry
+}
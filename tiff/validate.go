@@ -0,0 +1,387 @@
+package tiff
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Severity tells how serious an Issue is.
+type Severity int
+
+const (
+	Severity_Warning Severity = iota // the file deviates from the specification, but can be read
+	Severity_Error                   // (part of) the file cannot be read
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Severity_Warning:
+		return "warning"
+	case Severity_Error:
+		return "error"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// MarshalText encodes the severity as its name, e.g. in JSON reports.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity encoded by MarshalText.
+func (s *Severity) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "warning":
+		*s = Severity_Warning
+	case "error":
+		*s = Severity_Error
+	default:
+		return fmt.Errorf("unknown severity %q", text)
+	}
+
+	return nil
+}
+
+// Issue is a problem found by Validate.
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Offset   int64    `json:"offset"`          // offset of the IFD, entry or value the issue is about
+	Entry    EntryID  `json:"entry,omitempty"` // entry the issue is about, if any
+	Message  string   `json:"message"`
+}
+
+func (i Issue) String() string {
+	if i.Entry != 0 {
+		return fmt.Sprintf("%s at offset %d (entry 0x%04x): %s", i.Severity, i.Offset, uint16(i.Entry), i.Message)
+	}
+
+	return fmt.Sprintf("%s at offset %d: %s", i.Severity, i.Offset, i.Message)
+}
+
+// Report lists the issues found by Validate, in the order they were found.
+type Report struct {
+	Issues []Issue `json:"issues"`
+}
+
+// Valid tells whether the report holds no errors (it may hold warnings).
+func (r Report) Valid() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == Severity_Error {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Validate checks the structure of the file: the IFDs of the main chain and their sub-IFDs (Exif, GPSInfo and
+// SubIFDs), their entries, and the bounds of the values and image data they point to. Values overlapping IFD tables or
+// other values, a strong sign of corruption or crafting that writers would turn into broken files, are reported as
+// warnings. Unlike the other methods of the
+// parser, it does not stop at the first problem: it reports as many issues as it can find.
+func (p *Parser) Validate() Report {
+	v := &validator{parser: p, seen: make(map[int64]bool)}
+
+	size, err := p.reader.Seek(0, io.SeekEnd)
+	if err != nil {
+		v.errorf(0, 0, "cannot read file size: %v", err)
+		return v.report
+	}
+	v.size = size
+
+	for offset, index := p.firstIFDOffset, 0; offset != 0 && p.follows(pageGroup(index)); index++ {
+		if v.seen[offset] {
+			v.errorf(offset, 0, "IFD chain loops back to offset %d", offset)
+			break
+		}
+		offset = v.validateIFD(offset, pageGroup(index), fmt.Sprintf("IFD#%d", index))
+	}
+	v.validateOverlaps()
+
+	return v.report
+}
+
+// validator collects the issues of a file.
+type validator struct {
+	parser *Parser
+	size   int64
+	report Report
+	seen   map[int64]bool // offsets of the IFDs already visited
+	areas  []area         // IFD tables and values found so far
+}
+
+// area is a range of bytes of the file holding an IFD table or the value of an entry.
+type area struct {
+	offset, length int64
+	id             EntryID // entry whose value it holds, if any
+	name           string
+}
+
+func (v *validator) warnf(offset int64, id EntryID, format string, args ...any) {
+	v.report.Issues = append(v.report.Issues, Issue{Severity: Severity_Warning, Offset: offset, Entry: id, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) errorf(offset int64, id EntryID, format string, args ...any) {
+	v.report.Issues = append(v.report.Issues, Issue{Severity: Severity_Error, Offset: offset, Entry: id, Message: fmt.Sprintf(format, args...)})
+}
+
+// validateIFD validates an IFD of the given group (named after its position, e.g. "IFD#0" or "Exif") and the sub-IFDs
+// the parser reads (see WithGroups), returning the offset of the next IFD (0 if there is none or it cannot be read).
+func (v *validator) validateIFD(offset int64, group Group, name string) int64 {
+	v.seen[offset] = true
+
+	if offset < 8 || offset+2 > v.size {
+		v.errorf(offset, 0, "%s is out of bounds", name)
+		return 0
+	}
+	if offset%2 != 0 {
+		v.warnf(offset, 0, "%s does not start on a word boundary", name)
+	}
+
+	count, err := v.parser.readEntriesCount(offset)
+	if err != nil {
+		v.errorf(offset, 0, "cannot read %s: %v", name, err)
+		return 0
+	}
+	if count == 0 {
+		v.warnf(offset, 0, "%s has no entries", name)
+	}
+	tableSize := int64(count)*EntryLength + 4
+	if offset+2+tableSize > v.size {
+		v.errorf(offset, 0, "%s is truncated: its %d entries end after the end of the file", name, count)
+		return 0
+	}
+
+	v.areas = append(v.areas, area{offset: offset, length: 2 + tableSize, name: name})
+
+	table := make([]byte, tableSize)
+	if _, err := v.parser.reader.Seek(offset+2, io.SeekStart); err != nil {
+		v.errorf(offset, 0, "cannot read %s: %v", name, err)
+		return 0
+	}
+	if _, err := io.ReadFull(v.parser.reader, table); err != nil {
+		v.errorf(offset, 0, "cannot read %s: %v", name, err)
+		return 0
+	}
+
+	entries := make(map[EntryID]Entry, count)
+	var previous EntryID
+	for i := 0; i < int(count); i++ {
+		entryOffset := offset + 2 + int64(i)*EntryLength
+		buffer := table[i*EntryLength : (i+1)*EntryLength]
+		if v.parser.excludes(group, EntryID(v.parser.byteOrder.Uint16(buffer))) {
+			continue
+		}
+		if entry, ok := v.validateEntry(entryOffset, name, buffer); ok {
+			if _, ok := entries[entry.ID]; ok {
+				v.warnf(entryOffset, entry.ID, "duplicate entry in %s", name)
+			} else if entry.ID < previous {
+				v.warnf(entryOffset, entry.ID, "entries of %s are not sorted in ascending order", name)
+			}
+			entries[entry.ID] = entry
+			previous = entry.ID
+		}
+	}
+
+	v.validateImageData(offset, name, entries)
+	v.validateConsistency(offset, name, entries)
+
+	for _, sub := range subIFDPointers {
+		entry, ok := entries[sub.id]
+		if !ok || !v.parser.follows(sub.group) {
+			continue
+		}
+		offsets, err := entry.Uints()
+		if err != nil {
+			v.errorf(offset, sub.id, "%s pointer does not hold an offset", sub.name)
+			continue
+		}
+		for _, subOffset := range offsets {
+			if !v.seen[int64(subOffset)] {
+				v.validateIFD(int64(subOffset), sub.group, sub.name)
+			}
+		}
+	}
+
+	return int64(v.parser.byteOrder.Uint32(table[count*EntryLength:]))
+}
+
+// validateEntry validates the 12 bytes of an entry of the named IFD found at the given offset, returning the entry if
+// its value can be read.
+func (v *validator) validateEntry(offset int64, name string, buffer []byte) (Entry, bool) {
+	id := EntryID(v.parser.byteOrder.Uint16(buffer[:2]))
+	dt := DataType(v.parser.byteOrder.Uint16(buffer[2:4]))
+	length := v.parser.byteOrder.Uint32(buffer[4:8])
+	rawValue := v.parser.byteOrder.Uint32(buffer[8:12])
+
+	if dt.Size() == 0 {
+		v.warnf(offset, id, "unknown data type %d", dt)
+		return Entry{}, false
+	}
+	if size := int64(dt.Size()) * int64(length); size > 4 && int64(rawValue)+size > v.size {
+		v.errorf(offset, id, "value of %d bytes at offset %d ends after the end of the file", size, rawValue)
+		return Entry{}, false
+	}
+
+	entry, err := v.parser.readEntry(buffer)
+	if err != nil {
+		v.errorf(offset, id, "cannot read value: %v", err)
+		return Entry{}, false
+	}
+	if err := checkDataType(id, dt, length); err != nil {
+		v.warnf(offset, id, "%v", err)
+	}
+	if size := int64(dt.Size()) * int64(length); size > 4 {
+		label := id.Name()
+		if label == "" {
+			label = fmt.Sprintf("entry 0x%04x", uint16(id))
+		}
+		v.areas = append(v.areas, area{offset: int64(rawValue), length: size, id: id, name: fmt.Sprintf("value of %s in %s", label, name)})
+	}
+
+	return entry, true
+}
+
+// validateOverlaps reports the areas (IFD tables and values) overlapping each other, with the offsets of both.
+func (v *validator) validateOverlaps() {
+	slices.SortStableFunc(v.areas, func(a, b area) int { return cmp.Compare(a.offset, b.offset) })
+
+	var last area // area reaching the furthest so far
+	for i, a := range v.areas {
+		if i > 0 && a.offset < last.offset+last.length {
+			v.warnf(a.offset, a.id, "%s (%d bytes at offset %d) overlaps %s (%d bytes at offset %d)",
+				a.name, a.length, a.offset, last.name, last.length, last.offset)
+		}
+		if i == 0 || a.offset+a.length > last.offset+last.length {
+			last = a
+		}
+	}
+}
+
+// validateImageData checks that the strips or tiles of an IFD are fully described and within the bounds of the file.
+func (v *validator) validateImageData(offset int64, name string, entries map[EntryID]Entry) {
+	page := Page{Offset: offset, Entries: entries}
+	hasData := false
+
+	for _, ids := range [][2]EntryID{{StripOffsets, StripByteCounts}, {TileOffsets, TileByteCounts}} {
+		if _, ok := entries[ids[0]]; !ok {
+			continue
+		}
+		hasData = true
+
+		offsets, lengths, err := page.chunks(ids[0], ids[1])
+		if err != nil {
+			v.errorf(offset, ids[0], "%s: %v", name, err)
+			continue
+		}
+		for i := range offsets {
+			if int64(offsets[i])+int64(lengths[i]) > v.size {
+				v.errorf(offset, ids[0], "%s: strip or tile #%d ends after the end of the file", name, i)
+			}
+		}
+	}
+
+	if !hasData {
+		return
+	}
+	for _, id := range []EntryID{ImageWidth, ImageHeight} {
+		if _, ok := entries[id]; !ok {
+			v.warnf(offset, id, "%s has image data but no %s", name, id.Name())
+		}
+	}
+}
+
+// validateConsistency checks that related entries of an IFD agree with each other: the number of values of
+// BitsPerSample and SamplesPerPixel, the number of strips or tiles and the dimensions of the image, and the bounds of
+// the thumbnail and its JPEG markers.
+func (v *validator) validateConsistency(offset int64, name string, entries map[EntryID]Entry) {
+	page := Page{Offset: offset, Entries: entries}
+
+	samples, ok := page.Uint(SamplesPerPixel)
+	if !ok {
+		samples = 1
+	}
+	if bits, ok := page.Uints(BitsPerSample); ok && len(bits) != int(samples) {
+		v.warnf(offset, BitsPerSample, "%s: BitsPerSample holds %d values, but SamplesPerPixel is %d", name, len(bits), samples)
+	}
+
+	if id, want, ok := expectedChunks(page, samples); ok {
+		if offsets, ok := page.Uints(id); ok {
+			switch got := uint64(len(offsets)); {
+			case got < want:
+				v.errorf(offset, id, "%s: %s holds %d values, but the dimensions of the image require %d", name, id.Name(), got, want)
+			case got > want:
+				v.warnf(offset, id, "%s: %s holds %d values, but the dimensions of the image only require %d", name, id.Name(), got, want)
+			}
+		}
+	}
+
+	start, ok := page.Uint(ThumbnailOffset)
+	if !ok {
+		return
+	}
+	length, ok := page.Uint(ThumbnailLength)
+	if !ok {
+		v.warnf(offset, ThumbnailOffset, "%s has a ThumbnailOffset but no ThumbnailLength", name)
+		return
+	}
+	if int64(start)+int64(length) > v.size {
+		v.errorf(offset, ThumbnailOffset, "%s: thumbnail of %d bytes at offset %d ends after the end of the file", name, length, start)
+		return
+	}
+	if length < 4 {
+		v.warnf(offset, ThumbnailLength, "%s: thumbnail of %d bytes is too short to be a JPEG image", name, length)
+		return
+	}
+	if head, err := v.parser.readChunk(start, 2); err == nil && !bytes.Equal(head, jpegSOI) {
+		v.warnf(offset, ThumbnailOffset, "%s: thumbnail does not start with a JPEG SOI marker", name)
+	}
+	tailLength := min(length, 16)
+	if tail, err := v.parser.readChunk(start+length-tailLength, tailLength); err == nil && !bytes.HasSuffix(bytes.TrimRight(tail, "\x00"), jpegEOI) {
+		v.warnf(offset, ThumbnailLength, "%s: thumbnail does not end with a JPEG EOI marker (wrong ThumbnailLength?)", name)
+	}
+}
+
+// expectedChunks returns the entry holding the offsets of the strips or tiles of an image, and the number of strips or
+// tiles its dimensions require, if they are known.
+func expectedChunks(page Page, samples uint32) (EntryID, uint64, bool) {
+	width, ok := page.Width()
+	if !ok || width == 0 {
+		return 0, 0, false
+	}
+	height, ok := page.Height()
+	if !ok || height == 0 {
+		return 0, 0, false
+	}
+	planes := uint64(1)
+	if planar, ok := page.Uint(PlanarConfiguration); ok && planar == 2 {
+		planes = uint64(samples)
+	}
+	ceil := func(n, d uint32) uint64 { return (uint64(n) + uint64(d) - 1) / uint64(d) }
+
+	if page.Tiled() {
+		tileWidth, ok := page.Uint(TileWidth)
+		if !ok || tileWidth == 0 {
+			return 0, 0, false
+		}
+		tileLength, ok := page.Uint(TileLength)
+		if !ok || tileLength == 0 {
+			return 0, 0, false
+		}
+		return TileOffsets, ceil(width, tileWidth) * ceil(height, tileLength) * planes, true
+	}
+
+	if _, ok := page.Entries[StripOffsets]; !ok {
+		return 0, 0, false
+	}
+	rows, ok := page.Uint(RowsPerStrip)
+	if !ok || rows == 0 || rows > height {
+		rows = height
+	}
+
+	return StripOffsets, ceil(height, rows) * planes, true
+}
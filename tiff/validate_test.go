@@ -0,0 +1,208 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate_Images(t *testing.T) {
+	for _, image := range [][]byte{cr2Image, orfImage} {
+		p, err := NewParser(bytes.NewReader(image))
+		assert.NoError(t, err)
+
+		report := p.Validate()
+		assert.True(t, report.Valid(), report.Issues)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() *test.TIFFBuilder {
+		b := test.NewTIFFBuilder(binary.LittleEndian)
+		b.AddIFD().
+			WithUints16(uint16(ImageWidth), 2).
+			WithUints16(uint16(ImageHeight), 2).
+			WithData(uint16(StripOffsets), uint16(StripByteCounts), []byte{1, 2, 3, 4})
+		return b
+	}
+
+	tests := []struct {
+		name  string
+		data  func() []byte
+		valid bool
+		want  []string // messages of the expected issues
+	}{
+		{
+			name:  "valid",
+			data:  func() []byte { return valid().Bytes() },
+			valid: true,
+		},
+		{
+			name: "unsorted entries and unknown data type",
+			data: func() []byte {
+				b := test.NewTIFFBuilder(binary.LittleEndian)
+				b.AddIFD().
+					WithUints16(uint16(ImageWidth), 2).
+					WithUints16(uint16(ImageHeight), 2).
+					WithUints16(uint16(BitsPerSample), 8)
+				data := b.Bytes()
+				binary.LittleEndian.PutUint16(data[10:], uint16(Make)) // ID of the first entry
+				binary.LittleEndian.PutUint16(data[36:], 99)           // data type of the third entry
+				return data
+			},
+			valid: true,
+			want: []string{
+				"bad data type: entry 0x010f (Make) has data type 3, expected [2 129]",
+				"entries of IFD#0 are not sorted in ascending order",
+				"unknown data type 99",
+			},
+		},
+		{
+			name: "truncated IFD",
+			data: func() []byte {
+				data := valid().Bytes()
+				return data[:20]
+			},
+			want: []string{"IFD#0 is truncated: its 4 entries end after the end of the file"},
+		},
+		{
+			name: "value out of bounds",
+			data: func() []byte {
+				b := valid()
+				b.AddIFD().WithURationals(uint16(XResolution), 72, 1)
+				data := b.Bytes()
+				ifd1 := binary.LittleEndian.Uint32(data[8+2+4*EntryLength:])
+				binary.LittleEndian.PutUint32(data[ifd1+2+8:], 1000) // offset of the value
+				return data
+			},
+			want: []string{"value of 8 bytes at offset 1000 ends after the end of the file"},
+		},
+		{
+			name: "overlapping values and IFDs",
+			data: func() []byte {
+				b := valid()
+				b.AddIFD().
+					WithURationals(uint16(XResolution), 72, 1).
+					WithURationals(uint16(YResolution), 72, 1).
+					WithURationals(uint16(YCbCrCoefficients), 299, 1000, 587, 1000, 114, 1000)
+				data := b.Bytes()
+				ifd1 := binary.LittleEndian.Uint32(data[8+2+4*EntryLength:])
+				binary.LittleEndian.PutUint32(data[ifd1+2+8:], 8) // XResolution over IFD#0
+				coefficients := binary.LittleEndian.Uint32(data[ifd1+2+2*EntryLength+8:])
+				binary.LittleEndian.PutUint32(data[ifd1+2+EntryLength+8:], coefficients+4) // YResolution over YCbCrCoefficients
+				return data
+			},
+			valid: true,
+			want: []string{
+				"value of XResolution in IFD#1 (8 bytes at offset 8) overlaps IFD#0 (54 bytes at offset 8)",
+				"value of YResolution in IFD#1 (8 bytes at offset 128) overlaps value of YCbCrCoefficients in IFD#1 (24 bytes at offset 124)",
+			},
+		},
+		{
+			name: "strips out of bounds",
+			data: func() []byte {
+				b := valid()
+				b.AddIFD().WithData(uint16(TileOffsets), uint16(TileByteCounts), []byte{1, 2})
+				data := b.Bytes()
+				return data[:len(data)-1]
+			},
+			want: []string{
+				"IFD#1: strip or tile #0 ends after the end of the file",
+				"IFD#1 has image data but no ImageWidth",
+				"IFD#1 has image data but no ImageHeight",
+			},
+		},
+		{
+			name: "inconsistent entries",
+			data: func() []byte {
+				b := test.NewTIFFBuilder(binary.LittleEndian)
+				b.AddIFD().
+					WithUints16(uint16(ImageWidth), 2).
+					WithUints16(uint16(ImageHeight), 4).
+					WithUints16(uint16(BitsPerSample), 8, 8).
+					WithUints16(uint16(SamplesPerPixel), 3).
+					WithUints16(uint16(RowsPerStrip), 1).
+					WithData(uint16(StripOffsets), uint16(StripByteCounts), []byte{1, 2}, []byte{3, 4}).
+					WithData(uint16(ThumbnailOffset), uint16(ThumbnailLength), []byte{0xff, 0xd8, 0xff, 0xd9, 0, 0, 0xff})
+				return b.Bytes()
+			},
+			want: []string{
+				"IFD#0: BitsPerSample holds 2 values, but SamplesPerPixel is 3",
+				"IFD#0: StripOffsets holds 2 values, but the dimensions of the image require 4",
+				"IFD#0: thumbnail does not end with a JPEG EOI marker (wrong ThumbnailLength?)",
+			},
+		},
+		{
+			name: "consistent tiles and padded thumbnail",
+			data: func() []byte {
+				b := test.NewTIFFBuilder(binary.LittleEndian)
+				b.AddIFD().
+					WithUints16(uint16(ImageWidth), 20).
+					WithUints16(uint16(ImageHeight), 10).
+					WithUints16(uint16(TileWidth), 16).
+					WithUints16(uint16(TileLength), 16).
+					WithData(uint16(TileOffsets), uint16(TileByteCounts), []byte{1}, []byte{2}).
+					WithData(uint16(ThumbnailOffset), uint16(ThumbnailLength), []byte{0xff, 0xd8, 0xff, 0xd9, 0, 0})
+				return b.Bytes()
+			},
+			valid: true,
+		},
+		{
+			name: "loop",
+			data: func() []byte {
+				data := valid().Bytes()
+				binary.LittleEndian.PutUint32(data[8+2+4*EntryLength:], 8)
+				return data
+			},
+			want: []string{"IFD chain loops back to offset 8"},
+		},
+		{
+			name: "sub-IFD out of bounds",
+			data: func() []byte {
+				b := valid()
+				b.AddIFD().WithUints32(uint16(Exif), 1000)
+				return b.Bytes()
+			},
+			want: []string{"Exif is out of bounds"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(bytes.NewReader(tt.data()))
+			assert.NoError(t, err)
+
+			report := p.Validate()
+			assert.Equal(t, tt.valid, report.Valid())
+			var messages []string
+			for _, issue := range report.Issues {
+				messages = append(messages, issue.Message)
+			}
+			assert.Equal(t, tt.want, messages)
+		})
+	}
+}
+
+func TestReport_JSON(t *testing.T) {
+	report := Report{Issues: []Issue{
+		{Severity: Severity_Error, Offset: 8, Message: "IFD#0 is out of bounds"},
+		{Severity: Severity_Warning, Offset: 22, Entry: Make, Message: "unknown data type 99"},
+	}}
+
+	data, err := json.Marshal(report)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"issues": [
+		{"severity": "error", "offset": 8, "message": "IFD#0 is out of bounds"},
+		{"severity": "warning", "offset": 22, "entry": 271, "message": "unknown data type 99"}
+	]}`, string(data))
+	var decoded Report
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, report, decoded)
+	assert.Error(t, json.Unmarshal([]byte(`{"issues": [{"severity": "fatal"}]}`), &decoded))
+
+	assert.Equal(t, "warning at offset 22 (entry 0x010f): unknown data type 99", report.Issues[1].String())
+}
@@ -0,0 +1,210 @@
+package tiff
+
+import "fmt"
+
+// ValueKind tells which field of an EntryValue holds the value.
+type ValueKind int
+
+const (
+	ValueKind_None ValueKind = iota // no field is set (e.g. unknown data type)
+	ValueKind_UByte
+	ValueKind_String
+	ValueKind_Uint16
+	ValueKind_Uints16
+	ValueKind_Uint32
+	ValueKind_Uints32
+	ValueKind_URational
+	ValueKind_URationals
+	ValueKind_Byte
+	ValueKind_Bytes
+	ValueKind_Int16
+	ValueKind_Ints16
+	ValueKind_Int32
+	ValueKind_Ints32
+	ValueKind_Rational
+	ValueKind_Rationals
+	ValueKind_Float32
+	ValueKind_Floats32
+	ValueKind_Float64
+	ValueKind_Floats64
+	ValueKind_Strings
+	ValueKind_SBytes
+	ValueKind_Uint64
+	ValueKind_Uints64
+	ValueKind_Int64
+	ValueKind_Ints64
+)
+
+var valueKindNames = [...]string{
+	ValueKind_None:       "None",
+	ValueKind_UByte:      "UByte",
+	ValueKind_String:     "String",
+	ValueKind_Uint16:     "Uint16",
+	ValueKind_Uints16:    "Uints16",
+	ValueKind_Uint32:     "Uint32",
+	ValueKind_Uints32:    "Uints32",
+	ValueKind_URational:  "URational",
+	ValueKind_URationals: "URationals",
+	ValueKind_Byte:       "Byte",
+	ValueKind_Bytes:      "Bytes",
+	ValueKind_Int16:      "Int16",
+	ValueKind_Ints16:     "Ints16",
+	ValueKind_Int32:      "Int32",
+	ValueKind_Ints32:     "Ints32",
+	ValueKind_Rational:   "Rational",
+	ValueKind_Rationals:  "Rationals",
+	ValueKind_Float32:    "Float32",
+	ValueKind_Floats32:   "Floats32",
+	ValueKind_Float64:    "Float64",
+	ValueKind_Floats64:   "Floats64",
+	ValueKind_Strings:    "Strings",
+	ValueKind_SBytes:     "SBytes",
+	ValueKind_Uint64:     "Uint64",
+	ValueKind_Uints64:    "Uints64",
+	ValueKind_Int64:      "Int64",
+	ValueKind_Ints64:     "Ints64",
+}
+
+// String returns the name of the EntryValue field holding values of this kind, e.g. "Uints16".
+func (k ValueKind) String() string {
+	if k >= 0 && int(k) < len(valueKindNames) {
+		return valueKindNames[k]
+	}
+
+	return fmt.Sprintf("ValueKind(%d)", int(k))
+}
+
+// Slice tells whether values of this kind are slices.
+func (k ValueKind) Slice() bool {
+	switch k {
+	case ValueKind_Uints16, ValueKind_Uints32, ValueKind_URationals, ValueKind_Bytes, ValueKind_Ints16, ValueKind_Ints32,
+		ValueKind_Rationals, ValueKind_Floats32, ValueKind_Floats64, ValueKind_Strings, ValueKind_SBytes, ValueKind_Uints64,
+		ValueKind_Ints64:
+		return true
+	default:
+		return false
+	}
+}
+
+// Kind returns the kind of the value, i.e. which of its fields is set (ValueKind_Strings if an ASCII value holds several
+// strings, although String is set too).
+func (v EntryValue) Kind() ValueKind {
+	switch {
+	case v.UByte != nil:
+		return ValueKind_UByte
+	case v.Strings != nil:
+		return ValueKind_Strings
+	case v.String != nil:
+		return ValueKind_String
+	case v.Uint16 != nil:
+		return ValueKind_Uint16
+	case v.Uints16 != nil:
+		return ValueKind_Uints16
+	case v.Uint32 != nil:
+		return ValueKind_Uint32
+	case v.Uints32 != nil:
+		return ValueKind_Uints32
+	case v.URational != nil:
+		return ValueKind_URational
+	case v.URationals != nil:
+		return ValueKind_URationals
+	case v.Byte != nil:
+		return ValueKind_Byte
+	case v.Bytes != nil:
+		return ValueKind_Bytes
+	case v.SBytes != nil:
+		return ValueKind_SBytes
+	case v.Int16 != nil:
+		return ValueKind_Int16
+	case v.Ints16 != nil:
+		return ValueKind_Ints16
+	case v.Int32 != nil:
+		return ValueKind_Int32
+	case v.Ints32 != nil:
+		return ValueKind_Ints32
+	case v.Rational != nil:
+		return ValueKind_Rational
+	case v.Rationals != nil:
+		return ValueKind_Rationals
+	case v.Float32 != nil:
+		return ValueKind_Float32
+	case v.Floats32 != nil:
+		return ValueKind_Floats32
+	case v.Float64 != nil:
+		return ValueKind_Float64
+	case v.Floats64 != nil:
+		return ValueKind_Floats64
+	case v.Uint64 != nil:
+		return ValueKind_Uint64
+	case v.Uints64 != nil:
+		return ValueKind_Uints64
+	case v.Int64 != nil:
+		return ValueKind_Int64
+	case v.Ints64 != nil:
+		return ValueKind_Ints64
+	default:
+		return ValueKind_None
+	}
+}
+
+// Interface returns the value held by the field that is set, as a Go value: scalars are dereferenced (e.g. uint16
+// rather than *uint16, int8 for signed bytes), slices are returned as they are (e.g. []URational). It returns nil if no
+// field is set.
+func (v EntryValue) Interface() any {
+	switch v.Kind() {
+	case ValueKind_UByte:
+		return *v.UByte
+	case ValueKind_String:
+		return *v.String
+	case ValueKind_Uint16:
+		return *v.Uint16
+	case ValueKind_Uints16:
+		return v.Uints16
+	case ValueKind_Uint32:
+		return *v.Uint32
+	case ValueKind_Uints32:
+		return v.Uints32
+	case ValueKind_URational:
+		return *v.URational
+	case ValueKind_URationals:
+		return v.URationals
+	case ValueKind_Byte:
+		return int8(*v.Byte)
+	case ValueKind_Bytes:
+		return v.Bytes
+	case ValueKind_Int16:
+		return *v.Int16
+	case ValueKind_Ints16:
+		return v.Ints16
+	case ValueKind_Int32:
+		return *v.Int32
+	case ValueKind_Ints32:
+		return v.Ints32
+	case ValueKind_Rational:
+		return *v.Rational
+	case ValueKind_Rationals:
+		return v.Rationals
+	case ValueKind_Float32:
+		return *v.Float32
+	case ValueKind_Floats32:
+		return v.Floats32
+	case ValueKind_Float64:
+		return *v.Float64
+	case ValueKind_Floats64:
+		return v.Floats64
+	case ValueKind_Strings:
+		return v.Strings
+	case ValueKind_SBytes:
+		return v.SBytes
+	case ValueKind_Uint64:
+		return *v.Uint64
+	case ValueKind_Uints64:
+		return v.Uints64
+	case ValueKind_Int64:
+		return *v.Int64
+	case ValueKind_Ints64:
+		return v.Ints64
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,52 @@
+package tiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntryValue_Interface(t *testing.T) {
+	u16, s, b := uint16(5184), "Canon", byte(0xff)
+	r := Rational{Numerator: -1, Denominator: 3}
+
+	tests := []struct {
+		value EntryValue
+		kind  ValueKind
+		want  any
+	}{
+		{EntryValue{}, ValueKind_None, nil},
+		{EntryValue{Uint16: &u16}, ValueKind_Uint16, uint16(5184)},
+		{EntryValue{String: &s}, ValueKind_String, "Canon"},
+		{EntryValue{Byte: &b}, ValueKind_Byte, int8(-1)},
+		{EntryValue{SBytes: []int8{-1, 2}}, ValueKind_SBytes, []int8{-1, 2}},
+		{EntryValue{Rational: &r}, ValueKind_Rational, r},
+		{EntryValue{Uints32: []uint32{1, 2}}, ValueKind_Uints32, []uint32{1, 2}},
+		{EntryValue{URationals: []URational{{1, 2}}}, ValueKind_URationals, []URational{{1, 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind.String(), func(t *testing.T) {
+			assert.Equal(t, tt.kind, tt.value.Kind())
+			assert.Equal(t, tt.want, tt.value.Interface())
+		})
+	}
+
+	assert.True(t, ValueKind_Floats64.Slice())
+	assert.False(t, ValueKind_Float64.Slice())
+	assert.Equal(t, "ValueKind(99)", ValueKind(99).String())
+}
+
+func TestEntryValue_Interface_CR2(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+
+	entries, err := p.Parse(ImageWidth, BitsPerSample, Make, ExposureTime)
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint16(5184), entries[ImageWidth].Value.Interface())
+	assert.Equal(t, []uint16{8, 8, 8}, entries[BitsPerSample].Value.Interface())
+	assert.Equal(t, "Canon", entries[Make].Value.Interface())
+	assert.Equal(t, URational{Numerator: 1, Denominator: 40}, entries[ExposureTime].Value.Interface())
+}
@@ -0,0 +1,139 @@
+package tiff
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// Vendor is the manufacturer of the camera that wrote a file, as told by its Make.
+type Vendor int
+
+const (
+	Vendor_Unknown Vendor = iota
+	Vendor_Canon
+	Vendor_Fujifilm
+	Vendor_Nikon
+	Vendor_Olympus
+	Vendor_Panasonic
+	Vendor_Pentax
+	Vendor_Sony
+)
+
+func (v Vendor) String() string {
+	if profile, ok := vendorProfiles[v]; ok {
+		return profile.name
+	}
+
+	return "Unknown"
+}
+
+// vendorProfile describes the quirks of the files written by the cameras of a vendor.
+type vendorProfile struct {
+	name     string
+	makes    []string                           // prefixes of the Make of its cameras, in upper case
+	previews func(p *Parser) []previewCandidate // finds previews stored outside of IFDs (see Previews)
+	rawIFD   func(p *Parser) (int64, bool)      // finds the IFD of the raw image, when IFDs do not tell (see MainImage)
+}
+
+var vendorProfiles = map[Vendor]vendorProfile{
+	Vendor_Canon:     {name: "Canon", makes: []string{"CANON"}, rawIFD: canonRawIFD},
+	Vendor_Fujifilm:  {name: "Fujifilm", makes: []string{"FUJIFILM"}},
+	Vendor_Nikon:     {name: "Nikon", makes: []string{"NIKON"}},
+	Vendor_Olympus:   {name: "Olympus", makes: []string{"OLYMPUS", "OM DIGITAL"}, previews: olympusPreviews},
+	Vendor_Panasonic: {name: "Panasonic", makes: []string{"PANASONIC"}},
+	Vendor_Pentax:    {name: "Pentax", makes: []string{"PENTAX", "RICOH IMAGING"}},
+	Vendor_Sony:      {name: "Sony", makes: []string{"SONY"}},
+}
+
+// VendorOf returns the vendor of a camera given its Make (e.g. "OLYMPUS CORPORATION    " or "NIKON CORPORATION"),
+// ignoring case and surrounding spaces, or Vendor_Unknown if it is not one of the known vendors.
+func VendorOf(make string) Vendor {
+	make = strings.ToUpper(strings.TrimSpace(strings.TrimRight(make, "\x00")))
+	for vendor, profile := range vendorProfiles {
+		for _, prefix := range profile.makes {
+			if strings.HasPrefix(make, prefix) {
+				return vendor
+			}
+		}
+	}
+
+	return Vendor_Unknown
+}
+
+// Vendor returns the vendor of the camera that wrote the file, as told by the Make entry of IFD#0 (see VendorOf), or
+// Vendor_Unknown if it is missing or cannot be read. It is read once, on the first call.
+func (p *Parser) Vendor() Vendor {
+	if !p.vendorRead {
+		p.vendorRead = true
+		if entries, err := p.Parse(Make); err == nil {
+			if make, ok := entries[Make]; ok && make.Value.String != nil {
+				p.vendor = VendorOf(*make.Value.String)
+			}
+		}
+	}
+
+	return p.vendor
+}
+
+// Entries of the CameraSettings IFD of Olympus maker notes
+const (
+	olympusCameraSettings     EntryID = 0x2020
+	olympusPreviewImageValid  EntryID = 0x0100
+	olympusPreviewImageStart  EntryID = 0x0101
+	olympusPreviewImageLength EntryID = 0x0102
+)
+
+// olympusPreviews finds the preview that Olympus cameras store in the CameraSettings IFD of their maker note, rather
+// than in IFD#0 or IFD#1: ORF files have no other. Maker notes that cannot be read are ignored.
+func olympusPreviews(p *Parser) []previewCandidate {
+	m, err := p.MakerNote()
+	if err != nil || m.Vendor != "Olympus" {
+		return nil
+	}
+	entries, err := m.Entries(p.reader)
+	if err != nil {
+		return nil
+	}
+	settings, ok := entries[olympusCameraSettings]
+	if !ok {
+		return nil
+	}
+
+	offset := int64(settings.RawValue)
+	wanted := newWanted(olympusPreviewImageValid, olympusPreviewImageStart, olympusPreviewImageLength)
+	pg := Page{Offset: m.Base + offset}
+	if pg.Entries, err = m.Parser(p.reader).collect(offset, Group_MakerNote, wanted); err != nil {
+		return nil
+	}
+	if valid, ok := pg.Uint(olympusPreviewImageValid); ok && valid == 0 {
+		return nil
+	}
+	start, ok := pg.Uint(olympusPreviewImageStart)
+	if !ok {
+		return nil
+	}
+	length, ok := pg.Uint(olympusPreviewImageLength)
+	if !ok {
+		return nil
+	}
+
+	return []previewCandidate{{
+		Preview: Preview{Group: Group_MakerNote, IFDOffset: pg.Offset, Offset: m.Base + int64(start), Length: int64(length)},
+	}}
+}
+
+// canonRawIFD reads the offset of the IFD of the raw image from the header of CR2 files, which follows the TIFF header:
+// "CR", the major and minor versions of the format, then the offset. The raw image is the last IFD of the main chain,
+// and has neither NewSubfileType nor dimensions.
+func canonRawIFD(p *Parser) (int64, bool) {
+	header := make([]byte, 8)
+	if _, err := p.reader.Seek(8, io.SeekStart); err != nil {
+		return 0, false
+	}
+	if _, err := io.ReadFull(p.reader, header); err != nil || !bytes.HasPrefix(header, []byte("CR")) {
+		return 0, false
+	}
+
+	return int64(p.byteOrder.Uint32(header[4:])), true
+}
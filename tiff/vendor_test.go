@@ -0,0 +1,58 @@
+package tiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVendorOf(t *testing.T) {
+	tests := []struct {
+		make string
+		want Vendor
+	}{
+		{"Canon", Vendor_Canon},
+		{"OLYMPUS CORPORATION    ", Vendor_Olympus},
+		{"OM Digital Solutions", Vendor_Olympus},
+		{"NIKON CORPORATION\x00", Vendor_Nikon},
+		{" SONY ", Vendor_Sony},
+		{"RICOH IMAGING COMPANY, LTD.", Vendor_Pentax},
+		{"Apple", Vendor_Unknown},
+		{"", Vendor_Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.make, func(t *testing.T) {
+			assert.Equal(t, tt.want, VendorOf(tt.make))
+		})
+	}
+}
+
+func TestParser_Vendor(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	assert.Equal(t, Vendor_Canon, p.Vendor())
+	assert.Equal(t, "Canon", p.Vendor().String())
+
+	p, err = NewParser(bytes.NewReader(orfImage))
+	assert.NoError(t, err)
+	assert.Equal(t, Vendor_Olympus, p.Vendor())
+	assert.Equal(t, "Unknown", Vendor_Unknown.String())
+}
+
+func TestParser_Previews_Olympus(t *testing.T) {
+	p, err := NewParser(bytes.NewReader(orfImage))
+	assert.NoError(t, err)
+
+	previews, err := p.Previews()
+	assert.NoError(t, err)
+	if assert.Len(t, previews, 1) {
+		assert.Equal(t, Group_MakerNote, previews[0].Group)
+		assert.Equal(t, 3200, previews[0].Width)
+	}
+
+	thumbnail, err := p.ReadThumbnail()
+	assert.NoError(t, err)
+	assert.Equal(t, jpegSOI, thumbnail[:2])
+}
@@ -0,0 +1,134 @@
+package tiff
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// customGroups holds the names of the groups created with RegisterGroup, by group.
+var customGroups = struct {
+	sync.Mutex
+	names []string
+}{}
+
+// firstCustomGroup is the first group RegisterGroup returns.
+const firstCustomGroup = Group_MakerNote + 1
+
+// RegisterGroup returns a new group, named name (e.g. "Olympus Equipment"), for the entries of an IFD the parser does
+// not know about, such as the sub-IFDs of maker notes (see WithVendorIFD). It panics if too many groups are registered.
+func RegisterGroup(name string) Group {
+	customGroups.Lock()
+	defer customGroups.Unlock()
+
+	if int(firstCustomGroup)+len(customGroups.names) > 0xff {
+		panic("tiff: too many groups registered")
+	}
+	customGroups.names = append(customGroups.names, name)
+
+	return firstCustomGroup + Group(len(customGroups.names)-1)
+}
+
+// customGroupName returns the name of a group created with RegisterGroup.
+func customGroupName(g Group) (string, bool) {
+	customGroups.Lock()
+	defer customGroups.Unlock()
+
+	if i := int(g) - int(firstCustomGroup); i >= 0 && i < len(customGroups.names) {
+		return customGroups.names[i], true
+	}
+
+	return "", false
+}
+
+// vendorIFD is an IFD pointed to by an entry of the maker note (see WithVendorIFD).
+type vendorIFD struct {
+	vendor  Vendor
+	pointer EntryID
+}
+
+// WithVendorIFD tells the parser that, in files written by cameras of the given vendor (see Vendor), the pointer entry
+// of the IFD of the maker note points to an IFD whose entries belong to group (created with RegisterGroup), so that
+// Parse returns the entries mapped to that group (see WithMapping) by reading that IFD, e.g. for Olympus maker notes:
+//
+//	equipment := tiff.RegisterGroup("Olympus Equipment")
+//	p.WithVendorIFD(tiff.Vendor_Olympus, 0x2010, equipment).WithMapping(map[tiff.EntryID]tiff.Group{0x0202: equipment})
+//	entries, err := p.Parse(0x0202) // LensSerialNumber
+//
+// Entries mapped to Group_MakerNote are read from the IFD of the maker note itself. Like those of the maker note, the
+// offsets of the entries of these IFDs are relative to the base of the maker note (see MakerNote), and their values are
+// neither normalized nor checked against the types of the entries of the same IDs. Parse ignores these groups for files
+// written by other vendors, or whose maker note cannot be read.
+func (p *Parser) WithVendorIFD(vendor Vendor, pointer EntryID, group Group) *Parser {
+	if p.vendorIFDs == nil {
+		p.vendorIFDs = make(map[Group]vendorIFD)
+	}
+	p.vendorIFDs[group] = vendorIFD{vendor: vendor, pointer: pointer}
+
+	return p
+}
+
+// eachVendorEntry calls fn with each wanted entry of the maker note IFD (Group_MakerNote) and of the IFDs declared with
+// WithVendorIFD, by group.
+func (p *Parser) eachVendorEntry(wanted map[Group]*wanted, fn func(Entry) error) error {
+	m, err := p.MakerNote()
+	if errors.Is(err, ErrTruncated) {
+		return err
+	} else if err != nil {
+		// no maker note, or one that cannot be read
+		return nil
+	}
+
+	mp := m.Parser(p.reader)
+	// limits and decoding options apply, but not those that depend on the meaning of IDs in the main IFDs
+	mp.maxValueSize, mp.maxEntriesPerIFD, mp.skipValueSize = p.maxValueSize, p.maxEntriesPerIFD, p.skipValueSize
+	mp.trim, mp.textDecoder = p.trim, p.textDecoder
+
+	// the IFD of the maker note is scanned for the wanted entries, and for the pointers to the wanted IFDs
+	scanned := newWanted()
+	makerNoteWanted := wanted[Group_MakerNote]
+	if makerNoteWanted != nil {
+		for id := range makerNoteWanted.ids {
+			scanned.Put(id)
+		}
+	}
+	pointers := make(map[EntryID]bool)
+	for group := range wanted {
+		if ifd, ok := p.vendorIFDs[group]; ok && ifd.vendor == p.Vendor() {
+			pointers[ifd.pointer] = true
+			scanned.Put(ifd.pointer)
+		}
+	}
+
+	subOffsets := make(map[EntryID]int64)
+	err = mp.each(m.Offset-m.Base, Group_MakerNote, scanned, func(entry Entry) error {
+		if pointers[entry.ID] {
+			// the pointer is either the offset of the IFD, or an UNDEFINED value holding it: either way, RawValue
+			// is where the IFD starts
+			subOffsets[entry.ID] = int64(entry.RawValue)
+		}
+		if makerNoteWanted != nil && makerNoteWanted.Contains(entry.ID) {
+			return fn(entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("maker note: %w", err)
+	}
+
+	for group, w := range wanted {
+		ifd, ok := p.vendorIFDs[group]
+		if !ok {
+			continue
+		}
+		subOffset, ok := subOffsets[ifd.pointer]
+		if !ok {
+			continue
+		}
+		if err := mp.each(subOffset, group, w, fn); err != nil {
+			return fmt.Errorf("%s: %w", group, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package tiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_WithVendorIFD(t *testing.T) {
+	settings := RegisterGroup("Olympus CameraSettings")
+	assert.Equal(t, "Olympus CameraSettings", settings.String())
+
+	p, err := NewParser(bytes.NewReader(orfImage))
+	assert.NoError(t, err)
+	p.WithVendorIFD(Vendor_Olympus, olympusCameraSettings, settings).WithMapping(map[EntryID]Group{
+		olympusPreviewImageStart:  settings,
+		olympusPreviewImageLength: settings,
+		olympusCameraSettings:     Group_MakerNote,
+	})
+	// the mapping of the parser is its own
+	assert.Equal(t, Group_IFD0, Defaults[ImageHeight])
+
+	entries, err := p.Parse(Make, olympusPreviewImageStart, olympusPreviewImageLength, olympusCameraSettings)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 4)
+	assert.Equal(t, Group_MakerNote, entries[olympusCameraSettings].Group)
+
+	start, length := entries[olympusPreviewImageStart], entries[olympusPreviewImageLength]
+	assert.Equal(t, settings, start.Group)
+	assert.Equal(t, settings, length.Group)
+	assert.Equal(t, int64(entries[olympusCameraSettings].RawValue), start.IFDOffset)
+
+	m, err := p.MakerNote()
+	assert.NoError(t, err)
+	previews := olympusPreviews(p)
+	assert.Len(t, previews, 1)
+	assert.Equal(t, previews[0].Offset, m.Base+int64(*start.Value.Uint32))
+	assert.Equal(t, previews[0].Length, int64(*length.Value.Uint32))
+}
+
+func TestParser_WithVendorIFD_OtherVendor(t *testing.T) {
+	settings := RegisterGroup("Olympus CameraSettings")
+
+	p, err := NewParser(bytes.NewReader(cr2Image))
+	assert.NoError(t, err)
+	p.WithVendorIFD(Vendor_Olympus, olympusCameraSettings, settings).WithMapping(map[EntryID]Group{
+		olympusPreviewImageStart: settings,
+	})
+
+	entries, err := p.Parse(Model, olympusPreviewImageStart)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Contains(t, entries, Model)
+}
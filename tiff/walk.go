@@ -0,0 +1,78 @@
+package tiff
+
+// subIFDPointers lists the entries pointing to sub-IFDs, with the group of the entries of those sub-IFDs and the name
+// of the latter.
+var subIFDPointers = []struct {
+	id    EntryID
+	group Group
+	name  string
+}{
+	{Exif, Group_Exif, "Exif"},
+	{GPSInfo, Group_GPSInfo, "GPSInfo"},
+	{SubIFDs, Group_SubIFD, "SubIFD"},
+}
+
+// pageGroup returns the group of the entries of a page of the main IFD chain.
+func pageGroup(index int) Group {
+	switch index {
+	case 0:
+		return Group_IFD0
+	case 1:
+		return Group_IFD1
+	default:
+		return Group_Image
+	}
+}
+
+// walk visits every IFD of the file once: each IFD of the main chain, followed by its sub-IFDs (Exif, GPSInfo and
+// SubIFDs, recursively), leaving out those the parser does not read (see WithGroups). It stops at the first error,
+// returned by visit or met while reading IFDs.
+func (p *Parser) walk(visit func(offset int64, group Group, entries map[EntryID]Entry) error) error {
+	pages, err := p.Pages()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[int64]bool)
+	var visitIFD func(offset int64, group Group, entries map[EntryID]Entry) error
+	visitIFD = func(offset int64, group Group, entries map[EntryID]Entry) error {
+		if seen[offset] {
+			return nil
+		}
+		seen[offset] = true
+
+		if err := visit(offset, group, entries); err != nil {
+			return err
+		}
+
+		for _, pointer := range subIFDPointers {
+			entry, ok := entries[pointer.id]
+			if !ok || !p.follows(pointer.group) {
+				continue
+			}
+			offsets, err := entry.Uints()
+			if err != nil {
+				return err
+			}
+			for _, subOffset := range offsets {
+				subEntries, _, err := p.readIFD(int64(subOffset), pointer.group)
+				if err != nil {
+					return err
+				}
+				if err := visitIFD(int64(subOffset), pointer.group, subEntries); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	for _, page := range pages {
+		if err := visitIFD(page.Offset, pageGroup(page.Index), page.Entries); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
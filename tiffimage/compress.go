@@ -0,0 +1,310 @@
+package tiffimage
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+)
+
+// Compression schemes, as written in the Compression entry.
+const (
+	Compression_None         uint32 = 1
+	Compression_LZW          uint32 = 5
+	Compression_JPEG         uint32 = 7
+	Compression_AdobeDeflate uint32 = 8
+	Compression_PackBits     uint32 = 32773
+	Compression_Deflate      uint32 = 32946
+)
+
+const (
+	lzwClear    = 256
+	lzwEOI      = 257
+	lzwFirst    = 258
+	lzwMaxCode  = 4095
+	lzwMinWidth = 9
+	lzwMaxWidth = 12
+)
+
+// maxCompressionRatios bounds, for each compression scheme, the ratio between the size of the samples a strip or tile
+// decompresses to and its own size, however crafted it is.
+var maxCompressionRatios = map[uint32]uint64{
+	Compression_None:         1,
+	Compression_PackBits:     64,   // a run of 128 bytes takes 2 bytes
+	Compression_LZW:          4096, // a code takes at least 9 bits, and stands for at most 4096 bytes
+	Compression_AdobeDeflate: 1032, // the largest ratio Deflate can reach
+	Compression_Deflate:      1032,
+	Compression_JPEG:         1024, // a block of 64 samples takes at least 2 bits, with chroma subsampling undone
+}
+
+// decompress decompresses a strip or tile. jpegTables holds the tables shared by JPEG-compressed strips or tiles, if
+// any (see decodeJPEG).
+func decompress(compression uint32, src, jpegTables []byte) ([]byte, error) {
+	switch compression {
+	case Compression_None:
+		return src, nil
+	case Compression_JPEG:
+		return decodeJPEG(src, jpegTables)
+	case Compression_LZW:
+		return decodeLZW(src)
+	case Compression_AdobeDeflate, Compression_Deflate:
+		r, err := zlib.NewReader(bytes.NewReader(src))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	case Compression_PackBits:
+		return decodePackBits(src)
+	default:
+		return nil, fmt.Errorf("unsupported compression: %d", compression)
+	}
+}
+
+// decodeJPEG decodes a JPEG-compressed strip or tile (see TIFF Technical Note 2) to 8-bit samples, YCbCr ones being
+// converted to RGB. Tables shared by all strips or tiles are stored once in the JPEGTables entry, as an abbreviated
+// JPEG stream holding only tables (SOI, DQT and DHT segments, EOI): they are merged into the stream of the strip or
+// tile, which then lacks them, before decoding it.
+func decodeJPEG(src, tables []byte) ([]byte, error) {
+	soi, eoi := []byte{0xff, 0xd8}, []byte{0xff, 0xd9}
+	if len(tables) > 0 {
+		merged := bytes.TrimSuffix(tables, eoi)
+		merged = append(merged[:len(merged):len(merged)], bytes.TrimPrefix(src, soi)...)
+		src = merged
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	switch img := img.(type) {
+	case *image.Gray:
+		out := make([]byte, 0, bounds.Dx()*bounds.Dy())
+		for y := 0; y < bounds.Dy(); y++ {
+			out = append(out, img.Pix[y*img.Stride:y*img.Stride+bounds.Dx()]...)
+		}
+		return out, nil
+	case *image.CMYK:
+		out := make([]byte, 0, 4*bounds.Dx()*bounds.Dy())
+		for y := 0; y < bounds.Dy(); y++ {
+			out = append(out, img.Pix[y*img.Stride:y*img.Stride+4*bounds.Dx()]...)
+		}
+		return out, nil
+	case *image.YCbCr:
+		out := make([]byte, 0, 3*bounds.Dx()*bounds.Dy())
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				yi, ci := img.YOffset(x, y), img.COffset(x, y)
+				r, g, b := color.YCbCrToRGB(img.Y[yi], img.Cb[ci], img.Cr[ci])
+				out = append(out, r, g, b)
+			}
+		}
+		return out, nil
+	case *image.RGBA:
+		out := make([]byte, 0, 3*bounds.Dx()*bounds.Dy())
+		for i := 0; i < bounds.Dx()*bounds.Dy(); i++ {
+			p := img.Pix[(i/bounds.Dx())*img.Stride+4*(i%bounds.Dx()):]
+			out = append(out, p[0], p[1], p[2])
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unsupported JPEG image: %T", img)
+	}
+}
+
+// decodeLZW decodes TIFF's flavour of LZW: codes are written MSB-first and their width grows one code earlier than in
+// the GIF flavour implemented by compress/lzw.
+func decodeLZW(src []byte) ([]byte, error) {
+	var (
+		prefix [lzwMaxCode + 1]uint16
+		suffix [lzwMaxCode + 1]byte
+		length [lzwMaxCode + 1]uint16
+	)
+	for i := 0; i < 256; i++ {
+		suffix[i] = byte(i)
+		length[i] = 1
+	}
+
+	out := make([]byte, 0, 3*len(src))
+	width, next, prev := lzwMinWidth, lzwFirst, -1
+	var bits uint32
+	var numBits, pos int
+
+	for {
+		for numBits < width {
+			if pos >= len(src) {
+				return out, nil // some writers omit the EOI code
+			}
+			bits = bits<<8 | uint32(src[pos])
+			pos++
+			numBits += 8
+		}
+		code := int(bits>>(numBits-width)) & (1<<width - 1)
+		numBits -= width
+
+		switch {
+		case code == lzwClear:
+			width, next, prev = lzwMinWidth, lzwFirst, -1
+			continue
+		case code == lzwEOI:
+			return out, nil
+		case prev == -1:
+			if code > 255 {
+				return nil, fmt.Errorf("invalid LZW code %d after clear code", code)
+			}
+			out = append(out, byte(code))
+			prev = code
+			continue
+		}
+
+		start := len(out)
+		switch {
+		case code < next:
+			out = appendLZWEntry(out, code, &prefix, &suffix, &length)
+		case code == next:
+			out = appendLZWEntry(out, prev, &prefix, &suffix, &length)
+			out = append(out, out[start])
+		default:
+			return nil, fmt.Errorf("invalid LZW code %d", code)
+		}
+
+		if next <= lzwMaxCode {
+			prefix[next] = uint16(prev)
+			suffix[next] = out[start]
+			length[next] = length[prev] + 1
+			next++
+		}
+		if next >= 1<<width-1 && width < lzwMaxWidth {
+			width++
+		}
+		prev = code
+	}
+}
+
+// appendLZWEntry appends the string represented by a code to out.
+func appendLZWEntry(out []byte, code int, prefix *[lzwMaxCode + 1]uint16, suffix *[lzwMaxCode + 1]byte, length *[lzwMaxCode + 1]uint16) []byte {
+	n := int(length[code])
+	out = append(out, make([]byte, n)...)
+	for i := len(out) - 1; i >= len(out)-n; i-- {
+		out[i] = suffix[code]
+		code = int(prefix[code])
+	}
+
+	return out
+}
+
+// decodePackBits decodes Apple's PackBits run-length encoding.
+func decodePackBits(src []byte) ([]byte, error) {
+	out := make([]byte, 0, 2*len(src))
+	for i := 0; i < len(src); {
+		n := int(int8(src[i]))
+		i++
+		switch {
+		case n >= 0:
+			if i+n+1 > len(src) {
+				return nil, errors.New("truncated PackBits literal run")
+			}
+			out = append(out, src[i:i+n+1]...)
+			i += n + 1
+		case n > -128:
+			if i >= len(src) {
+				return nil, errors.New("truncated PackBits repeat run")
+			}
+			out = append(out, bytes.Repeat(src[i:i+1], 1-n)...)
+			i++
+		}
+	}
+
+	return out, nil
+}
+
+// compress compresses a strip or tile.
+func compress(compression uint32, src []byte) ([]byte, error) {
+	switch compression {
+	case Compression_None:
+		return src, nil
+	case Compression_LZW:
+		return encodeLZW(src), nil
+	case Compression_AdobeDeflate, Compression_Deflate:
+		var buf bytes.Buffer
+		w := zlib.NewWriter(&buf)
+		if _, err := w.Write(src); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression: %d", compression)
+	}
+}
+
+// encodeLZW encodes data with TIFF's flavour of LZW (see decodeLZW). Since the decoder only adds a string to its table
+// when it reads the code following the one of that string, the encoder grows the width of codes one code after the
+// decoder does.
+func encodeLZW(src []byte) []byte {
+	out := make([]byte, 0, len(src)/2)
+	var bits uint32
+	var numBits int
+	write := func(code, width int) {
+		bits = bits<<width | uint32(code)
+		numBits += width
+		for numBits >= 8 {
+			out = append(out, byte(bits>>(numBits-8)))
+			numBits -= 8
+		}
+	}
+
+	table := make(map[uint32]int)
+	width, next := lzwMinWidth, lzwFirst
+	write(lzwClear, width)
+	if len(src) == 0 {
+		write(lzwEOI, width)
+		return flushLZW(out, bits, numBits)
+	}
+
+	prefix := int(src[0])
+	for _, c := range src[1:] {
+		key := uint32(prefix)<<8 | uint32(c)
+		if code, ok := table[key]; ok {
+			prefix = code
+			continue
+		}
+
+		write(prefix, width)
+		table[key] = next
+		next++
+		if next == lzwMaxCode-1 {
+			write(lzwClear, width)
+			clear(table)
+			width, next = lzwMinWidth, lzwFirst
+		} else if next >= 1<<width {
+			width++
+		}
+		prefix = int(c)
+	}
+
+	write(prefix, width)
+	if next+1 >= 1<<width && width < lzwMaxWidth {
+		width++ // the decoder adds a string when reading the last code
+	}
+	write(lzwEOI, width)
+
+	return flushLZW(out, bits, numBits)
+}
+
+// flushLZW appends the remaining bits (if any) to out, padded with zeros.
+func flushLZW(out []byte, bits uint32, numBits int) []byte {
+	if numBits > 0 {
+		out = append(out, byte(bits<<(8-numBits)))
+	}
+
+	return out
+}
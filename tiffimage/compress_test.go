@@ -0,0 +1,198 @@
+package tiffimage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// packCodes packs LZW codes MSB-first, with the given widths.
+func packCodes(codes []int, widths []int) []byte {
+	var out []byte
+	var bits uint64
+	var n int
+	for i, code := range codes {
+		bits = bits<<widths[i] | uint64(code)
+		n += widths[i]
+		for n >= 8 {
+			out = append(out, byte(bits>>(n-8)))
+			n -= 8
+		}
+	}
+	if n > 0 {
+		out = append(out, byte(bits<<(8-n)))
+	}
+
+	return out
+}
+
+func TestDecodeLZW(t *testing.T) {
+	tests := []struct {
+		name    string
+		codes   []int
+		want    []byte
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			"decodes literals and repeated strings",
+			[]int{lzwClear, 'a', 'b', 258, 260, lzwEOI},
+			[]byte("abababa"),
+			assert.NoError,
+		},
+		{
+			"decodes a code referring to the entry being defined",
+			[]int{lzwClear, 1, 258, 259, lzwEOI},
+			[]byte{1, 1, 1, 1, 1, 1},
+			assert.NoError,
+		},
+		{
+			"stops at the end of data when EOI is missing",
+			[]int{lzwClear, 'x'},
+			[]byte("x"),
+			assert.NoError,
+		},
+		{
+			"returns an error on an undefined code",
+			[]int{lzwClear, 'a', 300, lzwEOI},
+			nil,
+			assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			widths := make([]int, len(tt.codes))
+			for i := range widths {
+				widths[i] = 9
+			}
+			got, err := decodeLZW(packCodes(tt.codes, widths))
+			if !tt.wantErr(t, err) {
+				return
+			}
+			if tt.want != nil {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDecodeLZW_WidthChange(t *testing.T) {
+	// 253 literals after the first one fill the table up to code 510, so that the code following them is 10 bits wide
+	codes := []int{lzwClear}
+	widths := []int{9}
+	var want []byte
+	for i := 0; i < 254; i++ {
+		codes = append(codes, i)
+		widths = append(widths, 9)
+		want = append(want, byte(i))
+	}
+	codes = append(codes, 258, lzwEOI)
+	widths = append(widths, 10, 10)
+	want = append(want, 0, 1)
+
+	got, err := decodeLZW(packCodes(codes, widths))
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestDecodePackBits(t *testing.T) {
+	got, err := decodePackBits([]byte{0xFE, 0xAA, 0x02, 0x80, 0x00, 0x2A, 0x80, 0xFD, 0xAA})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xAA, 0xAA, 0xAA, 0x80, 0x00, 0x2A, 0xAA, 0xAA, 0xAA, 0xAA}, got)
+
+	_, err = decodePackBits([]byte{0x05, 0x01})
+	assert.Error(t, err)
+}
+
+func TestEncodeLZW(t *testing.T) {
+	assert.Equal(t,
+		packCodes([]int{lzwClear, 'a', 'b', 258, 260, lzwEOI}, []int{9, 9, 9, 9, 9, 9}),
+		encodeLZW([]byte("abababa")),
+	)
+
+	// random data fills the table quickly, exercising width changes and clear codes
+	rnd := rand.New(rand.NewPCG(1, 2))
+	noise := make([]byte, 100_000)
+	for i := range noise {
+		noise[i] = byte(rnd.IntN(256))
+	}
+	gradient := make([]byte, 100_000)
+	for i := range gradient {
+		gradient[i] = byte(i / 300)
+	}
+
+	for _, data := range [][]byte{{}, {42}, noise, gradient} {
+		decoded, err := decodeLZW(encodeLZW(data))
+		assert.NoError(t, err)
+		assert.Equal(t, data, decoded)
+	}
+}
+
+// splitJPEG splits a JPEG image into an abbreviated stream holding only its quantization and Huffman tables, as stored
+// in the JPEGTables entry, and the rest of the image, as stored in JPEG-compressed strips or tiles.
+func splitJPEG(data []byte) (tables, rest []byte) {
+	tables, rest = []byte{0xff, 0xd8}, []byte{0xff, 0xd8}
+	for i := 2; i < len(data); {
+		if data[i+1] == 0xda { // start of scan
+			rest = append(rest, data[i:]...)
+			break
+		}
+		end := i + 2 + int(binary.BigEndian.Uint16(data[i+2:]))
+		if data[i+1] == 0xdb || data[i+1] == 0xc4 {
+			tables = append(tables, data[i:end]...)
+		} else {
+			rest = append(rest, data[i:end]...)
+		}
+		i = end
+	}
+
+	return append(tables, 0xff, 0xd9), rest
+}
+
+func TestDecodeJPEG(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 8, 2))
+	for i := range gray.Pix {
+		gray.Pix[i] = uint8(i * 16)
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&buf, gray, &jpeg.Options{Quality: 100}))
+	decoded, err := jpeg.Decode(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	var want []byte
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 8; x++ {
+			want = append(want, decoded.(*image.Gray).GrayAt(x, y).Y)
+		}
+	}
+
+	got, err := decodeJPEG(buf.Bytes(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	tables, rest := splitJPEG(buf.Bytes())
+	_, err = decodeJPEG(rest, nil)
+	assert.Error(t, err) // tables are missing
+	got, err = decodeJPEG(rest, tables)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	red := image.NewUniform(color.RGBA{R: 0xff, A: 0xff})
+	buf.Reset()
+	rgb := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	for x := 0; x < 2; x++ {
+		rgb.Set(x, 0, red.C)
+	}
+	assert.NoError(t, jpeg.Encode(&buf, rgb, &jpeg.Options{Quality: 100}))
+	got, err = decodeJPEG(buf.Bytes(), nil)
+	assert.NoError(t, err)
+	assert.Len(t, got, 6) // converted from YCbCr to RGB
+	assert.InDelta(t, 0xff, got[0], 2)
+	assert.InDelta(t, 0, got[1], 2)
+	assert.InDelta(t, 0, got[2], 2)
+}
@@ -0,0 +1,792 @@
+// Package tiffimage decodes the images stored in TIFF files, building on the low-level parser of package tiff.
+//
+// Importing it registers the TIFF format with the standard image package, so that image.Decode and
+// image.DecodeConfig can read TIFF files.
+package tiffimage
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// Photometric interpretations, as written in the PhotometricInterpretation entry.
+const (
+	Photometric_WhiteIsZero uint32 = 0
+	Photometric_BlackIsZero uint32 = 1
+	Photometric_RGB         uint32 = 2
+	Photometric_Palette     uint32 = 3
+	Photometric_Separated   uint32 = 5 // usually CMYK, see InkSet
+	Photometric_YCbCr       uint32 = 6
+)
+
+// InkSet_CMYK is the (default) value of the InkSet entry for CMYK separated images.
+const InkSet_CMYK uint32 = 1
+
+// Sample formats, as written in the SampleFormat entry.
+const (
+	SampleFormat_Uint  uint32 = 1
+	SampleFormat_Int   uint32 = 2
+	SampleFormat_Float uint32 = 3
+)
+
+// Predictors, as written in the Predictor entry.
+const (
+	Predictor_None          uint32 = 1
+	Predictor_Horizontal    uint32 = 2
+	Predictor_FloatingPoint uint32 = 3
+)
+
+// Planar configurations, as written in the PlanarConfiguration entry.
+const (
+	PlanarConfiguration_Contig   uint32 = 1 // samples of each pixel are stored together
+	PlanarConfiguration_Separate uint32 = 2 // each sample component is stored in its own strips or tiles
+)
+
+// Extra samples, as written in the ExtraSamples entry.
+const (
+	ExtraSample_Unspecified  uint32 = 0
+	ExtraSample_Associated   uint32 = 1
+	ExtraSample_Unassociated uint32 = 2
+)
+
+func init() {
+	image.RegisterFormat("tiff", "II*\x00", Decode, DecodeConfig)
+	image.RegisterFormat("tiff", "MM\x00*", Decode, DecodeConfig)
+}
+
+// Decode decodes the first image (the one described by IFD#0) of a TIFF file. Readers that cannot seek are buffered in
+// memory.
+//
+// Depending on the samples stored in the file, the returned image is an *image.Gray, *image.RGBA or *image.NRGBA
+// (1 to 8 bits per sample), an *image.Gray16, *image.NRGBA64 or *image.RGBA64 (16 or 32 bits per sample, the latter
+// being reduced to 16 bits), an *image.Paletted (palette images), a *Float32Image (16 or 32-bit floating point
+// samples, half floats being widened) or a *Float64Image (64-bit floating point samples). CMYK and YCbCr samples are
+// converted to RGB. Signed integer samples are offset so that their minimum value maps to black.
+func Decode(r io.Reader) (image.Image, error) {
+	p, err := tiff.NewParser(seekable(r))
+	if err != nil {
+		return nil, err
+	}
+	page, err := p.FirstPage()
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodePage(p, page)
+}
+
+// DecodeConfig returns the dimensions and color model of the first image of a TIFF file, reading only its header and
+// IFD#0.
+func DecodeConfig(r io.Reader) (image.Config, error) {
+	p, err := tiff.NewParser(seekable(r))
+	if err != nil {
+		return image.Config{}, err
+	}
+	page, err := p.FirstPage()
+	if err != nil {
+		return image.Config{}, err
+	}
+	d, err := newDecoder(p.ByteOrder(), page)
+	if err != nil {
+		return image.Config{}, err
+	}
+	model, err := d.colorModel()
+	if err != nil {
+		return image.Config{}, err
+	}
+
+	return image.Config{ColorModel: model, Width: d.width, Height: d.height}, nil
+}
+
+// DecodePage decodes the image described by a page of a TIFF file (see Decode for the type of the returned image).
+func DecodePage(p *tiff.Parser, page tiff.Page) (image.Image, error) {
+	d, err := newDecoder(p.ByteOrder(), page)
+	if err != nil {
+		return nil, err
+	}
+
+	var chunks [][]byte
+	if d.tiled {
+		chunks, err = p.ReadTiles(page)
+	} else {
+		chunks, err = p.ReadStrips(page)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pix []byte
+	if d.ycbcr != nil && d.ycbcr.subsampled() {
+		pix, err = d.decodeSubsampledChunks(chunks)
+	} else {
+		pix, err = d.decodeChunks(chunks)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return d.toImage(pix)
+}
+
+// DecodeRegion decodes the part of the image described by a tiled page that lies within a rectangle of its pixels,
+// reading and decompressing only the tiles intersecting it (see Parser.ReadRegion): combined with NewObjectReader, it
+// reads a region of a cloud-optimized GeoTIFF (COG) without downloading the whole file. The returned image is of the
+// same type as the one Decode returns, and its bounds are the rectangle, clipped to the bounds of the page.
+func DecodeRegion(p *tiff.Parser, page tiff.Page, rect image.Rectangle) (image.Image, error) {
+	d, err := newDecoder(p.ByteOrder(), page)
+	if err != nil {
+		return nil, err
+	}
+	if !d.tiled {
+		return nil, errors.New("regions can only be decoded from tiled images")
+	}
+	if d.ycbcr != nil && d.ycbcr.subsampled() {
+		return nil, errors.New("regions of subsampled YCbCr images are not supported")
+	}
+	level, err := page.Level()
+	if err != nil {
+		return nil, err
+	}
+	tiles, err := p.ReadRegion(level, rect)
+	if err != nil {
+		return nil, err
+	}
+	rect = rect.Intersect(d.bounds())
+
+	// each tile is decoded as an image of its own, then the part of it lying within the region is copied
+	tile := *d
+	tile.width, tile.height = d.chunkWidth, d.chunkHeight
+	planes := 1
+	if d.planar {
+		planes = d.samplesPerPixel
+	}
+	pixelSize := d.samplesPerPixel * d.bytesPerSample()
+	size, err := d.decodedSize(rect.Dx(), rect.Dy())
+	if err != nil {
+		return nil, err
+	}
+	data := make([][]byte, len(tiles))
+	for i, t := range tiles {
+		data[i] = t.Data
+	}
+	if err := d.checkDataSize(size, data); err != nil {
+		return nil, err
+	}
+	pix := make([]byte, size)
+	perPlane := len(tiles) / planes
+	for i := 0; i < perPlane; i++ {
+		chunks := make([][]byte, planes)
+		for plane := range chunks {
+			chunks[plane] = tiles[plane*perPlane+i].Data
+		}
+		tilePix, err := tile.decodeChunks(chunks)
+		if err != nil {
+			return nil, fmt.Errorf("tile (%d, %d): %w", tiles[i].Col, tiles[i].Row, err)
+		}
+
+		x0, y0 := int(tiles[i].Col)*d.chunkWidth, int(tiles[i].Row)*d.chunkHeight
+		r := tiles[i].Bounds.Intersect(rect)
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			src := tilePix[((y-y0)*d.chunkWidth+r.Min.X-x0)*pixelSize:]
+			dst := pix[((y-rect.Min.Y)*rect.Dx()+r.Min.X-rect.Min.X)*pixelSize:]
+			copy(dst[:r.Dx()*pixelSize], src)
+		}
+	}
+
+	region := *d
+	region.width, region.height, region.origin = rect.Dx(), rect.Dy(), rect.Min
+
+	return region.toImage(pix)
+}
+
+// decoder holds the layout of an image, as described by the entries of its IFD.
+type decoder struct {
+	byteOrder       binary.ByteOrder
+	width           int
+	height          int
+	bitsPerSample   int
+	samplesPerPixel int
+	sampleFormat    uint32
+	photometric     uint32
+	compression     uint32
+	predictor       uint32
+	extraSamples    []uint32
+	colorMap        []uint32
+	jpegTables      []byte // tables shared by JPEG-compressed strips or tiles
+	ycbcr           *ycbcr // only set for YCbCr images
+	planar          bool   // each sample component is stored in its own strips or tiles
+	tiled           bool
+	chunkWidth      int         // width of a strip (i.e. the image width) or tile
+	chunkHeight     int         // height of a strip (i.e. rows per strip) or tile
+	origin          image.Point // position of the decoded pixels in the image, not (0, 0) for regions (see DecodeRegion)
+}
+
+func newDecoder(byteOrder binary.ByteOrder, page tiff.Page) (*decoder, error) {
+	var err error
+	d := &decoder{
+		byteOrder:       byteOrder,
+		bitsPerSample:   1,
+		samplesPerPixel: 1,
+		sampleFormat:    SampleFormat_Uint,
+		compression:     Compression_None,
+		predictor:       Predictor_None,
+		tiled:           page.Tiled(),
+	}
+
+	width, ok := page.Width()
+	if !ok {
+		return nil, errors.New("image width not found")
+	}
+	height, ok := page.Height()
+	if !ok {
+		return nil, errors.New("image height not found")
+	}
+	d.width, d.height = int(width), int(height)
+	if d.width == 0 || d.height == 0 {
+		return nil, errors.New("empty image")
+	}
+
+	if values, ok := page.Uints(tiff.BitsPerSample); ok && len(values) > 0 {
+		for _, v := range values[1:] {
+			if v != values[0] {
+				return nil, fmt.Errorf("samples with different sizes are not supported: %v", values)
+			}
+		}
+		d.bitsPerSample = int(values[0])
+	}
+	if v, ok := page.Uint(tiff.SamplesPerPixel); ok {
+		d.samplesPerPixel = int(v)
+	}
+	if d.samplesPerPixel < 1 {
+		return nil, fmt.Errorf("invalid samples per pixel: %d", d.samplesPerPixel)
+	}
+	if v, ok := page.Uint(tiff.SampleFormat); ok {
+		d.sampleFormat = v
+	}
+	if v, ok := page.Uint(tiff.Compression); ok {
+		d.compression = v
+	}
+	if v, ok := page.Uint(tiff.Predictor); ok {
+		d.predictor = v
+	}
+	d.extraSamples, _ = page.Uints(tiff.ExtraSamples)
+	d.colorMap, _ = page.Uints(tiff.ColorMap)
+
+	if v, ok := page.Uint(tiff.PhotometricInterpretation); ok {
+		d.photometric = v
+	} else if d.samplesPerPixel >= 3 {
+		d.photometric = Photometric_RGB
+	} else {
+		d.photometric = Photometric_BlackIsZero
+	}
+
+	if d.photometric == Photometric_Palette && d.samplesPerPixel != 1 {
+		return nil, fmt.Errorf("palette images must have 1 sample per pixel, not %d", d.samplesPerPixel)
+	}
+
+	if d.compression == Compression_JPEG {
+		// decoding JPEG data converts YCbCr samples to RGB, whatever their subsampling
+		if d.bitsPerSample != 8 {
+			return nil, fmt.Errorf("unsupported bits per sample for JPEG-compressed images: %d", d.bitsPerSample)
+		}
+		if d.photometric == Photometric_YCbCr {
+			d.photometric = Photometric_RGB
+		}
+		if entry, ok := page.Entries[tiff.JPEGTables]; ok {
+			d.jpegTables = entry.Value.Bytes
+		}
+	}
+	if d.photometric == Photometric_YCbCr {
+		if d.ycbcr, err = newYCbCr(page); err != nil {
+			return nil, err
+		}
+	}
+
+	if v, ok := page.Uint(tiff.InkSet); ok && d.photometric == Photometric_Separated && v != InkSet_CMYK {
+		return nil, fmt.Errorf("unsupported ink set: %d", v)
+	}
+
+	if v, ok := page.Uint(tiff.PlanarConfiguration); ok {
+		switch v {
+		case PlanarConfiguration_Contig:
+		case PlanarConfiguration_Separate:
+			d.planar = d.samplesPerPixel > 1
+		default:
+			return nil, fmt.Errorf("unsupported planar configuration: %d", v)
+		}
+	}
+
+	switch d.sampleFormat {
+	case SampleFormat_Uint, SampleFormat_Int:
+		switch d.bitsPerSample {
+		case 1, 2, 4, 8, 16, 32:
+		default:
+			return nil, fmt.Errorf("unsupported bits per sample: %d", d.bitsPerSample)
+		}
+	case SampleFormat_Float:
+		switch d.bitsPerSample {
+		case 16, 32, 64:
+		default:
+			return nil, fmt.Errorf("unsupported bits per floating point sample: %d", d.bitsPerSample)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported sample format: %d", d.sampleFormat)
+	}
+
+	if d.tiled {
+		tileWidth, _ := page.Uint(tiff.TileWidth)
+		tileLength, _ := page.Uint(tiff.TileLength)
+		if tileWidth == 0 || tileLength == 0 {
+			return nil, errors.New("tile size not found")
+		}
+		d.chunkWidth, d.chunkHeight = int(tileWidth), int(tileLength)
+	} else {
+		d.chunkWidth, d.chunkHeight = d.width, d.height
+		if v, ok := page.Uint(tiff.RowsPerStrip); ok && v > 0 && int(v) < d.height {
+			d.chunkHeight = int(v)
+		}
+	}
+	// the whole image may be too large to decode at once (see DecodeRegion), but neither its size nor that of its
+	// strips or tiles may overflow
+	if uint64(d.width)*uint64(d.height) > math.MaxUint64/uint64(d.samplesPerPixel*d.bytesPerSample()) {
+		return nil, fmt.Errorf("image of %dx%d pixels is too large", d.width, d.height)
+	}
+	if _, err := d.decodedSize(d.chunkWidth, d.chunkHeight); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// bounds returns the bounds of the decoded image.
+func (d *decoder) bounds() image.Rectangle {
+	return image.Rect(0, 0, d.width, d.height).Add(d.origin)
+}
+
+// bytesPerSample returns the size of a decoded sample: samples smaller than a byte are unpacked to one byte each.
+func (d *decoder) bytesPerSample() int {
+	return (d.bitsPerSample + 7) / 8
+}
+
+// maxDecodedSize is the largest size, in bytes, of the samples decoded at once (a variable, so that tests can lower
+// it): dimensions are read from the file, and must not make the decoder exhaust memory.
+var maxDecodedSize uint64 = 1 << 32
+
+// decodedSize returns the size, in bytes, of the decoded samples of width x height pixels, or an error if it overflows
+// or exceeds maxDecodedSize.
+func (d *decoder) decodedSize(width, height int) (int, error) {
+	size := uint64(1)
+	for _, factor := range []int{width, height, d.samplesPerPixel, d.bytesPerSample()} {
+		if factor < 0 || (factor > 0 && size > maxDecodedSize/uint64(factor)) {
+			return 0, fmt.Errorf("image of %dx%d pixels is too large to decode", width, height)
+		}
+		size *= uint64(factor)
+	}
+
+	return int(size), nil
+}
+
+// checkDataSize returns an error if the strips or tiles holding the samples of an image cannot decompress to size
+// bytes, whatever their content (see maxCompressionRatios), so that files announcing large dimensions without the data
+// to fill them do not make the decoder allocate that much.
+func (d *decoder) checkDataSize(size int, chunks [][]byte) error {
+	ratio, ok := maxCompressionRatios[d.compression]
+	if !ok {
+		return nil // decompressing the chunks fails anyway
+	}
+	// samples smaller than a byte are unpacked to one byte each
+	ratio *= uint64((8*d.bytesPerSample() + d.bitsPerSample - 1) / d.bitsPerSample)
+
+	var held uint64
+	for _, chunk := range chunks {
+		held += uint64(len(chunk))
+	}
+	if uint64(size) > held*ratio {
+		return fmt.Errorf("%d bytes of samples cannot be decoded from strips or tiles holding %d bytes", size, held)
+	}
+
+	return nil
+}
+
+// decodeChunks decompresses all strips or tiles and assembles their samples into a single buffer holding the samples of
+// the whole image, row by row, in the byte order of the file. Samples stored in separate planes (i.e. all strips or
+// tiles of the first component, then all those of the second one, and so on) are interleaved.
+func (d *decoder) decodeChunks(chunks [][]byte) ([]byte, error) {
+	bps := d.bytesPerSample()
+	spp := d.samplesPerPixel
+	size, err := d.decodedSize(d.width, d.height)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.checkDataSize(size, chunks); err != nil {
+		return nil, err
+	}
+	stride := d.width * spp * bps
+	pix := make([]byte, size)
+
+	planes, chunkSamples := 1, spp
+	if d.planar {
+		planes, chunkSamples = spp, 1
+	}
+
+	across := (d.width + d.chunkWidth - 1) / d.chunkWidth
+	down := (d.height + d.chunkHeight - 1) / d.chunkHeight
+	perPlane := across * down
+	if len(chunks) < planes*perPlane {
+		return nil, fmt.Errorf("expected %d strips or tiles, found %d", planes*perPlane, len(chunks))
+	}
+	rowBytes := (d.chunkWidth*chunkSamples*d.bitsPerSample + 7) / 8
+
+	var unpacked []byte
+	if d.planar && d.bitsPerSample < 8 {
+		unpacked = make([]byte, d.chunkWidth)
+	}
+
+	for i := 0; i < planes*perPlane; i++ {
+		plane, j := i/perPlane, i%perPlane
+		x0, y0 := (j%across)*d.chunkWidth, (j/across)*d.chunkHeight
+		rows := min(d.chunkHeight, d.height-y0)
+		cols := min(d.chunkWidth, d.width-x0)
+
+		data, err := decompress(d.compression, chunks[i], d.jpegTables)
+		if err != nil {
+			return nil, fmt.Errorf("strip or tile #%d: %w", i, err)
+		}
+		if len(data) < rows*rowBytes {
+			return nil, fmt.Errorf("strip or tile #%d: expected %d bytes, found %d", i, rows*rowBytes, len(data))
+		}
+
+		for r := 0; r < rows; r++ {
+			row := data[r*rowBytes : (r+1)*rowBytes]
+			if err := d.undoPredictor(row, chunkSamples); err != nil {
+				return nil, err
+			}
+
+			dst := pix[(y0+r)*stride+x0*spp*bps:]
+			switch {
+			case d.planar:
+				samples := row
+				if d.bitsPerSample < 8 {
+					samples = unpacked
+					unpackBits(samples[:cols], row, d.bitsPerSample)
+				}
+				for c := 0; c < cols; c++ {
+					copy(dst[(c*spp+plane)*bps:(c*spp+plane+1)*bps], samples[c*bps:])
+				}
+			case d.bitsPerSample >= 8:
+				copy(dst, row[:cols*spp*bps])
+			default:
+				unpackBits(dst[:cols*spp], row, d.bitsPerSample)
+			}
+		}
+	}
+
+	return pix, nil
+}
+
+// undoPredictor reverses the predictor applied to a row of samples before compression, spp being the number of samples
+// per pixel stored in the row.
+func (d *decoder) undoPredictor(row []byte, spp int) error {
+	switch d.predictor {
+	case Predictor_None:
+	case Predictor_Horizontal:
+		switch d.bitsPerSample {
+		case 8:
+			for i := spp; i < len(row); i++ {
+				row[i] += row[i-spp]
+			}
+		case 16:
+			for i := 2 * spp; i+1 < len(row); i += 2 {
+				d.byteOrder.PutUint16(row[i:], d.byteOrder.Uint16(row[i:])+d.byteOrder.Uint16(row[i-2*spp:]))
+			}
+		case 32:
+			for i := 4 * spp; i+3 < len(row); i += 4 {
+				d.byteOrder.PutUint32(row[i:], d.byteOrder.Uint32(row[i:])+d.byteOrder.Uint32(row[i-4*spp:]))
+			}
+		default:
+			return fmt.Errorf("horizontal predictor not supported with %d bits per sample", d.bitsPerSample)
+		}
+	case Predictor_FloatingPoint:
+		// bytes are differenced, then stored by significance: most significant bytes of all samples first
+		for i := spp; i < len(row); i++ {
+			row[i] += row[i-spp]
+		}
+		size := d.bytesPerSample()
+		count := len(row) / size
+		shuffled := make([]byte, len(row))
+		copy(shuffled, row)
+		for i := 0; i < count; i++ {
+			for b := 0; b < size; b++ {
+				if d.byteOrder == binary.BigEndian {
+					row[i*size+b] = shuffled[b*count+i]
+				} else {
+					row[i*size+size-1-b] = shuffled[b*count+i]
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported predictor: %d", d.predictor)
+	}
+
+	return nil
+}
+
+// unpackBits unpacks samples smaller than a byte (MSB first) to one byte per sample.
+func unpackBits(dst, src []byte, bitsPerSample int) {
+	mask := byte(1<<bitsPerSample - 1)
+	for i := range dst {
+		bit := i * bitsPerSample
+		dst[i] = (src[bit/8] >> (8 - bitsPerSample - bit%8)) & mask
+	}
+}
+
+// sample returns the i-th sample of the decoded buffer, as stored in the file.
+func (d *decoder) sample(pix []byte, i int) uint32 {
+	switch d.bytesPerSample() {
+	case 1:
+		return uint32(pix[i])
+	case 2:
+		return uint32(d.byteOrder.Uint16(pix[2*i:]))
+	default:
+		return d.byteOrder.Uint32(pix[4*i:])
+	}
+}
+
+// to16 scales a sample to the [0, 0xffff] range.
+func (d *decoder) to16(v uint32) uint16 {
+	switch d.bitsPerSample {
+	case 8:
+		if d.sampleFormat == SampleFormat_Int {
+			v ^= 0x80
+		}
+		return uint16(v) * 0x101
+	case 16:
+		if d.sampleFormat == SampleFormat_Int {
+			v ^= 0x8000
+		}
+		return uint16(v)
+	case 32:
+		if d.sampleFormat == SampleFormat_Int {
+			v ^= 0x80000000
+		}
+		return uint16(v >> 16)
+	default:
+		return uint16(v * 0xffff / (1<<d.bitsPerSample - 1))
+	}
+}
+
+// channels returns the number of color channels of the image (before any conversion to RGB) and whether they are
+// followed by an alpha channel, premultiplied or not.
+func (d *decoder) channels() (channels int, alpha, premultiplied bool, err error) {
+	switch d.photometric {
+	case Photometric_WhiteIsZero, Photometric_BlackIsZero:
+		channels = 1
+	case Photometric_RGB:
+		channels = 3
+	case Photometric_Separated:
+		channels = 4
+	default:
+		return 0, false, false, fmt.Errorf("unsupported photometric interpretation: %d", d.photometric)
+	}
+	if d.samplesPerPixel < channels {
+		return 0, false, false, fmt.Errorf("expected at least %d samples per pixel, found %d", channels, d.samplesPerPixel)
+	}
+
+	alpha = d.samplesPerPixel > channels && len(d.extraSamples) > 0 && d.extraSamples[0] != ExtraSample_Unspecified
+	premultiplied = alpha && d.extraSamples[0] == ExtraSample_Associated
+
+	return channels, alpha, premultiplied, nil
+}
+
+// colorModel returns the color model of the image returned by toImage.
+func (d *decoder) colorModel() (color.Model, error) {
+	switch d.photometric {
+	case Photometric_Palette:
+		return d.palette()
+	case Photometric_YCbCr:
+		return color.RGBAModel, nil
+	}
+
+	channels, alpha, premultiplied, err := d.channels()
+	if err != nil {
+		return nil, err
+	}
+	if d.sampleFormat == SampleFormat_Float {
+		if channels == 1 && !alpha {
+			return color.Gray16Model, nil
+		}
+		return color.NRGBA64Model, nil
+	}
+	img, _ := newOutput(image.Rectangle{}, d.bitsPerSample > 8, channels == 1 && !alpha, alpha, premultiplied)
+
+	return img.ColorModel(), nil
+}
+
+// toImage converts the decoded samples to an image.
+func (d *decoder) toImage(pix []byte) (image.Image, error) {
+	switch d.photometric {
+	case Photometric_Palette:
+		return d.toPaletted(pix)
+	case Photometric_YCbCr:
+		return d.toYCbCrImage(pix)
+	}
+
+	channels, alpha, premultiplied, err := d.channels()
+	if err != nil {
+		return nil, err
+	}
+	rect := d.bounds()
+
+	if d.sampleFormat == SampleFormat_Float {
+		return d.toFloatImage(pix, rect, channels, alpha), nil
+	}
+
+	img, set := newOutput(rect, d.bitsPerSample > 8, channels == 1 && !alpha, alpha, premultiplied)
+	spp := d.samplesPerPixel
+	for i := 0; i < d.width*d.height; i++ {
+		a := uint16(0xffff)
+		if alpha {
+			a = d.to16(d.sample(pix, i*spp+channels))
+		}
+		switch channels {
+		case 1:
+			y := d.to16(d.sample(pix, i*spp))
+			if d.photometric == Photometric_WhiteIsZero {
+				y = 0xffff - y
+			}
+			set(i, y, y, y, a)
+		case 4:
+			c, m, y, k := d.to16(d.sample(pix, i*spp)), d.to16(d.sample(pix, i*spp+1)), d.to16(d.sample(pix, i*spp+2)), d.to16(d.sample(pix, i*spp+3))
+			set(i, cmykToRGB(c, k), cmykToRGB(m, k), cmykToRGB(y, k), a)
+		default:
+			set(i, d.to16(d.sample(pix, i*spp)), d.to16(d.sample(pix, i*spp+1)), d.to16(d.sample(pix, i*spp+2)), a)
+		}
+	}
+
+	return img, nil
+}
+
+// toPaletted converts the decoded color indexes to a paletted image.
+func (d *decoder) toPaletted(pix []byte) (image.Image, error) {
+	palette, err := d.palette()
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewPaletted(d.bounds(), palette)
+	for i := range img.Pix {
+		img.Pix[i] = pix[i*d.samplesPerPixel]
+	}
+
+	return img, nil
+}
+
+// palette returns the palette of a palette image, read from the ColorMap entry: it holds all red components first, then
+// all green ones, then all blue ones.
+func (d *decoder) palette() (color.Palette, error) {
+	if d.bitsPerSample > 8 {
+		return nil, fmt.Errorf("unsupported bits per sample for palette images: %d", d.bitsPerSample)
+	}
+	size := 1 << d.bitsPerSample
+	if len(d.colorMap) != 3*size {
+		return nil, fmt.Errorf("expected a color map of %d values, found %d", 3*size, len(d.colorMap))
+	}
+
+	palette := make(color.Palette, size)
+	for i := range palette {
+		palette[i] = color.RGBA64{
+			R: uint16(d.colorMap[i]),
+			G: uint16(d.colorMap[size+i]),
+			B: uint16(d.colorMap[2*size+i]),
+			A: 0xffff,
+		}
+	}
+
+	return palette, nil
+}
+
+// cmykToRGB converts an ink component (cyan, magenta or yellow) and black to the complementary RGB component.
+func cmykToRGB(ink, black uint16) uint16 {
+	return uint16(uint32(0xffff-ink) * uint32(0xffff-black) / 0xffff)
+}
+
+// toFloatImage converts the decoded floating point samples to a Float64Image if they are 64-bit wide, to a
+// Float32Image otherwise, dropping unspecified extra samples.
+func (d *decoder) toFloatImage(pix []byte, rect image.Rectangle, channels int, alpha bool) image.Image {
+	if alpha {
+		channels++
+	}
+
+	if d.bitsPerSample == 64 {
+		img := NewFloat64Image(rect, channels)
+		for i := 0; i < d.width*d.height; i++ {
+			for c := 0; c < channels; c++ {
+				img.Pix[i*channels+c] = math.Float64frombits(d.byteOrder.Uint64(pix[8*(i*d.samplesPerPixel+c):]))
+			}
+		}
+		return img
+	}
+
+	img := NewFloat32Image(rect, channels)
+	for i := 0; i < d.width*d.height; i++ {
+		for c := 0; c < channels; c++ {
+			bits := d.sample(pix, i*d.samplesPerPixel+c)
+			if d.bitsPerSample == 16 {
+				img.Pix[i*channels+c] = halfToFloat32(uint16(bits))
+			} else {
+				img.Pix[i*channels+c] = math.Float32frombits(bits)
+			}
+		}
+	}
+
+	return img
+}
+
+// pixelSetter sets the i-th pixel of an image to a color whose components are in the [0, 0xffff] range.
+type pixelSetter func(i int, r, g, b, a uint16)
+
+// newOutput returns an image of the most appropriate type, together with a function to set its pixels.
+func newOutput(rect image.Rectangle, deep, gray, alpha, premultiplied bool) (image.Image, pixelSetter) {
+	switch {
+	case gray && deep:
+		img := image.NewGray16(rect)
+		return img, func(i int, r, _, _, _ uint16) {
+			img.Pix[2*i], img.Pix[2*i+1] = uint8(r>>8), uint8(r)
+		}
+	case gray:
+		img := image.NewGray(rect)
+		return img, func(i int, r, _, _, _ uint16) {
+			img.Pix[i] = uint8(r >> 8)
+		}
+	case deep && premultiplied:
+		img := image.NewRGBA64(rect)
+		return img, func(i int, r, g, b, a uint16) { put16(img.Pix[8*i:], r, g, b, a) }
+	case deep:
+		img := image.NewNRGBA64(rect)
+		return img, func(i int, r, g, b, a uint16) { put16(img.Pix[8*i:], r, g, b, a) }
+	case alpha && !premultiplied:
+		img := image.NewNRGBA(rect)
+		return img, func(i int, r, g, b, a uint16) { put8(img.Pix[4*i:], r, g, b, a) }
+	default:
+		img := image.NewRGBA(rect)
+		return img, func(i int, r, g, b, a uint16) { put8(img.Pix[4*i:], r, g, b, a) }
+	}
+}
+
+func put8(pix []byte, r, g, b, a uint16) {
+	pix[0], pix[1], pix[2], pix[3] = uint8(r>>8), uint8(g>>8), uint8(b>>8), uint8(a>>8)
+}
+
+func put16(pix []byte, r, g, b, a uint16) {
+	pix[0], pix[1] = uint8(r>>8), uint8(r)
+	pix[2], pix[3] = uint8(g>>8), uint8(g)
+	pix[4], pix[5] = uint8(b>>8), uint8(b)
+	pix[6], pix[7] = uint8(a>>8), uint8(a)
+}
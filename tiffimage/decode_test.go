@@ -0,0 +1,607 @@
+package tiffimage
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"math"
+	"runtime"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/stretchr/testify/assert"
+)
+
+// newImageIFD returns a TIFF builder whose first IFD describes an image of the given size and layout.
+func newImageIFD(byteOrder test.ByteOrder, width, height uint16, photometric uint16, bitsPerSample ...uint16) (*test.TIFFBuilder, *test.IFDBuilder) {
+	b := test.NewTIFFBuilder(byteOrder)
+	ifd := b.AddIFD().
+		WithUints16(uint16(tiff.ImageWidth), width).
+		WithUints16(uint16(tiff.ImageHeight), height).
+		WithUints16(uint16(tiff.BitsPerSample), bitsPerSample...).
+		WithUints16(uint16(tiff.SamplesPerPixel), uint16(len(bitsPerSample))).
+		WithUints16(uint16(tiff.PhotometricInterpretation), photometric)
+
+	return b, ifd
+}
+
+func deflate(data []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	_, _ = w.Write(data)
+	_ = w.Close()
+
+	return buf.Bytes()
+}
+
+func TestDecode_Gray8Strips(t *testing.T) {
+	b, ifd := newImageIFD(binary.LittleEndian, 3, 3, 1, 8)
+	ifd.WithUints16(uint16(tiff.RowsPerStrip), 2).
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{0, 1, 2, 3, 4, 5}, []byte{6, 7, 8})
+
+	img, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	gray, ok := img.(*image.Gray)
+	assert.True(t, ok)
+	assert.Equal(t, []byte{0, 1, 2, 3, 4, 5, 6, 7, 8}, gray.Pix)
+}
+
+func TestDecode_WhiteIsZeroBilevel(t *testing.T) {
+	b, ifd := newImageIFD(binary.BigEndian, 10, 1, 0, 1)
+	ifd.WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{0b10100000, 0b01000000})
+
+	img, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	gray := img.(*image.Gray)
+	assert.Equal(t, []byte{0, 255, 0, 255, 255, 255, 255, 255, 255, 0}, gray.Pix)
+}
+
+func TestDecode_RGB16PredictorDeflate(t *testing.T) {
+	// 2x1 pixels: (0x1000, 0x2000, 0x3000), (0x1100, 0x2200, 0x3300), differenced horizontally
+	data := binary.BigEndian.AppendUint16(nil, 0x1000)
+	for _, v := range []uint16{0x2000, 0x3000, 0x0100, 0x0200, 0x0300} {
+		data = binary.BigEndian.AppendUint16(data, v)
+	}
+	b, ifd := newImageIFD(binary.BigEndian, 2, 1, 2, 16, 16, 16)
+	ifd.WithUints16(uint16(tiff.Compression), uint16(Compression_AdobeDeflate)).
+		WithUints16(uint16(tiff.Predictor), uint16(Predictor_Horizontal)).
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), deflate(data))
+
+	img, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	rgb, ok := img.(*image.NRGBA64)
+	assert.True(t, ok)
+	assert.Equal(t, color.NRGBA64{R: 0x1100, G: 0x2200, B: 0x3300, A: 0xffff}, rgb.NRGBA64At(1, 0))
+}
+
+func TestDecode_SignedInt16(t *testing.T) {
+	var data []byte
+	for _, v := range []int16{-32768, 0, 32767} {
+		data = binary.LittleEndian.AppendUint16(data, uint16(v))
+	}
+	b, ifd := newImageIFD(binary.LittleEndian, 3, 1, 1, 16)
+	ifd.WithUints16(uint16(tiff.SampleFormat), uint16(SampleFormat_Int)).
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), data)
+
+	img, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	gray := img.(*image.Gray16)
+	assert.Equal(t, uint16(0), gray.Gray16At(0, 0).Y)
+	assert.Equal(t, uint16(0x8000), gray.Gray16At(1, 0).Y)
+	assert.Equal(t, uint16(0xffff), gray.Gray16At(2, 0).Y)
+}
+
+func TestDecode_Uint32WithAlpha(t *testing.T) {
+	var data []byte
+	for _, v := range []uint32{0xffffffff, 0x80000000} {
+		data = binary.LittleEndian.AppendUint32(data, v)
+	}
+	b, ifd := newImageIFD(binary.LittleEndian, 1, 1, 1, 32, 32)
+	ifd.WithUints16(uint16(tiff.ExtraSamples), uint16(ExtraSample_Unassociated)).
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), data)
+
+	img, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	nrgba := img.(*image.NRGBA64)
+	assert.Equal(t, color.NRGBA64{R: 0xffff, G: 0xffff, B: 0xffff, A: 0x8000}, nrgba.NRGBA64At(0, 0))
+}
+
+// floatingPointPredictor applies the floating point predictor to a row of 32-bit samples.
+func floatingPointPredictor(samples []float32, spp int) []byte {
+	n := len(samples)
+	shuffled := make([]byte, 4*n)
+	for i, s := range samples {
+		bits := math.Float32bits(s)
+		for b := 0; b < 4; b++ {
+			shuffled[b*n+i] = byte(bits >> (24 - 8*b))
+		}
+	}
+	for i := len(shuffled) - 1; i >= spp; i-- {
+		shuffled[i] -= shuffled[i-spp]
+	}
+
+	return shuffled
+}
+
+func TestDecode_Float32(t *testing.T) {
+	for _, byteOrder := range []test.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		t.Run(byteOrder.String(), func(t *testing.T) {
+			b, ifd := newImageIFD(byteOrder, 3, 1, 1, 32)
+			ifd.WithUints16(uint16(tiff.SampleFormat), uint16(SampleFormat_Float)).
+				WithUints16(uint16(tiff.Predictor), uint16(Predictor_FloatingPoint)).
+				WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), floatingPointPredictor([]float32{0.25, -3.5, 1e6}, 1))
+
+			img, err := Decode(bytes.NewReader(b.Bytes()))
+			assert.NoError(t, err)
+
+			f, ok := img.(*Float32Image)
+			assert.True(t, ok)
+			assert.Equal(t, []float32{0.25, -3.5, 1e6}, f.Pix)
+			assert.Equal(t, color.Gray16{Y: 0x4000}, f.At(0, 0))
+			assert.Equal(t, color.Gray16{Y: 0}, f.At(1, 0))
+			assert.Equal(t, color.Gray16{Y: 0xffff}, f.At(2, 0))
+		})
+	}
+}
+
+func TestDecode_HalfFloat(t *testing.T) {
+	// 1, -2, the smallest subnormal, +Inf and 0.5 as half floats, with an alpha channel
+	var data []byte
+	for _, v := range []uint16{0x3c00, 0xc000, 0x0001, 0x7c00, 0x3800, 0x3c00} {
+		data = binary.BigEndian.AppendUint16(data, v)
+	}
+	b, ifd := newImageIFD(binary.BigEndian, 3, 1, uint16(Photometric_BlackIsZero), 16, 16)
+	ifd.WithUints16(uint16(tiff.SampleFormat), uint16(SampleFormat_Float), uint16(SampleFormat_Float)).
+		WithUints16(uint16(tiff.ExtraSamples), uint16(ExtraSample_Unassociated)).
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), data)
+
+	img, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	f, ok := img.(*Float32Image)
+	assert.True(t, ok)
+	assert.Equal(t, []float32{1, -2, 0x1p-24, float32(math.Inf(1)), 0.5, 1}, f.Pix)
+	assert.Equal(t, color.NRGBA64{R: 0x8000, G: 0x8000, B: 0x8000, A: 0xffff}, f.At(2, 0))
+}
+
+func TestDecode_Float64(t *testing.T) {
+	for _, byteOrder := range []test.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		t.Run(byteOrder.String(), func(t *testing.T) {
+			var data []byte
+			for _, v := range []float64{0.1, -1e300} {
+				data = byteOrder.AppendUint64(data, math.Float64bits(v))
+			}
+			b, ifd := newImageIFD(byteOrder, 2, 1, uint16(Photometric_BlackIsZero), 64)
+			ifd.WithUints16(uint16(tiff.SampleFormat), uint16(SampleFormat_Float)).
+				WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), data)
+
+			img, err := Decode(bytes.NewReader(b.Bytes()))
+			assert.NoError(t, err)
+
+			f, ok := img.(*Float64Image)
+			assert.True(t, ok)
+			assert.Equal(t, []float64{0.1, -1e300}, f.Pix)
+			assert.Equal(t, color.Gray16{Y: 0x199a}, f.At(0, 0))
+		})
+	}
+}
+
+func TestHalfToFloat32(t *testing.T) {
+	tests := []struct {
+		half uint16
+		want float32
+	}{
+		{0x0000, 0},
+		{0x3555, 0.333251953125},
+		{0x7bff, 65504},
+		{0x0400, 0x1p-14},
+		{0x03ff, 0x3ffp-24},
+		{0x8001, -0x1p-24},
+		{0xfc00, float32(math.Inf(-1))},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, halfToFloat32(tt.half), "0x%04x", tt.half)
+	}
+	assert.True(t, math.IsNaN(float64(halfToFloat32(0x7e00))))
+}
+
+func TestDecode_JPEGTiles(t *testing.T) {
+	// 32x16 image made of 2 tiles of 16x16 pixels sharing the same tables
+	var tables []byte
+	var tiles, jpegs [][]byte
+	for i := 0; i < 2; i++ {
+		tile := image.NewNRGBA(image.Rect(0, 0, 16, 16))
+		for y := 0; y < 16; y++ {
+			for x := 0; x < 16; x++ {
+				tile.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 16), G: uint8(y * 16), B: uint8(i * 200), A: 0xff})
+			}
+		}
+		var buf bytes.Buffer
+		assert.NoError(t, jpeg.Encode(&buf, tile, &jpeg.Options{Quality: 90}))
+		var rest []byte
+		tables, rest = splitJPEG(buf.Bytes())
+		tiles, jpegs = append(tiles, rest), append(jpegs, buf.Bytes())
+	}
+
+	b, ifd := newImageIFD(binary.LittleEndian, 32, 16, uint16(Photometric_YCbCr), 8, 8, 8)
+	ifd.WithUints16(uint16(tiff.Compression), uint16(Compression_JPEG)).
+		WithUints16(uint16(tiff.TileWidth), 16).
+		WithUints16(uint16(tiff.TileLength), 16).
+		WithUints16(uint16(tiff.YCbCrSubSampling), 2, 2).
+		WithBytes(uint16(tiff.JPEGTables), test.TypeUndefined, tables...).
+		WithData(uint16(tiff.TileOffsets), uint16(tiff.TileByteCounts), tiles...)
+
+	img, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+	rgba, ok := img.(*image.RGBA)
+	assert.True(t, ok)
+
+	for i, data := range jpegs {
+		tile, err := jpeg.Decode(bytes.NewReader(data))
+		assert.NoError(t, err)
+		ycbcr := tile.(*image.YCbCr)
+		for y := 0; y < 16; y++ {
+			for x := 0; x < 16; x++ {
+				c := ycbcr.YCbCrAt(x, y)
+				r, g, b := color.YCbCrToRGB(c.Y, c.Cb, c.Cr)
+				assert.Equal(t, color.RGBA{R: r, G: g, B: b, A: 0xff}, rgba.RGBAAt(i*16+x, y))
+			}
+		}
+	}
+}
+
+func TestDecode_TiledRGB(t *testing.T) {
+	// 3x3 image made of 2x2 tiles: the right and bottom tiles are padded
+	tile := func(v byte) []byte {
+		return bytes.Repeat([]byte{v, v, v}, 4)
+	}
+	b, ifd := newImageIFD(binary.LittleEndian, 3, 3, 2, 8, 8, 8)
+	ifd.WithUints16(uint16(tiff.TileWidth), 2).
+		WithUints16(uint16(tiff.TileLength), 2).
+		WithData(uint16(tiff.TileOffsets), uint16(tiff.TileByteCounts), tile(10), tile(20), tile(30), tile(40))
+
+	img, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	rgba := img.(*image.RGBA)
+	assert.Equal(t, color.RGBA{R: 10, G: 10, B: 10, A: 255}, rgba.RGBAAt(1, 1))
+	assert.Equal(t, color.RGBA{R: 20, G: 20, B: 20, A: 255}, rgba.RGBAAt(2, 0))
+	assert.Equal(t, color.RGBA{R: 30, G: 30, B: 30, A: 255}, rgba.RGBAAt(0, 2))
+	assert.Equal(t, color.RGBA{R: 40, G: 40, B: 40, A: 255}, rgba.RGBAAt(2, 2))
+}
+
+func TestDecode_Errors(t *testing.T) {
+	b, ifd := newImageIFD(binary.LittleEndian, 2, 2, 1, 8)
+	ifd.WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{1, 2, 3})
+	_, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.Error(t, err, "strip too short")
+
+	b, ifd = newImageIFD(binary.LittleEndian, 1, 1, 1, 12)
+	ifd.WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{1, 2})
+	_, err = Decode(bytes.NewReader(b.Bytes()))
+	assert.Error(t, err, "unsupported bits per sample")
+}
+
+func TestDecode_Palette(t *testing.T) {
+	colorMap := make([]uint16, 3*16)
+	colorMap[1], colorMap[16+1], colorMap[32+1] = 0xffff, 0x8000, 0 // orange
+	colorMap[15], colorMap[16+15], colorMap[32+15] = 0, 0, 0xffff   // blue
+
+	b, ifd := newImageIFD(binary.LittleEndian, 3, 1, uint16(Photometric_Palette), 4)
+	ifd.WithUints16(uint16(tiff.ColorMap), colorMap...).
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{0x1F, 0x00})
+
+	img, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	paletted, ok := img.(*image.Paletted)
+	assert.True(t, ok)
+	assert.Equal(t, []uint8{1, 15, 0}, paletted.Pix)
+	assert.Equal(t, color.RGBA64{R: 0xffff, G: 0x8000, A: 0xffff}, paletted.At(0, 0))
+	assert.Equal(t, color.RGBA64{B: 0xffff, A: 0xffff}, paletted.At(1, 0))
+
+	b, ifd = newImageIFD(binary.LittleEndian, 1, 1, uint16(Photometric_Palette), 4)
+	ifd.WithUints16(uint16(tiff.ColorMap), 1, 2, 3).
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{0x10})
+	_, err = Decode(bytes.NewReader(b.Bytes()))
+	assert.Error(t, err, "color map too short")
+
+	for _, samples := range []uint16{0, 3} {
+		b, ifd = newImageIFD(binary.LittleEndian, 1, 1, uint16(Photometric_Palette), 8)
+		ifd.WithUints16(uint16(tiff.SamplesPerPixel), samples).
+			WithUints16(uint16(tiff.ColorMap), make([]uint16, 3*256)...).
+			WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{0, 0, 0})
+		_, err = Decode(bytes.NewReader(b.Bytes()))
+		assert.Error(t, err, "%d samples per pixel", samples)
+	}
+}
+
+func TestDecode_CMYK(t *testing.T) {
+	b, ifd := newImageIFD(binary.LittleEndian, 3, 1, uint16(Photometric_Separated), 8, 8, 8, 8)
+	ifd.WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{
+		0, 0, 0, 0, // white
+		255, 0, 255, 0, // green
+		0, 0, 0, 255, // black
+	})
+
+	img, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	rgba, ok := img.(*image.RGBA)
+	assert.True(t, ok)
+	assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, rgba.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{G: 255, A: 255}, rgba.RGBAAt(1, 0))
+	assert.Equal(t, color.RGBA{A: 255}, rgba.RGBAAt(2, 0))
+}
+
+func TestDecode_CMYKWithAlpha(t *testing.T) {
+	b, ifd := newImageIFD(binary.BigEndian, 1, 1, uint16(Photometric_Separated), 16, 16, 16, 16, 16)
+	ifd.WithUints16(uint16(tiff.ExtraSamples), uint16(ExtraSample_Unassociated)).
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{
+			0x80, 0x00, 0, 0, 0, 0, 0, 0, 0x80, 0x00,
+		})
+
+	img, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	nrgba, ok := img.(*image.NRGBA64)
+	assert.True(t, ok)
+	assert.Equal(t, color.NRGBA64{R: 0x7fff, G: 0xffff, B: 0xffff, A: 0x8000}, nrgba.NRGBA64At(0, 0))
+}
+
+func TestDecode_PlanarStrips(t *testing.T) {
+	// 2x3 RGB image, 2 rows per strip: 2 strips per plane
+	b, ifd := newImageIFD(binary.LittleEndian, 2, 3, uint16(Photometric_RGB), 8, 8, 8)
+	ifd.WithUints16(uint16(tiff.PlanarConfiguration), uint16(PlanarConfiguration_Separate)).
+		WithUints16(uint16(tiff.RowsPerStrip), 2).
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts),
+			[]byte{10, 11, 12, 13}, []byte{14, 15}, // red
+			[]byte{20, 21, 22, 23}, []byte{24, 25}, // green
+			[]byte{30, 31, 32, 33}, []byte{34, 35}, // blue
+		)
+
+	img, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	rgba, ok := img.(*image.RGBA)
+	assert.True(t, ok)
+	assert.Equal(t, color.RGBA{R: 10, G: 20, B: 30, A: 255}, rgba.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{R: 13, G: 23, B: 33, A: 255}, rgba.RGBAAt(1, 1))
+	assert.Equal(t, color.RGBA{R: 15, G: 25, B: 35, A: 255}, rgba.RGBAAt(1, 2))
+
+	b, ifd = newImageIFD(binary.LittleEndian, 2, 1, uint16(Photometric_RGB), 8, 8, 8)
+	ifd.WithUints16(uint16(tiff.PlanarConfiguration), uint16(PlanarConfiguration_Separate)).
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{1, 2}, []byte{3, 4})
+	_, err = Decode(bytes.NewReader(b.Bytes()))
+	assert.Error(t, err, "missing blue plane")
+}
+
+func TestDecode_PlanarTiles16Predictor(t *testing.T) {
+	// 3x1 gray + alpha image made of 2x1 tiles, each plane differenced horizontally
+	tile := func(values ...uint16) []byte {
+		var data []byte
+		for _, v := range values {
+			data = binary.BigEndian.AppendUint16(data, v)
+		}
+		return data
+	}
+	b, ifd := newImageIFD(binary.BigEndian, 3, 1, uint16(Photometric_BlackIsZero), 16, 16)
+	ifd.WithUints16(uint16(tiff.PlanarConfiguration), uint16(PlanarConfiguration_Separate)).
+		WithUints16(uint16(tiff.ExtraSamples), uint16(ExtraSample_Unassociated)).
+		WithUints16(uint16(tiff.Predictor), uint16(Predictor_Horizontal)).
+		WithUints16(uint16(tiff.TileWidth), 2).
+		WithUints16(uint16(tiff.TileLength), 1).
+		WithData(uint16(tiff.TileOffsets), uint16(tiff.TileByteCounts),
+			tile(0x1000, 0x1000), tile(0x3000, 0), // gray: 0x1000, 0x2000, 0x3000
+			tile(0xffff, 0x8001), tile(0x4000, 0), // alpha: 0xffff, 0x8000, 0x4000
+		)
+
+	img, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	nrgba, ok := img.(*image.NRGBA64)
+	assert.True(t, ok)
+	assert.Equal(t, color.NRGBA64{R: 0x1000, G: 0x1000, B: 0x1000, A: 0xffff}, nrgba.NRGBA64At(0, 0))
+	assert.Equal(t, color.NRGBA64{R: 0x2000, G: 0x2000, B: 0x2000, A: 0x8000}, nrgba.NRGBA64At(1, 0))
+	assert.Equal(t, color.NRGBA64{R: 0x3000, G: 0x3000, B: 0x3000, A: 0x4000}, nrgba.NRGBA64At(2, 0))
+}
+
+func TestDecodeRegion(t *testing.T) {
+	regions := []image.Rectangle{
+		image.Rect(5, 3, 30, 19),   // across tiles
+		image.Rect(16, 16, 32, 21), // a single tile, clipped by the bottom of the image
+		image.Rect(30, -5, 100, 2), // clipped by the image
+	}
+
+	for name, img := range testImages() {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, Encode(&buf, img, Options{Compression: Compression_Deflate, TileSize: 16}))
+			p, err := tiff.NewParser(bytes.NewReader(buf.Bytes()))
+			assert.NoError(t, err)
+			page, err := p.FirstPage()
+			assert.NoError(t, err)
+			full, err := DecodePage(p, page)
+			assert.NoError(t, err)
+
+			for _, rect := range regions {
+				region, err := DecodeRegion(p, page, rect)
+				if !assert.NoError(t, err) {
+					return
+				}
+				bounds := rect.Intersect(img.Bounds())
+				assert.Equal(t, bounds, region.Bounds())
+				assert.IsType(t, full, region)
+				for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+					for x := bounds.Min.X; x < bounds.Max.X; x++ {
+						assert.Equal(t, full.At(x, y), region.At(x, y), "(%d, %d) of %v", x, y, rect)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeRegion_Planar(t *testing.T) {
+	// 3x2 RGB image in a single 16x16 tile per plane
+	plane := func(v byte) []byte {
+		data := make([]byte, 16*16)
+		for i := range data {
+			data[i] = v + byte(i)
+		}
+		return data
+	}
+	b, ifd := newImageIFD(binary.LittleEndian, 3, 2, uint16(Photometric_RGB), 8, 8, 8)
+	ifd.WithUints16(uint16(tiff.PlanarConfiguration), uint16(PlanarConfiguration_Separate)).
+		WithUints16(uint16(tiff.TileWidth), 16).
+		WithUints16(uint16(tiff.TileLength), 16).
+		WithData(uint16(tiff.TileOffsets), uint16(tiff.TileByteCounts), plane(0), plane(100), plane(200))
+
+	p, err := tiff.NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+	page, err := p.FirstPage()
+	assert.NoError(t, err)
+
+	region, err := DecodeRegion(p, page, image.Rect(1, 1, 3, 2))
+	assert.NoError(t, err)
+	assert.Equal(t, color.RGBA{R: 17, G: 117, B: 217, A: 0xff}, region.At(1, 1))
+	assert.Equal(t, color.RGBA{R: 18, G: 118, B: 218, A: 0xff}, region.At(2, 1))
+}
+
+func TestDecodeRegion_Strips(t *testing.T) {
+	b, ifd := newImageIFD(binary.LittleEndian, 2, 1, uint16(Photometric_BlackIsZero), 8)
+	ifd.WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{1, 2})
+
+	p, err := tiff.NewParser(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+	page, err := p.FirstPage()
+	assert.NoError(t, err)
+
+	_, err = DecodeRegion(p, page, image.Rect(0, 0, 1, 1))
+	assert.Error(t, err)
+}
+
+func TestDecodeConfig(t *testing.T) {
+	gray, ifd := newImageIFD(binary.LittleEndian, 3, 2, uint16(Photometric_BlackIsZero), 8)
+	ifd.WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), make([]byte, 6))
+
+	rgba, ifd := newImageIFD(binary.BigEndian, 1, 1, uint16(Photometric_RGB), 16, 16, 16, 16)
+	ifd.WithUints16(uint16(tiff.ExtraSamples), uint16(ExtraSample_Associated)).
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), make([]byte, 8))
+
+	float, ifd := newImageIFD(binary.LittleEndian, 1, 1, uint16(Photometric_BlackIsZero), 32)
+	ifd.WithUints16(uint16(tiff.SampleFormat), uint16(SampleFormat_Float)).
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), make([]byte, 4))
+
+	tests := []struct {
+		name   string
+		data   []byte
+		width  int
+		height int
+		model  color.Model
+	}{
+		{name: "gray", data: gray.Bytes(), width: 3, height: 2, model: color.GrayModel},
+		{name: "premultiplied RGBA", data: rgba.Bytes(), width: 1, height: 1, model: color.RGBA64Model},
+		{name: "floating point", data: float.Bytes(), width: 1, height: 1, model: color.Gray16Model},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := DecodeConfig(bytes.NewReader(tt.data))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.width, config.Width)
+			assert.Equal(t, tt.height, config.Height)
+			assert.Equal(t, tt.model, config.ColorModel)
+
+			img, err := Decode(bytes.NewReader(tt.data))
+			assert.NoError(t, err)
+			assert.Equal(t, config.ColorModel, img.ColorModel())
+		})
+	}
+}
+
+func TestRegisterFormat(t *testing.T) {
+	b, ifd := newImageIFD(binary.BigEndian, 2, 1, uint16(Photometric_BlackIsZero), 8)
+	ifd.WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{1, 2})
+
+	// hide Seek, as the standard image package does not require it
+	r := struct{ io.Reader }{bytes.NewReader(b.Bytes())}
+	img, format, err := image.Decode(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "tiff", format)
+	assert.Equal(t, []byte{1, 2}, img.(*image.Gray).Pix)
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, "tiff", format)
+	assert.Equal(t, 2, config.Width)
+}
+
+func TestDecode_TooLarge(t *testing.T) {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	b.AddIFD().
+		WithUints32(uint16(tiff.ImageWidth), math.MaxUint32).
+		WithUints32(uint16(tiff.ImageHeight), math.MaxUint32).
+		WithUints16(uint16(tiff.BitsPerSample), 8).
+		WithUints16(uint16(tiff.PhotometricInterpretation), 1).
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{0})
+	_, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.Error(t, err)
+
+	defer func(max uint64) { maxDecodedSize = max }(maxDecodedSize)
+	maxDecodedSize = 1000
+
+	var buf bytes.Buffer
+	assert.NoError(t, Encode(&buf, image.NewGray(image.Rect(0, 0, 64, 64)), Options{TileSize: 16}))
+	_, err = Decode(bytes.NewReader(buf.Bytes()))
+	assert.Error(t, err)
+
+	p, err := tiff.NewParser(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	page, err := p.FirstPage()
+	assert.NoError(t, err)
+	region, err := DecodeRegion(p, page, image.Rect(0, 0, 20, 20))
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 20, 20), region.Bounds())
+	_, err = DecodeRegion(p, page, image.Rect(0, 0, 40, 40))
+	assert.Error(t, err)
+}
+
+func TestDecode_CraftedSizes(t *testing.T) {
+	// decoding must fail without allocating what the file announces (gigabytes) rather than what it holds
+	decode := func(data []byte) {
+		t.Helper()
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		_, err := Decode(bytes.NewReader(data))
+		runtime.ReadMemStats(&after)
+		assert.Error(t, err)
+		assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(64<<20))
+	}
+
+	// an entry whose count makes its value 3.6 GB long
+	b, ifd := newImageIFD(binary.LittleEndian, 1, 1, 1, 8)
+	ifd.WithUints16(uint16(tiff.ResolutionUnit), 2, 2, 2).
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{0})
+	data := b.Bytes()
+	entry := bytes.Index(data, []byte{0x28, 0x01, 0x03, 0x00, 0x03, 0x00, 0x00, 0x00})
+	assert.Positive(t, entry)
+	binary.LittleEndian.PutUint32(data[entry+4:], 0x6f000001)
+	decode(data)
+
+	// dimensions whose samples (2 GB) cannot be decoded from a single byte
+	b, ifd = newImageIFD(binary.LittleEndian, 0xffff, 0x7fff, 1, 8)
+	ifd.WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{0})
+	decode(b.Bytes())
+}
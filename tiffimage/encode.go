@@ -0,0 +1,367 @@
+package tiffimage
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"math"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// stripSize is the approximate size of the (uncompressed) strips written by Encode, as recommended by the TIFF
+// specification.
+const stripSize = 8 * 1024
+
+// SubfileType_ReducedResolution is the NewSubfileType of reduced-resolution versions (aka overviews) of an image.
+const SubfileType_ReducedResolution uint32 = 1
+
+// Options are the options of Encode. The zero value writes uncompressed strips.
+type Options struct {
+	// Compression is one of Compression_None (used if zero), Compression_LZW, Compression_Deflate or
+	// Compression_AdobeDeflate.
+	Compression uint32
+	// Predictor is one of Predictor_None (used if zero) or Predictor_Horizontal.
+	Predictor uint32
+	// TileSize is the width and height of tiles, which must be a multiple of 16. If zero, the image is written in strips.
+	TileSize int
+	// Overviews are the reduction factors (e.g. 2, 4, 8) of reduced-resolution versions of the image, written in
+	// sub-IFDs of IFD#0 so that viewers can quickly display it at lower zoom levels. Each pixel of an overview is the
+	// average of the corresponding pixels of the image (palette images use the top-left pixel instead).
+	Overviews []int
+	// BigTIFF forces writing a BigTIFF file, whose offsets are 64-bit. Otherwise, BigTIFF is only used if the file would
+	// be larger than 4 GiB.
+	BigTIFF bool
+}
+
+// Encode writes an image to w in TIFF format.
+//
+// *image.Gray, *image.Gray16, *image.Paletted and *Float32Image images are written as they are. *image.RGBA,
+// *image.NRGBA, *image.RGBA64 and *image.NRGBA64 images are written with an associated or unassociated alpha channel,
+// unless they are opaque. Any other image is converted to an *image.RGBA64 first.
+func Encode(w io.Writer, img image.Image, opts Options) error {
+	if opts.Compression == 0 {
+		opts.Compression = Compression_None
+	}
+	if opts.Predictor == 0 {
+		opts.Predictor = Predictor_None
+	}
+	switch opts.Compression {
+	case Compression_None, Compression_LZW, Compression_Deflate, Compression_AdobeDeflate:
+	default:
+		return fmt.Errorf("unsupported compression: %d", opts.Compression)
+	}
+	if opts.Predictor != Predictor_None && opts.Predictor != Predictor_Horizontal {
+		return fmt.Errorf("unsupported predictor: %d", opts.Predictor)
+	}
+	if opts.TileSize < 0 || opts.TileSize%16 != 0 {
+		return fmt.Errorf("tile size must be a multiple of 16: %d", opts.TileSize)
+	}
+	for _, factor := range opts.Overviews {
+		if factor < 2 {
+			return fmt.Errorf("invalid overview reduction factor: %d", factor)
+		}
+	}
+
+	e := newEncoder(img)
+	if e.width == 0 || e.height == 0 {
+		return errors.New("empty image")
+	}
+	if uint64(e.width) > math.MaxUint32 || uint64(e.height) > math.MaxUint32 {
+		return fmt.Errorf("image too large: %d x %d", e.width, e.height)
+	}
+
+	chunks, err := e.encodeChunks(opts)
+	if err != nil {
+		return err
+	}
+
+	var overviews []*ifd
+	for _, factor := range opts.Overviews {
+		o := e.reduce(factor)
+		chunks, err := o.encodeChunks(opts)
+		if err != nil {
+			return err
+		}
+		d := o.ifd(opts, chunks)
+		d.fields = append(d.fields, ifdField{id: tiff.NewSubfileType, dataType: tiff.DataType_ULong, values: []uint64{uint64(SubfileType_ReducedResolution)}})
+		overviews = append(overviews, d)
+	}
+
+	return writeTIFF(w, e.ifd(opts, chunks), overviews, opts.BigTIFF)
+}
+
+// encoder holds the samples of an image and their layout.
+type encoder struct {
+	width           int
+	height          int
+	bitsPerSample   int
+	samplesPerPixel int
+	sampleFormat    uint32
+	photometric     uint32
+	extraSamples    []uint32
+	colorMap        []uint32
+	pix             []byte // samples of the whole image, row by row, big-endian
+	chunkWidth      int    // width of a strip (i.e. the image width) or tile
+	chunkHeight     int    // height of a strip (i.e. rows per strip) or tile
+}
+
+// newEncoder extracts the samples of an image.
+func newEncoder(img image.Image) *encoder {
+	b := img.Bounds()
+	e := &encoder{
+		width:           b.Dx(),
+		height:          b.Dy(),
+		bitsPerSample:   8,
+		samplesPerPixel: 1,
+		sampleFormat:    SampleFormat_Uint,
+		photometric:     Photometric_BlackIsZero,
+	}
+
+	switch m := img.(type) {
+	case *image.Gray:
+		e.copyPix(m.Pix, m.Stride, m.PixOffset(b.Min.X, b.Min.Y), 1)
+	case *image.Gray16:
+		e.bitsPerSample = 16
+		e.copyPix(m.Pix, m.Stride, m.PixOffset(b.Min.X, b.Min.Y), 2)
+	case *image.Paletted:
+		e.photometric = Photometric_Palette
+		e.colorMap = make([]uint32, 3*256)
+		for i, c := range m.Palette[:min(len(m.Palette), 256)] { // colors past 256 cannot be indexed by pixels
+			r, g, b, _ := c.RGBA()
+			e.colorMap[i], e.colorMap[256+i], e.colorMap[512+i] = r, g, b
+		}
+		e.copyPix(m.Pix, m.Stride, m.PixOffset(b.Min.X, b.Min.Y), 1)
+	case *image.RGBA:
+		e.setRGB(m.Opaque(), ExtraSample_Associated, 8)
+		e.copyPix(m.Pix, m.Stride, m.PixOffset(b.Min.X, b.Min.Y), 4)
+	case *image.NRGBA:
+		e.setRGB(m.Opaque(), ExtraSample_Unassociated, 8)
+		e.copyPix(m.Pix, m.Stride, m.PixOffset(b.Min.X, b.Min.Y), 4)
+	case *image.RGBA64:
+		e.setRGB(m.Opaque(), ExtraSample_Associated, 16)
+		e.copyPix(m.Pix, m.Stride, m.PixOffset(b.Min.X, b.Min.Y), 8)
+	case *image.NRGBA64:
+		e.setRGB(m.Opaque(), ExtraSample_Unassociated, 16)
+		e.copyPix(m.Pix, m.Stride, m.PixOffset(b.Min.X, b.Min.Y), 8)
+	case *Float32Image:
+		e.bitsPerSample, e.samplesPerPixel, e.sampleFormat = 32, m.Channels, SampleFormat_Float
+		if m.Channels >= 3 {
+			e.photometric = Photometric_RGB
+		}
+		if m.Channels == 2 || m.Channels == 4 {
+			e.extraSamples = []uint32{ExtraSample_Unassociated}
+		}
+		e.pix = make([]byte, 0, e.width*e.height*m.Channels*4)
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			i := m.PixOffset(b.Min.X, y)
+			for _, v := range m.Pix[i : i+e.width*m.Channels] {
+				e.pix = binary.BigEndian.AppendUint32(e.pix, math.Float32bits(v))
+			}
+		}
+	default:
+		rgba := image.NewRGBA64(b)
+		draw.Draw(rgba, b, img, b.Min, draw.Src)
+		return newEncoder(rgba)
+	}
+
+	return e
+}
+
+// setRGB sets the layout of an RGB image, with an alpha channel of the given kind unless it is opaque.
+func (e *encoder) setRGB(opaque bool, alpha uint32, bitsPerSample int) {
+	e.photometric, e.bitsPerSample, e.samplesPerPixel = Photometric_RGB, bitsPerSample, 3
+	if !opaque {
+		e.samplesPerPixel = 4
+		e.extraSamples = []uint32{alpha}
+	}
+}
+
+// copyPix copies the samples of an image whose pixels are pixelSize bytes long, dropping the ones that are not written
+// (i.e. the alpha channel of opaque images). The samples of 16-bit images are already big-endian.
+func (e *encoder) copyPix(src []byte, stride, offset, pixelSize int) {
+	size := e.samplesPerPixel * e.bitsPerSample / 8
+	e.pix = make([]byte, e.width*e.height*size)
+	for y := 0; y < e.height; y++ {
+		row, dst := src[offset+y*stride:], e.pix[y*e.width*size:]
+		if size == pixelSize {
+			copy(dst, row[:e.width*size])
+			continue
+		}
+		for x := 0; x < e.width; x++ {
+			copy(dst[x*size:(x+1)*size], row[x*pixelSize:])
+		}
+	}
+}
+
+// reduce returns an encoder holding a version of the image whose width and height are reduced by the given factor.
+func (e *encoder) reduce(factor int) *encoder {
+	o := *e
+	o.width, o.height = (e.width+factor-1)/factor, (e.height+factor-1)/factor
+	bps := e.bitsPerSample / 8
+	o.pix = make([]byte, o.width*o.height*e.samplesPerPixel*bps)
+
+	sample := func(pix []byte, i int) float64 {
+		switch {
+		case bps == 1:
+			return float64(pix[i])
+		case bps == 2:
+			return float64(binary.BigEndian.Uint16(pix[2*i:]))
+		default:
+			return float64(math.Float32frombits(binary.BigEndian.Uint32(pix[4*i:])))
+		}
+	}
+
+	for y := 0; y < o.height; y++ {
+		for x := 0; x < o.width; x++ {
+			for s := 0; s < e.samplesPerPixel; s++ {
+				i := (y*o.width+x)*e.samplesPerPixel + s
+				if e.photometric == Photometric_Palette {
+					o.pix[i] = e.pix[(y*factor*e.width+x*factor)*e.samplesPerPixel+s]
+					continue
+				}
+
+				var sum float64
+				var n int
+				for yy := y * factor; yy < min((y+1)*factor, e.height); yy++ {
+					for xx := x * factor; xx < min((x+1)*factor, e.width); xx++ {
+						sum += sample(e.pix, (yy*e.width+xx)*e.samplesPerPixel+s)
+						n++
+					}
+				}
+				switch avg := sum / float64(n); {
+				case bps == 1:
+					o.pix[i] = uint8(math.Round(avg))
+				case bps == 2:
+					binary.BigEndian.PutUint16(o.pix[2*i:], uint16(math.Round(avg)))
+				default:
+					binary.BigEndian.PutUint32(o.pix[4*i:], math.Float32bits(float32(avg)))
+				}
+			}
+		}
+	}
+
+	return &o
+}
+
+// encodeChunks splits the samples in strips or tiles, applying the predictor and compression of the options.
+func (e *encoder) encodeChunks(opts Options) ([][]byte, error) {
+	pixelSize := e.samplesPerPixel * e.bitsPerSample / 8
+	if opts.TileSize > 0 {
+		e.chunkWidth, e.chunkHeight = opts.TileSize, opts.TileSize
+	} else {
+		e.chunkWidth, e.chunkHeight = e.width, min(e.height, max(1, stripSize/(e.width*pixelSize)))
+	}
+
+	across := (e.width + e.chunkWidth - 1) / e.chunkWidth
+	down := (e.height + e.chunkHeight - 1) / e.chunkHeight
+	chunks := make([][]byte, across*down)
+
+	for i := range chunks {
+		x0, y0 := (i%across)*e.chunkWidth, (i/across)*e.chunkHeight
+		rows := e.chunkHeight
+		if opts.TileSize == 0 {
+			rows = min(e.chunkHeight, e.height-y0) // only tiles are padded
+		}
+		cols := min(e.chunkWidth, e.width-x0)
+
+		data := make([]byte, rows*e.chunkWidth*pixelSize)
+		for r := 0; r < rows && y0+r < e.height; r++ {
+			start := ((y0+r)*e.width + x0) * pixelSize
+			row := data[r*e.chunkWidth*pixelSize : (r+1)*e.chunkWidth*pixelSize]
+			copy(row, e.pix[start:start+cols*pixelSize])
+			if opts.Predictor == Predictor_Horizontal {
+				e.applyPredictor(row)
+			}
+		}
+
+		var err error
+		if chunks[i], err = compress(opts.Compression, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return chunks, nil
+}
+
+// applyPredictor replaces the samples of a row with their difference to the corresponding samples of the previous
+// pixel (see decoder.undoPredictor).
+func (e *encoder) applyPredictor(row []byte) {
+	spp := e.samplesPerPixel
+	switch e.bitsPerSample {
+	case 8:
+		for i := len(row) - 1; i >= spp; i-- {
+			row[i] -= row[i-spp]
+		}
+	case 16:
+		for i := len(row) - 2; i >= 2*spp; i -= 2 {
+			binary.BigEndian.PutUint16(row[i:], binary.BigEndian.Uint16(row[i:])-binary.BigEndian.Uint16(row[i-2*spp:]))
+		}
+	case 32:
+		for i := len(row) - 4; i >= 4*spp; i -= 4 {
+			binary.BigEndian.PutUint32(row[i:], binary.BigEndian.Uint32(row[i:])-binary.BigEndian.Uint32(row[i-4*spp:]))
+		}
+	}
+}
+
+// ifd returns the IFD describing the image, whose data is made of the given strips or tiles.
+func (e *encoder) ifd(opts Options, chunks [][]byte) *ifd {
+	bitsPerSample := make([]uint64, e.samplesPerPixel)
+	for i := range bitsPerSample {
+		bitsPerSample[i] = uint64(e.bitsPerSample)
+	}
+
+	fields := []ifdField{
+		{id: tiff.ImageWidth, dataType: tiff.DataType_ULong, values: []uint64{uint64(e.width)}},
+		{id: tiff.ImageHeight, dataType: tiff.DataType_ULong, values: []uint64{uint64(e.height)}},
+		{id: tiff.BitsPerSample, dataType: tiff.DataType_UShort, values: bitsPerSample},
+		{id: tiff.Compression, dataType: tiff.DataType_UShort, values: []uint64{uint64(opts.Compression)}},
+		{id: tiff.PhotometricInterpretation, dataType: tiff.DataType_UShort, values: []uint64{uint64(e.photometric)}},
+		{id: tiff.SamplesPerPixel, dataType: tiff.DataType_UShort, values: []uint64{uint64(e.samplesPerPixel)}},
+		{id: tiff.XResolution, dataType: tiff.DataType_URational, values: []uint64{72, 1}},
+		{id: tiff.YResolution, dataType: tiff.DataType_URational, values: []uint64{72, 1}},
+		{id: tiff.PlanarConfiguration, dataType: tiff.DataType_UShort, values: []uint64{uint64(PlanarConfiguration_Contig)}},
+		{id: tiff.ResolutionUnit, dataType: tiff.DataType_UShort, values: []uint64{2}}, // inches
+	}
+	if opts.Predictor != Predictor_None {
+		fields = append(fields, ifdField{id: tiff.Predictor, dataType: tiff.DataType_UShort, values: []uint64{uint64(opts.Predictor)}})
+	}
+	if e.colorMap != nil {
+		fields = append(fields, ifdField{id: tiff.ColorMap, dataType: tiff.DataType_UShort, values: toUint64s(e.colorMap)})
+	}
+	if e.extraSamples != nil {
+		fields = append(fields, ifdField{id: tiff.ExtraSamples, dataType: tiff.DataType_UShort, values: toUint64s(e.extraSamples)})
+	}
+	if e.sampleFormat != SampleFormat_Uint {
+		formats := make([]uint64, e.samplesPerPixel)
+		for i := range formats {
+			formats[i] = uint64(e.sampleFormat)
+		}
+		fields = append(fields, ifdField{id: tiff.SampleFormat, dataType: tiff.DataType_UShort, values: formats})
+	}
+
+	d := &ifd{fields: fields, chunks: chunks, offsetsID: tiff.StripOffsets, lengthsID: tiff.StripByteCounts}
+	if opts.TileSize > 0 {
+		d.offsetsID, d.lengthsID = tiff.TileOffsets, tiff.TileByteCounts
+		d.fields = append(d.fields,
+			ifdField{id: tiff.TileWidth, dataType: tiff.DataType_ULong, values: []uint64{uint64(e.chunkWidth)}},
+			ifdField{id: tiff.TileLength, dataType: tiff.DataType_ULong, values: []uint64{uint64(e.chunkHeight)}},
+		)
+	} else {
+		d.fields = append(d.fields, ifdField{id: tiff.RowsPerStrip, dataType: tiff.DataType_ULong, values: []uint64{uint64(e.chunkHeight)}})
+	}
+
+	return d
+}
+
+func toUint64s(values []uint32) []uint64 {
+	out := make([]uint64, len(values))
+	for i, v := range values {
+		out[i] = uint64(v)
+	}
+
+	return out
+}
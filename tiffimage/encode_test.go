@@ -0,0 +1,186 @@
+package tiffimage
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/stretchr/testify/assert"
+)
+
+// testImages returns images of all types written as they are by Encode, with a size that is not a multiple of the tile
+// size used in tests.
+func testImages() map[string]image.Image {
+	rect := image.Rect(0, 0, 37, 21)
+	gray, gray16 := image.NewGray(rect), image.NewGray16(rect)
+	rgba, nrgba := image.NewRGBA(rect), image.NewNRGBA(rect)
+	rgba64, nrgba64 := image.NewRGBA64(rect), image.NewNRGBA64(rect)
+	opaque := image.NewNRGBA(rect)
+	paletted := image.NewPaletted(rect, color.Palette{color.Black, color.White, color.RGBA{R: 0xff, A: 0xff}})
+	float := NewFloat32Image(rect, 2)
+
+	for y := 0; y < rect.Dy(); y++ {
+		for x := 0; x < rect.Dx(); x++ {
+			v := uint16(x*1500 + y*700)
+			a := uint16(0xffff - y*3000)
+			gray.SetGray(x, y, color.Gray{Y: uint8(v >> 8)})
+			gray16.SetGray16(x, y, color.Gray16{Y: v})
+			rgba.SetRGBA(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: uint8(x * y), A: 0xff})
+			nrgba.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 200, A: uint8(a >> 8)})
+			rgba64.SetRGBA64(x, y, color.RGBA64{R: v / 2, G: v / 3, B: v / 4, A: v})
+			nrgba64.SetNRGBA64(x, y, color.NRGBA64{R: v, G: ^v, B: 0x1234, A: a})
+			opaque.SetNRGBA(x, y, color.NRGBA{R: uint8(y), G: uint8(x), B: 7, A: 0xff})
+			paletted.SetColorIndex(x, y, uint8((x+y)%3))
+			s := float.Samples(x, y)
+			s[0], s[1] = float32(x)/37, float32(y)/-21
+		}
+	}
+
+	return map[string]image.Image{
+		"gray":     gray,
+		"gray16":   gray16,
+		"rgba":     rgba,
+		"nrgba":    nrgba,
+		"rgba64":   rgba64,
+		"nrgba64":  nrgba64,
+		"opaque":   opaque,
+		"paletted": paletted,
+		"float":    float,
+	}
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	options := map[string]Options{
+		"uncompressed strips":  {},
+		"LZW with predictor":   {Compression: Compression_LZW, Predictor: Predictor_Horizontal},
+		"Deflate tiles":        {Compression: Compression_Deflate, TileSize: 16},
+		"LZW predictor tiles":  {Compression: Compression_LZW, Predictor: Predictor_Horizontal, TileSize: 32},
+		"PackBits unsupported": {Compression: Compression_PackBits},
+	}
+
+	for imageName, img := range testImages() {
+		for optionsName, opts := range options {
+			t.Run(imageName+"/"+optionsName, func(t *testing.T) {
+				var buf bytes.Buffer
+				err := Encode(&buf, img, opts)
+				if opts.Compression == Compression_PackBits {
+					assert.Error(t, err)
+					return
+				}
+				assert.NoError(t, err)
+
+				decoded, err := Decode(bytes.NewReader(buf.Bytes()))
+				assert.NoError(t, err)
+				assert.Equal(t, img.Bounds(), decoded.Bounds())
+
+				if float, ok := img.(*Float32Image); ok {
+					assert.Equal(t, float.Pix, decoded.(*Float32Image).Pix)
+					return
+				}
+				for y := 0; y < img.Bounds().Dy(); y++ {
+					for x := 0; x < img.Bounds().Dx(); x++ {
+						if !assert.Equal(t, rgba64(img.At(x, y)), rgba64(decoded.At(x, y)), "pixel (%d, %d)", x, y) {
+							return
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func rgba64(c color.Color) color.RGBA64 {
+	return color.RGBA64Model.Convert(c).(color.RGBA64)
+}
+
+func TestEncode_Layout(t *testing.T) {
+	img := testImages()["opaque"]
+
+	var buf bytes.Buffer
+	assert.NoError(t, Encode(&buf, img, Options{TileSize: 16}))
+
+	p, err := tiff.NewParser(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	page, err := p.FirstPage()
+	assert.NoError(t, err)
+
+	spp, _ := page.Uint(tiff.SamplesPerPixel)
+	assert.Equal(t, uint32(3), spp, "alpha is dropped from opaque images")
+	offsets, _ := page.Uints(tiff.TileOffsets)
+	assert.Len(t, offsets, 6)
+
+	decoded, err := Decode(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.IsType(t, &image.RGBA{}, decoded)
+
+	assert.Error(t, Encode(&buf, img, Options{TileSize: 20}), "tile size not a multiple of 16")
+	assert.Error(t, Encode(&buf, img, Options{Predictor: Predictor_FloatingPoint}))
+	assert.Error(t, Encode(&buf, image.NewGray(image.Rect(0, 0, 0, 0)), Options{}))
+}
+
+func TestEncode_LargePalette(t *testing.T) {
+	palette := make(color.Palette, 300)
+	for i := range palette {
+		palette[i] = color.Gray16{Y: uint16(i * 200)}
+	}
+	img := image.NewPaletted(image.Rect(0, 0, 2, 1), palette)
+	img.SetColorIndex(1, 0, 255)
+
+	var buf bytes.Buffer
+	assert.NoError(t, Encode(&buf, img, Options{}))
+
+	decoded, err := Decode(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Len(t, decoded.(*image.Paletted).Palette, 256)
+	assert.Equal(t, rgba64(img.At(1, 0)), rgba64(decoded.At(1, 0)))
+}
+
+func TestEncode_ConvertsOtherImages(t *testing.T) {
+	img := image.NewYCbCr(image.Rect(0, 0, 4, 4), image.YCbCrSubsampleRatio444)
+	for i := range img.Y {
+		img.Y[i], img.Cb[i], img.Cr[i] = uint8(i*16), 100, 200
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, Encode(&buf, img, Options{Compression: Compression_LZW}))
+
+	decoded, err := Decode(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.IsType(t, &image.NRGBA64{}, decoded)
+	assert.Equal(t, rgba64(img.At(3, 2)), rgba64(decoded.At(3, 2)))
+}
+
+func TestEncode_Overviews(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 64, 48))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i % 64 * 4) // horizontal gradient
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, Encode(&buf, img, Options{Compression: Compression_LZW, TileSize: 16, Overviews: []int{2, 4, 8}}))
+
+	p, err := tiff.NewParser(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	levels, err := p.Levels()
+	assert.NoError(t, err)
+	assert.Len(t, levels, 4)
+
+	for i, want := range []image.Rectangle{
+		image.Rect(0, 0, 64, 48),
+		image.Rect(0, 0, 32, 24),
+		image.Rect(0, 0, 16, 12),
+		image.Rect(0, 0, 8, 6),
+	} {
+		overview, err := DecodePage(p, levels[i].Page)
+		assert.NoError(t, err)
+		assert.Equal(t, want, overview.Bounds())
+	}
+
+	overview, err := DecodePage(p, levels[2].Page)
+	assert.NoError(t, err)
+	assert.Equal(t, color.Gray{Y: 6}, overview.At(0, 5), "average of 0, 4, 8 and 12")
+
+	assert.Error(t, Encode(&buf, img, Options{Overviews: []int{1}}))
+}
@@ -0,0 +1,171 @@
+package tiffimage
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Float32Image is an image whose samples are floating point values, as found in scientific TIFFs
+// (SampleFormat=IEEEFP). Samples are kept as they are in the file: when converted to colors (e.g. by At), they are
+// expected to be in the [0, 1] range and clamped otherwise.
+type Float32Image struct {
+	// Pix holds the samples of the image, interleaved (e.g. R, G, B, R, G, B, ...), rows top to bottom.
+	Pix []float32
+	// Stride is the number of samples between vertically adjacent pixels.
+	Stride int
+	// Channels is the number of samples per pixel: 1 for gray, 2 for gray and alpha, 3 for RGB, 4 for RGBA.
+	Channels int
+	Rect     image.Rectangle
+}
+
+// NewFloat32Image returns a new Float32Image with the given bounds and number of samples per pixel.
+func NewFloat32Image(r image.Rectangle, channels int) *Float32Image {
+	return &Float32Image{
+		Pix:      make([]float32, r.Dx()*r.Dy()*channels),
+		Stride:   r.Dx() * channels,
+		Channels: channels,
+		Rect:     r,
+	}
+}
+
+func (f *Float32Image) ColorModel() color.Model {
+	if f.Channels == 1 {
+		return color.Gray16Model
+	}
+	return color.NRGBA64Model
+}
+
+func (f *Float32Image) Bounds() image.Rectangle {
+	return f.Rect
+}
+
+func (f *Float32Image) At(x, y int) color.Color {
+	if !(image.Point{X: x, Y: y}.In(f.Rect)) {
+		if f.Channels == 1 {
+			return color.Gray16{}
+		}
+		return color.NRGBA64{}
+	}
+
+	s := f.Samples(x, y)
+	switch f.Channels {
+	case 1:
+		return color.Gray16{Y: toUint16(s[0])}
+	case 2:
+		return color.NRGBA64{R: toUint16(s[0]), G: toUint16(s[0]), B: toUint16(s[0]), A: toUint16(s[1])}
+	case 3:
+		return color.NRGBA64{R: toUint16(s[0]), G: toUint16(s[1]), B: toUint16(s[2]), A: 0xffff}
+	default:
+		return color.NRGBA64{R: toUint16(s[0]), G: toUint16(s[1]), B: toUint16(s[2]), A: toUint16(s[3])}
+	}
+}
+
+// Samples returns the samples of the pixel at (x, y).
+func (f *Float32Image) Samples(x, y int) []float32 {
+	i := f.PixOffset(x, y)
+	return f.Pix[i : i+f.Channels]
+}
+
+// PixOffset returns the index of the first sample of the pixel at (x, y).
+func (f *Float32Image) PixOffset(x, y int) int {
+	return (y-f.Rect.Min.Y)*f.Stride + (x-f.Rect.Min.X)*f.Channels
+}
+
+// Float64Image is like Float32Image, for images whose samples are 64-bit floating point values, kept at full
+// precision.
+type Float64Image struct {
+	// Pix holds the samples of the image, interleaved (e.g. R, G, B, R, G, B, ...), rows top to bottom.
+	Pix []float64
+	// Stride is the number of samples between vertically adjacent pixels.
+	Stride int
+	// Channels is the number of samples per pixel: 1 for gray, 2 for gray and alpha, 3 for RGB, 4 for RGBA.
+	Channels int
+	Rect     image.Rectangle
+}
+
+// NewFloat64Image returns a new Float64Image with the given bounds and number of samples per pixel.
+func NewFloat64Image(r image.Rectangle, channels int) *Float64Image {
+	return &Float64Image{
+		Pix:      make([]float64, r.Dx()*r.Dy()*channels),
+		Stride:   r.Dx() * channels,
+		Channels: channels,
+		Rect:     r,
+	}
+}
+
+func (f *Float64Image) ColorModel() color.Model {
+	if f.Channels == 1 {
+		return color.Gray16Model
+	}
+	return color.NRGBA64Model
+}
+
+func (f *Float64Image) Bounds() image.Rectangle {
+	return f.Rect
+}
+
+func (f *Float64Image) At(x, y int) color.Color {
+	if !(image.Point{X: x, Y: y}.In(f.Rect)) {
+		if f.Channels == 1 {
+			return color.Gray16{}
+		}
+		return color.NRGBA64{}
+	}
+
+	s := f.Samples(x, y)
+	switch f.Channels {
+	case 1:
+		return color.Gray16{Y: toUint16(s[0])}
+	case 2:
+		return color.NRGBA64{R: toUint16(s[0]), G: toUint16(s[0]), B: toUint16(s[0]), A: toUint16(s[1])}
+	case 3:
+		return color.NRGBA64{R: toUint16(s[0]), G: toUint16(s[1]), B: toUint16(s[2]), A: 0xffff}
+	default:
+		return color.NRGBA64{R: toUint16(s[0]), G: toUint16(s[1]), B: toUint16(s[2]), A: toUint16(s[3])}
+	}
+}
+
+// Samples returns the samples of the pixel at (x, y).
+func (f *Float64Image) Samples(x, y int) []float64 {
+	i := f.PixOffset(x, y)
+	return f.Pix[i : i+f.Channels]
+}
+
+// PixOffset returns the index of the first sample of the pixel at (x, y).
+func (f *Float64Image) PixOffset(x, y int) int {
+	return (y-f.Rect.Min.Y)*f.Stride + (x-f.Rect.Min.X)*f.Channels
+}
+
+// halfToFloat32 converts an IEEE 754 half precision (16-bit) floating point value to a float32, which represents all
+// of them exactly.
+func halfToFloat32(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exp := uint32(h>>10) & 0x1f
+	frac := uint32(h & 0x3ff)
+
+	switch exp {
+	case 0: // zero or subnormal
+		v := float32(frac) / (1 << 24)
+		if sign != 0 {
+			v = -v
+		}
+		return v
+	case 0x1f: // infinity or NaN
+		return math.Float32frombits(sign | 0xff<<23 | frac<<13)
+	default:
+		return math.Float32frombits(sign | (exp+127-15)<<23 | frac<<13)
+	}
+}
+
+// toUint16 converts a [0, 1] value to the [0, 0xffff] range, clamping values outside of it.
+func toUint16[F float32 | float64](v F) uint16 {
+	switch {
+	case v != v || v <= 0: // NaN or negative
+		return 0
+	case v >= 1:
+		return 0xffff
+	default:
+		return uint16(v*0xffff + 0.5)
+	}
+}
@@ -0,0 +1,72 @@
+package tiffimage
+
+import (
+	"errors"
+	"io"
+)
+
+// seekable returns r itself if it can seek, or a reader that buffers what it reads from r otherwise.
+func seekable(r io.Reader) io.ReadSeeker {
+	if rs, ok := r.(io.ReadSeeker); ok {
+		return rs
+	}
+
+	return &bufferedSeeker{r: r}
+}
+
+// bufferedSeeker makes a reader seekable, keeping in memory all bytes read so far. Seeking forward only reads from the
+// underlying reader as far as needed, so that e.g. reading the header and IFD#0 does not require reading the whole file.
+type bufferedSeeker struct {
+	r   io.Reader
+	buf []byte
+	off int64
+	err error // error returned by the underlying reader, if any
+}
+
+func (b *bufferedSeeker) Read(p []byte) (int, error) {
+	if err := b.fill(b.off + int64(len(p))); err != nil && b.off >= int64(len(b.buf)) {
+		return 0, err
+	}
+	n := copy(p, b.buf[min(b.off, int64(len(b.buf))):])
+	b.off += int64(n)
+
+	return n, nil
+}
+
+func (b *bufferedSeeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += b.off
+	case io.SeekEnd:
+		if err := b.fill(-1); err != io.EOF {
+			return 0, err
+		}
+		offset += int64(len(b.buf))
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	b.off = offset
+
+	return offset, nil
+}
+
+// fill reads from the underlying reader until the buffer holds size bytes (all remaining bytes if size is negative).
+func (b *bufferedSeeker) fill(size int64) error {
+	for b.err == nil && (size < 0 || int64(len(b.buf)) < size) {
+		if len(b.buf) == cap(b.buf) {
+			b.buf = append(b.buf, 0)[:len(b.buf)]
+		}
+		var n int
+		n, b.err = b.r.Read(b.buf[len(b.buf):cap(b.buf)])
+		b.buf = b.buf[:len(b.buf)+n]
+	}
+	if b.err == nil || (b.err == io.EOF && size >= 0 && int64(len(b.buf)) >= size) {
+		return nil
+	}
+
+	return b.err
+}
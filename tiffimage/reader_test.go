@@ -0,0 +1,41 @@
+package tiffimage
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBufferedSeeker(t *testing.T) {
+	src := bytes.NewReader([]byte("0123456789"))
+	b := seekable(iotest.OneByteReader(src))
+
+	buffer := make([]byte, 3)
+	_, err := b.Seek(4, io.SeekStart)
+	assert.NoError(t, err)
+	_, err = io.ReadFull(b, buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, "456", string(buffer))
+	assert.Equal(t, 3, src.Len(), "only the needed bytes are read")
+
+	_, err = b.Seek(-6, io.SeekCurrent)
+	assert.NoError(t, err)
+	_, err = io.ReadFull(b, buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, "123", string(buffer))
+
+	_, err = b.Seek(-2, io.SeekEnd)
+	assert.NoError(t, err)
+	n, err := b.Read(buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, "89", string(buffer[:n]))
+
+	_, err = b.Read(buffer)
+	assert.Equal(t, io.EOF, err)
+
+	_, err = b.Seek(-1, io.SeekStart)
+	assert.Error(t, err)
+}
@@ -0,0 +1,204 @@
+package tiffimage
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// ifdField is an entry of an IFD being written.
+type ifdField struct {
+	id       tiff.EntryID
+	dataType tiff.DataType // one of DataType_UShort, DataType_ULong, DataType_URational, DataType_IFD or their 64-bit versions
+	values   []uint64      // numerators and denominators alternate in rationals
+}
+
+// size returns the size of the field's value, in bytes.
+func (f ifdField) size() int {
+	switch f.dataType {
+	case tiff.DataType_UShort:
+		return 2 * len(f.values)
+	case tiff.DataType_ULong8, tiff.DataType_IFD8:
+		return 8 * len(f.values)
+	default:
+		return 4 * len(f.values)
+	}
+}
+
+// count returns the number of values of the field, as written in its entry.
+func (f ifdField) count() int {
+	if f.dataType == tiff.DataType_URational {
+		return len(f.values) / 2
+	}
+
+	return len(f.values)
+}
+
+// ifd is an IFD being written, together with the strips or tiles it points to.
+type ifd struct {
+	fields    []ifdField
+	chunks    [][]byte
+	offsetsID tiff.EntryID // entry holding the offsets of chunks, added when writing
+	lengthsID tiff.EntryID // entry holding the lengths of chunks, added when writing
+}
+
+// maxClassicOffset is the largest offset of a classic TIFF file (a variable, so that tests can lower it).
+var maxClassicOffset uint64 = math.MaxUint32
+
+// format holds the sizes of the structures of a classic TIFF or BigTIFF file.
+type format struct {
+	bigTIFF    bool
+	offsetSize int // size of offsets, and of the value (or offset) of entries
+	countSize  int // size of the number of entries of an IFD
+}
+
+var (
+	classicFormat = format{offsetSize: 4, countSize: 2}
+	bigFormat     = format{bigTIFF: true, offsetSize: 8, countSize: 8}
+)
+
+func (f format) headerSize() int {
+	return 2 * f.offsetSize
+}
+
+func (f format) ifdSize(entries int) int {
+	return f.countSize + entries*(4+2*f.offsetSize) + f.offsetSize
+}
+
+// writeTIFF writes a (big-endian) TIFF file made of a main IFD (IFD#0) and its sub-IFDs, if any. The file is laid out
+// as follows: header, then each IFD followed by the values that do not fit in its entries, then the image data of each
+// IFD. Unless bigTIFF is set, a BigTIFF file is only written if offsets do not fit in 32 bits.
+func writeTIFF(w io.Writer, main *ifd, subIFDs []*ifd, bigTIFF bool) error {
+	f := classicFormat
+	if bigTIFF {
+		f = bigFormat
+	}
+
+	ifds := append([]*ifd{main}, subIFDs...)
+	fields := make([][]ifdField, len(ifds))
+	offsets, lengths := make([][]uint64, len(ifds)), make([][]uint64, len(ifds))
+	for i, d := range ifds {
+		offsets[i], lengths[i] = make([]uint64, len(d.chunks)), make([]uint64, len(d.chunks))
+		fields[i] = append(slices.Clone(d.fields),
+			ifdField{id: d.offsetsID, dataType: tiff.DataType_ULong, values: offsets[i]},
+			ifdField{id: d.lengthsID, dataType: tiff.DataType_ULong, values: lengths[i]},
+		)
+	}
+	subIFDOffsets := make([]uint64, len(subIFDs))
+	if len(subIFDs) > 0 {
+		fields[0] = append(fields[0], ifdField{id: tiff.SubIFDs, dataType: tiff.DataType_IFD, values: subIFDOffsets})
+	}
+	for _, fs := range fields {
+		sort.Slice(fs, func(i, j int) bool { return fs[i].id < fs[j].id })
+	}
+
+	ifdOffsets := make([]uint64, len(ifds))
+	for {
+		if f.bigTIFF {
+			for i, d := range ifds {
+				for j := range fields[i] {
+					switch fields[i][j].id {
+					case d.offsetsID, d.lengthsID:
+						fields[i][j].dataType = tiff.DataType_ULong8
+					case tiff.SubIFDs:
+						fields[i][j].dataType = tiff.DataType_IFD8
+					}
+				}
+			}
+		}
+
+		position := uint64(f.headerSize())
+		for i := range ifds {
+			ifdOffsets[i] = position
+			position += uint64(f.ifdSize(len(fields[i])))
+			for _, field := range fields[i] {
+				if field.size() > f.offsetSize {
+					position += uint64(field.size())
+				}
+			}
+		}
+		copy(subIFDOffsets, ifdOffsets[1:])
+		for i, d := range ifds {
+			for j, chunk := range d.chunks {
+				offsets[i][j], lengths[i][j] = position, uint64(len(chunk))
+				position += uint64(len(chunk))
+			}
+		}
+
+		if f.bigTIFF || position <= maxClassicOffset {
+			break
+		}
+		f = bigFormat
+	}
+
+	buffer := []byte{'M', 'M'}
+	if f.bigTIFF {
+		buffer = binary.BigEndian.AppendUint16(buffer, 43)
+		buffer = binary.BigEndian.AppendUint16(buffer, 8) // size of offsets
+		buffer = binary.BigEndian.AppendUint16(buffer, 0)
+	} else {
+		buffer = binary.BigEndian.AppendUint16(buffer, 42)
+	}
+	buffer = appendUint(buffer, ifdOffsets[0], f.offsetSize)
+	for i := range ifds {
+		buffer = appendIFD(buffer, f, fields[i])
+	}
+
+	if _, err := w.Write(buffer); err != nil {
+		return err
+	}
+	for _, d := range ifds {
+		for _, chunk := range d.chunks {
+			if _, err := w.Write(chunk); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// appendIFD appends an IFD, followed by the values that do not fit in its entries, to b. It is not linked to any
+// other IFD.
+func appendIFD(b []byte, f format, fields []ifdField) []byte {
+	valuesOffset := len(b) + f.ifdSize(len(fields))
+	var values []byte
+
+	b = appendUint(b, uint64(len(fields)), f.countSize)
+	for _, field := range fields {
+		b = binary.BigEndian.AppendUint16(b, uint16(field.id))
+		b = binary.BigEndian.AppendUint16(b, uint16(field.dataType))
+		b = appendUint(b, uint64(field.count()), f.offsetSize)
+
+		value := make([]byte, 0, field.size())
+		for _, v := range field.values {
+			value = appendUint(value, v, field.size()/len(field.values))
+		}
+		if len(value) <= f.offsetSize {
+			b = append(b, value...)
+			b = append(b, make([]byte, f.offsetSize-len(value))...)
+		} else {
+			b = appendUint(b, uint64(valuesOffset+len(values)), f.offsetSize)
+			values = append(values, value...)
+		}
+	}
+	b = appendUint(b, 0, f.offsetSize) // no next IFD
+
+	return append(b, values...)
+}
+
+// appendUint appends a big-endian unsigned integer of the given size (2, 4 or 8 bytes) to b.
+func appendUint(b []byte, v uint64, size int) []byte {
+	switch size {
+	case 2:
+		return binary.BigEndian.AppendUint16(b, uint16(v))
+	case 4:
+		return binary.BigEndian.AppendUint32(b, uint32(v))
+	default:
+		return binary.BigEndian.AppendUint64(b, v)
+	}
+}
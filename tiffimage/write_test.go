@@ -0,0 +1,89 @@
+package tiffimage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/stretchr/testify/assert"
+)
+
+// readBigTIFF reads the entries of the first IFD of a BigTIFF file written by Encode, returning their values.
+func readBigTIFF(t *testing.T, data []byte) map[tiff.EntryID][]uint64 {
+	order := binary.BigEndian
+	assert.Equal(t, "MM", string(data[:2]))
+	assert.Equal(t, uint16(43), order.Uint16(data[2:]))
+	assert.Equal(t, uint16(8), order.Uint16(data[4:]))
+
+	offset := order.Uint64(data[8:])
+	count := order.Uint64(data[offset:])
+	entries := make(map[tiff.EntryID][]uint64)
+	for i := uint64(0); i < count; i++ {
+		entry := data[offset+8+i*20:]
+		id, dt, n := tiff.EntryID(order.Uint16(entry)), tiff.DataType(order.Uint16(entry[2:])), order.Uint64(entry[4:])
+
+		size := map[tiff.DataType]uint64{tiff.DataType_UShort: 2, tiff.DataType_ULong: 4, tiff.DataType_URational: 8, tiff.DataType_ULong8: 8}[dt]
+		value := entry[12:]
+		if n*size > 8 {
+			value = data[order.Uint64(entry[12:]):]
+		}
+		for j := uint64(0); j < n; j++ {
+			switch size {
+			case 2:
+				entries[id] = append(entries[id], uint64(order.Uint16(value[j*2:])))
+			case 4:
+				entries[id] = append(entries[id], uint64(order.Uint32(value[j*4:])))
+			default:
+				entries[id] = append(entries[id], order.Uint64(value[j*8:]))
+			}
+		}
+	}
+
+	return entries
+}
+
+func TestEncode_BigTIFF(t *testing.T) {
+	img := testImages()["rgba64"]
+	opts := Options{Compression: Compression_Deflate, TileSize: 16}
+
+	var classic bytes.Buffer
+	assert.NoError(t, Encode(&classic, img, opts))
+	p, err := tiff.NewParser(bytes.NewReader(classic.Bytes()))
+	assert.NoError(t, err)
+	page, err := p.FirstPage()
+	assert.NoError(t, err)
+	tiles, err := p.ReadTiles(page)
+	assert.NoError(t, err)
+
+	opts.BigTIFF, opts.Overviews = true, []int{2}
+	var big bytes.Buffer
+	assert.NoError(t, Encode(&big, img, opts))
+	entries := readBigTIFF(t, big.Bytes())
+
+	assert.Equal(t, []uint64{37}, entries[tiff.ImageWidth])
+	assert.Equal(t, []uint64{16, 16, 16, 16}, entries[tiff.BitsPerSample])
+	assert.Equal(t, []uint64{16}, entries[tiff.TileWidth])
+	assert.Len(t, entries[tiff.SubIFDs], 1)
+	offsets, lengths := entries[tiff.TileOffsets], entries[tiff.TileByteCounts]
+	assert.Len(t, offsets, len(tiles))
+	for i, tile := range tiles {
+		assert.Equal(t, tile, big.Bytes()[offsets[i]:offsets[i]+lengths[i]])
+	}
+}
+
+func TestEncode_BigTIFFWhenTooLarge(t *testing.T) {
+	defer func(max uint64) { maxClassicOffset = max }(maxClassicOffset)
+	maxClassicOffset = 1000
+
+	img := testImages()["gray"] // 37x21, i.e. less than 1000 bytes of data
+	var buf bytes.Buffer
+	assert.NoError(t, Encode(&buf, img, Options{}))
+	assert.Equal(t, uint16(42), binary.BigEndian.Uint16(buf.Bytes()[2:]))
+
+	img = testImages()["rgba"] // 37x21x3 bytes of data
+	buf.Reset()
+	assert.NoError(t, Encode(&buf, img, Options{}))
+	entries := readBigTIFF(t, buf.Bytes())
+	assert.Equal(t, []uint64{37 * 21 * 3}, entries[tiff.StripByteCounts])
+}
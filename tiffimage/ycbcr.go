@@ -0,0 +1,218 @@
+package tiffimage
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// Chroma positionings, as written in the YCbCrPositioning entry.
+const (
+	YCbCrPositioning_Centered uint32 = 1
+	YCbCrPositioning_Cosited  uint32 = 2
+)
+
+// ycbcr holds the parameters needed to convert YCbCr samples to RGB.
+type ycbcr struct {
+	subH, subV     int        // chroma subsampling factors
+	positioning    uint32     // position of chroma samples relative to luma samples
+	coefficients   [3]float64 // LumaRed, LumaGreen, LumaBlue
+	referenceBlack [3]float64 // codes of black (Y) and zero chroma (Cb, Cr)
+	referenceWhite [3]float64 // codes of white (Y) and maximum chroma (Cb, Cr)
+}
+
+// newYCbCr reads the YCbCr parameters of a page, using the defaults of the TIFF specification for missing entries.
+func newYCbCr(page tiff.Page) (*ycbcr, error) {
+	y := &ycbcr{
+		subH:           2,
+		subV:           2,
+		positioning:    YCbCrPositioning_Centered,
+		coefficients:   [3]float64{0.299, 0.587, 0.114},
+		referenceBlack: [3]float64{0, 128, 128},
+		referenceWhite: [3]float64{255, 255, 255},
+	}
+
+	if values, ok := page.Uints(tiff.YCbCrSubSampling); ok && len(values) == 2 {
+		y.subH, y.subV = int(values[0]), int(values[1])
+	}
+	for _, sub := range []int{y.subH, y.subV} {
+		if sub != 1 && sub != 2 && sub != 4 {
+			return nil, fmt.Errorf("unsupported YCbCr subsampling: %d x %d", y.subH, y.subV)
+		}
+	}
+	if v, ok := page.Uint(tiff.YCbCrPositioning); ok {
+		y.positioning = v
+	}
+	if entry, ok := page.Entries[tiff.YCbCrCoefficients]; ok {
+		if values, err := entry.Floats(); err == nil && len(values) == 3 {
+			copy(y.coefficients[:], values)
+		}
+	}
+	if entry, ok := page.Entries[tiff.ReferenceBlackWhite]; ok {
+		if values, err := entry.Floats(); err == nil && len(values) == 6 {
+			for i := 0; i < 3; i++ {
+				y.referenceBlack[i], y.referenceWhite[i] = values[2*i], values[2*i+1]
+			}
+		}
+	}
+
+	return y, nil
+}
+
+// subsampled tells whether chroma samples are subsampled.
+func (y *ycbcr) subsampled() bool {
+	return y.subH != 1 || y.subV != 1
+}
+
+// toRGB converts a YCbCr color to RGB, according to section 21 of the TIFF specification.
+func (y *ycbcr) toRGB(yy, cb, cr float64) (uint8, uint8, uint8) {
+	yy = (yy - y.referenceBlack[0]) * 255 / (y.referenceWhite[0] - y.referenceBlack[0])
+	cb = (cb - y.referenceBlack[1]) * 127 / (y.referenceWhite[1] - y.referenceBlack[1])
+	cr = (cr - y.referenceBlack[2]) * 127 / (y.referenceWhite[2] - y.referenceBlack[2])
+
+	lumaRed, lumaGreen, lumaBlue := y.coefficients[0], y.coefficients[1], y.coefficients[2]
+	r := cr*(2-2*lumaRed) + yy
+	b := cb*(2-2*lumaBlue) + yy
+	g := (yy - lumaBlue*b - lumaRed*r) / lumaGreen
+
+	return clamp8(r), clamp8(g), clamp8(b)
+}
+
+// clamp8 rounds a value to the nearest integer in the [0, 255] range.
+func clamp8(v float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(255, v))))
+}
+
+// decodeSubsampledChunks decompresses all strips or tiles of an image with subsampled chroma, whose samples are stored
+// in data units made of subH * subV luma samples followed by one Cb and one Cr sample. It returns a buffer holding the
+// full-resolution luma plane, followed by the (subsampled) Cb and Cr planes.
+func (d *decoder) decodeSubsampledChunks(chunks [][]byte) ([]byte, error) {
+	if d.bitsPerSample != 8 || d.samplesPerPixel != 3 {
+		return nil, fmt.Errorf("unsupported subsampled YCbCr layout: %d samples of %d bits", d.samplesPerPixel, d.bitsPerSample)
+	}
+	if d.predictor != Predictor_None {
+		return nil, fmt.Errorf("predictor %d not supported with subsampled YCbCr", d.predictor)
+	}
+	if d.planar {
+		return nil, errors.New("separate planes not supported with subsampled YCbCr")
+	}
+
+	if _, err := d.decodedSize(d.width, d.height); err != nil {
+		return nil, err
+	}
+	h, v := d.ycbcr.subH, d.ycbcr.subV
+	unitsAcross, unitsDown := (d.width+h-1)/h, (d.height+v-1)/v
+	lumaSize, chromaSize := d.width*d.height, unitsAcross*unitsDown
+	if err := d.checkDataSize(lumaSize+2*chromaSize, chunks); err != nil {
+		return nil, err
+	}
+	pix := make([]byte, lumaSize+2*chromaSize)
+
+	across := (d.width + d.chunkWidth - 1) / d.chunkWidth
+	down := (d.height + d.chunkHeight - 1) / d.chunkHeight
+	if len(chunks) < across*down {
+		return nil, fmt.Errorf("expected %d strips or tiles, found %d", across*down, len(chunks))
+	}
+	chunkUnitsAcross := (d.chunkWidth + h - 1) / h
+	unitSize := h*v + 2
+
+	for i := 0; i < across*down; i++ {
+		x0, y0 := (i%across)*d.chunkWidth, (i/across)*d.chunkHeight
+		chunkUnitsDown := (min(d.chunkHeight, d.height-y0) + v - 1) / v
+
+		data, err := decompress(d.compression, chunks[i], d.jpegTables)
+		if err != nil {
+			return nil, fmt.Errorf("strip or tile #%d: %w", i, err)
+		}
+		if len(data) < chunkUnitsAcross*chunkUnitsDown*unitSize {
+			return nil, fmt.Errorf("strip or tile #%d: expected %d bytes, found %d", i, chunkUnitsAcross*chunkUnitsDown*unitSize, len(data))
+		}
+
+		for uy := 0; uy < chunkUnitsDown; uy++ {
+			for ux := 0; ux < chunkUnitsAcross; ux++ {
+				unit := data[(uy*chunkUnitsAcross+ux)*unitSize:]
+				for j := 0; j < v; j++ {
+					for k := 0; k < h; k++ {
+						x, y := x0+ux*h+k, y0+uy*v+j
+						if x < d.width && y < d.height {
+							pix[y*d.width+x] = unit[j*h+k]
+						}
+					}
+				}
+
+				gx, gy := x0/h+ux, y0/v+uy
+				if gx < unitsAcross && gy < unitsDown {
+					pix[lumaSize+gy*unitsAcross+gx] = unit[h*v]
+					pix[lumaSize+chromaSize+gy*unitsAcross+gx] = unit[h*v+1]
+				}
+			}
+		}
+	}
+
+	return pix, nil
+}
+
+// toYCbCrImage converts decoded YCbCr samples to an RGB image. Subsampled chroma samples are interpolated bilinearly,
+// taking into account where they are positioned relative to luma samples.
+func (d *decoder) toYCbCrImage(pix []byte) (image.Image, error) {
+	img := image.NewRGBA(d.bounds())
+
+	if !d.ycbcr.subsampled() {
+		if d.bitsPerSample != 8 || d.samplesPerPixel < 3 {
+			return nil, fmt.Errorf("unsupported YCbCr layout: %d samples of %d bits", d.samplesPerPixel, d.bitsPerSample)
+		}
+		for i := 0; i < d.width*d.height; i++ {
+			s := pix[i*d.samplesPerPixel:]
+			r, g, b := d.ycbcr.toRGB(float64(s[0]), float64(s[1]), float64(s[2]))
+			img.Pix[4*i], img.Pix[4*i+1], img.Pix[4*i+2], img.Pix[4*i+3] = r, g, b, 0xff
+		}
+		return img, nil
+	}
+
+	h, v := d.ycbcr.subH, d.ycbcr.subV
+	unitsAcross, unitsDown := (d.width+h-1)/h, (d.height+v-1)/v
+	lumaSize, chromaSize := d.width*d.height, unitsAcross*unitsDown
+	cbPlane, crPlane := pix[lumaSize:lumaSize+chromaSize], pix[lumaSize+chromaSize:]
+
+	// offsets of the first chroma sample relative to the first luma sample, in luma samples
+	var offsetH, offsetV float64
+	if d.ycbcr.positioning == YCbCrPositioning_Centered {
+		offsetH, offsetV = float64(h-1)/2, float64(v-1)/2
+	}
+
+	for y := 0; y < d.height; y++ {
+		cy0, cy1, wy := interpolationWeights((float64(y)-offsetV)/float64(v), unitsDown)
+		for x := 0; x < d.width; x++ {
+			cx0, cx1, wx := interpolationWeights((float64(x)-offsetH)/float64(h), unitsAcross)
+
+			chroma := func(plane []byte) float64 {
+				top := float64(plane[cy0*unitsAcross+cx0])*(1-wx) + float64(plane[cy0*unitsAcross+cx1])*wx
+				bottom := float64(plane[cy1*unitsAcross+cx0])*(1-wx) + float64(plane[cy1*unitsAcross+cx1])*wx
+				return top*(1-wy) + bottom*wy
+			}
+
+			i := y*d.width + x
+			r, g, b := d.ycbcr.toRGB(float64(pix[i]), chroma(cbPlane), chroma(crPlane))
+			img.Pix[4*i], img.Pix[4*i+1], img.Pix[4*i+2], img.Pix[4*i+3] = r, g, b, 0xff
+		}
+	}
+
+	return img, nil
+}
+
+// interpolationWeights returns the indexes of the 2 samples surrounding a (fractional) position and the weight of the
+// second one, clamping positions to the [0, n-1] range.
+func interpolationWeights(pos float64, n int) (int, int, float64) {
+	if pos <= 0 {
+		return 0, 0, 0
+	}
+	if pos >= float64(n-1) {
+		return n - 1, n - 1, 0
+	}
+	i := int(pos)
+
+	return i, i + 1, pos - float64(i)
+}
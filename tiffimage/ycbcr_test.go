@@ -0,0 +1,68 @@
+package tiffimage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecode_YCbCrSubsampled(t *testing.T) {
+	// 3x3 image with 2x2 subsampling, in 2 strips: the last data unit of each row and the last row are padded
+	b, ifd := newImageIFD(binary.LittleEndian, 3, 3, uint16(Photometric_YCbCr), 8, 8, 8)
+	ifd.WithUints16(uint16(tiff.RowsPerStrip), 2).
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts),
+			[]byte{10, 20, 40, 50, 128, 128, 30, 0, 60, 0, 128, 128},
+			[]byte{70, 80, 0, 0, 128, 128, 90, 0, 0, 0, 128, 128},
+		)
+
+	img, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	rgba, ok := img.(*image.RGBA)
+	assert.True(t, ok)
+	for i, want := range []uint8{10, 20, 30, 40, 50, 60, 70, 80, 90} {
+		assert.Equal(t, color.RGBA{R: want, G: want, B: want, A: 255}, rgba.RGBAAt(i%3, i/3), "pixel %d", i)
+	}
+}
+
+func TestDecode_YCbCrChromaInterpolation(t *testing.T) {
+	// 4x1 image with 2x1 subsampling: cosited chroma samples sit on pixels 0 and 2
+	b, ifd := newImageIFD(binary.LittleEndian, 4, 1, uint16(Photometric_YCbCr), 8, 8, 8)
+	ifd.WithUints16(uint16(tiff.YCbCrSubSampling), 2, 1).
+		WithUints16(uint16(tiff.YCbCrPositioning), uint16(YCbCrPositioning_Cosited)).
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{100, 100, 128, 128, 100, 100, 128, 178})
+
+	img, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	rgba := img.(*image.RGBA)
+	assert.EqualValues(t, 100, rgba.RGBAAt(0, 0).R)
+	assert.EqualValues(t, 135, rgba.RGBAAt(1, 0).R)
+	assert.EqualValues(t, 170, rgba.RGBAAt(2, 0).R)
+	assert.EqualValues(t, 170, rgba.RGBAAt(3, 0).R)
+}
+
+func TestDecode_YCbCrNotSubsampled(t *testing.T) {
+	b, ifd := newImageIFD(binary.LittleEndian, 1, 1, uint16(Photometric_YCbCr), 8, 8, 8)
+	ifd.WithUints16(uint16(tiff.YCbCrSubSampling), 1, 1).
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{76, 85, 255})
+
+	img, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.NoError(t, err)
+
+	assert.Equal(t, color.RGBA{R: 254, A: 255}, img.(*image.RGBA).RGBAAt(0, 0))
+}
+
+func TestDecode_YCbCrUnsupportedSubsampling(t *testing.T) {
+	b, ifd := newImageIFD(binary.LittleEndian, 1, 1, uint16(Photometric_YCbCr), 8, 8, 8)
+	ifd.WithUints16(uint16(tiff.YCbCrSubSampling), 3, 1).
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{76, 85, 255})
+
+	_, err := Decode(bytes.NewReader(b.Bytes()))
+	assert.Error(t, err)
+}
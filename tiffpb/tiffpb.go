@@ -0,0 +1,305 @@
+// Package tiffpb converts the entries of TIFF files to and from the protobuf messages defined in tiffpb.proto, so that
+// they can be passed between services.
+//
+// The Go types of this package are written by hand, so that the module does not depend on the protobuf runtime: they
+// mirror the messages of tiffpb.proto, and Marshal and Unmarshal encode them in the protobuf wire format, so that they
+// can be exchanged with code generated from tiffpb.proto in any language.
+package tiffpb
+
+import (
+	"fmt"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// Metadata holds the entries of a TIFF file, grouped by the IFD they were read from.
+type Metadata struct {
+	Ifds []*IFD
+}
+
+type IFD struct {
+	Group   tiff.Group
+	Offset  int64
+	Entries []*Entry
+}
+
+type Entry struct {
+	Id       uint32
+	DataType uint32
+	Length   uint32
+	RawValue uint32
+	Value    *Value
+}
+
+// Value holds the decoded value of an entry: Kind tells which of the other fields is used, and whether it holds a
+// single value or a list of values.
+type Value struct {
+	Kind      tiff.ValueKind
+	String    string
+	Bytes     []byte      // UByte, Byte, Bytes, SBytes (two's complement)
+	Uints     []uint32    // Uint16, Uints16, Uint32, Uints32
+	Ints      []int32     // Int16, Ints16, Int32, Ints32
+	Rationals []*Rational // URational, URationals, Rational, Rationals
+	Floats    []float64   // Float32, Floats32, Float64, Floats64
+	Strings   []string    // Strings
+	Uints64   []uint64    // Uint64, Uints64
+	Ints64    []int64     // Int64, Ints64
+}
+
+type Rational struct {
+	Numerator   int64
+	Denominator int64
+}
+
+// ToProto converts entries (e.g. returned by Parser.Find) to a Metadata message, grouping them by IFD. IFDs are listed
+// in the order they first appear in entries.
+func ToProto(entries []tiff.Entry) *Metadata {
+	m := &Metadata{}
+	ifds := make(map[int64]*IFD)
+	for _, entry := range entries {
+		ifd, ok := ifds[entry.IFDOffset]
+		if !ok {
+			ifd = &IFD{Group: entry.Group, Offset: entry.IFDOffset}
+			ifds[entry.IFDOffset] = ifd
+			m.Ifds = append(m.Ifds, ifd)
+		}
+		ifd.Entries = append(ifd.Entries, EntryToProto(entry))
+	}
+
+	return m
+}
+
+// FromProto converts a Metadata message back to entries, in the order of its IFDs.
+func FromProto(m *Metadata) ([]tiff.Entry, error) {
+	var entries []tiff.Entry
+	for _, ifd := range m.Ifds {
+		for _, e := range ifd.Entries {
+			entry, err := EntryFromProto(e)
+			if err != nil {
+				return nil, err
+			}
+			entry.Group = ifd.Group
+			entry.IFDOffset = ifd.Offset
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// EntryToProto converts an entry to an Entry message. The group and IFD offset of the entry are held by the IFD message
+// (see ToProto).
+func EntryToProto(entry tiff.Entry) *Entry {
+	return &Entry{
+		Id:       uint32(entry.ID),
+		DataType: uint32(entry.DataType),
+		Length:   entry.Length,
+		RawValue: entry.RawValue,
+		Value:    valueToProto(entry.Value),
+	}
+}
+
+// EntryFromProto converts an Entry message back to an entry.
+func EntryFromProto(e *Entry) (tiff.Entry, error) {
+	if e.Id > 0xffff || e.DataType > 0xffff {
+		return tiff.Entry{}, fmt.Errorf("invalid entry 0x%x of data type %d", e.Id, e.DataType)
+	}
+
+	entry := tiff.Entry{
+		ID:       tiff.EntryID(e.Id),
+		DataType: tiff.DataType(e.DataType),
+		Length:   e.Length,
+		RawValue: e.RawValue,
+	}
+	if e.Value != nil {
+		value, err := valueFromProto(e.Value)
+		if err != nil {
+			return tiff.Entry{}, fmt.Errorf("entry 0x%04x: %w", e.Id, err)
+		}
+		entry.Value = value
+	}
+
+	return entry, nil
+}
+
+func valueToProto(v tiff.EntryValue) *Value {
+	res := &Value{Kind: v.Kind()}
+	switch res.Kind {
+	case tiff.ValueKind_UByte:
+		res.Bytes = []byte{*v.UByte}
+	case tiff.ValueKind_String:
+		res.String = *v.String
+	case tiff.ValueKind_Uint16:
+		res.Uints = []uint32{uint32(*v.Uint16)}
+	case tiff.ValueKind_Uints16:
+		res.Uints = convert(v.Uints16, func(x uint16) uint32 { return uint32(x) })
+	case tiff.ValueKind_Uint32:
+		res.Uints = []uint32{*v.Uint32}
+	case tiff.ValueKind_Uints32:
+		res.Uints = v.Uints32
+	case tiff.ValueKind_URational:
+		res.Rationals = []*Rational{uRationalToProto(*v.URational)}
+	case tiff.ValueKind_URationals:
+		res.Rationals = convert(v.URationals, uRationalToProto)
+	case tiff.ValueKind_Byte:
+		res.Bytes = []byte{*v.Byte}
+	case tiff.ValueKind_Bytes:
+		res.Bytes = v.Bytes
+	case tiff.ValueKind_Int16:
+		res.Ints = []int32{int32(*v.Int16)}
+	case tiff.ValueKind_Ints16:
+		res.Ints = convert(v.Ints16, func(x int16) int32 { return int32(x) })
+	case tiff.ValueKind_Int32:
+		res.Ints = []int32{*v.Int32}
+	case tiff.ValueKind_Ints32:
+		res.Ints = v.Ints32
+	case tiff.ValueKind_Rational:
+		res.Rationals = []*Rational{rationalToProto(*v.Rational)}
+	case tiff.ValueKind_Rationals:
+		res.Rationals = convert(v.Rationals, rationalToProto)
+	case tiff.ValueKind_Float32:
+		res.Floats = []float64{float64(*v.Float32)}
+	case tiff.ValueKind_Floats32:
+		res.Floats = convert(v.Floats32, func(x float32) float64 { return float64(x) })
+	case tiff.ValueKind_Float64:
+		res.Floats = []float64{*v.Float64}
+	case tiff.ValueKind_Floats64:
+		res.Floats = v.Floats64
+	case tiff.ValueKind_Strings:
+		res.Strings = v.Strings
+	case tiff.ValueKind_SBytes:
+		res.Bytes = convert(v.SBytes, func(x int8) byte { return byte(x) })
+	case tiff.ValueKind_Uint64:
+		res.Uints64 = []uint64{*v.Uint64}
+	case tiff.ValueKind_Uints64:
+		res.Uints64 = v.Uints64
+	case tiff.ValueKind_Int64:
+		res.Ints64 = []int64{*v.Int64}
+	case tiff.ValueKind_Ints64:
+		res.Ints64 = v.Ints64
+	}
+
+	return res
+}
+
+func valueFromProto(v *Value) (tiff.EntryValue, error) {
+	var res tiff.EntryValue
+	var count int
+	switch v.Kind {
+	case tiff.ValueKind_None:
+		return res, nil
+	case tiff.ValueKind_String:
+		res.String = &v.String
+		return res, nil
+	case tiff.ValueKind_UByte, tiff.ValueKind_Byte:
+		count = len(v.Bytes)
+	case tiff.ValueKind_Bytes:
+		res.Bytes = v.Bytes
+		return res, nil
+	case tiff.ValueKind_SBytes:
+		res.SBytes = convert(v.Bytes, func(x byte) int8 { return int8(x) })
+		return res, nil
+	case tiff.ValueKind_Uint16, tiff.ValueKind_Uints16, tiff.ValueKind_Uint32, tiff.ValueKind_Uints32:
+		count = len(v.Uints)
+	case tiff.ValueKind_Int16, tiff.ValueKind_Ints16, tiff.ValueKind_Int32, tiff.ValueKind_Ints32:
+		count = len(v.Ints)
+	case tiff.ValueKind_URational, tiff.ValueKind_URationals, tiff.ValueKind_Rational, tiff.ValueKind_Rationals:
+		count = len(v.Rationals)
+	case tiff.ValueKind_Float32, tiff.ValueKind_Floats32, tiff.ValueKind_Float64, tiff.ValueKind_Floats64:
+		count = len(v.Floats)
+	case tiff.ValueKind_Uint64, tiff.ValueKind_Uints64:
+		count = len(v.Uints64)
+	case tiff.ValueKind_Int64, tiff.ValueKind_Ints64:
+		count = len(v.Ints64)
+	case tiff.ValueKind_Strings:
+		if len(v.Strings) == 0 {
+			return res, fmt.Errorf("%s value holds no values", v.Kind)
+		}
+		res.String, res.Strings = &v.Strings[0], v.Strings
+		return res, nil
+	default:
+		return res, fmt.Errorf("unknown value kind %d", int(v.Kind))
+	}
+	if !v.Kind.Slice() && count != 1 {
+		return res, fmt.Errorf("%s value holds %d values instead of 1", v.Kind, count)
+	}
+
+	switch v.Kind {
+	case tiff.ValueKind_UByte:
+		res.UByte = &v.Bytes[0]
+	case tiff.ValueKind_Byte:
+		res.Byte = &v.Bytes[0]
+	case tiff.ValueKind_Uint16:
+		value := uint16(v.Uints[0])
+		res.Uint16 = &value
+	case tiff.ValueKind_Uints16:
+		res.Uints16 = convert(v.Uints, func(x uint32) uint16 { return uint16(x) })
+	case tiff.ValueKind_Uint32:
+		res.Uint32 = &v.Uints[0]
+	case tiff.ValueKind_Uints32:
+		res.Uints32 = v.Uints
+	case tiff.ValueKind_Int16:
+		value := int16(v.Ints[0])
+		res.Int16 = &value
+	case tiff.ValueKind_Ints16:
+		res.Ints16 = convert(v.Ints, func(x int32) int16 { return int16(x) })
+	case tiff.ValueKind_Int32:
+		res.Int32 = &v.Ints[0]
+	case tiff.ValueKind_Ints32:
+		res.Ints32 = v.Ints
+	case tiff.ValueKind_URational:
+		value := uRationalFromProto(v.Rationals[0])
+		res.URational = &value
+	case tiff.ValueKind_URationals:
+		res.URationals = convert(v.Rationals, uRationalFromProto)
+	case tiff.ValueKind_Rational:
+		value := rationalFromProto(v.Rationals[0])
+		res.Rational = &value
+	case tiff.ValueKind_Rationals:
+		res.Rationals = convert(v.Rationals, rationalFromProto)
+	case tiff.ValueKind_Float32:
+		value := float32(v.Floats[0])
+		res.Float32 = &value
+	case tiff.ValueKind_Floats32:
+		res.Floats32 = convert(v.Floats, func(x float64) float32 { return float32(x) })
+	case tiff.ValueKind_Float64:
+		res.Float64 = &v.Floats[0]
+	case tiff.ValueKind_Floats64:
+		res.Floats64 = v.Floats
+	case tiff.ValueKind_Uint64:
+		res.Uint64 = &v.Uints64[0]
+	case tiff.ValueKind_Uints64:
+		res.Uints64 = v.Uints64
+	case tiff.ValueKind_Int64:
+		res.Int64 = &v.Ints64[0]
+	case tiff.ValueKind_Ints64:
+		res.Ints64 = v.Ints64
+	}
+
+	return res, nil
+}
+
+func uRationalToProto(r tiff.URational) *Rational {
+	return &Rational{Numerator: int64(r.Numerator), Denominator: int64(r.Denominator)}
+}
+
+func uRationalFromProto(r *Rational) tiff.URational {
+	return tiff.URational{Numerator: uint32(r.Numerator), Denominator: uint32(r.Denominator)}
+}
+
+func rationalToProto(r tiff.Rational) *Rational {
+	return &Rational{Numerator: int64(r.Numerator), Denominator: int64(r.Denominator)}
+}
+
+func rationalFromProto(r *Rational) tiff.Rational {
+	return tiff.Rational{Numerator: int32(r.Numerator), Denominator: int32(r.Denominator)}
+}
+
+func convert[S, T any](values []S, f func(S) T) []T {
+	res := make([]T, len(values))
+	for i, v := range values {
+		res[i] = f(v)
+	}
+
+	return res
+}
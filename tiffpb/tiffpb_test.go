@@ -0,0 +1,102 @@
+package tiffpb
+
+import (
+	"os"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundTrip(t *testing.T) {
+	r, err := os.Open("../tiff/testdata/image.cr2")
+	assert.NoError(t, err)
+	defer r.Close()
+
+	p, err := tiff.NewParser(r)
+	assert.NoError(t, err)
+
+	entries, err := p.Find(func(tiff.Entry) bool { return true })
+	assert.NoError(t, err)
+
+	data, err := ToProto(entries).Marshal()
+	assert.NoError(t, err)
+
+	var m Metadata
+	assert.NoError(t, m.Unmarshal(data))
+	assert.Equal(t, []int64{16, 446, 46958, 48752, 48782, 48966}, offsets(m))
+
+	decoded, err := FromProto(&m)
+	assert.NoError(t, err)
+	assert.Equal(t, entries, decoded)
+}
+
+func offsets(m Metadata) []int64 {
+	var res []int64
+	for _, ifd := range m.Ifds {
+		res = append(res, ifd.Offset)
+	}
+
+	return res
+}
+
+func TestEntry_Marshal(t *testing.T) {
+	width := uint16(5184)
+	e := EntryToProto(tiff.Entry{ID: tiff.ImageWidth, DataType: tiff.DataType_UShort, Length: 1, RawValue: 5184, Value: tiff.EntryValue{Uint16: &width}})
+
+	data, err := e.Marshal()
+	assert.NoError(t, err)
+	// as encoded by the protobuf runtime
+	assert.Equal(t, []byte{0x08, 0x80, 0x02, 0x10, 0x03, 0x18, 0x01, 0x20, 0xc0, 0x28, 0x2a, 0x06, 0x08, 0x03, 0x22, 0x02, 0xc0, 0x28}, data)
+}
+
+func TestValue(t *testing.T) {
+	b, s := byte(0x80), "Canon"
+	i16 := int16(-2)
+	f32 := float32(1.5)
+	r := tiff.Rational{Numerator: -1, Denominator: 3}
+	u64 := uint64(1<<63 + 1)
+
+	tests := []tiff.EntryValue{
+		{},
+		{UByte: &b},
+		{Byte: &b},
+		{String: &s},
+		{Bytes: []byte{1, 2, 3}},
+		{Int16: &i16},
+		{Ints32: []int32{-1, 1 << 30}},
+		{Uints16: []uint16{8, 8, 8}},
+		{URationals: []tiff.URational{{Numerator: 1, Denominator: 40}, {Numerator: 1 << 31, Denominator: 1}}},
+		{Rational: &r},
+		{Float32: &f32},
+		{Floats64: []float64{-0.5, 2}},
+		{String: &s, Strings: []string{s, "", "EOS"}},
+		{SBytes: []int8{-128, 0, 127}},
+		{Uint64: &u64},
+		{Ints64: []int64{-1 << 63, 0, 1<<63 - 1}},
+	}
+
+	for _, value := range tests {
+		t.Run(value.Kind().String(), func(t *testing.T) {
+			data, err := EntryToProto(tiff.Entry{ID: 1, Value: value}).Marshal()
+			assert.NoError(t, err)
+
+			var e Entry
+			assert.NoError(t, e.Unmarshal(data))
+			entry, err := EntryFromProto(&e)
+			assert.NoError(t, err)
+			assert.Equal(t, value, entry.Value)
+		})
+	}
+}
+
+func TestEntryFromProto_Invalid(t *testing.T) {
+	_, err := EntryFromProto(&Entry{Id: 1, Value: &Value{Kind: tiff.ValueKind_Uint16, Uints: []uint32{1, 2}}})
+	assert.Error(t, err)
+
+	_, err = EntryFromProto(&Entry{Id: 0x10000})
+	assert.Error(t, err)
+
+	var m Metadata
+	assert.Error(t, m.Unmarshal([]byte{0x0a, 0x05, 0x08}))
+}
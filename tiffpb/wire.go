@@ -0,0 +1,353 @@
+package tiffpb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// Wire types of the protobuf encoding
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("truncated message")
+
+// Marshal encodes the message in the protobuf wire format.
+func (m *Metadata) Marshal() ([]byte, error) {
+	return m.append(nil), nil
+}
+
+// Unmarshal decodes a message encoded in the protobuf wire format, replacing the content of m.
+func (m *Metadata) Unmarshal(data []byte) error {
+	*m = Metadata{}
+
+	return decode(data, func(field int, wire int, value uint64, b []byte) error {
+		if field == 1 && wire == wireBytes {
+			ifd := &IFD{}
+			if err := ifd.unmarshal(b); err != nil {
+				return err
+			}
+			m.Ifds = append(m.Ifds, ifd)
+		}
+		return nil
+	})
+}
+
+// Marshal encodes the message in the protobuf wire format.
+func (e *Entry) Marshal() ([]byte, error) {
+	return e.append(nil), nil
+}
+
+// Unmarshal decodes a message encoded in the protobuf wire format, replacing the content of e.
+func (e *Entry) Unmarshal(data []byte) error {
+	return e.unmarshal(data)
+}
+
+func (m *Metadata) append(buf []byte) []byte {
+	for _, ifd := range m.Ifds {
+		buf = appendMessage(buf, 1, ifd.append(nil))
+	}
+
+	return buf
+}
+
+func (ifd *IFD) append(buf []byte) []byte {
+	buf = appendVarint(buf, 1, uint64(ifd.Group))
+	buf = appendVarint(buf, 2, uint64(ifd.Offset))
+	for _, e := range ifd.Entries {
+		buf = appendMessage(buf, 3, e.append(nil))
+	}
+
+	return buf
+}
+
+func (ifd *IFD) unmarshal(data []byte) error {
+	*ifd = IFD{}
+
+	return decode(data, func(field int, wire int, value uint64, b []byte) error {
+		switch {
+		case field == 1 && wire == wireVarint:
+			ifd.Group = tiff.Group(value)
+		case field == 2 && wire == wireVarint:
+			ifd.Offset = int64(value)
+		case field == 3 && wire == wireBytes:
+			e := &Entry{}
+			if err := e.unmarshal(b); err != nil {
+				return err
+			}
+			ifd.Entries = append(ifd.Entries, e)
+		}
+		return nil
+	})
+}
+
+func (e *Entry) append(buf []byte) []byte {
+	buf = appendVarint(buf, 1, uint64(e.Id))
+	buf = appendVarint(buf, 2, uint64(e.DataType))
+	buf = appendVarint(buf, 3, uint64(e.Length))
+	buf = appendVarint(buf, 4, uint64(e.RawValue))
+	if e.Value != nil {
+		buf = appendMessage(buf, 5, e.Value.append(nil))
+	}
+
+	return buf
+}
+
+func (e *Entry) unmarshal(data []byte) error {
+	*e = Entry{}
+
+	return decode(data, func(field int, wire int, value uint64, b []byte) error {
+		switch {
+		case field == 1 && wire == wireVarint:
+			e.Id = uint32(value)
+		case field == 2 && wire == wireVarint:
+			e.DataType = uint32(value)
+		case field == 3 && wire == wireVarint:
+			e.Length = uint32(value)
+		case field == 4 && wire == wireVarint:
+			e.RawValue = uint32(value)
+		case field == 5 && wire == wireBytes:
+			e.Value = &Value{}
+			return e.Value.unmarshal(b)
+		}
+		return nil
+	})
+}
+
+func (v *Value) append(buf []byte) []byte {
+	buf = appendVarint(buf, 1, uint64(v.Kind))
+	if v.String != "" {
+		buf = appendBytes(buf, 2, []byte(v.String))
+	}
+	if len(v.Bytes) > 0 {
+		buf = appendBytes(buf, 3, v.Bytes)
+	}
+	if len(v.Uints) > 0 {
+		var packed []byte
+		for _, x := range v.Uints {
+			packed = binary.AppendUvarint(packed, uint64(x))
+		}
+		buf = appendBytes(buf, 4, packed)
+	}
+	if len(v.Ints) > 0 {
+		var packed []byte
+		for _, x := range v.Ints {
+			packed = binary.AppendVarint(packed, int64(x)) // zigzag encoding, as sint32
+		}
+		buf = appendBytes(buf, 5, packed)
+	}
+	for _, r := range v.Rationals {
+		buf = appendMessage(buf, 6, r.append(nil))
+	}
+	if len(v.Floats) > 0 {
+		var packed []byte
+		for _, x := range v.Floats {
+			packed = binary.LittleEndian.AppendUint64(packed, math.Float64bits(x))
+		}
+		buf = appendBytes(buf, 7, packed)
+	}
+	for _, str := range v.Strings {
+		buf = appendBytes(buf, 8, []byte(str))
+	}
+	if len(v.Uints64) > 0 {
+		var packed []byte
+		for _, x := range v.Uints64 {
+			packed = binary.AppendUvarint(packed, x)
+		}
+		buf = appendBytes(buf, 9, packed)
+	}
+	if len(v.Ints64) > 0 {
+		var packed []byte
+		for _, x := range v.Ints64 {
+			packed = binary.AppendVarint(packed, x) // zigzag encoding, as sint64
+		}
+		buf = appendBytes(buf, 10, packed)
+	}
+
+	return buf
+}
+
+func (v *Value) unmarshal(data []byte) error {
+	*v = Value{}
+
+	return decode(data, func(field int, wire int, value uint64, b []byte) error {
+		switch {
+		case field == 1 && wire == wireVarint:
+			v.Kind = tiff.ValueKind(value)
+		case field == 2 && wire == wireBytes:
+			v.String = string(b)
+		case field == 3 && wire == wireBytes:
+			v.Bytes = append([]byte{}, b...)
+		case field == 4 && wire == wireVarint:
+			v.Uints = append(v.Uints, uint32(value))
+		case field == 4 && wire == wireBytes:
+			for len(b) > 0 {
+				x, n := binary.Uvarint(b)
+				if n <= 0 {
+					return errTruncated
+				}
+				v.Uints = append(v.Uints, uint32(x))
+				b = b[n:]
+			}
+		case field == 5 && wire == wireVarint:
+			v.Ints = append(v.Ints, int32(zigzag(value)))
+		case field == 5 && wire == wireBytes:
+			for len(b) > 0 {
+				x, n := binary.Uvarint(b)
+				if n <= 0 {
+					return errTruncated
+				}
+				v.Ints = append(v.Ints, int32(zigzag(x)))
+				b = b[n:]
+			}
+		case field == 6 && wire == wireBytes:
+			r := &Rational{}
+			if err := r.unmarshal(b); err != nil {
+				return err
+			}
+			v.Rationals = append(v.Rationals, r)
+		case field == 7 && wire == wireFixed64:
+			v.Floats = append(v.Floats, math.Float64frombits(value))
+		case field == 7 && wire == wireBytes:
+			if len(b)%8 != 0 {
+				return errTruncated
+			}
+			for ; len(b) > 0; b = b[8:] {
+				v.Floats = append(v.Floats, math.Float64frombits(binary.LittleEndian.Uint64(b)))
+			}
+		case field == 8 && wire == wireBytes:
+			v.Strings = append(v.Strings, string(b))
+		case field == 9 && wire == wireVarint:
+			v.Uints64 = append(v.Uints64, value)
+		case field == 9 && wire == wireBytes:
+			for len(b) > 0 {
+				x, n := binary.Uvarint(b)
+				if n <= 0 {
+					return errTruncated
+				}
+				v.Uints64 = append(v.Uints64, x)
+				b = b[n:]
+			}
+		case field == 10 && wire == wireVarint:
+			v.Ints64 = append(v.Ints64, zigzag(value))
+		case field == 10 && wire == wireBytes:
+			for len(b) > 0 {
+				x, n := binary.Uvarint(b)
+				if n <= 0 {
+					return errTruncated
+				}
+				v.Ints64 = append(v.Ints64, zigzag(x))
+				b = b[n:]
+			}
+		}
+		return nil
+	})
+}
+
+func (r *Rational) append(buf []byte) []byte {
+	buf = binary.AppendUvarint(buf, 1<<3|wireVarint)
+	buf = binary.AppendVarint(buf, r.Numerator)
+	buf = binary.AppendUvarint(buf, 2<<3|wireVarint)
+
+	return binary.AppendVarint(buf, r.Denominator)
+}
+
+func (r *Rational) unmarshal(data []byte) error {
+	*r = Rational{}
+
+	return decode(data, func(field int, wire int, value uint64, b []byte) error {
+		switch {
+		case field == 1 && wire == wireVarint:
+			r.Numerator = zigzag(value)
+		case field == 2 && wire == wireVarint:
+			r.Denominator = zigzag(value)
+		}
+		return nil
+	})
+}
+
+// appendVarint appends a varint field, unless its value is the default one (0).
+func appendVarint(buf []byte, field int, value uint64) []byte {
+	if value == 0 {
+		return buf
+	}
+	buf = binary.AppendUvarint(buf, uint64(field)<<3|wireVarint)
+
+	return binary.AppendUvarint(buf, value)
+}
+
+func appendBytes(buf []byte, field int, value []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(field)<<3|wireBytes)
+	buf = binary.AppendUvarint(buf, uint64(len(value)))
+
+	return append(buf, value...)
+}
+
+// appendMessage appends an embedded message field, which is encoded like a bytes field.
+func appendMessage(buf []byte, field int, message []byte) []byte {
+	return appendBytes(buf, field, message)
+}
+
+// zigzag decodes a zigzag-encoded value (sint32 or sint64).
+func zigzag(value uint64) int64 {
+	return int64(value>>1) ^ -int64(value&1)
+}
+
+// decode calls visit for each field of a message, with its number and wire type, and either its value (varint and fixed
+// fields) or its bytes (length-delimited fields). Fields with unknown numbers are expected to be ignored by visit, as
+// the protobuf specification requires.
+func decode(data []byte, visit func(field int, wire int, value uint64, b []byte) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errTruncated
+		}
+		data = data[n:]
+		field, wire := int(key>>3), int(key&7)
+		if field == 0 {
+			return errors.New("invalid field number 0")
+		}
+
+		var value uint64
+		var b []byte
+		switch wire {
+		case wireVarint:
+			value, n = binary.Uvarint(data)
+			if n <= 0 {
+				return errTruncated
+			}
+			data = data[n:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return errTruncated
+			}
+			value, data = binary.LittleEndian.Uint64(data), data[8:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return errTruncated
+			}
+			value, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		case wireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return errTruncated
+			}
+			b, data = data[n:n+int(length)], data[n+int(length):]
+		default:
+			return fmt.Errorf("unsupported wire type %d of field %d", wire, field)
+		}
+
+		if err := visit(field, wire, value, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
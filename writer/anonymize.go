@@ -0,0 +1,121 @@
+package writer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// Profile tells how Anonymize scrubs files: which entries it removes, and which value it normalizes Software to.
+type Profile struct {
+	Delete   []tiff.EntryID // entries removed from all IFDs, e.g. GPSInfo for all GPS data
+	Software string         // value Software is set to, if the file has it, hiding the software used; removed if empty
+}
+
+// DefaultProfile returns the profile removing GPS data, serial numbers (of the camera, its body and its lens), the
+// names of the owner of the camera, of the creator and of the computer used, maker notes (including the copy kept by
+// DNG files), unique IDs and the other entries tracking the lineage of the image (ImageUniqueID, OriginalRawFileName
+// and DocumentName), and the XMP, IPTC and Photoshop data embedded in IFD#0, which may hold any of the above (e.g.
+// xmpMM:DocumentID), and normalizing Software to "Anonymized".
+func DefaultProfile() Profile {
+	return Profile{
+		Delete: []tiff.EntryID{
+			tiff.GPSInfo,
+			tiff.CameraSerialNumber,
+			tiff.BodySerialNumber,
+			tiff.LensSerialNumber,
+			tiff.CameraOwnerName,
+			tiff.Artist,
+			tiff.HostComputer,
+			tiff.MakerNotes,
+			tiff.DNGPrivateData,
+			tiff.XMLPacket,
+			tiff.IPTC,
+			tiff.PhotoshopSettings,
+			tiff.ImageUniqueID,
+			tiff.OriginalRawFileName,
+			tiff.DocumentName,
+		},
+		Software: "Anonymized",
+	}
+}
+
+// Anonymize scrubs the file following profile, then checks that none of the entries the profile removes is left. The
+// removed values are zero-filled, whatever WithZeroFill says, and the result only depends on the file and the profile:
+// anonymizing the same file twice gives the same bytes.
+func (w *Writer) Anonymize(profile Profile) error {
+	edits := Edits{Delete: slices.Clone(profile.Delete)}
+	if profile.Software == "" {
+		if !slices.Contains(edits.Delete, tiff.Software) {
+			edits.Delete = append(edits.Delete, tiff.Software)
+		}
+	} else {
+		p, err := tiff.NewParser(bytes.NewReader(w.data))
+		if err != nil {
+			return err
+		}
+		entries, err := p.Parse(tiff.Software)
+		if err != nil {
+			return err
+		}
+		if _, ok := entries[tiff.Software]; ok {
+			edits.Set = map[tiff.EntryID]string{tiff.Software: profile.Software}
+		}
+	}
+
+	keepOrphans := w.keepOrphans
+	w.keepOrphans = false
+	defer func() { w.keepOrphans = keepOrphans }()
+	if err := w.Apply(edits); err != nil {
+		return err
+	}
+
+	p, err := tiff.NewParser(bytes.NewReader(w.data))
+	if err != nil {
+		return err
+	}
+	infos, err := p.ListIDs()
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		if slices.Contains(edits.Delete, info.ID) {
+			return fmt.Errorf("%s is left in the IFD at offset %d", info.ID.Name(), info.IFDOffset)
+		}
+	}
+
+	return nil
+}
+
+// Anonymize copies a TIFF or JPEG file from r to w, scrubbed following profile (see Writer.Anonymize), e.g. before
+// publishing images whose sources must be protected. JPEG files also lose their XMP and IPTC segments and their
+// comments.
+func Anonymize(r io.Reader, w io.Writer, profile Profile) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	if bytes.HasPrefix(data, jpegSOI) {
+		data, err = rewriteJPEG(data, func(w *Writer) error { return w.Anonymize(profile) }, true)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	wr, err := newWriter(data)
+	if err != nil {
+		return err
+	}
+	if err := wr.Anonymize(profile); err != nil {
+		return err
+	}
+	_, err = wr.WriteTo(w)
+
+	return err
+}
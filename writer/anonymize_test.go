@@ -0,0 +1,135 @@
+package writer
+
+import (
+	"bytes"
+	"image/jpeg"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter_Anonymize(t *testing.T) {
+	w, err := newWriter(newTestFile())
+	assert.NoError(t, err)
+	assert.NoError(t, w.SetSoftware("Photo Editor 1.2.3 (build 4567)"))
+	assert.NoError(t, w.SetString(tiff.ImageUniqueID, "0123456789abcdef0123456789abcdef"))
+	data := bytes.Clone(w.Bytes())
+
+	tests := []struct {
+		name     string
+		profile  Profile
+		software string // empty if removed
+		secrets  []string
+		retained []string
+	}{
+		{
+			name:     "default",
+			profile:  DefaultProfile(),
+			software: "Anonymized",
+			secrets:  []string{string(latitude), "maker note secrets", "Jane Doe", "Photo Editor", "0123456789abcdef"},
+			retained: []string{"Secret Camera Co", "2021:11:19 12:21:10"},
+		},
+		{
+			name:     "without Software",
+			profile:  Profile{Delete: []tiff.EntryID{tiff.GPSInfo}},
+			secrets:  []string{string(latitude), "Photo Editor"},
+			retained: []string{"maker note secrets", "Jane Doe", "0123456789abcdef"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, Anonymize(bytes.NewReader(data), &buf, tt.profile))
+			out := buf.Bytes()
+
+			_, strips := firstPage(t, out)
+			assert.Equal(t, [][]byte{{1, 2, 3, 4}}, strips)
+			for _, secret := range tt.secrets {
+				assert.False(t, bytes.Contains(out, []byte(secret)), secret)
+			}
+			for _, value := range tt.retained {
+				assert.True(t, bytes.Contains(out, []byte(value)), value)
+			}
+
+			p, err := tiff.NewParser(bytes.NewReader(out))
+			assert.NoError(t, err)
+			entries, err := p.Parse(tiff.Software, tiff.ImageUniqueID)
+			assert.NoError(t, err)
+			if slices.Contains(tt.profile.Delete, tiff.ImageUniqueID) {
+				assert.NotContains(t, entries, tiff.ImageUniqueID)
+			} else {
+				assert.Contains(t, entries, tiff.ImageUniqueID)
+			}
+			if tt.software == "" {
+				assert.NotContains(t, entries, tiff.Software)
+			} else {
+				assert.Equal(t, tt.software, entries[tiff.Software].Text())
+			}
+
+			var again bytes.Buffer
+			assert.NoError(t, Anonymize(bytes.NewReader(data), &again, tt.profile))
+			assert.Equal(t, out, again.Bytes())
+		})
+	}
+
+	t.Run("invalid profile", func(t *testing.T) {
+		w, err := newWriter(bytes.Clone(data))
+		assert.NoError(t, err)
+		assert.Error(t, w.Anonymize(Profile{Delete: []tiff.EntryID{tiff.StripOffsets}}))
+		assert.Error(t, w.Anonymize(Profile{Delete: []tiff.EntryID{tiff.Software}, Software: "Anonymized"}))
+	})
+}
+
+func TestAnonymize_CR2(t *testing.T) {
+	data, err := os.ReadFile("../tiff/testdata/image.cr2")
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, Anonymize(bytes.NewReader(data), &buf, DefaultProfile()))
+	assert.False(t, bytes.Contains(buf.Bytes(), []byte("Riccardo Zen")))
+
+	p, err := tiff.NewParser(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	has, err := p.HasGroup(tiff.Group_MakerNote)
+	assert.NoError(t, err)
+	assert.False(t, has)
+	has, err = p.HasGroup(tiff.Group_GPSInfo)
+	assert.NoError(t, err)
+	assert.False(t, has)
+	entries, err := p.Parse(tiff.Make, tiff.DateTimeOriginal)
+	assert.NoError(t, err)
+	assert.Equal(t, "Canon", entries[tiff.Make].Text())
+	assert.Equal(t, "2021:11:19 12:21:10", entries[tiff.DateTimeOriginal].Text())
+}
+
+func TestAnonymize_JPEG(t *testing.T) {
+	w, err := newWriter(newTestFile())
+	assert.NoError(t, err)
+	assert.NoError(t, w.SetSoftware("Ed 1"))
+	jpg := newTestJPEG(t)
+	exif, ok := jpegExif(jpg)
+	assert.True(t, ok)
+	// replace the Exif segment with one holding Software, which anonymizing makes larger
+	data := append(bytes.Clone(jpg[:len(jpegSOI)]), segment(markerAPP1, exifHeader, w.Bytes())...)
+	data = append(data, jpg[len(jpegSOI)+4+len(exifHeader)+len(exif):]...)
+
+	var buf bytes.Buffer
+	assert.NoError(t, Anonymize(bytes.NewReader(data), &buf, DefaultProfile()))
+	for _, secret := range []string{"Jane Doe", "a comment", "maker note secrets", "Ed 1"} {
+		assert.False(t, bytes.Contains(buf.Bytes(), []byte(secret)), secret)
+	}
+	_, err = jpeg.Decode(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+
+	exif, ok = jpegExif(buf.Bytes())
+	assert.True(t, ok)
+	p, err := tiff.NewParser(bytes.NewReader(exif))
+	assert.NoError(t, err)
+	entries, err := p.Parse(tiff.Software, tiff.Make)
+	assert.NoError(t, err)
+	assert.Equal(t, "Anonymized", entries[tiff.Software].Text())
+	assert.Equal(t, "Secret Camera Co", entries[tiff.Make].Text())
+}
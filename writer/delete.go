@@ -0,0 +1,36 @@
+package writer
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// imageDataEntries lists the entries locating strips or tiles, which Delete refuses to remove: the image could no
+// longer be read, and its data would be zero-filled.
+var imageDataEntries = []tiff.EntryID{tiff.StripOffsets, tiff.StripByteCounts, tiff.TileOffsets, tiff.TileByteCounts}
+
+// Delete removes the given entries (e.g. CameraSerialNumber or Artist, before sharing a file) from all the IFDs holding
+// them, compacting their tables: the following entries move up, and counts are adjusted. As the IDs of GPS entries
+// collide with those of other IFDs, GPS entries are only removed from GPSInfo IFDs, and other entries from other IFDs.
+// Removing an entry pointing to a sub-IFD (e.g. GPSInfo) removes the whole sub-IFD. Values that are no longer
+// referenced are zero-filled, unless disabled with WithZeroFill. Entries that are missing are ignored.
+func (w *Writer) Delete(ids ...tiff.EntryID) error {
+	for _, id := range ids {
+		if slices.Contains(imageDataEntries, id) {
+			return fmt.Errorf("cannot delete %s, which locates image data", id.Name())
+		}
+	}
+
+	return w.deleteEntries(func(id tiff.EntryID) bool { return slices.Contains(ids, id) })
+}
+
+// WithZeroFill tells whether edits zero-fill the bytes of the file that are no longer referenced (e.g. removed or
+// replaced values), so that they cannot be recovered from the file, which they do by default. Without it, those bytes
+// are left as they were, e.g. to keep edits minimal when the file is under version control.
+func (w *Writer) WithZeroFill(enabled bool) *Writer {
+	w.keepOrphans = !enabled
+
+	return w
+}
@@ -0,0 +1,151 @@
+package writer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter_Delete(t *testing.T) {
+	tests := []struct {
+		name     string
+		ids      []tiff.EntryID
+		zeroFill bool
+		removed  []tiff.EntryID // entries of IFD#0
+		secrets  []string       // values that must no longer be found in the file
+		retained []string
+	}{
+		{
+			name:     "entries of IFD#0",
+			ids:      []tiff.EntryID{tiff.Artist, tiff.Make},
+			zeroFill: true,
+			removed:  []tiff.EntryID{tiff.Artist, tiff.Make},
+			secrets:  []string{"Jane Doe", "Secret Camera Co"},
+			retained: []string{"maker note secrets", string(latitude)},
+		},
+		{
+			name:     "entries of the Exif IFD",
+			ids:      []tiff.EntryID{tiff.MakerNotes},
+			zeroFill: true,
+			secrets:  []string{"maker note secrets"},
+			retained: []string{"Jane Doe", "2021:11:19 12:21:10"},
+		},
+		{
+			name:     "sub-IFD",
+			ids:      []tiff.EntryID{tiff.GPSInfo},
+			zeroFill: true,
+			removed:  []tiff.EntryID{tiff.GPSInfo},
+			secrets:  []string{string(latitude)},
+			retained: []string{"Jane Doe"},
+		},
+		{
+			name:     "missing entries",
+			ids:      []tiff.EntryID{tiff.Copyright, tiff.CameraSerialNumber},
+			zeroFill: true,
+			retained: []string{"Jane Doe", "Secret Camera Co", "maker note secrets"},
+		},
+		{
+			name:     "without zero-fill",
+			ids:      []tiff.EntryID{tiff.Artist},
+			removed:  []tiff.EntryID{tiff.Artist},
+			retained: []string{"Jane Doe"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := newTestFile()
+			before, _ := firstPage(t, data)
+
+			w, err := newWriter(bytes.Clone(data))
+			assert.NoError(t, err)
+			assert.NoError(t, w.WithZeroFill(tt.zeroFill).Delete(tt.ids...))
+
+			out := w.Bytes()
+			assert.Len(t, out, len(data))
+			page, strips := firstPage(t, out)
+			assert.Equal(t, [][]byte{{1, 2, 3, 4}}, strips)
+			assert.Len(t, page.Entries, len(before.Entries)-len(tt.removed))
+			for _, id := range tt.removed {
+				assert.NotContains(t, page.Entries, id)
+			}
+			for _, secret := range tt.secrets {
+				assert.False(t, bytes.Contains(out, []byte(secret)), secret)
+			}
+			for _, value := range tt.retained {
+				assert.True(t, bytes.Contains(out, []byte(value)), value)
+			}
+
+			p, err := tiff.NewParser(bytes.NewReader(out))
+			assert.NoError(t, err)
+			entries, err := p.Parse(tt.ids...)
+			assert.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+func TestWriter_Delete_CollidingIDs(t *testing.T) {
+	// 0x0001 is GPSLatitudeRef in the GPSInfo IFD, but another entry (e.g. InteroperabilityIndex) elsewhere
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	ifd := b.AddIFD().
+		WithString(0x0001, "R98").
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{1, 2, 3, 4})
+	ifd.WithSubIFD(uint16(tiff.GPSInfo)).
+		WithString(uint16(tiff.GPSLatitudeRef), "N").
+		WithURationals(uint16(tiff.GPSLatitude), 52, 1, 22, 1, 1234, 100)
+
+	for _, del := range []func(w *Writer) error{
+		func(w *Writer) error { return w.Delete(tiff.GPSLatitudeRef) },
+		func(w *Writer) error { return w.Apply(Edits{Delete: []tiff.EntryID{tiff.GPSLatitudeRef}}) },
+	} {
+		w, err := newWriter(b.Bytes())
+		assert.NoError(t, err)
+		assert.NoError(t, del(w))
+
+		p, err := tiff.NewParser(bytes.NewReader(w.Bytes()))
+		assert.NoError(t, err)
+		infos, err := p.ListIDs()
+		assert.NoError(t, err)
+		var left []tiff.Group
+		for _, info := range infos {
+			if info.ID == 0x0001 {
+				left = append(left, info.Group)
+			}
+		}
+		assert.Equal(t, []tiff.Group{tiff.Group_IFD0}, left)
+	}
+
+	w, err := newWriter(b.Bytes())
+	assert.NoError(t, err)
+	assert.NoError(t, w.Delete(0x0002)) // GPSLatitude, as IFD#0 has no entry of that ID
+	p, err := tiff.NewParser(bytes.NewReader(w.Bytes()))
+	assert.NoError(t, err)
+	entries, err := p.Parse(tiff.GPSLatitude, tiff.GPSLatitudeRef)
+	assert.NoError(t, err)
+	assert.NotContains(t, entries, tiff.GPSLatitude)
+	assert.Contains(t, entries, tiff.GPSLatitudeRef)
+}
+
+func TestWriter_Delete_ImageData(t *testing.T) {
+	data := newTestFile()
+	w, err := newWriter(bytes.Clone(data))
+	assert.NoError(t, err)
+
+	assert.EqualError(t, w.Delete(tiff.Artist, tiff.StripOffsets), "cannot delete StripOffsets, which locates image data")
+	assert.Equal(t, data, w.Bytes())
+}
+
+func TestWriter_Strip_WithoutZeroFill(t *testing.T) {
+	w, err := newWriter(newTestFile())
+	assert.NoError(t, err)
+	assert.NoError(t, w.WithZeroFill(false).Strip(StripOptions{MakerNotes: true}))
+	assert.False(t, bytes.Contains(w.Bytes(), []byte("maker note secrets")))
+
+	assert.NoError(t, w.Delete(tiff.Artist))
+	assert.True(t, bytes.Contains(w.Bytes(), []byte("Jane Doe")), "Strip must not change the option")
+}
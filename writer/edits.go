@@ -0,0 +1,125 @@
+package writer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// shiftedTimes lists the entries shifted by Edits.ShiftTime.
+var shiftedTimes = []tiff.EntryID{tiff.DateTime, tiff.DateTimeOriginal}
+
+// Edits is a declarative set of edits, e.g. setting Artist and Copyright, deleting GPS data and shifting times by two
+// hours, applied to any number of files with Writer.Apply or ApplyFile (as `tiffdump edit` does).
+type Edits struct {
+	Set       map[tiff.EntryID]string // text entries of IFD#0, of the Exif or of the GPSInfo IFD to set (see Writer.SetString)
+	Delete    []tiff.EntryID          // entries to delete (see Writer.Delete), e.g. GPSInfo for all GPS data
+	ShiftTime time.Duration           // shift of DateTime and DateTimeOriginal, e.g. to fix the clock of a camera
+}
+
+// Validate checks that the edits can be applied to any file: values must not contain NUL bytes, entries cannot be
+// both set and deleted, and entries locating image data cannot be deleted.
+func (e Edits) Validate() error {
+	for id, value := range e.Set {
+		if strings.IndexByte(value, 0) >= 0 {
+			return fmt.Errorf("value of %s contains a NUL byte", id.Name())
+		}
+		if slices.Contains(e.Delete, id) {
+			return fmt.Errorf("cannot both set and delete %s", id.Name())
+		}
+	}
+	for _, id := range e.Delete {
+		if slices.Contains(imageDataEntries, id) {
+			return fmt.Errorf("cannot delete %s, which locates image data", id.Name())
+		}
+	}
+
+	return nil
+}
+
+// Apply applies edits to the file: entries are deleted first, then times are shifted and entries are set. Unlike
+// applying them one at a time, the file is only scanned for bytes to zero-fill once.
+func (w *Writer) Apply(e Edits) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+
+	return w.edit(func() error {
+		if len(e.Delete) > 0 {
+			if err := w.removeEntries(func(id tiff.EntryID) bool { return slices.Contains(e.Delete, id) }); err != nil {
+				return err
+			}
+		}
+		if e.ShiftTime != 0 {
+			if err := w.shiftTimes(e.ShiftTime); err != nil {
+				return err
+			}
+		}
+		for _, id := range slices.Sorted(maps.Keys(e.Set)) {
+			data := append([]byte(e.Set[id]), 0)
+			if err := w.setEntry(id, tiff.DataType_String, uint32(len(data)), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// shiftTimes shifts the times of the file (see shiftedTimes) by d, in place, as shifted times have the same length.
+// Missing times are ignored.
+func (w *Writer) shiftTimes(d time.Duration) error {
+	p, err := tiff.NewParser(bytes.NewReader(w.data))
+	if err != nil {
+		return err
+	}
+	entries, err := p.Parse(shiftedTimes...)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range shiftedTimes {
+		entry, ok := entries[id]
+		if !ok || entry.Value.String == nil {
+			continue
+		}
+		value := strings.TrimSpace(*entry.Value.String)
+		t, err := time.Parse(tiff.DateTimeLayout, value)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", id.Name(), value)
+		}
+		data := append([]byte(t.Add(d).Format(tiff.DateTimeLayout)), 0)
+		if err := w.setEntry(id, tiff.DataType_String, uint32(len(data)), data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ApplyFile applies edits to the TIFF file at path (see Writer.Apply), replacing it atomically (see WriteFile).
+func ApplyFile(path string, e Edits, opts FileOptions) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if bytes.HasPrefix(data, jpegSOI) {
+		return errors.New("editing JPEG files is not supported")
+	}
+
+	w, err := newWriter(data)
+	if err != nil {
+		return err
+	}
+	if err := w.Apply(e); err != nil {
+		return err
+	}
+
+	return w.WriteFile(path, opts)
+}
@@ -0,0 +1,97 @@
+package writer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter_Apply(t *testing.T) {
+	data := newTestFile()
+	w, err := newWriter(bytes.Clone(data))
+	assert.NoError(t, err)
+
+	assert.NoError(t, w.Apply(Edits{
+		Set:       map[tiff.EntryID]string{tiff.Artist: "John Doe", tiff.Copyright: "(c) 2021 John Doe"},
+		Delete:    []tiff.EntryID{tiff.GPSInfo, tiff.Make},
+		ShiftTime: 2 * time.Hour,
+	}))
+
+	out := w.Bytes()
+	p, err := tiff.NewParser(bytes.NewReader(out))
+	assert.NoError(t, err)
+	entries, err := p.Parse(tiff.Artist, tiff.Copyright, tiff.Make, tiff.DateTimeOriginal, tiff.MakerNotes)
+	assert.NoError(t, err)
+	assert.Equal(t, "John Doe", *entries[tiff.Artist].Value.String)
+	assert.Equal(t, "(c) 2021 John Doe", *entries[tiff.Copyright].Value.String)
+	assert.Equal(t, "2021:11:19 14:21:10", *entries[tiff.DateTimeOriginal].Value.String)
+	assert.Equal(t, []byte("maker note secrets"), entries[tiff.MakerNotes].Value.Bytes)
+	assert.NotContains(t, entries, tiff.Make)
+	for _, secret := range []string{"Jane Doe", "Secret Camera Co", string(latitude), "2021:11:19 12:21:10"} {
+		assert.False(t, bytes.Contains(out, []byte(secret)), secret)
+	}
+	page, strips := firstPage(t, out)
+	assert.NotContains(t, page.Entries, tiff.GPSInfo)
+	assert.Equal(t, [][]byte{{1, 2, 3, 4}}, strips)
+}
+
+func TestEdits_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		edits Edits
+		err   string
+	}{
+		{name: "empty", edits: Edits{}},
+		{
+			name:  "valid",
+			edits: Edits{Set: map[tiff.EntryID]string{tiff.Artist: "Jane Doe"}, Delete: []tiff.EntryID{tiff.GPSInfo}, ShiftTime: -time.Hour},
+		},
+		{
+			name:  "NUL byte",
+			edits: Edits{Set: map[tiff.EntryID]string{tiff.Artist: "Jane\x00Doe"}},
+			err:   "value of Artist contains a NUL byte",
+		},
+		{
+			name:  "set and deleted",
+			edits: Edits{Set: map[tiff.EntryID]string{tiff.Artist: "Jane Doe"}, Delete: []tiff.EntryID{tiff.Artist}},
+			err:   "cannot both set and delete Artist",
+		},
+		{
+			name:  "image data",
+			edits: Edits{Delete: []tiff.EntryID{tiff.TileOffsets}},
+			err:   "cannot delete TileOffsets, which locates image data",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.edits.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestApplyFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "image.tif")
+	assert.NoError(t, os.WriteFile(path, newTestFile(), 0o600))
+
+	assert.NoError(t, ApplyFile(path, Edits{ShiftTime: -24 * time.Hour}, FileOptions{CaptureTime: true}))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.True(t, info.ModTime().Equal(time.Date(2021, 11, 18, 12, 21, 10, 0, time.UTC)), info.ModTime())
+
+	jpeg := filepath.Join(dir, "image.jpg")
+	assert.NoError(t, os.WriteFile(jpeg, newTestJPEG(t), 0o600))
+	assert.EqualError(t, ApplyFile(jpeg, Edits{ShiftTime: time.Hour}, FileOptions{}), "editing JPEG files is not supported")
+	assert.Error(t, ApplyFile(filepath.Join(dir, "missing.tif"), Edits{}, FileOptions{}))
+}
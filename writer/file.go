@@ -0,0 +1,121 @@
+package writer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// FileOptions tells how WriteFile replaces a file.
+type FileOptions struct {
+	Perm        fs.FileMode // permissions of the file if it does not exist yet (0644 if zero)
+	CaptureTime bool        // sets the modification time of the file to the time the image was taken (see tiff.CaptureTime)
+}
+
+// WriteFile writes the edited file to path (see the WriteFile function).
+func (w *Writer) WriteFile(path string, opts FileOptions) error {
+	return WriteFile(path, w.data, opts)
+}
+
+// WriteFile atomically replaces the file at path with data (a TIFF or JPEG file), so that a crash leaves either the
+// original file or the new one, never a mix of both: data is written to a temporary file of the same directory, synced
+// to disk, then renamed over the original. The permissions and modification time of the original file are kept or,
+// with FileOptions.CaptureTime, the modification time is set to the time the image was taken. Symbolic links are
+// followed, replacing the file they point to.
+func WriteFile(path string, data []byte, opts FileOptions) error {
+	perm, modTime := opts.Perm, time.Time{}
+	if perm == 0 {
+		perm = 0o644
+	}
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	if info, err := os.Stat(path); err == nil {
+		perm, modTime = info.Mode().Perm(), info.ModTime()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if opts.CaptureTime {
+		var err error
+		if modTime, err = captureTime(data); err != nil {
+			return fmt.Errorf("cannot read capture time: %w", err)
+		}
+	}
+
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// removes the temporary file on failure (a no-op once renamed)
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if !modTime.IsZero() {
+		if err := os.Chtimes(tmp.Name(), time.Time{}, modTime); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	return syncDir(dir)
+}
+
+// syncDir syncs a directory, so that a rename in it survives a crash. Directories cannot be synced on Windows, where
+// renames are durable once MoveFileEx returns.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
+
+// captureTime reads the time a TIFF or JPEG file was taken.
+func captureTime(data []byte) (time.Time, error) {
+	if bytes.HasPrefix(data, jpegSOI) {
+		exif, ok := jpegExif(data)
+		if !ok {
+			return time.Time{}, errors.New("missing Exif segment")
+		}
+		data = exif
+	}
+	p, err := tiff.NewParser(bytes.NewReader(data))
+	if err != nil {
+		return time.Time{}, err
+	}
+	entries, err := p.Parse(tiff.DateTimeOriginal, tiff.OffsetTimeOriginal, tiff.OffsetTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return tiff.CaptureTime(entries)
+}
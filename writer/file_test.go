@@ -0,0 +1,79 @@
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "image.tif")
+	original := newTestFile()
+	assert.NoError(t, os.WriteFile(path, original, 0o600))
+	modTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+
+	w, err := newWriter(original)
+	assert.NoError(t, err)
+	assert.NoError(t, w.SetArtist("Jane Doe and John Doe"))
+	assert.NoError(t, w.WriteFile(path, FileOptions{}))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, w.Bytes(), data)
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.True(t, info.ModTime().Equal(modTime), info.ModTime())
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+
+	// no temporary file is left behind
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestWriteFile_CaptureTime(t *testing.T) {
+	captured := time.Date(2021, 11, 19, 12, 21, 10, 0, time.UTC)
+
+	for name, data := range map[string][]byte{"image.tif": newTestFile(), "image.jpg": newTestJPEG(t)} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			assert.NoError(t, WriteFile(path, data, FileOptions{CaptureTime: true}))
+
+			info, err := os.Stat(path)
+			assert.NoError(t, err)
+			assert.True(t, info.ModTime().Equal(captured), info.ModTime())
+			if runtime.GOOS != "windows" {
+				assert.Equal(t, os.FileMode(0o644), info.Mode().Perm())
+			}
+		})
+	}
+}
+
+func TestWriteFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "image.tif")
+	original := newTestFile()
+	assert.NoError(t, os.WriteFile(path, original, 0o644))
+
+	// the original is left untouched
+	w, err := newWriter(newTestFile())
+	assert.NoError(t, err)
+	assert.NoError(t, w.Strip(StripOptions{All: true}))
+	assert.Error(t, w.WriteFile(path, FileOptions{CaptureTime: true}))
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, original, data)
+
+	assert.Error(t, WriteFile(filepath.Join(dir, "missing", "image.tif"), original, FileOptions{}))
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+}
@@ -0,0 +1,67 @@
+package writer
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// gpsSecondsScale is the denominator of the seconds of the coordinates written by SetGPSPosition: 1/10000 of a second of
+// arc is about 3 millimeters.
+const gpsSecondsScale = 10000
+
+// SetGPSPosition geotags the file, setting GPSLatitude and GPSLongitude (in degrees, minutes and seconds, positive north
+// of the equator and east of the Greenwich meridian) with their references. Files that have no GPSInfo IFD get one, and
+// an Exif IFD too, with the entries the Exif specification requires (e.g. GPSVersionID, ExifVersion), so that they
+// validate against Exif checkers.
+func (w *Writer) SetGPSPosition(latitude, longitude float64) error {
+	if math.IsNaN(latitude) || math.Abs(latitude) > 90 {
+		return fmt.Errorf("invalid latitude %v", latitude)
+	}
+	if math.IsNaN(longitude) || math.Abs(longitude) > 180 {
+		return fmt.Errorf("invalid longitude %v", longitude)
+	}
+
+	return w.edit(func() error {
+		if _, err := w.ifdPointer(tiff.Group_Exif); err != nil {
+			return err
+		}
+		for _, c := range []struct {
+			id, refID tiff.EntryID
+			value     float64
+			refs      string // positive and negative references
+		}{
+			{tiff.GPSLatitude, tiff.GPSLatitudeRef, latitude, "NS"},
+			{tiff.GPSLongitude, tiff.GPSLongitudeRef, longitude, "EW"},
+		} {
+			ref := c.refs[:1]
+			if c.value < 0 {
+				ref = c.refs[1:]
+			}
+			if err := w.setEntry(c.refID, tiff.DataType_String, 2, []byte{ref[0], 0}); err != nil {
+				return err
+			}
+			if err := w.setEntry(c.id, tiff.DataType_URational, 3, w.degreesMinutesSeconds(c.value)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// degreesMinutesSeconds encodes the absolute value of a coordinate as three rationals: whole degrees, whole minutes and
+// seconds (see gpsSecondsScale).
+func (w *Writer) degreesMinutesSeconds(value float64) []byte {
+	total := uint64(math.Round(math.Abs(value) * 3600 * gpsSecondsScale))
+	degrees, rest := total/(3600*gpsSecondsScale), total%(3600*gpsSecondsScale)
+	minutes, seconds := rest/(60*gpsSecondsScale), rest%(60*gpsSecondsScale)
+
+	data := make([]byte, 24)
+	for i, r := range [][2]uint64{{degrees, 1}, {minutes, 1}, {seconds, gpsSecondsScale}} {
+		w.byteOrder.PutUint32(data[i*8:], uint32(r[0]))
+		w.byteOrder.PutUint32(data[i*8+4:], uint32(r[1]))
+	}
+
+	return data
+}
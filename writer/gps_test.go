@@ -0,0 +1,63 @@
+package writer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter_SetGPSPosition(t *testing.T) {
+	tests := []struct {
+		name  string
+		strip bool // the file has neither Exif nor GPSInfo IFD
+	}{
+		{name: "existing IFDs"},
+		{name: "missing IFDs", strip: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := newWriter(newTestFile())
+			assert.NoError(t, err)
+			if tt.strip {
+				assert.NoError(t, w.Strip(StripOptions{All: true}))
+			}
+
+			assert.NoError(t, w.SetGPSPosition(-33.856784, 151.215297))
+
+			p, err := tiff.NewParser(bytes.NewReader(w.Bytes()))
+			assert.NoError(t, err)
+			entries, err := p.Parse(tiff.GPSVersionID, tiff.GPSLatitudeRef, tiff.GPSLatitude, tiff.GPSLongitudeRef, tiff.GPSLongitude, tiff.ExifVersion)
+			assert.NoError(t, err)
+			assert.Equal(t, "S", *entries[tiff.GPSLatitudeRef].Value.String)
+			assert.Equal(t, "E", *entries[tiff.GPSLongitudeRef].Value.String)
+			latitude, err := entries[tiff.GPSLatitude].Floats()
+			assert.NoError(t, err)
+			assert.Equal(t, []float64{33, 51, 24.4224}, latitude)
+			longitude, err := entries[tiff.GPSLongitude].Floats()
+			assert.NoError(t, err)
+			assert.Equal(t, []float64{151, 12, 55.0692}, longitude)
+			if tt.strip {
+				assert.Equal(t, []byte{2, 3, 0, 0}, entries[tiff.GPSVersionID].Value.Bytes)
+				assert.Equal(t, []byte("0232"), entries[tiff.ExifVersion].Value.Bytes)
+			} else {
+				assert.NotContains(t, entries, tiff.ExifVersion, "existing IFDs are left as they are")
+			}
+			assert.True(t, p.Validate().Valid())
+
+			page, strips := firstPage(t, w.Bytes())
+			assert.Contains(t, page.Entries, tiff.Exif)
+			assert.Contains(t, page.Entries, tiff.GPSInfo)
+			assert.Equal(t, [][]byte{{1, 2, 3, 4}}, strips)
+		})
+	}
+}
+
+func TestWriter_SetGPSPosition_Invalid(t *testing.T) {
+	w, err := newWriter(newTestFile())
+	assert.NoError(t, err)
+	assert.EqualError(t, w.SetGPSPosition(91, 0), "invalid latitude 91")
+	assert.EqualError(t, w.SetGPSPosition(0, -180.5), "invalid longitude -180.5")
+}
@@ -0,0 +1,126 @@
+package writer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// JPEG markers
+const (
+	markerRST0  = 0xd0
+	markerRST7  = 0xd7
+	markerSOI   = 0xd8
+	markerEOI   = 0xd9
+	markerSOS   = 0xda
+	markerAPP1  = 0xe1 // Exif or XMP
+	markerAPP13 = 0xed // IPTC (Photoshop image resources)
+	markerCOM   = 0xfe
+)
+
+var (
+	jpegSOI    = []byte{0xff, markerSOI}
+	exifHeader = []byte("Exif\x00\x00") // start of the APP1 segment holding Exif metadata, followed by a TIFF file
+)
+
+// stripJPEG strips the metadata of a JPEG file (see Strip), returning the stripped file.
+func stripJPEG(data []byte, opts StripOptions) ([]byte, error) {
+	if opts.All {
+		return rewriteJPEG(data, nil, true)
+	}
+
+	return rewriteJPEG(data, func(w *Writer) error { return w.Strip(opts) }, false)
+}
+
+// rewriteJPEG edits the TIFF structure of the Exif segment of a JPEG file with exif, or removes the segment if exif is
+// nil, and removes the XMP and IPTC segments and the comments if others is true, returning the rewritten file. Other
+// segments are copied as they are, up to the start of the scan: the rest of the file is copied untouched.
+func rewriteJPEG(data []byte, exif func(w *Writer) error, others bool) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	out = append(out, jpegSOI...)
+
+	for pos := len(jpegSOI); pos < len(data); {
+		if pos+2 > len(data) || data[pos] != 0xff {
+			return nil, fmt.Errorf("invalid JPEG marker at offset %d", pos)
+		}
+		marker := data[pos+1]
+		switch {
+		case marker == 0xff: // fill byte
+			pos++
+			continue
+		case marker == markerEOI, marker >= markerRST0 && marker <= markerRST7, marker == 0x01: // markers without a segment
+			out = append(out, data[pos:pos+2]...)
+			pos += 2
+			continue
+		case marker == markerSOS:
+			return append(out, data[pos:]...), nil
+		}
+
+		if pos+4 > len(data) {
+			return nil, fmt.Errorf("truncated JPEG segment at offset %d", pos)
+		}
+		end := pos + 2 + int(binary.BigEndian.Uint16(data[pos+2:]))
+		if end < pos+4 || end > len(data) {
+			return nil, fmt.Errorf("invalid length of JPEG segment at offset %d", pos)
+		}
+		segment, payload := data[pos:end], data[pos+4:end]
+		pos = end
+
+		switch {
+		case marker == markerAPP1 && bytes.HasPrefix(payload, exifHeader):
+			if exif == nil {
+				continue
+			}
+			w, err := newWriter(bytes.Clone(payload[len(exifHeader):]))
+			if err != nil {
+				return nil, fmt.Errorf("exif segment: %w", err)
+			}
+			if err := exif(w); err != nil {
+				return nil, fmt.Errorf("exif segment: %w", err)
+			}
+			// edits may have appended values to the TIFF structure, changing the length of the segment
+			length := 2 + len(exifHeader) + len(w.Bytes())
+			if length > 0xffff {
+				return nil, fmt.Errorf("exif segment: too large once edited (%d bytes)", length)
+			}
+			out = append(out, segment[:2]...)
+			out = binary.BigEndian.AppendUint16(out, uint16(length))
+			out = append(out, exifHeader...)
+			out = append(out, w.Bytes()...)
+		case others && (marker == markerAPP1 || marker == markerAPP13 || marker == markerCOM):
+			continue
+		default:
+			out = append(out, segment...)
+		}
+	}
+
+	return out, nil
+}
+
+// jpegExif returns the TIFF file embedded in the Exif segment of a JPEG file, if any.
+func jpegExif(data []byte) ([]byte, bool) {
+	for pos := len(jpegSOI); pos+4 <= len(data) && data[pos] == 0xff; {
+		marker := data[pos+1]
+		switch {
+		case marker == 0xff:
+			pos++
+			continue
+		case marker == markerSOS || marker == markerEOI:
+			return nil, false
+		case marker >= markerRST0 && marker <= markerRST7, marker == 0x01:
+			pos += 2
+			continue
+		}
+
+		end := pos + 2 + int(binary.BigEndian.Uint16(data[pos+2:]))
+		if end < pos+4 || end > len(data) {
+			return nil, false
+		}
+		if payload := data[pos+4 : end]; marker == markerAPP1 && bytes.HasPrefix(payload, exifHeader) {
+			return payload[len(exifHeader):], true
+		}
+		pos = end
+	}
+
+	return nil, false
+}
@@ -0,0 +1,58 @@
+package writer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// segment returns a JPEG segment with the given marker and payload.
+func segment(marker byte, payload ...[]byte) []byte {
+	data := bytes.Join(payload, nil)
+	s := []byte{0xff, marker}
+	s = binary.BigEndian.AppendUint16(s, uint16(len(data)+2))
+
+	return append(s, data...)
+}
+
+// newTestJPEG returns a JPEG file holding newTestFile in its Exif segment, followed by XMP metadata and a comment.
+func newTestJPEG(t *testing.T) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 8, 8)), nil))
+	encoded := buf.Bytes()
+
+	data := append([]byte{}, jpegSOI...)
+	data = append(data, segment(markerAPP1, exifHeader, newTestFile())...)
+	data = append(data, segment(markerAPP1, []byte("http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta>Jane Doe</x:xmpmeta>"))...)
+	data = append(data, segment(markerCOM, []byte("a comment"))...)
+
+	return append(data, encoded[len(jpegSOI):]...)
+}
+
+func TestStrip_JPEG(t *testing.T) {
+	data := newTestJPEG(t)
+
+	var buf bytes.Buffer
+	assert.NoError(t, Strip(bytes.NewReader(data), &buf, StripOptions{GPS: true}))
+	assert.Len(t, buf.Bytes(), len(data))
+	assert.False(t, bytes.Contains(buf.Bytes(), latitude))
+	assert.True(t, bytes.Contains(buf.Bytes(), []byte("maker note secrets")))
+	assert.True(t, bytes.Contains(buf.Bytes(), []byte("a comment")))
+	_, err := jpeg.Decode(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+
+	buf.Reset()
+	assert.NoError(t, Strip(bytes.NewReader(data), &buf, StripOptions{All: true}))
+	for _, secret := range []string{"Exif", "Jane Doe", "a comment", "maker note secrets"} {
+		assert.False(t, bytes.Contains(buf.Bytes(), []byte(secret)), secret)
+	}
+	_, err = jpeg.Decode(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+
+	truncated := append([]byte{}, data[:len(jpegSOI)+3]...)
+	assert.Error(t, Strip(bytes.NewReader(truncated), &buf, StripOptions{All: true}))
+}
@@ -0,0 +1,99 @@
+package writer
+
+import (
+	"bytes"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// Plan describes the changes an edit would make to a file, without making them (see Writer.Plan).
+type Plan struct {
+	Changes     []Change     // ranges of bytes that would change, sorted by offset
+	Relocations []Relocation // values and IFD tables that would move, in the order they would be moved
+	Size        int64        // size of the file after the edit
+}
+
+// TouchesImageData tells whether the edit would change strips or tiles of the file, which edits of metadata never
+// should.
+func (p Plan) TouchesImageData() bool {
+	for _, change := range p.Changes {
+		for _, region := range change.Regions {
+			if region.Kind == tiff.Region_ImageData {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Change is a range of bytes that an edit would change, e.g. to show a diff to users.
+type Change struct {
+	Offset  int64
+	Before  []byte        // bytes of the range before the edit: shorter than After if the edit appends to the file
+	After   []byte        // bytes of the range after the edit
+	Regions []tiff.Region // regions of the file, before the edit, that the range overlaps (see tiff.Parser.Layout)
+}
+
+// Relocation is a value or an IFD table that an edit would move to the end of the file, as it no longer fits where it
+// was (see SetString).
+type Relocation struct {
+	Kind   tiff.RegionKind // tiff.Region_Value or tiff.Region_IFD
+	Entry  tiff.EntryID    // entry whose value moves (0 for IFD tables)
+	From   int64           // offset of the value or table before the edit
+	To     int64           // offset of the value or table after the edit
+	Length int64           // length of the value or table after the edit
+}
+
+// Plan runs an edit on a copy of the file, returning the changes it would make, so that tools can show them to users
+// (or refuse them) before editing the file in place. The file itself is left as it is.
+//
+//	plan, err := w.Plan(func(w *writer.Writer) error { return w.SetCopyright("(c) 2021 Jane Doe") })
+//	if err == nil && !plan.TouchesImageData() {
+//		err = w.SetCopyright("(c) 2021 Jane Doe")
+//	}
+func (w *Writer) Plan(edit func(w *Writer) error) (Plan, error) {
+	regions, err := w.layout()
+	if err != nil {
+		return Plan{}, err
+	}
+
+	dry := &Writer{data: bytes.Clone(w.data), byteOrder: w.byteOrder, keepOrphans: w.keepOrphans}
+	if err := edit(dry); err != nil {
+		return Plan{}, err
+	}
+
+	changes := diff(w.data, dry.data)
+	for i, change := range changes {
+		end := change.Offset + int64(max(len(change.Before), len(change.After)))
+		for _, region := range regions {
+			if region.Offset < end && region.End() > change.Offset {
+				changes[i].Regions = append(changes[i].Regions, region)
+			}
+		}
+	}
+
+	return Plan{Changes: changes, Relocations: dry.relocations, Size: int64(len(dry.data))}, nil
+}
+
+// diff returns the ranges of bytes that differ between before and after, bytes past the end of either being different.
+func diff(before, after []byte) []Change {
+	var res []Change
+	for i := 0; i < max(len(before), len(after)); {
+		if i < len(before) && i < len(after) && before[i] == after[i] {
+			i++
+			continue
+		}
+		start := i
+		for i < max(len(before), len(after)) && (i >= len(before) || i >= len(after) || before[i] != after[i]) {
+			i++
+		}
+		res = append(res, Change{
+			Offset: int64(start),
+			Before: bytes.Clone(before[min(start, len(before)):min(i, len(before))]),
+			After:  bytes.Clone(after[min(start, len(after)):min(i, len(after))]),
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,85 @@
+package writer
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter_Plan(t *testing.T) {
+	data := newTestFile()
+	w, err := newWriter(bytes.Clone(data))
+	assert.NoError(t, err)
+	page, _ := firstPage(t, data)
+	artist := int64(page.Entries[tiff.Artist].RawValue)
+
+	// the longer value moves to the end of the file, the previous one is zero-filled
+	plan, err := w.Plan(func(w *Writer) error { return w.SetArtist("Jane Doe and John Doe") })
+	assert.NoError(t, err)
+	assert.Equal(t, data, w.Bytes(), "the file must not change")
+	assert.False(t, plan.TouchesImageData())
+	assert.Equal(t, []Relocation{
+		{Kind: tiff.Region_Value, Entry: tiff.Artist, From: artist, To: int64(len(data)), Length: 22},
+	}, plan.Relocations)
+	assert.Equal(t, int64(len(data)+22), plan.Size)
+
+	// count and offset of the entry, previous value, new value
+	assert.Len(t, plan.Changes, 4)
+	for _, change := range plan.Changes[:2] {
+		assert.Equal(t, tiff.Region_IFD, change.Regions[0].Kind)
+	}
+	previous, appended := plan.Changes[2], plan.Changes[3]
+	assert.Equal(t, Change{
+		Offset:  artist,
+		Before:  []byte("Jane Doe"),
+		After:   make([]byte, 8),
+		Regions: []tiff.Region{{Kind: tiff.Region_Value, Offset: artist, Length: 9, IFDOffset: page.Offset, Entry: tiff.Artist}},
+	}, previous)
+	assert.Equal(t, Change{Offset: int64(len(data)), Before: []byte{}, After: []byte("Jane Doe and John Doe\x00")}, appended)
+
+	// applying the edit makes the planned changes
+	out := bytes.Clone(data)
+	out = append(out, make([]byte, plan.Size-int64(len(out)))...)
+	for _, change := range plan.Changes {
+		copy(out[change.Offset:], change.After)
+	}
+	assert.NoError(t, w.SetArtist("Jane Doe and John Doe"))
+	assert.Equal(t, w.Bytes(), out)
+}
+
+func TestWriter_Plan_NewEntry(t *testing.T) {
+	data, err := os.ReadFile("../tiff/testdata/image.cr2")
+	assert.NoError(t, err)
+	w, err := newWriter(data)
+	assert.NoError(t, err)
+
+	plan, err := w.Plan(func(w *Writer) error { return w.SetUint16(tiff.Rating, 3) })
+	assert.NoError(t, err)
+	assert.False(t, plan.TouchesImageData())
+	assert.Len(t, plan.Relocations, 1)
+	assert.Equal(t, tiff.Region_IFD, plan.Relocations[0].Kind)
+	assert.Equal(t, int64(16), plan.Relocations[0].From)
+
+	// the header points to the copy of IFD#0
+	header := plan.Changes[0]
+	assert.Equal(t, int64(4), header.Offset)
+	assert.Equal(t, tiff.Region_Header, header.Regions[0].Kind)
+}
+
+func TestWriter_Plan_Error(t *testing.T) {
+	w, err := newWriter(newTestFile())
+	assert.NoError(t, err)
+
+	failure := errors.New("failure")
+	_, err = w.Plan(func(w *Writer) error { return failure })
+	assert.ErrorIs(t, err, failure)
+}
+
+func TestPlan_TouchesImageData(t *testing.T) {
+	plan := Plan{Changes: []Change{{Regions: []tiff.Region{{Kind: tiff.Region_Value}, {Kind: tiff.Region_ImageData}}}}}
+	assert.True(t, plan.TouchesImageData())
+}
@@ -0,0 +1,264 @@
+package writer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// SetImageDescription sets the ImageDescription of IFD#0 (see SetString).
+func (w *Writer) SetImageDescription(value string) error {
+	return w.SetString(tiff.ImageDescription, value)
+}
+
+// SetArtist sets the Artist of IFD#0 (see SetString).
+func (w *Writer) SetArtist(value string) error {
+	return w.SetString(tiff.Artist, value)
+}
+
+// SetCopyright sets the Copyright of IFD#0 (see SetString).
+func (w *Writer) SetCopyright(value string) error {
+	return w.SetString(tiff.Copyright, value)
+}
+
+// SetSoftware sets the Software of IFD#0 (see SetString).
+func (w *Writer) SetSoftware(value string) error {
+	return w.SetString(tiff.Software, value)
+}
+
+// SetRating sets the rating of the image, from 0 (unrated) to 5 stars, writing both Rating and RatingPercent of IFD#0
+// (see tiff.RatingToPercent), as Windows does.
+func (w *Writer) SetRating(stars int) error {
+	if stars < 0 || stars > tiff.MaxRating {
+		return fmt.Errorf("invalid rating %d, expected 0 to %d stars", stars, tiff.MaxRating)
+	}
+
+	return w.edit(func() error {
+		if err := w.setUint16(tiff.Rating, uint16(stars)); err != nil {
+			return err
+		}
+		return w.setUint16(tiff.RatingPercent, uint16(tiff.RatingToPercent(stars)))
+	})
+}
+
+// SetUint16 sets the value of an entry of IFD#0 holding a single unsigned short, adding the entry if it is missing (see
+// SetString).
+func (w *Writer) SetUint16(id tiff.EntryID, value uint16) error {
+	return w.edit(func() error {
+		return w.setUint16(id, value)
+	})
+}
+
+func (w *Writer) setUint16(id tiff.EntryID, value uint16) error {
+	data := make([]byte, 2)
+	w.byteOrder.PutUint16(data, value)
+
+	return w.setEntry(id, tiff.DataType_UShort, 1, data)
+}
+
+// SetString sets the value of an ASCII entry of IFD#0 (or of the Exif or GPSInfo IFD, for entries of their group, such
+// as DateTimeOriginal), adding the entry if it is missing, and the IFD too, with the entries the Exif specification
+// requires (e.g. ExifVersion).
+//
+// As the Writer only moves what it must, values that do not fit where the previous value was are appended to the end of
+// the file, as is a copy of the table of the IFD when an entry must be added to it: the header (or the entry of IFD#0
+// pointing to the IFD) is then updated to point to the copy. Bytes that are no longer referenced (e.g. the previous
+// value) are zero-filled.
+func (w *Writer) SetString(id tiff.EntryID, value string) error {
+	if bytes.IndexByte([]byte(value), 0) >= 0 {
+		return errors.New("value contains a NUL byte")
+	}
+	data := append([]byte(value), 0)
+
+	return w.edit(func() error {
+		return w.setEntry(id, tiff.DataType_String, uint32(len(data)), data)
+	})
+}
+
+// setEntry sets the data type, count and value of an entry of IFD#0 (or of the Exif or GPSInfo IFD, for entries of
+// their group), adding the entry if it is missing, and the IFD too (see addIFD).
+func (w *Writer) setEntry(id tiff.EntryID, dataType tiff.DataType, count uint32, data []byte) error {
+	if id == tiff.MakerNotes {
+		// maker notes may hold offsets relative to the TIFF header, which would break if their value moved
+		return errors.New("maker notes cannot be set")
+	}
+	pointer, err := w.ifdPointer(tiff.Defaults[id])
+	if err != nil {
+		return err
+	}
+	p, err := tiff.NewParser(bytes.NewReader(w.data))
+	if err != nil {
+		return err
+	}
+	offset := int64(w.byteOrder.Uint32(w.data[pointer:]))
+	entries, next, err := p.ReadRawIFD(offset)
+	if err != nil {
+		return err
+	}
+	found := slices.IndexFunc(entries, func(e tiff.RawEntry) bool { return e.ID == id })
+
+	var field [4]byte
+	if len(data) <= len(field) {
+		copy(field[:], data)
+	} else {
+		var valueOffset int64
+		if found >= 0 && !entries[found].Inline() && entries[found].Size() >= int64(len(data)) {
+			valueOffset = int64(entries[found].RawValue)
+			copy(w.data[valueOffset:], data)
+		} else if valueOffset, err = w.append(data); err != nil {
+			return err
+		} else if found >= 0 && !entries[found].Inline() {
+			w.relocations = append(w.relocations, Relocation{
+				Kind:   tiff.Region_Value,
+				Entry:  id,
+				From:   int64(entries[found].RawValue),
+				To:     valueOffset,
+				Length: int64(len(data)),
+			})
+		}
+		w.byteOrder.PutUint32(field[:], uint32(valueOffset))
+	}
+
+	entry := w.encodeEntry(id, dataType, count, field)
+	if found >= 0 {
+		copy(w.data[entries[found].Offset:], entry)
+		return nil
+	}
+
+	// add the entry to a copy of the table, keeping entries sorted by ID
+	i := slices.IndexFunc(entries, func(e tiff.RawEntry) bool { return e.ID > id })
+	if i < 0 {
+		i = len(entries)
+	}
+	table := make([]byte, 2, 2+(len(entries)+1)*tiff.EntryLength+4)
+	w.byteOrder.PutUint16(table, uint16(len(entries)+1))
+	for j := 0; j <= len(entries); j++ {
+		if j == i {
+			table = append(table, entry...)
+		}
+		if j < len(entries) {
+			table = append(table, w.data[entries[j].Offset:entries[j].Offset+tiff.EntryLength]...)
+		}
+	}
+	table = table[:len(table)+4]
+	w.byteOrder.PutUint32(table[len(table)-4:], uint32(next))
+
+	tableOffset, err := w.append(table)
+	if err != nil {
+		return err
+	}
+	w.relocations = append(w.relocations, Relocation{Kind: tiff.Region_IFD, From: offset, To: tableOffset, Length: int64(len(table))})
+	w.byteOrder.PutUint32(w.data[pointer:], uint32(tableOffset))
+
+	return nil
+}
+
+// encodeEntry returns the bytes of an entry, given its value or the offset of its value.
+func (w *Writer) encodeEntry(id tiff.EntryID, dataType tiff.DataType, count uint32, field [4]byte) []byte {
+	entry := make([]byte, tiff.EntryLength)
+	w.byteOrder.PutUint16(entry, uint16(id))
+	w.byteOrder.PutUint16(entry[2:], uint16(dataType))
+	w.byteOrder.PutUint32(entry[4:], count)
+	copy(entry[8:], field[:])
+
+	return entry
+}
+
+// subIFDs maps the groups of the sub-IFDs of IFD#0 that can be edited to the entries pointing to them.
+var subIFDs = map[tiff.Group]tiff.EntryID{
+	tiff.Group_Exif:    tiff.Exif,
+	tiff.Group_GPSInfo: tiff.GPSInfo,
+}
+
+// ifdPointer returns the position of the offset of the IFD holding the entries of a group: in the header for IFD#0, in
+// the value of the entry of IFD#0 pointing to them for the Exif and GPSInfo IFDs, which are added if they are missing.
+// Entries of other groups cannot be set.
+func (w *Writer) ifdPointer(group tiff.Group) (int64, error) {
+	if group == tiff.Group_IFD0 {
+		return 4, nil
+	}
+	pointerID, ok := subIFDs[group]
+	if !ok {
+		return 0, fmt.Errorf("cannot set entries of the %s IFD", group)
+	}
+
+	p, err := tiff.NewParser(bytes.NewReader(w.data))
+	if err != nil {
+		return 0, err
+	}
+	entries, _, err := p.ReadRawIFD(int64(w.byteOrder.Uint32(w.data[4:8])))
+	if err != nil {
+		return 0, err
+	}
+	if i := slices.IndexFunc(entries, func(e tiff.RawEntry) bool { return e.ID == pointerID }); i >= 0 {
+		return entries[i].Offset + 8, nil
+	}
+
+	if err := w.addIFD(group, pointerID); err != nil {
+		return 0, err
+	}
+
+	return w.ifdPointer(group)
+}
+
+// mandatoryEntry is an entry that the Exif specification requires in an IFD, with the value written by addIFD.
+type mandatoryEntry struct {
+	id       tiff.EntryID
+	dataType tiff.DataType
+	value    []byte
+}
+
+// mandatoryEntries lists, by group, the entries that IFDs created by addIFD start with, sorted by ID, so that files
+// that had no Exif or GPSInfo IFD (e.g. before being geotagged) validate against Exif checkers once they have one.
+var mandatoryEntries = map[tiff.Group][]mandatoryEntry{
+	tiff.Group_Exif: {
+		{tiff.ExifVersion, tiff.DataType_UByte_Sequence, []byte("0232")},
+		{tiff.ComponentsConfiguration, tiff.DataType_UByte_Sequence, []byte{1, 2, 3, 0}}, // Y Cb Cr, as exiftool writes
+		{tiff.FlashpixVersion, tiff.DataType_UByte_Sequence, []byte("0100")},
+	},
+	tiff.Group_GPSInfo: {
+		{tiff.GPSVersionID, tiff.DataType_UByte, []byte{2, 3, 0, 0}},
+	},
+}
+
+// addIFD appends a new IFD of the given group, holding its mandatory entries (see mandatoryEntries), then adds the
+// entry pointing to it to IFD#0.
+func (w *Writer) addIFD(group tiff.Group, pointerID tiff.EntryID) error {
+	entries := mandatoryEntries[group]
+	table := make([]byte, 2, 2+len(entries)*tiff.EntryLength+4)
+	w.byteOrder.PutUint16(table, uint16(len(entries)))
+	for _, e := range entries {
+		var field [4]byte
+		copy(field[:], e.value)
+		table = append(table, w.encodeEntry(e.id, e.dataType, uint32(len(e.value)), field)...)
+	}
+	table = append(table, 0, 0, 0, 0) // no next IFD
+
+	offset, err := w.append(table)
+	if err != nil {
+		return err
+	}
+	pointer := make([]byte, 4)
+	w.byteOrder.PutUint32(pointer, uint32(offset))
+
+	return w.setEntry(pointerID, tiff.DataType_ULong, 1, pointer)
+}
+
+// append appends data to the end of the file, on a word boundary as required by the TIFF specification, returning its
+// offset.
+func (w *Writer) append(data []byte) (int64, error) {
+	offset := int64(len(w.data) + len(w.data)%2)
+	if offset+int64(len(data)) > math.MaxUint32 {
+		return 0, fmt.Errorf("file would exceed %d bytes", uint32(math.MaxUint32))
+	}
+	if offset > int64(len(w.data)) {
+		w.data = append(w.data, 0)
+	}
+	w.data = append(w.data, data...)
+
+	return offset, nil
+}
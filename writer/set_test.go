@@ -0,0 +1,201 @@
+package writer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"os"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter_SetString(t *testing.T) {
+	tests := []struct {
+		name     string
+		set      func(w *Writer) error
+		id       tiff.EntryID
+		want     string
+		added    bool     // the entry was missing from IFD#0
+		grown    bool     // data was appended to the file
+		replaced []string // previous values, that must no longer be found in the file
+	}{
+		{
+			name:     "shorter value",
+			set:      func(w *Writer) error { return w.SetArtist("John Do") },
+			id:       tiff.Artist,
+			want:     "John Do",
+			replaced: []string{"Jane Doe"},
+		},
+		{
+			name:     "inline value",
+			set:      func(w *Writer) error { return w.SetArtist("Jo") },
+			id:       tiff.Artist,
+			want:     "Jo",
+			replaced: []string{"Jane Doe"},
+		},
+		{
+			name:     "longer value",
+			set:      func(w *Writer) error { return w.SetArtist("Jane Doe and John Doe") },
+			id:       tiff.Artist,
+			want:     "Jane Doe and John Doe",
+			grown:    true,
+			replaced: []string{"Jane Doe\x00"},
+		},
+		{
+			name:  "missing entry",
+			set:   func(w *Writer) error { return w.SetCopyright("(c) 2021 Jane Doe") },
+			id:    tiff.Copyright,
+			want:  "(c) 2021 Jane Doe",
+			added: true,
+			grown: true,
+		},
+		{
+			name:  "missing inline entry",
+			set:   func(w *Writer) error { return w.SetSoftware("v1") },
+			id:    tiff.Software,
+			want:  "v1",
+			added: true,
+			grown: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := newTestFile()
+			before, _ := firstPage(t, data)
+
+			w, err := newWriter(bytes.Clone(data))
+			assert.NoError(t, err)
+			assert.NoError(t, tt.set(w))
+
+			out := w.Bytes()
+			if tt.grown {
+				assert.Greater(t, len(out), len(data))
+			} else {
+				assert.Len(t, out, len(data))
+			}
+
+			page, strips := firstPage(t, out)
+			assert.Equal(t, [][]byte{{1, 2, 3, 4}}, strips)
+			assert.Equal(t, tt.want, *page.Entries[tt.id].Value.String)
+			if tt.added {
+				assert.Len(t, page.Entries, len(before.Entries)+1)
+			} else {
+				assert.Len(t, page.Entries, len(before.Entries))
+			}
+			for id, entry := range before.Entries {
+				if id != tt.id && id != tiff.Exif && id != tiff.GPSInfo && id != tiff.StripOffsets {
+					assert.Equal(t, entry.Value, page.Entries[id].Value, id.Name())
+				}
+			}
+			for _, value := range tt.replaced {
+				assert.False(t, bytes.Contains(out, []byte(value)), value)
+			}
+
+			// sub-IFDs are still reachable
+			p, err := tiff.NewParser(bytes.NewReader(out))
+			assert.NoError(t, err)
+			entries, err := p.Parse(tiff.DateTimeOriginal, tiff.GPSLatitude)
+			assert.NoError(t, err)
+			assert.Len(t, entries, 2)
+		})
+	}
+}
+
+func TestWriter_SetString_InvalidValue(t *testing.T) {
+	w, err := newWriter(newTestFile())
+	assert.NoError(t, err)
+	assert.Error(t, w.SetArtist("Jane\x00Doe"))
+}
+
+func TestWriter_SetString_CR2(t *testing.T) {
+	data, err := os.ReadFile("../tiff/testdata/image.cr2")
+	assert.NoError(t, err)
+
+	hash := func(data []byte) []byte {
+		p, err := tiff.NewParser(bytes.NewReader(data))
+		assert.NoError(t, err)
+		h := sha256.New()
+		assert.NoError(t, p.ImageDataHash(h))
+		return h.Sum(nil)
+	}
+
+	w, err := newWriter(bytes.Clone(data))
+	assert.NoError(t, err)
+	assert.NoError(t, w.SetArtist("Jane Doe"))
+	assert.NoError(t, w.SetCopyright("(c) 2021 Jane Doe"))
+	assert.NoError(t, w.SetImageDescription("Somewhere"))
+	assert.Equal(t, hash(data), hash(w.Bytes()))
+
+	p, err := tiff.NewParser(bytes.NewReader(w.Bytes()))
+	assert.NoError(t, err)
+	entries, err := p.Parse(tiff.Artist, tiff.Copyright, tiff.ImageDescription, tiff.Model, tiff.DateTimeOriginal)
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane Doe", *entries[tiff.Artist].Value.String)
+	assert.Equal(t, "(c) 2021 Jane Doe", *entries[tiff.Copyright].Value.String)
+	assert.Equal(t, "Somewhere", *entries[tiff.ImageDescription].Value.String)
+	assert.Equal(t, "Canon EOS 7D", *entries[tiff.Model].Value.String)
+	assert.Equal(t, "2021:11:19 12:21:10", *entries[tiff.DateTimeOriginal].Value.String)
+
+	thumbnail, err := p.ReadThumbnail()
+	assert.NoError(t, err)
+	assert.Len(t, thumbnail, 14557)
+}
+
+func TestWriter_SetRating(t *testing.T) {
+	w, err := newWriter(newTestFile())
+	assert.NoError(t, err)
+	assert.NoError(t, w.SetRating(3))
+
+	page, strips := firstPage(t, w.Bytes())
+	assert.Equal(t, [][]byte{{1, 2, 3, 4}}, strips)
+	assert.Equal(t, uint16(3), *page.Entries[tiff.Rating].Value.Uint16)
+	assert.Equal(t, uint16(50), *page.Entries[tiff.RatingPercent].Value.Uint16)
+
+	// updated in place
+	size := len(w.Bytes())
+	assert.NoError(t, w.SetRating(5))
+	assert.Len(t, w.Bytes(), size)
+	page, _ = firstPage(t, w.Bytes())
+	rating, err := tiff.ReadRating(page.Entries)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, rating)
+	assert.Equal(t, uint16(99), *page.Entries[tiff.RatingPercent].Value.Uint16)
+
+	assert.Error(t, w.SetRating(6))
+}
+
+func TestWriter_SetString_Exif(t *testing.T) {
+	w, err := newWriter(newTestFile())
+	assert.NoError(t, err)
+	assert.NoError(t, w.SetString(tiff.DateTimeOriginal, "2021:11:19 14:21:10"))
+	assert.NoError(t, w.SetString(tiff.OffsetTimeOriginal, "+02:00"))
+
+	p, err := tiff.NewParser(bytes.NewReader(w.Bytes()))
+	assert.NoError(t, err)
+	entries, err := p.Parse(tiff.DateTimeOriginal, tiff.OffsetTimeOriginal, tiff.MakerNotes, tiff.Artist)
+	assert.NoError(t, err)
+	assert.Equal(t, "2021:11:19 14:21:10", *entries[tiff.DateTimeOriginal].Value.String)
+	assert.Equal(t, "+02:00", *entries[tiff.OffsetTimeOriginal].Value.String)
+	assert.Equal(t, []byte("maker note secrets"), entries[tiff.MakerNotes].Value.Bytes)
+	assert.Equal(t, "Jane Doe", *entries[tiff.Artist].Value.String)
+	assert.Len(t, w.relocations, 1) // the table of the Exif IFD, which needed a new entry
+	assert.Equal(t, tiff.Region_IFD, w.relocations[0].Kind)
+
+	w, err = newWriter(newTestFile())
+	assert.NoError(t, err)
+	assert.NoError(t, w.Strip(StripOptions{All: true}))
+	assert.NoError(t, w.SetString(tiff.DateTimeOriginal, "2021:11:19 14:21:10"))
+
+	// the missing Exif IFD is added, with its mandatory entries
+	p, err = tiff.NewParser(bytes.NewReader(w.Bytes()))
+	assert.NoError(t, err)
+	entries, err = p.Parse(tiff.DateTimeOriginal, tiff.ExifVersion, tiff.ComponentsConfiguration, tiff.FlashpixVersion)
+	assert.NoError(t, err)
+	assert.Equal(t, "2021:11:19 14:21:10", *entries[tiff.DateTimeOriginal].Value.String)
+	assert.Equal(t, []byte("0232"), entries[tiff.ExifVersion].Value.Bytes)
+	assert.Equal(t, []byte{1, 2, 3, 0}, entries[tiff.ComponentsConfiguration].Value.Bytes)
+	assert.Equal(t, []byte("0100"), entries[tiff.FlashpixVersion].Value.Bytes)
+	assert.True(t, p.Validate().Valid())
+}
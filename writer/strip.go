@@ -0,0 +1,85 @@
+package writer
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// StripOptions tells which metadata to strip from a file.
+type StripOptions struct {
+	GPS        bool // the GPSInfo IFD
+	MakerNotes bool // maker notes, including the copy of the original maker note kept by DNG files
+	All        bool // all of the above, the Exif IFD and the descriptive entries of image IFDs (e.g. Make, Artist)
+}
+
+// descriptive lists the entries of image IFDs removed by StripOptions.All: they describe the image, its author or the
+// camera that took it, but are not needed to display it.
+var descriptive = map[tiff.EntryID]bool{
+	tiff.ImageDescription:    true,
+	tiff.Make:                true,
+	tiff.Model:               true,
+	tiff.Software:            true,
+	tiff.DateTime:            true,
+	tiff.Artist:              true,
+	tiff.HostComputer:        true,
+	tiff.XMLPacket:           true,
+	tiff.Copyright:           true,
+	tiff.IPTC:                true,
+	tiff.PhotoshopSettings:   true,
+	tiff.CameraSerialNumber:  true,
+	tiff.DocumentName:        true,
+	tiff.OriginalRawFileName: true,
+}
+
+// Strip removes the metadata selected by opts from the file. Removed values are zero-filled, so that they cannot be
+// recovered from the file, whatever WithZeroFill says.
+func (w *Writer) Strip(opts StripOptions) error {
+	keepOrphans := w.keepOrphans
+	w.keepOrphans = false
+	defer func() { w.keepOrphans = keepOrphans }()
+
+	return w.deleteEntries(func(id tiff.EntryID) bool {
+		switch {
+		case opts.All && (id == tiff.Exif || descriptive[id]):
+			return true
+		case (opts.All || opts.GPS) && id == tiff.GPSInfo:
+			return true
+		case (opts.All || opts.MakerNotes) && (id == tiff.MakerNotes || id == tiff.DNGPrivateData):
+			return true
+		default:
+			return false
+		}
+	})
+}
+
+// Strip copies a TIFF or JPEG file from r to w, removing the metadata selected by opts (see Writer.Strip). In JPEG
+// files, the TIFF structure of the Exif segment is stripped as a TIFF file; StripOptions.All also removes the Exif and
+// XMP segments, IPTC data and comments.
+func Strip(r io.Reader, w io.Writer, opts StripOptions) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	if bytes.HasPrefix(data, jpegSOI) {
+		data, err = stripJPEG(data, opts)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	wr, err := newWriter(data)
+	if err != nil {
+		return err
+	}
+	if err := wr.Strip(opts); err != nil {
+		return err
+	}
+	_, err = wr.WriteTo(w)
+
+	return err
+}
@@ -0,0 +1,105 @@
+package writer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"os"
+	"testing"
+
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter_Strip(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     StripOptions
+		removed  []tiff.EntryID // entries of IFD#0
+		secrets  []string
+		retained []string
+	}{
+		{
+			name:     "GPS",
+			opts:     StripOptions{GPS: true},
+			removed:  []tiff.EntryID{tiff.GPSInfo},
+			secrets:  []string{string(latitude)},
+			retained: []string{"maker note secrets", "Jane Doe", "Secret Camera Co"},
+		},
+		{
+			name:     "maker notes",
+			opts:     StripOptions{MakerNotes: true},
+			secrets:  []string{"maker note secrets"},
+			retained: []string{string(latitude), "Jane Doe", "2021:11:19 12:21:10"},
+		},
+		{
+			name:    "all",
+			opts:    StripOptions{All: true},
+			removed: []tiff.EntryID{tiff.Exif, tiff.GPSInfo, tiff.Make, tiff.Artist},
+			secrets: []string{string(latitude), "maker note secrets", "Jane Doe", "Secret Camera Co", "2021:11:19 12:21:10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := newTestFile()
+			before, _ := firstPage(t, data)
+
+			w, err := newWriter(bytes.Clone(data))
+			assert.NoError(t, err)
+			assert.NoError(t, w.Strip(tt.opts))
+
+			out := w.Bytes()
+			assert.Len(t, out, len(data))
+			page, strips := firstPage(t, out)
+			assert.Equal(t, [][]byte{{1, 2, 3, 4}}, strips)
+			assert.Len(t, page.Entries, len(before.Entries)-len(tt.removed))
+			for _, id := range tt.removed {
+				assert.NotContains(t, page.Entries, id)
+			}
+			for _, secret := range tt.secrets {
+				assert.False(t, bytes.Contains(out, []byte(secret)), secret)
+			}
+			for _, value := range tt.retained {
+				assert.True(t, bytes.Contains(out, []byte(value)), value)
+			}
+		})
+	}
+}
+
+func TestWriter_Strip_CR2(t *testing.T) {
+	data, err := os.ReadFile("../tiff/testdata/image.cr2")
+	assert.NoError(t, err)
+
+	hash := func(data []byte) []byte {
+		p, err := tiff.NewParser(bytes.NewReader(data))
+		assert.NoError(t, err)
+		h := sha256.New()
+		assert.NoError(t, p.ImageDataHash(h))
+		return h.Sum(nil)
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, Strip(bytes.NewReader(data), &buf, StripOptions{MakerNotes: true}))
+	assert.Len(t, buf.Bytes(), len(data))
+	assert.Equal(t, hash(data), hash(buf.Bytes()))
+
+	p, err := tiff.NewParser(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	entries, err := p.Parse(tiff.MakerNotes, tiff.DateTimeOriginal, tiff.Make)
+	assert.NoError(t, err)
+	assert.NotContains(t, entries, tiff.MakerNotes)
+	assert.Equal(t, "2021:11:19 12:21:10", *entries[tiff.DateTimeOriginal].Value.String)
+	assert.Equal(t, "Canon", *entries[tiff.Make].Value.String)
+	assert.Equal(t, make([]byte, 45494), buf.Bytes()[984:984+45494])
+
+	buf.Reset()
+	assert.NoError(t, Strip(bytes.NewReader(data), &buf, StripOptions{All: true}))
+	assert.Equal(t, hash(data), hash(buf.Bytes()))
+	p, err = tiff.NewParser(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	entries, err = p.Parse(tiff.Make, tiff.Model)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+	_, err = p.Parse(tiff.DateTimeOriginal)
+	assert.Error(t, err)
+}
@@ -0,0 +1,196 @@
+// Package writer edits the metadata of TIFF files (and of the TIFF structure embedded in the Exif segment of JPEG
+// files).
+package writer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/fedragon/tiff-parser/tiff"
+)
+
+// Writer edits the metadata of a TIFF file held in memory. Edits are made in place where they fit: IFD tables that need
+// a new entry, and values that do not fit where the previous ones were, are appended to the end of the file (see Plan),
+// and the offsets pointing to them are updated. Everything else stays where it is, so that the offsets of the file stay
+// valid: image data (which is never touched), maker notes and the other values and tables.
+type Writer struct {
+	data        []byte
+	byteOrder   binary.ByteOrder
+	relocations []Relocation // values and tables moved so far (see Plan)
+	keepOrphans bool         // see WithZeroFill
+}
+
+// New reads a TIFF file, returning a Writer to edit it.
+func New(r io.Reader) (*Writer, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return newWriter(data)
+}
+
+// newWriter returns a Writer editing data, which it takes ownership of.
+func newWriter(data []byte) (*Writer, error) {
+	p, err := tiff.NewParser(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{data: data, byteOrder: p.ByteOrder()}, nil
+}
+
+// Bytes returns the content of the edited file.
+func (w *Writer) Bytes() []byte {
+	return w.data
+}
+
+// WriteTo writes the edited file to out.
+func (w *Writer) WriteTo(out io.Writer) (int64, error) {
+	n, err := out.Write(w.data)
+
+	return int64(n), err
+}
+
+// layout returns the regions of the file, as it is now.
+func (w *Writer) layout() ([]tiff.Region, error) {
+	p, err := tiff.NewParser(bytes.NewReader(w.data))
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Layout()
+}
+
+// deleteEntries removes the entries matching a predicate from all IFDs (see removeEntries), then zero-fills the bytes
+// that were only referenced by them (e.g. their values, or the sub-IFDs they pointed to).
+func (w *Writer) deleteEntries(match func(tiff.EntryID) bool) error {
+	return w.edit(func() error {
+		return w.removeEntries(match)
+	})
+}
+
+// removeEntries removes the entries matching a predicate from the IFDs where they mean what they mean in tiff.Defaults:
+// as the IDs of GPSInfo IFDs collide with those of other IFDs (e.g. 0x0001 is GPSLatitudeRef in GPSInfo IFDs, but
+// InteroperabilityIndex elsewhere), GPS entries are only removed from GPSInfo IFDs, and other entries from other IFDs.
+func (w *Writer) removeEntries(match func(tiff.EntryID) bool) error {
+	p, err := tiff.NewParser(bytes.NewReader(w.data))
+	if err != nil {
+		return err
+	}
+	infos, err := p.ListIDs()
+	if err != nil {
+		return err
+	}
+	gpsInfo := make(map[int64]bool)
+	for _, info := range infos {
+		if info.Group == tiff.Group_GPSInfo {
+			gpsInfo[info.IFDOffset] = true
+		}
+	}
+
+	regions, err := p.Layout()
+	if err != nil {
+		return err
+	}
+	for _, region := range regions {
+		if region.Kind != tiff.Region_IFD {
+			continue
+		}
+		inGPSInfo := gpsInfo[region.Offset]
+		err := w.compact(region.Offset, func(id tiff.EntryID) bool {
+			return (tiff.Defaults[id] == tiff.Group_GPSInfo) == inGPSInfo && match(id)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// edit applies a change to the file, then zero-fills the bytes that were referenced by the file before the change, but
+// no longer are (see WithZeroFill).
+func (w *Writer) edit(change func() error) error {
+	before, err := w.layout()
+	if err != nil {
+		return err
+	}
+	if err := change(); err != nil {
+		return err
+	}
+
+	after, err := w.layout()
+	if err != nil {
+		return err
+	}
+	if w.keepOrphans {
+		return nil
+	}
+	for _, region := range orphans(before, after) {
+		clear(w.data[min(region.Offset, int64(len(w.data))):min(region.End(), int64(len(w.data)))])
+	}
+
+	return nil
+}
+
+// compact removes the entries matching a predicate from the IFD starting at the given offset, moving the following
+// entries (and the offset of the next IFD) up so that the table stays contiguous, then zero-fills the bytes it no longer
+// uses.
+func (w *Writer) compact(offset int64, match func(tiff.EntryID) bool) error {
+	if offset+2 > int64(len(w.data)) {
+		return fmt.Errorf("IFD at offset %d is out of bounds", offset)
+	}
+	count := int(w.byteOrder.Uint16(w.data[offset:]))
+	end := offset + 2 + int64(count)*tiff.EntryLength + 4
+	if end > int64(len(w.data)) {
+		return fmt.Errorf("IFD at offset %d is out of bounds", offset)
+	}
+
+	table := w.data[offset+2 : end]
+	kept := 0
+	for i := 0; i < count; i++ {
+		entry := table[i*tiff.EntryLength : (i+1)*tiff.EntryLength]
+		if match(tiff.EntryID(w.byteOrder.Uint16(entry))) {
+			continue
+		}
+		copy(table[kept*tiff.EntryLength:], entry)
+		kept++
+	}
+	if kept == count {
+		return nil
+	}
+
+	copy(table[kept*tiff.EntryLength:], table[count*tiff.EntryLength:]) // offset of the next IFD
+	clear(table[kept*tiff.EntryLength+4:])
+	w.byteOrder.PutUint16(w.data[offset:], uint16(kept))
+
+	return nil
+}
+
+// orphans returns the parts of the regions of before that are not covered by any region of after, which must be sorted
+// by offset.
+func orphans(before, after []tiff.Region) []tiff.Region {
+	var res []tiff.Region
+	for _, region := range before {
+		start := region.Offset
+		i := sort.Search(len(after), func(i int) bool { return after[i].Offset >= region.End() })
+		for _, other := range after[:i] {
+			if other.End() <= start {
+				continue
+			}
+			if other.Offset > start {
+				res = append(res, tiff.Region{Kind: region.Kind, Offset: start, Length: other.Offset - start, IFDOffset: region.IFDOffset, Entry: region.Entry})
+			}
+			start = max(start, other.End())
+		}
+		if start < region.End() {
+			res = append(res, tiff.Region{Kind: region.Kind, Offset: start, Length: region.End() - start, IFDOffset: region.IFDOffset, Entry: region.Entry})
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,136 @@
+package writer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/fedragon/tiff-parser/test"
+	"github.com/fedragon/tiff-parser/tiff"
+	"github.com/stretchr/testify/assert"
+)
+
+// latitude is the GPSLatitude of newTestFile, as written in the file.
+var latitude = binary.LittleEndian.AppendUint32(binary.LittleEndian.AppendUint32(nil, 1234), 100)
+
+// newTestFile returns a TIFF file with a 2x2 grayscale image and some metadata: descriptive entries, an Exif IFD with a
+// maker note, and a GPSInfo IFD.
+func newTestFile() []byte {
+	return newTestFileWithMakerNote([]byte("maker note secrets"))
+}
+
+// newTestFileWithMakerNote returns newTestFile with the given maker note.
+func newTestFileWithMakerNote(note []byte) []byte {
+	b := test.NewTIFFBuilder(binary.LittleEndian)
+	ifd := b.AddIFD().
+		WithUints16(uint16(tiff.ImageWidth), 2).
+		WithUints16(uint16(tiff.ImageHeight), 2).
+		WithUints16(uint16(tiff.BitsPerSample), 8).
+		WithUints16(uint16(tiff.SamplesPerPixel), 1).
+		WithUints16(uint16(tiff.PhotometricInterpretation), 1).
+		WithString(uint16(tiff.Make), "Secret Camera Co").
+		WithString(uint16(tiff.Artist), "Jane Doe").
+		WithData(uint16(tiff.StripOffsets), uint16(tiff.StripByteCounts), []byte{1, 2, 3, 4})
+	ifd.WithSubIFD(uint16(tiff.Exif)).
+		WithString(uint16(tiff.DateTimeOriginal), "2021:11:19 12:21:10").
+		WithBytes(uint16(tiff.MakerNotes), test.TypeUndefined, note...)
+	ifd.WithSubIFD(uint16(tiff.GPSInfo)).
+		WithURationals(uint16(tiff.GPSLatitude), 52, 1, 22, 1, 1234, 100)
+
+	return b.Bytes()
+}
+
+// firstPage parses a file, returning its IFD#0 and strips.
+func firstPage(t *testing.T, data []byte) (tiff.Page, [][]byte) {
+	p, err := tiff.NewParser(bytes.NewReader(data))
+	assert.NoError(t, err)
+	page, err := p.FirstPage()
+	assert.NoError(t, err)
+	strips, err := p.ReadStrips(page)
+	assert.NoError(t, err)
+
+	return page, strips
+}
+
+func TestWriter_compact(t *testing.T) {
+	w, err := newWriter(newTestFile())
+	assert.NoError(t, err)
+	page, _ := firstPage(t, w.Bytes())
+
+	assert.NoError(t, w.compact(page.Offset, func(id tiff.EntryID) bool { return id == tiff.ImageWidth || id == tiff.Make }))
+
+	got, strips := firstPage(t, w.Bytes())
+	assert.Len(t, got.Entries, len(page.Entries)-2)
+	assert.NotContains(t, got.Entries, tiff.ImageWidth)
+	assert.NotContains(t, got.Entries, tiff.Make)
+	assert.Equal(t, page.Entries[tiff.Artist], got.Entries[tiff.Artist])
+	assert.Equal(t, [][]byte{{1, 2, 3, 4}}, strips)
+
+	// the bytes no longer used by the table are zero-filled
+	end := got.Offset + 2 + int64(len(got.Entries))*tiff.EntryLength + 4
+	assert.Equal(t, make([]byte, 2*tiff.EntryLength), w.Bytes()[end:end+2*tiff.EntryLength])
+
+	assert.Error(t, w.compact(int64(len(w.Bytes())), func(tiff.EntryID) bool { return true }))
+}
+
+func TestOrphans(t *testing.T) {
+	before := []tiff.Region{
+		{Kind: tiff.Region_IFD, Offset: 0, Length: 10},
+		{Kind: tiff.Region_Value, Offset: 20, Length: 10},
+		{Kind: tiff.Region_Value, Offset: 40, Length: 10},
+	}
+	after := []tiff.Region{
+		{Kind: tiff.Region_IFD, Offset: 0, Length: 6},
+		{Kind: tiff.Region_Value, Offset: 22, Length: 2},
+		{Kind: tiff.Region_Value, Offset: 23, Length: 4},
+		{Kind: tiff.Region_Value, Offset: 40, Length: 10},
+	}
+
+	assert.Equal(t, []tiff.Region{
+		{Kind: tiff.Region_IFD, Offset: 6, Length: 4},
+		{Kind: tiff.Region_Value, Offset: 20, Length: 2},
+		{Kind: tiff.Region_Value, Offset: 27, Length: 3},
+	}, orphans(before, after))
+}
+
+func TestWriter_MakerNoteNeverMoves(t *testing.T) {
+	// a maker note whose single entry (ImageType) holds an offset relative to the TIFF header, as Canon writes them
+	const imageType = "Model 1\x00"
+	makerNote := func(offset uint32) []byte {
+		note := binary.LittleEndian.AppendUint16(nil, 1)
+		note = binary.LittleEndian.AppendUint16(note, 0x0006)
+		note = binary.LittleEndian.AppendUint16(note, uint16(tiff.DataType_String))
+		note = binary.LittleEndian.AppendUint32(note, uint32(len(imageType)))
+		note = binary.LittleEndian.AppendUint32(note, offset+18)
+		note = binary.LittleEndian.AppendUint32(note, 0)
+		return append(note, imageType...)
+	}
+	data := newTestFileWithMakerNote(makerNote(0))
+	p, err := tiff.NewParser(bytes.NewReader(data))
+	assert.NoError(t, err)
+	entries, err := p.Parse(tiff.MakerNotes)
+	assert.NoError(t, err)
+	offset := entries[tiff.MakerNotes].RawValue
+	note := makerNote(offset)
+	copy(data[offset:], note)
+
+	w, err := newWriter(data)
+	assert.NoError(t, err)
+	assert.NoError(t, w.SetImageDescription("a description too long to fit where the previous one was"))
+	assert.NoError(t, w.SetString(tiff.OffsetTimeOriginal, "+02:00")) // copies the table of the Exif IFD
+	assert.NoError(t, w.SetGPSPosition(48.8584, 2.2945))
+	assert.NoError(t, w.Apply(Edits{Delete: []tiff.EntryID{tiff.Artist}, ShiftTime: time.Hour}))
+	assert.NoError(t, w.Anonymize(Profile{Delete: []tiff.EntryID{tiff.GPSInfo}}))
+	assert.EqualError(t, w.SetString(tiff.MakerNotes, "maker note"), "maker notes cannot be set")
+	assert.Error(t, w.Apply(Edits{Set: map[tiff.EntryID]string{tiff.MakerNotes: "maker note"}}))
+
+	out := w.Bytes()
+	p, err = tiff.NewParser(bytes.NewReader(out))
+	assert.NoError(t, err)
+	entries, err = p.Parse(tiff.MakerNotes)
+	assert.NoError(t, err)
+	assert.Equal(t, offset, entries[tiff.MakerNotes].RawValue)
+	assert.Equal(t, note, entries[tiff.MakerNotes].Value.Bytes)
+	assert.Equal(t, imageType, string(out[offset+18:offset+18+uint32(len(imageType))]))
+}